@@ -246,3 +246,30 @@ func (h *Hello) UnmarshalBinary(data []byte) error {
 	}
 	return err
 }
+
+// SupportsVersion returns true if the sender of the hello supports the version. The supported versions are set in
+// the version bitmap element, or are all the versions up to the version in the header if the hello has no version
+// bitmap, e.g. the hello of an OpenFlow 1.0 switch.
+func (h *Hello) SupportsVersion(ver uint8) bool {
+	for _, e := range h.Elements {
+		if bitmap, ok := e.(*HelloElemVersionBitmap); ok {
+			index := int(ver / 32)
+			return index < len(bitmap.Bitmaps) && bitmap.Bitmaps[index]&(1<<(ver%32)) != 0
+		}
+	}
+	return ver <= h.Version
+}
+
+// NegotiateVersion returns the highest version in versions that is also supported by the peer which sent the hello.
+func NegotiateVersion(hello *Hello, versions ...uint8) (uint8, error) {
+	var negotiated uint8
+	for _, ver := range versions {
+		if ver > negotiated && hello.SupportsVersion(ver) {
+			negotiated = ver
+		}
+	}
+	if negotiated == 0 {
+		return 0, errors.New("no common OpenFlow version is supported by the peer")
+	}
+	return negotiated, nil
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"encoding/hex"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHello(version uint8, bitmaps ...uint32) *Hello {
+	hello := &Hello{Header: Header{Version: version}}
+	if bitmaps != nil {
+		elem := NewHelloElemVersionBitmap()
+		elem.Bitmaps = bitmaps
+		elem.Length = elem.Len()
+		hello.Elements = append(hello.Elements, elem)
+	}
+	return hello
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	// The hello of OVS supporting OpenFlow 1.0, 1.2, 1.3 and 1.5.
+	data, err := hex.DecodeString("0600001000000001000100080000005a")
+	require.NoError(t, err)
+	ovsHello := new(Hello)
+	require.NoError(t, ovsHello.UnmarshalBinary(data))
+	defaultHello, err := NewHello(6)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name       string
+		hello      *Hello
+		versions   []uint8
+		supported  []uint8
+		negotiated uint8
+	}{
+		{
+			name:       "version bitmap",
+			hello:      ovsHello,
+			versions:   []uint8{1, 4, 5, 6},
+			supported:  []uint8{1, 3, 4, 6},
+			negotiated: 6,
+		},
+		{
+			name:       "default version bitmap",
+			hello:      defaultHello,
+			versions:   []uint8{1, 4, 6},
+			supported:  []uint8{1, 6},
+			negotiated: 6,
+		},
+		{
+			name:       "default version bitmap without the highest version",
+			hello:      defaultHello,
+			versions:   []uint8{1, 4},
+			supported:  []uint8{1, 6},
+			negotiated: 1,
+		},
+		{
+			name:       "version bitmap without the highest version",
+			hello:      newTestHello(6, 1<<1|1<<4),
+			versions:   []uint8{4, 5, 6},
+			supported:  []uint8{1, 4},
+			negotiated: 4,
+		},
+		{
+			name:       "OpenFlow 1.0 peer without version bitmap",
+			hello:      newTestHello(1),
+			versions:   []uint8{1, 4, 6},
+			supported:  []uint8{1},
+			negotiated: 1,
+		},
+		{
+			name:       "versions beyond the bitmap length",
+			hello:      newTestHello(40, 1<<4, 1<<1),
+			versions:   []uint8{4, 33, 65},
+			supported:  []uint8{4, 33},
+			negotiated: 33,
+		},
+		{
+			name:      "no common version",
+			hello:     newTestHello(1, 1<<1),
+			versions:  []uint8{4, 6},
+			supported: []uint8{1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for ver := uint8(1); ver < 100; ver++ {
+				assert.Equal(t, slices.Contains(tc.supported, ver), tc.hello.SupportsVersion(ver), "version %d", ver)
+			}
+			negotiated, err := NegotiateVersion(tc.hello, tc.versions...)
+			if tc.negotiated == 0 {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.negotiated, negotiated)
+		})
+	}
+}
@@ -0,0 +1,537 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"antrea.io/libOpenflow/openflow13"
+)
+
+// ofp_action_type 1.0
+const (
+	ActionType_Output     = 0
+	ActionType_SetVlanVid = 1
+	ActionType_SetVlanPcp = 2
+	ActionType_StripVlan  = 3
+	ActionType_SetDlSrc   = 4
+	ActionType_SetDlDst   = 5
+	ActionType_SetNwSrc   = 6
+	ActionType_SetNwDst   = 7
+	ActionType_SetNwTos   = 8
+	ActionType_SetTpSrc   = 9
+	ActionType_SetTpDst   = 10
+	ActionType_Enqueue    = 11
+
+	ActionType_Vendor = 0xffff
+)
+
+// Decode Action types.
+func DecodeAction(data []byte) (Action, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to decode an action")
+	}
+	t := binary.BigEndian.Uint16(data[:2])
+	var a Action
+	switch t {
+	case ActionType_Output:
+		a = new(ActionOutput)
+	case ActionType_SetVlanVid:
+		a = new(ActionVlanVid)
+	case ActionType_SetVlanPcp:
+		a = new(ActionVlanPcp)
+	case ActionType_StripVlan:
+		a = new(ActionStripVlan)
+	case ActionType_SetDlSrc, ActionType_SetDlDst:
+		a = new(ActionDlAddr)
+	case ActionType_SetNwSrc, ActionType_SetNwDst:
+		a = new(ActionNwAddr)
+	case ActionType_SetNwTos:
+		a = new(ActionNwTos)
+	case ActionType_SetTpSrc, ActionType_SetTpDst:
+		a = new(ActionTpPort)
+	case ActionType_Enqueue:
+		a = new(ActionEnqueue)
+	case ActionType_Vendor:
+		if len(data) >= 8 && binary.BigEndian.Uint32(data[4:8]) == NxVendorID {
+			var err error
+			if a, err = openflow13.DecodeNxAction(data); err != nil {
+				return nil, err
+			}
+		} else {
+			a = new(ActionVendor)
+		}
+	default:
+		return nil, fmt.Errorf("DecodeAction unknown type: %v", t)
+	}
+	err := a.UnmarshalBinary(data)
+	if err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+// decodeActions decodes the actions in the first length bytes of data.
+func decodeActions(data []byte, length uint16) ([]Action, error) {
+	if len(data) < int(length) {
+		return nil, errors.New("the []byte is too short to decode the actions")
+	}
+	var actions []Action
+	var n uint16
+	for n < length {
+		a, err := DecodeAction(data[n:length])
+		if err != nil {
+			return nil, err
+		}
+		if a.Len() == 0 {
+			return nil, errors.New("the action has a zero length")
+		}
+		actions = append(actions, a)
+		n += a.Len()
+	}
+	return actions, nil
+}
+
+// Action structure for OFPAT_OUTPUT, which sends packets out ’port’.
+// When the ’port’ is the OFPP_CONTROLLER, ’max_len’ indicates the max
+// number of bytes to send. A ’max_len’ of zero means no bytes of the
+// packet should be sent.
+type ActionOutput struct {
+	ActionHeader
+	Port   uint16
+	MaxLen uint16
+}
+
+// Returns a new Action Output message which sends packets out
+// port number.
+func NewActionOutput(portNum uint16) *ActionOutput {
+	act := new(ActionOutput)
+	act.Type = ActionType_Output
+	act.Length = act.Len()
+	act.Port = portNum
+	act.MaxLen = 256
+	return act
+}
+
+func (a *ActionOutput) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionOutput) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	if b, err = a.ActionHeader.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.Port)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.MaxLen)
+	n += 2
+	return
+}
+
+func (a *ActionOutput) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionOutput message")
+	}
+	n := 0
+	err := a.ActionHeader.UnmarshalBinary(data[n:])
+	n += int(a.ActionHeader.Len())
+	a.Port = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.MaxLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	return err
+}
+
+// Action structure for OFPAT_SET_VLAN_VID.
+type ActionVlanVid struct {
+	ActionHeader
+	VlanVid uint16
+}
+
+func NewActionVlanVid(vid uint16) *ActionVlanVid {
+	a := new(ActionVlanVid)
+	a.Type = ActionType_SetVlanVid
+	a.Length = a.Len()
+	a.VlanVid = vid
+	return a
+}
+
+func (a *ActionVlanVid) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionVlanVid) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	binary.BigEndian.PutUint16(data[4:], a.VlanVid)
+	// 2 bytes for padding
+	return
+}
+
+func (a *ActionVlanVid) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionVlanVid message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.VlanVid = binary.BigEndian.Uint16(data[4:])
+	return err
+}
+
+// Action structure for OFPAT_SET_VLAN_PCP.
+type ActionVlanPcp struct {
+	ActionHeader
+	VlanPcp uint8
+}
+
+func NewActionVlanPcp(pcp uint8) *ActionVlanPcp {
+	a := new(ActionVlanPcp)
+	a.Type = ActionType_SetVlanPcp
+	a.Length = a.Len()
+	a.VlanPcp = pcp
+	return a
+}
+
+func (a *ActionVlanPcp) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionVlanPcp) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	data[4] = a.VlanPcp
+	// 3 bytes for padding
+	return
+}
+
+func (a *ActionVlanPcp) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionVlanPcp message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.VlanPcp = data[4]
+	return err
+}
+
+// Action structure for OFPAT_STRIP_VLAN.
+type ActionStripVlan struct {
+	ActionHeader
+}
+
+func NewActionStripVlan() *ActionStripVlan {
+	a := new(ActionStripVlan)
+	a.Type = ActionType_StripVlan
+	a.Length = a.Len()
+	return a
+}
+
+func (a *ActionStripVlan) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionStripVlan) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	copy(data, b)
+	// 4 bytes for padding
+	return
+}
+
+func (a *ActionStripVlan) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionStripVlan message")
+	}
+	return a.ActionHeader.UnmarshalBinary(data)
+}
+
+// Action structure for OFPAT_SET_DL_SRC/DST.
+type ActionDlAddr struct {
+	ActionHeader
+	DlAddr net.HardwareAddr
+}
+
+// NewActionDlSrc returns the action to set the Ethernet source address.
+func NewActionDlSrc(addr net.HardwareAddr) *ActionDlAddr {
+	return newActionDlAddr(ActionType_SetDlSrc, addr)
+}
+
+// NewActionDlDst returns the action to set the Ethernet destination address.
+func NewActionDlDst(addr net.HardwareAddr) *ActionDlAddr {
+	return newActionDlAddr(ActionType_SetDlDst, addr)
+}
+
+func newActionDlAddr(actionType uint16, addr net.HardwareAddr) *ActionDlAddr {
+	a := new(ActionDlAddr)
+	a.Type = actionType
+	a.Length = a.Len()
+	a.DlAddr = addr
+	return a
+}
+
+func (a *ActionDlAddr) Len() (n uint16) {
+	return a.ActionHeader.Len() + 12
+}
+
+func (a *ActionDlAddr) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	copy(data[4:4+ETH_ALEN], a.DlAddr)
+	// 6 bytes for padding
+	return
+}
+
+func (a *ActionDlAddr) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionDlAddr message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.DlAddr = make(net.HardwareAddr, ETH_ALEN)
+	copy(a.DlAddr, data[4:])
+	return err
+}
+
+// Action structure for OFPAT_SET_NW_SRC/DST.
+type ActionNwAddr struct {
+	ActionHeader
+	NwAddr net.IP
+}
+
+// NewActionNwSrc returns the action to set the IPv4 source address.
+func NewActionNwSrc(addr net.IP) *ActionNwAddr {
+	return newActionNwAddr(ActionType_SetNwSrc, addr)
+}
+
+// NewActionNwDst returns the action to set the IPv4 destination address.
+func NewActionNwDst(addr net.IP) *ActionNwAddr {
+	return newActionNwAddr(ActionType_SetNwDst, addr)
+}
+
+func newActionNwAddr(actionType uint16, addr net.IP) *ActionNwAddr {
+	a := new(ActionNwAddr)
+	a.Type = actionType
+	a.Length = a.Len()
+	a.NwAddr = addr
+	return a
+}
+
+func (a *ActionNwAddr) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionNwAddr) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	copy(data[4:], a.NwAddr.To4())
+	return
+}
+
+func (a *ActionNwAddr) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionNwAddr message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.NwAddr = net.IPv4(data[4], data[5], data[6], data[7]).To4()
+	return err
+}
+
+// Action structure for OFPAT_SET_NW_TOS.
+type ActionNwTos struct {
+	ActionHeader
+	NwTos uint8
+}
+
+func NewActionNwTos(tos uint8) *ActionNwTos {
+	a := new(ActionNwTos)
+	a.Type = ActionType_SetNwTos
+	a.Length = a.Len()
+	a.NwTos = tos
+	return a
+}
+
+func (a *ActionNwTos) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionNwTos) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	data[4] = a.NwTos
+	// 3 bytes for padding
+	return
+}
+
+func (a *ActionNwTos) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionNwTos message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.NwTos = data[4]
+	return err
+}
+
+// Action structure for OFPAT_SET_TP_SRC/DST.
+type ActionTpPort struct {
+	ActionHeader
+	TpPort uint16
+}
+
+// NewActionTpSrc returns the action to set the TCP/UDP source port.
+func NewActionTpSrc(port uint16) *ActionTpPort {
+	return newActionTpPort(ActionType_SetTpSrc, port)
+}
+
+// NewActionTpDst returns the action to set the TCP/UDP destination port.
+func NewActionTpDst(port uint16) *ActionTpPort {
+	return newActionTpPort(ActionType_SetTpDst, port)
+}
+
+func newActionTpPort(actionType uint16, port uint16) *ActionTpPort {
+	a := new(ActionTpPort)
+	a.Type = actionType
+	a.Length = a.Len()
+	a.TpPort = port
+	return a
+}
+
+func (a *ActionTpPort) Len() (n uint16) {
+	return a.ActionHeader.Len() + 4
+}
+
+func (a *ActionTpPort) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	binary.BigEndian.PutUint16(data[4:], a.TpPort)
+	// 2 bytes for padding
+	return
+}
+
+func (a *ActionTpPort) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionTpPort message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.TpPort = binary.BigEndian.Uint16(data[4:])
+	return err
+}
+
+// Action structure for OFPAT_ENQUEUE, which sends packets to the queue of the port.
+type ActionEnqueue struct {
+	ActionHeader
+	Port    uint16
+	QueueId uint32
+}
+
+func NewActionEnqueue(port uint16, queue uint32) *ActionEnqueue {
+	a := new(ActionEnqueue)
+	a.Type = ActionType_Enqueue
+	a.Length = a.Len()
+	a.Port = port
+	a.QueueId = queue
+	return a
+}
+
+func (a *ActionEnqueue) Len() (n uint16) {
+	return a.ActionHeader.Len() + 12
+}
+
+func (a *ActionEnqueue) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	binary.BigEndian.PutUint16(data[4:], a.Port)
+	// 6 bytes for padding
+	binary.BigEndian.PutUint32(data[12:], a.QueueId)
+	return
+}
+
+func (a *ActionEnqueue) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full ActionEnqueue message")
+	}
+	err := a.ActionHeader.UnmarshalBinary(data)
+	a.Port = binary.BigEndian.Uint16(data[4:])
+	a.QueueId = binary.BigEndian.Uint32(data[12:])
+	return err
+}
+
+// Action structure for OFPAT_VENDOR. The body of the vendor action, e.g. the Nicira subtype and arguments, is kept
+// in Data.
+type ActionVendor struct {
+	ActionHeader
+	Vendor uint32
+	Data   []byte
+}
+
+func NewActionVendor(vendor uint32, data []byte) *ActionVendor {
+	a := new(ActionVendor)
+	a.Type = ActionType_Vendor
+	a.Vendor = vendor
+	a.Data = data
+	a.Length = a.Len()
+	return a
+}
+
+// Len returns the length of ActionVendor, which is rounded up to a multiple of 8 bytes.
+func (a *ActionVendor) Len() (n uint16) {
+	n = a.ActionHeader.Len() + 4 + uint16(len(a.Data))
+	return (n + 7) / 8 * 8
+}
+
+func (a *ActionVendor) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	a.Length = a.Len()
+	b, err := a.ActionHeader.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	binary.BigEndian.PutUint32(data[4:], a.Vendor)
+	copy(data[8:], a.Data)
+	return
+}
+
+func (a *ActionVendor) UnmarshalBinary(data []byte) error {
+	if err := a.ActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if a.Length < 8 || a.Length%8 != 0 || len(data) < int(a.Length) {
+		return errors.New("the []byte is too short to unmarshal a full ActionVendor message")
+	}
+	a.Vendor = binary.BigEndian.Uint32(data[4:])
+	a.Data = make([]byte, a.Length-8)
+	copy(a.Data, data[8:a.Length])
+	return nil
+}
@@ -0,0 +1,257 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/common"
+)
+
+// ofp_flow_mod 1.0
+type FlowMod struct {
+	common.Header
+	Match       Match
+	Cookie      uint64
+	Command     uint16
+	IdleTimeout uint16
+	HardTimeout uint16
+	Priority    uint16
+	BufferId    uint32
+	OutPort     uint16
+	Flags       uint16
+	Actions     []Action
+}
+
+// ofp_flow_mod_command 1.0
+const (
+	FC_ADD           = iota // OFPFC_ADD = 0
+	FC_MODIFY               // OFPFC_MODIFY = 1
+	FC_MODIFY_STRICT        // OFPFC_MODIFY_STRICT = 2
+	FC_DELETE               // OFPFC_DELETE = 3
+	FC_DELETE_STRICT        // OFPFC_DELETE_STRICT = 4
+)
+
+// ofp_flow_mod_flags 1.0
+const (
+	FF_SEND_FLOW_REM = 1 << 0 /* Send flow removed message when flow expires or is deleted. */
+	FF_CHECK_OVERLAP = 1 << 1 /* Check for overlapping entries first. */
+	FF_EMERG         = 1 << 2 /* Remark this is for emergency. */
+)
+
+// Create a new flow mod message
+func NewFlowMod() *FlowMod {
+	f := new(FlowMod)
+	f.Header = NewOfp10Header()
+	f.Header.Type = Type_FlowMod
+	f.Match = *NewMatch()
+	f.Command = FC_ADD
+	f.Priority = 0x8000
+	f.BufferId = 0xffffffff
+	f.OutPort = P_NONE
+	f.Actions = make([]Action, 0)
+	return f
+}
+
+func (f *FlowMod) AddAction(act Action) {
+	f.Actions = append(f.Actions, act)
+}
+
+func (f *FlowMod) Len() (n uint16) {
+	n = f.Header.Len()
+	n += f.Match.Len()
+	n += 24
+	for _, a := range f.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (f *FlowMod) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	f.Header.Length = f.Len()
+	b, err := f.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := len(b)
+
+	if b, err = f.Match.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	binary.BigEndian.PutUint64(data[n:], f.Cookie)
+	n += 8
+	binary.BigEndian.PutUint16(data[n:], f.Command)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.HardTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.Priority)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], f.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], f.OutPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.Flags)
+	n += 2
+
+	for _, a := range f.Actions {
+		if b, err = a.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (f *FlowMod) UnmarshalBinary(data []byte) error {
+	if err := f.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(f.Header.Length) || f.Header.Length < 72 {
+		return errors.New("the []byte is too short to unmarshal a full FlowMod message")
+	}
+	n := f.Header.Len()
+
+	if err := f.Match.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += f.Match.Len()
+
+	f.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	f.Command = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	f.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	actions, err := decodeActions(data[n:f.Header.Length], f.Header.Length-n)
+	if err != nil {
+		return err
+	}
+	f.Actions = actions
+	return nil
+}
+
+// ofp_flow_removed 1.0
+type FlowRemoved struct {
+	common.Header
+	Match        Match
+	Cookie       uint64
+	Priority     uint16
+	Reason       uint8
+	DurationSec  uint32
+	DurationNSec uint32
+	IdleTimeout  uint16
+	PacketCount  uint64
+	ByteCount    uint64
+}
+
+// ofp_flow_removed_reason 1.0
+const (
+	RR_IDLE_TIMEOUT = iota /* Flow idle time exceeded idle_timeout. */
+	RR_HARD_TIMEOUT        /* Time exceeded hard_timeout. */
+	RR_DELETE              /* Evicted by a DELETE flow mod. */
+)
+
+func NewFlowRemoved() *FlowRemoved {
+	f := new(FlowRemoved)
+	f.Header = NewOfp10Header()
+	f.Header.Type = Type_FlowRemoved
+	f.Match = *NewMatch()
+	return f
+}
+
+func (f *FlowRemoved) Len() (n uint16) {
+	n = f.Header.Len()
+	n += f.Match.Len()
+	n += 40
+	return
+}
+
+func (f *FlowRemoved) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	f.Header.Length = f.Len()
+	b, err := f.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := len(b)
+
+	if b, err = f.Match.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	binary.BigEndian.PutUint64(data[n:], f.Cookie)
+	n += 8
+	binary.BigEndian.PutUint16(data[n:], f.Priority)
+	n += 2
+	data[n] = f.Reason
+	n += 1
+	n += 1 // pad
+	binary.BigEndian.PutUint32(data[n:], f.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], f.DurationNSec)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], f.IdleTimeout)
+	n += 2
+	n += 2 // pad2
+	binary.BigEndian.PutUint64(data[n:], f.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], f.ByteCount)
+	n += 8
+	return
+}
+
+func (f *FlowRemoved) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowRemoved message")
+	}
+	if err := f.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := f.Header.Len()
+
+	if err := f.Match.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += f.Match.Len()
+
+	f.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	f.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.Reason = data[n]
+	n += 1
+	n += 1 // pad
+	f.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	f.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	f.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 2 // pad2
+	f.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	f.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	return nil
+}
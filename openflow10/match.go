@@ -0,0 +1,167 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// MATCH_LEN is the length of ofp_match 1.0.
+const MATCH_LEN = 40
+
+// ofp_match 1.0
+// The fields are matched only if the corresponding FW_* bit is cleared in Wildcards. The IPv4 addresses are matched
+// with the prefix set in the FW_NW_SRC_* and FW_NW_DST_* bits of Wildcards.
+type Match struct {
+	Wildcards uint32
+	InPort    uint16
+	DlSrc     net.HardwareAddr
+	DlDst     net.HardwareAddr
+	DlVlan    uint16
+	DlVlanPcp uint8
+	DlType    uint16
+	NwTos     uint8
+	NwProto   uint8
+	NwSrc     net.IP
+	NwDst     net.IP
+	TpSrc     uint16
+	TpDst     uint16
+}
+
+// ofp_flow_wildcards 1.0
+const (
+	FW_IN_PORT  = 1 << 0 /* Switch input port. */
+	FW_DL_VLAN  = 1 << 1 /* VLAN id. */
+	FW_DL_SRC   = 1 << 2 /* Ethernet source address. */
+	FW_DL_DST   = 1 << 3 /* Ethernet destination address. */
+	FW_DL_TYPE  = 1 << 4 /* Ethernet frame type. */
+	FW_NW_PROTO = 1 << 5 /* IP protocol. */
+	FW_TP_SRC   = 1 << 6 /* TCP/UDP source port. */
+	FW_TP_DST   = 1 << 7 /* TCP/UDP destination port. */
+
+	/* IP source address wildcard bit count. 0 is exact match, 1 ignores the
+	 * LSB, 2 ignores the 2 least-significant bits, ..., 32 and higher wildcard
+	 * the entire field. */
+	FW_NW_SRC_SHIFT = 8
+	FW_NW_SRC_BITS  = 6
+	FW_NW_SRC_MASK  = ((1 << FW_NW_SRC_BITS) - 1) << FW_NW_SRC_SHIFT
+	FW_NW_SRC_ALL   = 32 << FW_NW_SRC_SHIFT
+
+	/* IP destination address wildcard bit count. Same format as source. */
+	FW_NW_DST_SHIFT = 14
+	FW_NW_DST_BITS  = 6
+	FW_NW_DST_MASK  = ((1 << FW_NW_DST_BITS) - 1) << FW_NW_DST_SHIFT
+	FW_NW_DST_ALL   = 32 << FW_NW_DST_SHIFT
+
+	FW_DL_VLAN_PCP = 1 << 20 /* VLAN priority. */
+	FW_NW_TOS      = 1 << 21 /* IP ToS (DSCP field, 6 bits). */
+
+	/* Wildcard all fields. */
+	FW_ALL = (1 << 22) - 1
+)
+
+// NewMatch returns a Match with all the fields wildcarded.
+func NewMatch() *Match {
+	m := new(Match)
+	m.Wildcards = FW_ALL
+	m.DlSrc = make([]byte, ETH_ALEN)
+	m.DlDst = make([]byte, ETH_ALEN)
+	m.NwSrc = net.IPv4zero.To4()
+	m.NwDst = net.IPv4zero.To4()
+	return m
+}
+
+// SetNwSrc matches the IPv4 source address with the prefix length.
+func (m *Match) SetNwSrc(ip net.IP, prefixLen uint8) {
+	m.NwSrc = ip.To4()
+	m.Wildcards = m.Wildcards&^FW_NW_SRC_MASK | nwWildcardBits(prefixLen)<<FW_NW_SRC_SHIFT
+}
+
+// SetNwDst matches the IPv4 destination address with the prefix length.
+func (m *Match) SetNwDst(ip net.IP, prefixLen uint8) {
+	m.NwDst = ip.To4()
+	m.Wildcards = m.Wildcards&^FW_NW_DST_MASK | nwWildcardBits(prefixLen)<<FW_NW_DST_SHIFT
+}
+
+func nwWildcardBits(prefixLen uint8) uint32 {
+	if prefixLen > 32 {
+		prefixLen = 32
+	}
+	return uint32(32 - prefixLen)
+}
+
+func (m *Match) Len() (n uint16) {
+	return MATCH_LEN
+}
+
+func (m *Match) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(m.Len()))
+	n := 0
+	binary.BigEndian.PutUint32(data[n:], m.Wildcards)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], m.InPort)
+	n += 2
+	copy(data[n:n+ETH_ALEN], m.DlSrc)
+	n += ETH_ALEN
+	copy(data[n:n+ETH_ALEN], m.DlDst)
+	n += ETH_ALEN
+	binary.BigEndian.PutUint16(data[n:], m.DlVlan)
+	n += 2
+	data[n] = m.DlVlanPcp
+	n += 1
+	n += 1 // pad1
+	binary.BigEndian.PutUint16(data[n:], m.DlType)
+	n += 2
+	data[n] = m.NwTos
+	n += 1
+	data[n] = m.NwProto
+	n += 1
+	n += 2 // pad2
+	copy(data[n:n+4], m.NwSrc.To4())
+	n += 4
+	copy(data[n:n+4], m.NwDst.To4())
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], m.TpSrc)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], m.TpDst)
+	n += 2
+	return
+}
+
+func (m *Match) UnmarshalBinary(data []byte) error {
+	if len(data) < MATCH_LEN {
+		return errors.New("the []byte is too short to unmarshal a full Match message")
+	}
+	n := 0
+	m.Wildcards = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.InPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.DlSrc = make(net.HardwareAddr, ETH_ALEN)
+	copy(m.DlSrc, data[n:])
+	n += ETH_ALEN
+	m.DlDst = make(net.HardwareAddr, ETH_ALEN)
+	copy(m.DlDst, data[n:])
+	n += ETH_ALEN
+	m.DlVlan = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.DlVlanPcp = data[n]
+	n += 1
+	n += 1 // pad1
+	m.DlType = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.NwTos = data[n]
+	n += 1
+	m.NwProto = data[n]
+	n += 1
+	n += 2 // pad2
+	m.NwSrc = net.IPv4(data[n], data[n+1], data[n+2], data[n+3]).To4()
+	n += 4
+	m.NwDst = net.IPv4(data[n], data[n+1], data[n+2], data[n+3]).To4()
+	n += 4
+	m.TpSrc = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.TpDst = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	return nil
+}
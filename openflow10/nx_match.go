@@ -0,0 +1,141 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/util"
+)
+
+// newNXMOfField returns the NXM_OF_* field with the name, the value and the optional mask. The NXM_NX_* fields are
+// built with the constructors of openflow13, e.g., openflow13.NewRegMatchField and openflow13.NewCTStateMatchField.
+func newNXMOfField(name string, value util.Message, mask util.Message) *MatchField {
+	field, _ := openflow13.FindFieldHeaderByName(name, mask != nil)
+	field.Value = value
+	field.Mask = mask
+	return field
+}
+
+func NewInPortField(port uint16) *MatchField {
+	return newNXMOfField("NXM_OF_IN_PORT", &openflow13.Uint16Message{Data: port}, nil)
+}
+
+func NewEthTypeField(ethType uint16) *MatchField {
+	return newNXMOfField("NXM_OF_ETH_TYPE", &openflow13.EthTypeField{EthType: ethType}, nil)
+}
+
+func NewEthSrcField(addr net.HardwareAddr, mask net.HardwareAddr) *MatchField {
+	var m util.Message
+	if mask != nil {
+		m = &openflow13.EthSrcField{EthSrc: mask}
+	}
+	return newNXMOfField("NXM_OF_ETH_SRC", &openflow13.EthSrcField{EthSrc: addr}, m)
+}
+
+func NewEthDstField(addr net.HardwareAddr, mask net.HardwareAddr) *MatchField {
+	var m util.Message
+	if mask != nil {
+		m = &openflow13.EthDstField{EthDst: mask}
+	}
+	return newNXMOfField("NXM_OF_ETH_DST", &openflow13.EthDstField{EthDst: addr}, m)
+}
+
+func NewIPProtoField(proto uint8) *MatchField {
+	return newNXMOfField("NXM_OF_IP_PROTO", &openflow13.IpProtoField{Protocol: proto}, nil)
+}
+
+func NewIPv4SrcField(ip net.IP, mask net.IPMask) *MatchField {
+	var m util.Message
+	if mask != nil {
+		m = &openflow13.Ipv4SrcField{Ipv4Src: net.IP(mask)}
+	}
+	return newNXMOfField("NXM_OF_IP_SRC", &openflow13.Ipv4SrcField{Ipv4Src: ip}, m)
+}
+
+func NewIPv4DstField(ip net.IP, mask net.IPMask) *MatchField {
+	var m util.Message
+	if mask != nil {
+		m = &openflow13.Ipv4DstField{Ipv4Dst: net.IP(mask)}
+	}
+	return newNXMOfField("NXM_OF_IP_DST", &openflow13.Ipv4DstField{Ipv4Dst: ip}, m)
+}
+
+func NewTCPSrcField(port uint16) *MatchField {
+	return newNXMOfField("NXM_OF_TCP_SRC", openflow13.NewPortField(port), nil)
+}
+
+func NewTCPDstField(port uint16) *MatchField {
+	return newNXMOfField("NXM_OF_TCP_DST", openflow13.NewPortField(port), nil)
+}
+
+func NewUDPSrcField(port uint16) *MatchField {
+	return newNXMOfField("NXM_OF_UDP_SRC", openflow13.NewPortField(port), nil)
+}
+
+func NewUDPDstField(port uint16) *MatchField {
+	return newNXMOfField("NXM_OF_UDP_DST", openflow13.NewPortField(port), nil)
+}
+
+// NXMatch is the nx_match of the Nicira messages, which is a list of the NXM fields. Len doesn't count the padding to 8
+// bytes that the messages add after the match.
+type NXMatch struct {
+	Fields []MatchField
+}
+
+func (m *NXMatch) AddField(f MatchField) {
+	m.Fields = append(m.Fields, f)
+}
+
+func (m *NXMatch) Len() (n uint16) {
+	for _, f := range m.Fields {
+		n += f.Len()
+	}
+	return
+}
+
+func (m *NXMatch) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(m.Len()))
+	n := 0
+	for _, f := range m.Fields {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+// UnmarshalBinary decodes all of data as the fields, data must be exactly the match_len bytes of the message.
+func (m *NXMatch) UnmarshalBinary(data []byte) error {
+	m.Fields = nil
+	n := 0
+	for n < len(data) {
+		if len(data)-n < 4 {
+			return errors.New("the []byte is too short to unmarshal a full NXM field")
+		}
+		// The header is the class in 16 bits, the field in 7 bits, the hasmask bit and the payload length in 8 bits.
+		fieldLen := 4 + int(binary.BigEndian.Uint32(data[n:])&0xff)
+		if len(data)-n < fieldLen {
+			return errors.New("the []byte is too short to unmarshal a full NXM field")
+		}
+		f := new(MatchField)
+		if err := f.UnmarshalBinary(data[n : n+fieldLen]); err != nil {
+			return err
+		}
+		if int(f.Len()) != fieldLen {
+			return errors.New("the payload length of the NXM field doesn't match its value")
+		}
+		m.Fields = append(m.Fields, *f)
+		n += fieldLen
+	}
+	return nil
+}
+
+// padLen8 returns the length padded to a multiple of 8 bytes.
+func padLen8(n uint16) uint16 {
+	return (n + 7) / 8 * 8
+}
@@ -0,0 +1,284 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/protocol"
+	"antrea.io/libOpenflow/util"
+)
+
+// Nicira extension messages of OpenFlow 1.0, the other subtypes are shared with openflow13.
+const (
+	Type_NXFlowMod  = 13
+	Type_NXPacketIn = 17
+)
+
+// nx_packet_in_format
+const (
+	NXPIF_OPENFLOW10 = 0 /* Standard OpenFlow 1.0 compatible. */
+	NXPIF_NXM        = 1 /* Nicira Extended. */
+)
+
+// decodeNXMessage decodes the body of the Nicira message of the subtype, the bodies of the unknown subtypes are kept as
+// a util.Buffer.
+func decodeNXMessage(subtype uint32, data []byte) (util.Message, error) {
+	var msg util.Message
+	switch subtype {
+	case Type_SetFlowFormat, Type_FlowModTableId, Type_SetPacketInFormat:
+		return openflow13.DecodeVendorData(subtype, data)
+	case Type_NXFlowMod:
+		msg = new(NXFlowMod)
+	case Type_NXPacketIn:
+		msg = new(NXPacketIn)
+	default:
+		if len(data) == 0 {
+			return nil, nil
+		}
+		return util.NewBuffer(append([]byte(nil), data...)), nil
+	}
+	if err := msg.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func NewNXTVendorHeader(msgType uint32) *VendorHeader {
+	h := NewOfp10Header()
+	h.Type = Type_Vendor
+	return &VendorHeader{
+		Header:  h,
+		Vendor:  NxVendorID,
+		Subtype: msgType,
+	}
+}
+
+// NewSetFlowFormat returns the NXT_SET_FLOW_FORMAT message with a NXFF_* format.
+func NewSetFlowFormat(format uint32) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetFlowFormat)
+	msg.VendorData = &FlowFormat{
+		Format: format,
+	}
+	return msg
+}
+
+// NewFlowModTableID returns the NXT_FLOW_MOD_TABLE_ID message. When it is enabled, the switch takes the table ID
+// from the high 8 bits of the command of the flow mods sent on the connection.
+func NewFlowModTableID(enable bool) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_FlowModTableId)
+	msg.VendorData = &FlowModTableID{
+		Enable: enable,
+	}
+	return msg
+}
+
+// NewSetPacketInFormat returns the NXT_SET_PACKET_IN_FORMAT message with a NXPIF_* format, NXPIF_NXM makes the
+// switch send NXT_PACKET_IN instead of OFPT_PACKET_IN.
+func NewSetPacketInFormat(format uint32) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetPacketInFormat)
+	msg.VendorData = &PacketInFormat{
+		Spif: format,
+	}
+	return msg
+}
+
+// NXFlowMod is the body of NXT_FLOW_MOD, which is the flow mod with the nx_match instead of ofp_match 1.0. The
+// nx_match is padded to 8 bytes and followed by the actions.
+type NXFlowMod struct {
+	Cookie      uint64
+	Command     uint16
+	IdleTimeout uint16
+	HardTimeout uint16
+	Priority    uint16
+	BufferId    uint32
+	OutPort     uint16
+	Flags       uint16
+	Match       NXMatch
+	Actions     []Action
+}
+
+// NewNXFlowMod returns a NXT_FLOW_MOD message, the body is set in the VendorData of the message.
+func NewNXFlowMod() (*VendorHeader, *NXFlowMod) {
+	f := new(NXFlowMod)
+	f.Command = FC_ADD
+	f.Priority = 0x8000
+	f.BufferId = 0xffffffff
+	f.OutPort = P_NONE
+	f.Actions = make([]Action, 0)
+	msg := NewNXTVendorHeader(Type_NXFlowMod)
+	msg.VendorData = f
+	return msg, f
+}
+
+func (f *NXFlowMod) AddAction(act Action) {
+	f.Actions = append(f.Actions, act)
+}
+
+func (f *NXFlowMod) Len() (n uint16) {
+	n = 32 + padLen8(f.Match.Len())
+	for _, a := range f.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (f *NXFlowMod) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	n := 0
+	binary.BigEndian.PutUint64(data[n:], f.Cookie)
+	n += 8
+	binary.BigEndian.PutUint16(data[n:], f.Command)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.HardTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.Priority)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], f.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], f.OutPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.Flags)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], f.Match.Len())
+	n += 2
+	n += 6 // pad
+
+	b, err := f.Match.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += int(padLen8(f.Match.Len()))
+
+	for _, a := range f.Actions {
+		if b, err = a.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (f *NXFlowMod) UnmarshalBinary(data []byte) error {
+	if len(data) < 32 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowMod message")
+	}
+	n := uint16(0)
+	f.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	f.Command = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	f.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	f.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	matchLen := binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 6 // pad
+
+	if len(data) < int(n+padLen8(matchLen)) {
+		return errors.New("the []byte is too short to unmarshal the match of a NXFlowMod message")
+	}
+	if err := f.Match.UnmarshalBinary(data[n : n+matchLen]); err != nil {
+		return err
+	}
+	n += padLen8(matchLen)
+
+	actions, err := decodeActions(data[n:], uint16(len(data))-n)
+	if err != nil {
+		return err
+	}
+	f.Actions = actions
+	return nil
+}
+
+// NXPacketIn is the body of NXT_PACKET_IN, which is the packet-in with the nx_match of the metadata of the packet. The
+// nx_match is padded to 8 bytes and followed by 2 bytes of padding and the packet.
+type NXPacketIn struct {
+	BufferId uint32
+	TotalLen uint16
+	Reason   uint8
+	TableId  uint8
+	Cookie   uint64
+	Match    NXMatch
+	Data     protocol.Ethernet
+}
+
+func (p *NXPacketIn) Len() (n uint16) {
+	return 24 + padLen8(p.Match.Len()) + 2 + p.Data.Len()
+}
+
+func (p *NXPacketIn) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	n := 0
+	binary.BigEndian.PutUint32(data[n:], p.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], p.TotalLen)
+	n += 2
+	data[n] = p.Reason
+	n += 1
+	data[n] = p.TableId
+	n += 1
+	binary.BigEndian.PutUint64(data[n:], p.Cookie)
+	n += 8
+	binary.BigEndian.PutUint16(data[n:], p.Match.Len())
+	n += 2
+	n += 6 // pad
+
+	b, err := p.Match.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += int(padLen8(p.Match.Len()))
+	n += 2 // pad
+
+	if b, err = p.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	return
+}
+
+func (p *NXPacketIn) UnmarshalBinary(data []byte) error {
+	if len(data) < 24 {
+		return errors.New("the []byte is too short to unmarshal a full NXPacketIn message")
+	}
+	n := uint16(0)
+	p.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.TotalLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.Reason = data[n]
+	n += 1
+	p.TableId = data[n]
+	n += 1
+	p.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	matchLen := binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 6 // pad
+
+	if len(data) < int(n+padLen8(matchLen)+2) {
+		return errors.New("the []byte is too short to unmarshal the match of a NXPacketIn message")
+	}
+	if err := p.Match.UnmarshalBinary(data[n : n+matchLen]); err != nil {
+		return err
+	}
+	n += padLen8(matchLen)
+	n += 2 // pad
+
+	return p.Data.UnmarshalBinary(data[n:])
+}
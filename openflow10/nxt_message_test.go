@@ -0,0 +1,102 @@
+package openflow10
+
+import (
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/openflow13"
+)
+
+func TestNXFlowMod(t *testing.T) {
+	msg, flowMod := NewNXFlowMod()
+	flowMod.Cookie = 0x1234
+	flowMod.Match.AddField(*NewInPortField(1))
+	flowMod.Match.AddField(*NewEthTypeField(0x0800))
+	flowMod.Match.AddField(*NewIPv4DstField(net.ParseIP("10.0.0.0"), net.CIDRMask(24, 32)))
+	flowMod.Match.AddField(*openflow13.NewRegMatchFieldWithMask(1, 0x10, 0xff))
+	flowMod.AddAction(NewActionOutput(2))
+	flowMod.AddAction(openflow13.NewNXActionResubmitTableAction(P_IN_PORT, 3))
+	// The match of 36 bytes is padded to 40 bytes.
+	assert.Equal(t, uint16(16+32+40+8+16), msg.Len())
+
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "0024", hex.EncodeToString(data[40:42]))
+	assert.Equal(t, "000000020001"+"000006020800"+"000011080a000000ffffff00"+"0001030800000010000000ff"+"00000000",
+		hex.EncodeToString(data[48:88]))
+
+	parsed := assertParse(t, msg).(*VendorHeader)
+	assert.Equal(t, uint32(Type_NXFlowMod), parsed.Subtype)
+	parsedMod := parsed.VendorData.(*NXFlowMod)
+	assert.Equal(t, uint64(0x1234), parsedMod.Cookie)
+	require.Len(t, parsedMod.Match.Fields, 4)
+	inPort := parsedMod.Match.Fields[0]
+	assert.Equal(t, uint16(openflow13.OXM_CLASS_NXM_0), inPort.Class)
+	assert.Equal(t, &openflow13.Uint16Message{Data: 1}, inPort.Value)
+	ipDst := parsedMod.Match.Fields[2]
+	assert.Equal(t, openflow13.NXM_OF_IP_DST, ipDst.Field)
+	assert.True(t, ipDst.HasMask)
+	assert.True(t, net.IP(net.CIDRMask(24, 32)).Equal(ipDst.Mask.(*openflow13.Ipv4DstField).Ipv4Dst))
+	reg := parsedMod.Match.Fields[3]
+	assert.Equal(t, uint16(openflow13.OXM_CLASS_NXM_1), reg.Class)
+	assert.Equal(t, uint8(openflow13.NXM_NX_REG1), reg.Field)
+	assert.Equal(t, &openflow13.Uint32Message{Data: 0x10}, reg.Value)
+	require.Len(t, parsedMod.Actions, 2)
+	assert.Equal(t, uint16(2), parsedMod.Actions[0].(*ActionOutput).Port)
+	resubmit, ok := parsedMod.Actions[1].(*openflow13.NXActionResubmitTable)
+	require.True(t, ok, "Failed to cast NXActionResubmitTable from result")
+	assert.Equal(t, uint8(3), resubmit.TableID)
+}
+
+func TestNXPacketIn(t *testing.T) {
+	packetIn := &NXPacketIn{
+		BufferId: 0xffffffff,
+		Reason:   R_ACTION,
+		TableId:  3,
+		Cookie:   0x1234,
+		Data:     *newTestEthernet(),
+	}
+	packetIn.TotalLen = packetIn.Data.Len()
+	packetIn.Match.AddField(*NewInPortField(1))
+	packetIn.Match.AddField(*openflow13.NewCTZoneMatchField(5))
+	msg := NewNXTVendorHeader(Type_NXPacketIn)
+	msg.VendorData = packetIn
+
+	parsed := assertParse(t, msg).(*VendorHeader)
+	parsedIn := parsed.VendorData.(*NXPacketIn)
+	assert.Equal(t, uint8(3), parsedIn.TableId)
+	require.Len(t, parsedIn.Match.Fields, 2)
+	assert.Equal(t, uint8(openflow13.NXM_NX_CT_ZONE), parsedIn.Match.Fields[1].Field)
+	assert.Equal(t, &openflow13.Uint16Message{Data: 5}, parsedIn.Match.Fields[1].Value)
+	assert.Equal(t, uint16(0x88b5), parsedIn.Data.Ethertype)
+}
+
+func TestNXMatchUnmarshal(t *testing.T) {
+	// NXM_NX_TUN_ID=100 and NXM_OF_ARP_SPA=10.0.0.1
+	data, err := hex.DecodeString("000120080000000000000064" + "000020040a000001")
+	require.NoError(t, err)
+	var match NXMatch
+	require.NoError(t, match.UnmarshalBinary(data))
+	require.Len(t, match.Fields, 2)
+	assert.Equal(t, &openflow13.Uint64Message{Data: 100}, match.Fields[0].Value)
+	assert.True(t, net.ParseIP("10.0.0.1").Equal(match.Fields[1].Value.(*openflow13.ArpXPaField).ArpPa))
+	newData, err := match.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+
+	// The payload length of NXM_OF_IN_PORT is beyond the match.
+	assert.Error(t, match.UnmarshalBinary([]byte{0, 0, 0, 4, 0, 1}))
+}
+
+func TestNXFormatMessages(t *testing.T) {
+	flowFormat := assertParse(t, NewSetFlowFormat(NXFF_NXM)).(*VendorHeader)
+	assert.Equal(t, uint32(NXFF_NXM), flowFormat.VendorData.(*FlowFormat).Format)
+	packetInFormat := assertParse(t, NewSetPacketInFormat(NXPIF_NXM)).(*VendorHeader)
+	assert.Equal(t, uint32(NXPIF_NXM), packetInFormat.VendorData.(*PacketInFormat).Spif)
+	tableID := assertParse(t, NewFlowModTableID(true)).(*VendorHeader)
+	assert.True(t, tableID.VendorData.(*FlowModTableID).Enable)
+}
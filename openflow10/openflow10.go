@@ -0,0 +1,732 @@
+package openflow10
+
+// Package openflow10 provides OpenFlow 1.0 structs along with Read
+// and Write methods for each.
+// OpenFlow Wire Protocol 0x01
+//
+// Struct documentation is taken from the OpenFlow Switch
+// Specification Version 1.0.0.
+// https://opennetworking.org/wp-content/uploads/2013/04/openflow-spec-v1.0.0.pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"antrea.io/libOpenflow/common"
+	"antrea.io/libOpenflow/protocol"
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	VERSION = 1
+)
+
+// Returns a new OpenFlow header with version field set to v1.0.
+var NewOfp10Header func() common.Header = common.NewHeaderGenerator(VERSION)
+
+// Echo request/reply messages can be sent from either the
+// switch or the controller, and must return an echo reply. They
+// can be used to indicate the latency, bandwidth, and/or
+// liveness of a controller-switch connection.
+func NewEchoRequest() *common.Header {
+	h := NewOfp10Header()
+	h.Type = Type_EchoRequest
+	return &h
+}
+
+// Echo request/reply messages can be sent from either the
+// switch or the controller, and must return an echo reply. They
+// can be used to indicate the latency, bandwidth, and/or
+// liveness of a controller-switch connection.
+func NewEchoReply() *common.Header {
+	h := NewOfp10Header()
+	h.Type = Type_EchoReply
+	return &h
+}
+
+// ofp_type 1.0
+const (
+	/* Immutable messages. */
+	Type_Hello       = 0
+	Type_Error       = 1
+	Type_EchoRequest = 2
+	Type_EchoReply   = 3
+	Type_Vendor      = 4
+
+	/* Switch configuration messages. */
+	Type_FeaturesRequest  = 5
+	Type_FeaturesReply    = 6
+	Type_GetConfigRequest = 7
+	Type_GetConfigReply   = 8
+	Type_SetConfig        = 9
+
+	/* Asynchronous messages. */
+	Type_PacketIn    = 10
+	Type_FlowRemoved = 11
+	Type_PortStatus  = 12
+
+	/* Controller command messages. */
+	Type_PacketOut = 13
+	Type_FlowMod   = 14
+	Type_PortMod   = 15
+
+	/* Statistics messages. */
+	Type_StatsRequest = 16
+	Type_StatsReply   = 17
+
+	/* Barrier messages. */
+	Type_BarrierRequest = 18
+	Type_BarrierReply   = 19
+
+	/* Queue Configuration messages. */
+	Type_QueueGetConfigRequest = 20
+	Type_QueueGetConfigReply   = 21
+)
+
+func Parse(b []byte) (message util.Message, err error) {
+	if len(b) < 8 {
+		return nil, errors.New("the []byte is too short to unmarshal a full OpenFlow 1.0 message")
+	}
+	switch b[1] {
+	case Type_Hello:
+		message = new(common.Hello)
+	case Type_Error:
+		message = NewErrorMsg()
+	case Type_EchoRequest, Type_EchoReply:
+		message = new(common.Header)
+	case Type_Vendor:
+		message = new(VendorHeader)
+	case Type_FeaturesRequest:
+		message = new(common.Header)
+	case Type_FeaturesReply:
+		message = NewFeaturesReply()
+	case Type_GetConfigRequest:
+		message = new(common.Header)
+	case Type_GetConfigReply, Type_SetConfig:
+		message = new(SwitchConfig)
+	case Type_PacketIn:
+		message = new(PacketIn)
+	case Type_FlowRemoved:
+		message = NewFlowRemoved()
+	case Type_PortStatus:
+		message = NewPortStatus()
+	case Type_PacketOut:
+		message = NewPacketOut()
+	case Type_FlowMod:
+		message = NewFlowMod()
+	case Type_PortMod:
+		message = NewPortMod(0)
+	case Type_StatsRequest:
+		message = new(StatsRequest)
+	case Type_StatsReply:
+		message = new(StatsReply)
+	case Type_BarrierRequest, Type_BarrierReply:
+		message = new(common.Header)
+	default:
+		return nil, fmt.Errorf("an unknown v1.0 packet type %d was received", b[1])
+	}
+	err = message.UnmarshalBinary(b)
+	return
+}
+
+// When the controller wishes to send a packet out through the
+// datapath, it uses the OFPT_PACKET_OUT message: The buffer_id
+// is the same given in the ofp_packet_in message. If the
+// buffer_id is -1, then the packet data is included in the data
+// array.
+type PacketOut struct {
+	common.Header
+	BufferId   uint32
+	InPort     uint16
+	ActionsLen uint16
+	Actions    []Action
+	Data       util.Message
+}
+
+func NewPacketOut() *PacketOut {
+	p := new(PacketOut)
+	p.Header = NewOfp10Header()
+	p.Header.Type = Type_PacketOut
+	p.BufferId = 0xffffffff
+	p.InPort = P_NONE
+	p.ActionsLen = 0
+	p.Actions = make([]Action, 0)
+	return p
+}
+
+func (p *PacketOut) AddAction(act Action) {
+	p.Actions = append(p.Actions, act)
+	p.ActionsLen += act.Len()
+}
+
+func (p *PacketOut) Len() (n uint16) {
+	n += p.Header.Len()
+	n += 8
+	for _, a := range p.Actions {
+		n += a.Len()
+	}
+	if p.Data != nil {
+		n += p.Data.Len()
+	}
+	return
+}
+
+func (p *PacketOut) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	var b []byte
+	n := 0
+
+	p.Header.Length = p.Len()
+	if b, err = p.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	binary.BigEndian.PutUint32(data[n:], p.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], p.InPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], p.ActionsLen)
+	n += 2
+
+	for _, a := range p.Actions {
+		if b, err = a.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+
+	if p.Data != nil {
+		if b, err = p.Data.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (p *PacketOut) UnmarshalBinary(data []byte) error {
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(p.Header.Length) || p.Header.Length < 16 {
+		return errors.New("the []byte is too short to unmarshal a full PacketOut message")
+	}
+	n := p.Header.Len()
+
+	p.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.InPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.ActionsLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	actions, err := decodeActions(data[n:p.Header.Length], p.ActionsLen)
+	if err != nil {
+		return err
+	}
+	p.Actions = actions
+	n += p.ActionsLen
+
+	if n < p.Header.Length {
+		p.Data = new(util.Buffer)
+		return p.Data.UnmarshalBinary(data[n:p.Header.Length])
+	}
+	return nil
+}
+
+// ofp_packet_in 1.0
+type PacketIn struct {
+	common.Header
+	BufferId uint32
+	TotalLen uint16
+	InPort   uint16
+	Reason   uint8
+	pad      uint8
+	Data     protocol.Ethernet
+}
+
+func NewPacketIn() *PacketIn {
+	p := new(PacketIn)
+	p.Header = NewOfp10Header()
+	p.Header.Type = Type_PacketIn
+	p.BufferId = 0xffffffff
+	return p
+}
+
+func (p *PacketIn) Len() (n uint16) {
+	n += p.Header.Len()
+	n += 10
+	n += p.Data.Len()
+	return
+}
+
+func (p *PacketIn) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	var b []byte
+	n := 0
+
+	p.Header.Length = p.Len()
+	if b, err = p.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	binary.BigEndian.PutUint32(data[n:], p.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], p.TotalLen)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], p.InPort)
+	n += 2
+	data[n] = p.Reason
+	n += 1
+	n += 1 // pad
+
+	if b, err = p.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	return
+}
+
+func (p *PacketIn) UnmarshalBinary(data []byte) error {
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(p.Header.Length) || p.Header.Length < 18 {
+		return errors.New("the []byte is too short to unmarshal a full PacketIn message")
+	}
+	n := p.Header.Len()
+
+	p.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.TotalLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.InPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.Reason = data[n]
+	n += 1
+	p.pad = data[n]
+	n += 1
+
+	return p.Data.UnmarshalBinary(data[n:p.Header.Length])
+}
+
+// ofp_packet_in_reason 1.0
+const (
+	R_NO_MATCH = iota /* No matching flow. */
+	R_ACTION          /* Action explicitly output to controller. */
+)
+
+func NewConfigRequest() *common.Header {
+	h := NewOfp10Header()
+	h.Type = Type_GetConfigRequest
+	return &h
+}
+
+// ofp_config_flags 1.0
+const (
+	C_FRAG_NORMAL = 0
+	C_FRAG_DROP   = 1
+	C_FRAG_REASM  = 2
+	C_FRAG_MASK   = 3
+)
+
+// ofp_switch_config 1.0
+type SwitchConfig struct {
+	common.Header
+	Flags       uint16 // OFPC_* flags
+	MissSendLen uint16
+}
+
+func NewSetConfig() *SwitchConfig {
+	c := new(SwitchConfig)
+	c.Header = NewOfp10Header()
+	c.Header.Type = Type_SetConfig
+	c.Flags = 0
+	c.MissSendLen = 0
+	return c
+}
+
+func (c *SwitchConfig) Len() (n uint16) {
+	n = c.Header.Len()
+	n += 4
+	return
+}
+
+func (c *SwitchConfig) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(c.Len()))
+	var bytes []byte
+	next := 0
+
+	c.Header.Length = c.Len()
+	if bytes, err = c.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	binary.BigEndian.PutUint16(data[next:], c.Flags)
+	next += 2
+	binary.BigEndian.PutUint16(data[next:], c.MissSendLen)
+	next += 2
+	return
+}
+
+func (c *SwitchConfig) UnmarshalBinary(data []byte) error {
+	if len(data) < int(c.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full SwitchConfig message")
+	}
+	next := 0
+	err := c.Header.UnmarshalBinary(data[next:])
+	next += int(c.Header.Len())
+	c.Flags = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	c.MissSendLen = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	return err
+}
+
+// BEGIN: ofp10 - 5.4.4
+// ofp_error_msg 1.0
+type ErrorMsg struct {
+	common.Header
+	Type uint16
+	Code uint16
+	Data util.Buffer
+}
+
+func NewErrorMsg() *ErrorMsg {
+	e := new(ErrorMsg)
+	e.Header = NewOfp10Header()
+	e.Header.Type = Type_Error
+	e.Data = *util.NewBuffer(make([]byte, 0))
+	return e
+}
+
+func (e *ErrorMsg) Len() (n uint16) {
+	n = e.Header.Len()
+	n += 2
+	n += 2
+	n += e.Data.Len()
+	return
+}
+
+func (e *ErrorMsg) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(e.Len()))
+	var bytes []byte
+	next := 0
+
+	e.Header.Length = e.Len()
+	if bytes, err = e.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	binary.BigEndian.PutUint16(data[next:], e.Type)
+	next += 2
+	binary.BigEndian.PutUint16(data[next:], e.Code)
+	next += 2
+	if bytes, err = e.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	return
+}
+
+func (e *ErrorMsg) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return errors.New("the []byte is too short to unmarshal a full ErrorMsg message")
+	}
+	next := 0
+	err := e.Header.UnmarshalBinary(data[next:])
+	next += int(e.Header.Len())
+	e.Type = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	e.Code = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	if int(e.Header.Length) <= len(data) && next < int(e.Header.Length) {
+		e.Data.UnmarshalBinary(data[next:e.Header.Length])
+	}
+	return err
+}
+
+// ofp_error_type 1.0
+const (
+	ET_HELLO_FAILED    = 0 /* Hello protocol failed. */
+	ET_BAD_REQUEST     = 1 /* Request was not understood. */
+	ET_BAD_ACTION      = 2 /* Error in action description. */
+	ET_FLOW_MOD_FAILED = 3 /* Problem modifying flow entry. */
+	ET_PORT_MOD_FAILED = 4 /* Port mod request failed. */
+	ET_QUEUE_OP_FAILED = 5 /* Queue operation failed. */
+)
+
+// ofp_hello_failed_code 1.0
+const (
+	HFC_INCOMPATIBLE = iota
+	HFC_EPERM
+)
+
+// ofp_bad_request_code 1.0
+const (
+	BRC_BAD_VERSION = iota
+	BRC_BAD_TYPE
+	BRC_BAD_STAT
+	BRC_BAD_VENDOR
+	BRC_BAD_SUBTYPE
+	BRC_EPERM
+	BRC_BAD_LEN
+	BRC_BUFFER_EMPTY
+	BRC_BUFFER_UNKNOWN
+)
+
+// ofp_bad_action_code 1.0
+const (
+	BAC_BAD_TYPE = iota
+	BAC_BAD_LEN
+	BAC_BAD_VENDOR
+	BAC_BAD_VENDOR_TYPE
+	BAC_BAD_OUT_PORT
+	BAC_BAD_ARGUMENT
+	BAC_EPERM
+	BAC_TOO_MANY
+	BAC_BAD_QUEUE
+)
+
+// ofp_flow_mod_failed_code 1.0
+const (
+	FMFC_ALL_TABLES_FULL   = 0 /* Flow not added because of full tables. */
+	FMFC_OVERLAP           = 1 /* Attempted to add overlapping flow with CHECK_OVERLAP flag set. */
+	FMFC_EPERM             = 2 /* Permissions error. */
+	FMFC_BAD_EMERG_TIMEOUT = 3 /* Flow not added because of non-zero idle/hard timeout. */
+	FMFC_BAD_COMMAND       = 4 /* Unknown command. */
+	FMFC_UNSUPPORTED       = 5 /* Unsupported action list - cannot process in the order specified. */
+)
+
+// ofp_port_mod_failed_code 1.0
+const (
+	PMFC_BAD_PORT = iota
+	PMFC_BAD_HW_ADDR
+)
+
+// ofp_queue_op_failed_code 1.0
+const (
+	QOFC_BAD_PORT = iota
+	QOFC_BAD_QUEUE
+	QOFC_EPERM
+)
+
+// END: ofp10 - 5.4.4
+
+// ofp_switch_features 1.0
+type SwitchFeatures struct {
+	common.Header
+	DPID         net.HardwareAddr // Size 8
+	Buffers      uint32
+	NumTables    uint8
+	pad          []uint8 // Size 3
+	Capabilities uint32
+	Actions      uint32
+
+	Ports []PhyPort
+}
+
+// FeaturesRequest constructor
+func NewFeaturesRequest() *common.Header {
+	req := NewOfp10Header()
+	req.Type = Type_FeaturesRequest
+	return &req
+}
+
+// FeaturesReply constructor
+func NewFeaturesReply() *SwitchFeatures {
+	res := new(SwitchFeatures)
+	res.Header = NewOfp10Header()
+	res.Header.Type = Type_FeaturesReply
+	res.DPID = make([]byte, 8)
+	res.pad = make([]byte, 3)
+	res.Ports = make([]PhyPort, 0)
+	return res
+}
+
+func (s *SwitchFeatures) Len() (n uint16) {
+	n = s.Header.Len()
+	n += uint16(len(s.DPID))
+	n += 16
+	for _, p := range s.Ports {
+		n += p.Len()
+	}
+	return
+}
+
+func (s *SwitchFeatures) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	var bytes []byte
+	next := 0
+
+	s.Header.Length = s.Len()
+	if bytes, err = s.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	copy(data[next:], s.DPID)
+	next += len(s.DPID)
+	binary.BigEndian.PutUint32(data[next:], s.Buffers)
+	next += 4
+	data[next] = s.NumTables
+	next += 1
+	next += 3 // pad
+	binary.BigEndian.PutUint32(data[next:], s.Capabilities)
+	next += 4
+	binary.BigEndian.PutUint32(data[next:], s.Actions)
+	next += 4
+
+	for _, p := range s.Ports {
+		if bytes, err = p.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[next:], bytes)
+		next += len(bytes)
+	}
+	return
+}
+
+func (s *SwitchFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < 32 {
+		return errors.New("the []byte is too short to unmarshal a full SwitchFeatures message")
+	}
+	next := 0
+	err := s.Header.UnmarshalBinary(data[next:])
+	if err != nil {
+		return err
+	}
+	next = int(s.Header.Len())
+	s.DPID = make([]byte, 8)
+	copy(s.DPID, data[next:])
+	next += len(s.DPID)
+	s.Buffers = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	s.NumTables = data[next]
+	next += 1
+	next += 3 // pad
+	s.Capabilities = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	s.Actions = binary.BigEndian.Uint32(data[next:])
+	next += 4
+
+	s.Ports = make([]PhyPort, 0)
+	for next+PHY_PORT_LEN <= int(s.Header.Length) && next+PHY_PORT_LEN <= len(data) {
+		p := NewPhyPort()
+		if err = p.UnmarshalBinary(data[next:]); err != nil {
+			return err
+		}
+		s.Ports = append(s.Ports, *p)
+		next += int(p.Len())
+	}
+	return nil
+}
+
+// ofp_capabilities 1.0
+const (
+	C_FLOW_STATS   = 1 << 0
+	C_TABLE_STATS  = 1 << 1
+	C_PORT_STATS   = 1 << 2
+	C_STP          = 1 << 3
+	C_RESERVED     = 1 << 4
+	C_IP_REASM     = 1 << 5
+	C_QUEUE_STATS  = 1 << 6
+	C_ARP_MATCH_IP = 1 << 7
+)
+
+// ofp_vendor_header 1.0
+// The Nicira vendor messages carry the subtype of nicira_header after the vendor, the VendorData of the known Nicira
+// subtypes is decoded, and the VendorData of other vendors is kept as a util.Buffer.
+type VendorHeader struct {
+	Header     common.Header /*Type OFPT_VENDOR*/
+	Vendor     uint32
+	Subtype    uint32 // Only used by the Nicira vendor messages.
+	VendorData util.Message
+}
+
+func (v *VendorHeader) Len() (n uint16) {
+	n = 12
+	if v.Vendor == NxVendorID {
+		n += 4
+	}
+	if v.VendorData != nil {
+		n += v.VendorData.Len()
+	}
+	return
+}
+
+func (v *VendorHeader) MarshalBinary() (data []byte, err error) {
+	v.Header.Length = v.Len()
+	data = make([]byte, v.Len())
+	b, err := v.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint32(data[n:], v.Vendor)
+	n += 4
+	if v.Vendor == NxVendorID {
+		binary.BigEndian.PutUint32(data[n:], v.Subtype)
+		n += 4
+	}
+	if v.VendorData != nil {
+		vd, err := v.VendorData.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], vd)
+	}
+	return
+}
+
+func (v *VendorHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return errors.New("the []byte is too short to unmarshal a full VendorHeader message")
+	}
+	if err := v.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(v.Header.Length) {
+		return errors.New("the []byte is too short to unmarshal a full VendorHeader message")
+	}
+	n := int(v.Header.Len())
+	v.Vendor = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	if v.Vendor != NxVendorID {
+		if n < int(v.Header.Length) {
+			v.VendorData = util.NewBuffer(append([]byte(nil), data[n:v.Header.Length]...))
+		}
+		return nil
+	}
+	if int(v.Header.Length) < n+4 {
+		return errors.New("the []byte is too short to unmarshal a full Nicira vendor message")
+	}
+	v.Subtype = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	var err error
+	v.VendorData, err = decodeNXMessage(v.Subtype, data[n:v.Header.Length])
+	return err
+}
+
+func NewBarrierRequest() *common.Header {
+	h := NewOfp10Header()
+	h.Type = Type_BarrierRequest
+	return &h
+}
+
+func NewBarrierReply() *common.Header {
+	h := NewOfp10Header()
+	h.Type = Type_BarrierReply
+	return &h
+}
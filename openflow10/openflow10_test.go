@@ -0,0 +1,166 @@
+package openflow10
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/protocol"
+	"antrea.io/libOpenflow/util"
+)
+
+// assertParse encodes msg, decodes the data with Parse, and checks the decoded message is encoded the same as msg.
+func assertParse(t *testing.T, msg util.Message) util.Message {
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, uint8(VERSION), data[0])
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.IsType(t, msg, parsed)
+	newData, err := parsed.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+	return parsed
+}
+
+func newTestEthernet() *protocol.Ethernet {
+	eth := protocol.NewEthernet()
+	eth.HWDst, _ = net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	eth.HWSrc, _ = net.ParseMAC("00:11:22:33:44:55")
+	eth.Ethertype = 0x88b5
+	eth.Data = util.NewBuffer([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	return eth
+}
+
+func TestFeaturesReply(t *testing.T) {
+	reply := NewFeaturesReply()
+	reply.DPID = net.HardwareAddr{0, 0, 0, 0, 0, 0, 0, 1}
+	reply.NumTables = 2
+	reply.Capabilities = C_FLOW_STATS | C_ARP_MATCH_IP
+	for i := uint16(1); i <= 2; i++ {
+		port := NewPhyPort()
+		port.PortNo = i
+		copy(port.Name, "eth")
+		reply.Ports = append(reply.Ports, *port)
+	}
+	parsed := assertParse(t, reply).(*SwitchFeatures)
+	require.Len(t, parsed.Ports, 2)
+	assert.Equal(t, uint16(2), parsed.Ports[1].PortNo)
+}
+
+func TestFlowMessages(t *testing.T) {
+	flowMod := NewFlowMod()
+	flowMod.Cookie = 0x1234
+	flowMod.Flags = FF_SEND_FLOW_REM
+	flowMod.Match.Wildcards &^= FW_IN_PORT | FW_DL_TYPE
+	flowMod.Match.InPort = 1
+	flowMod.Match.DlType = 0x0800
+	flowMod.Match.SetNwDst(net.ParseIP("10.0.0.0"), 24)
+	flowMod.AddAction(NewActionNwDst(net.ParseIP("10.0.0.2")))
+	flowMod.AddAction(NewActionVlanVid(100))
+	flowMod.AddAction(NewActionOutput(2))
+	parsedMod := assertParse(t, flowMod).(*FlowMod)
+	assert.Equal(t, uint16(1), parsedMod.Match.InPort)
+	assert.Equal(t, uint32(8), (parsedMod.Match.Wildcards&FW_NW_DST_MASK)>>FW_NW_DST_SHIFT)
+	assert.Equal(t, net.ParseIP("10.0.0.0").To4(), parsedMod.Match.NwDst)
+	require.Len(t, parsedMod.Actions, 3)
+	assert.Equal(t, uint16(2), parsedMod.Actions[2].(*ActionOutput).Port)
+
+	flowRemoved := NewFlowRemoved()
+	flowRemoved.Cookie = 0x1234
+	flowRemoved.Reason = RR_IDLE_TIMEOUT
+	flowRemoved.PacketCount = 10
+	parsedRemoved := assertParse(t, flowRemoved).(*FlowRemoved)
+	assert.Equal(t, uint64(10), parsedRemoved.PacketCount)
+}
+
+func TestPacketMessages(t *testing.T) {
+	packetIn := NewPacketIn()
+	packetIn.InPort = 3
+	packetIn.Reason = R_ACTION
+	packetIn.Data = *newTestEthernet()
+	packetIn.TotalLen = packetIn.Data.Len()
+	parsedIn := assertParse(t, packetIn).(*PacketIn)
+	assert.Equal(t, uint16(3), parsedIn.InPort)
+	assert.Equal(t, uint16(0x88b5), parsedIn.Data.Ethertype)
+
+	packetOut := NewPacketOut()
+	packetOut.InPort = P_CONTROLLER
+	packetOut.AddAction(NewActionOutput(P_FLOOD))
+	packetOut.Data = newTestEthernet()
+	parsedOut := assertParse(t, packetOut).(*PacketOut)
+	require.Len(t, parsedOut.Actions, 1)
+	assert.Equal(t, uint16(P_FLOOD), parsedOut.Actions[0].(*ActionOutput).Port)
+}
+
+func TestPortMessages(t *testing.T) {
+	portStatus := NewPortStatus()
+	portStatus.Reason = PR_MODIFY
+	portStatus.Desc.PortNo = 3
+	portStatus.Desc.State = PS_LINK_DOWN
+	parsedStatus := assertParse(t, portStatus).(*PortStatus)
+	assert.Equal(t, uint16(3), parsedStatus.Desc.PortNo)
+
+	portMod := NewPortMod(3)
+	portMod.Config = PC_NO_FLOOD
+	portMod.Mask = PC_NO_FLOOD
+	parsedMod := assertParse(t, portMod).(*PortMod)
+	assert.Equal(t, uint32(PC_NO_FLOOD), parsedMod.Config)
+}
+
+func TestErrorMsg(t *testing.T) {
+	errMsg := NewErrorMsg()
+	errMsg.Type = ET_FLOW_MOD_FAILED
+	errMsg.Code = FMFC_OVERLAP
+	errMsg.Data = *util.NewBuffer([]byte{1, 2, 3, 4})
+	parsed := assertParse(t, errMsg).(*ErrorMsg)
+	assert.Equal(t, uint16(FMFC_OVERLAP), parsed.Code)
+}
+
+func TestStatsMessages(t *testing.T) {
+	flowRequest := NewFlowStatsRequest()
+	flowRequest.OutPort = 2
+	parsedRequest := assertParse(t, NewStatsRequest(StatsType_Flow, flowRequest)).(*StatsRequest)
+	assert.Equal(t, uint16(2), parsedRequest.Body.(*FlowStatsRequest).OutPort)
+	assertParse(t, NewStatsRequest(StatsType_Desc, nil))
+	assertParse(t, NewStatsRequest(StatsType_Aggregate, NewAggregateStatsRequest()))
+	assertParse(t, NewStatsRequest(StatsType_Port, NewPortStatsRequest(P_NONE)))
+	assertParse(t, NewStatsRequest(StatsType_Queue, NewQueueStatsRequest(P_ALL, Q_ALL)))
+	assertParse(t, NewStatsRequest(StatsType_Vendor, &VendorStats{Vendor: NxVendorID, Data: []byte{0, 0, 0, 0}}))
+
+	desc := NewDescStats()
+	copy(desc.MfrDesc, "vendor")
+	descReply := NewStatsReply(StatsType_Desc)
+	descReply.Body = append(descReply.Body, desc)
+	parsedDesc := assertParse(t, descReply).(*StatsReply)
+	require.Len(t, parsedDesc.Body, 1)
+	assert.Equal(t, desc.MfrDesc, parsedDesc.Body[0].(*DescStats).MfrDesc)
+
+	flowReply := NewStatsReply(StatsType_Flow)
+	flowReply.Flags = SF_REPLY_MORE
+	for i := 0; i < 2; i++ {
+		flow := NewFlowStats()
+		flow.Priority = uint16(i)
+		flow.AddAction(NewActionOutput(uint16(i)))
+		flowReply.Body = append(flowReply.Body, flow)
+	}
+	parsedFlow := assertParse(t, flowReply).(*StatsReply)
+	require.Len(t, parsedFlow.Body, 2)
+	assert.Equal(t, uint16(1), parsedFlow.Body[1].(*FlowStats).Priority)
+	require.Len(t, parsedFlow.Body[1].(*FlowStats).Actions, 1)
+
+	for statsType, body := range map[uint16]util.Message{
+		StatsType_Aggregate: &AggregateStats{PacketCount: 1, ByteCount: 2, FlowCount: 3},
+		StatsType_Table:     NewTableStats(),
+		StatsType_Port:      &PortStats{PortNo: 1, Collisions: 5},
+		StatsType_Queue:     &QueueStats{PortNo: 1, QueueId: 2, TxErrors: 3},
+	} {
+		reply := NewStatsReply(statsType)
+		reply.Body = append(reply.Body, body)
+		parsed := assertParse(t, reply).(*StatsReply)
+		require.Len(t, parsed.Body, 1)
+		assert.Equal(t, body, parsed.Body[0])
+	}
+}
@@ -0,0 +1,281 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+
+	"antrea.io/libOpenflow/common"
+)
+
+// PHY_PORT_LEN is the length of ofp_phy_port 1.0.
+const PHY_PORT_LEN = 48
+
+// ofp_phy_port 1.0
+type PhyPort struct {
+	PortNo uint16
+	HWAddr net.HardwareAddr
+	Name   []byte // Size 16
+
+	Config uint32
+	State  uint32
+
+	Curr       uint32
+	Advertised uint32
+	Supported  uint32
+	Peer       uint32
+}
+
+func NewPhyPort() *PhyPort {
+	p := new(PhyPort)
+	p.HWAddr = make([]byte, ETH_ALEN)
+	p.Name = make([]byte, MAX_PORT_NAME_LEN)
+	return p
+}
+
+func (p *PhyPort) Len() (n uint16) {
+	return PHY_PORT_LEN
+}
+
+func (p *PhyPort) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	binary.BigEndian.PutUint16(data, p.PortNo)
+	n := 2
+	copy(data[n:n+ETH_ALEN], p.HWAddr)
+	n += ETH_ALEN
+	copy(data[n:n+MAX_PORT_NAME_LEN], p.Name)
+	n += MAX_PORT_NAME_LEN
+
+	binary.BigEndian.PutUint32(data[n:], p.Config)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.State)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Curr)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Advertised)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Supported)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Peer)
+	n += 4
+	return
+}
+
+func (p *PhyPort) UnmarshalBinary(data []byte) error {
+	if len(data) < int(p.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PhyPort message")
+	}
+	p.PortNo = binary.BigEndian.Uint16(data)
+	n := 2
+	p.HWAddr = make(net.HardwareAddr, ETH_ALEN)
+	copy(p.HWAddr, data[n:])
+	n += ETH_ALEN
+	p.Name = make([]byte, MAX_PORT_NAME_LEN)
+	copy(p.Name, data[n:])
+	n += MAX_PORT_NAME_LEN
+
+	p.Config = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.State = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Curr = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Advertised = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Supported = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Peer = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	return nil
+}
+
+// ofp_port_mod 1.0
+type PortMod struct {
+	common.Header
+	PortNo uint16
+	HWAddr net.HardwareAddr
+
+	Config    uint32
+	Mask      uint32
+	Advertise uint32
+}
+
+func NewPortMod(port uint16) *PortMod {
+	p := new(PortMod)
+	p.Header = NewOfp10Header()
+	p.Header.Type = Type_PortMod
+	p.PortNo = port
+	p.HWAddr = make([]byte, ETH_ALEN)
+	return p
+}
+
+func (p *PortMod) Len() (n uint16) {
+	return p.Header.Len() + 2 + ETH_ALEN + 12 + 4
+}
+
+func (p *PortMod) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	p.Header.Length = p.Len()
+	b, err := p.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := int(p.Header.Len())
+	binary.BigEndian.PutUint16(data[n:], p.PortNo)
+	n += 2
+	copy(data[n:n+ETH_ALEN], p.HWAddr)
+	n += ETH_ALEN
+	binary.BigEndian.PutUint32(data[n:], p.Config)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Mask)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.Advertise)
+	n += 4
+	n += 4 // pad
+	return
+}
+
+func (p *PortMod) UnmarshalBinary(data []byte) error {
+	if len(data) < int(p.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PortMod message")
+	}
+	err := p.Header.UnmarshalBinary(data)
+	n := int(p.Header.Len())
+
+	p.PortNo = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.HWAddr = make(net.HardwareAddr, ETH_ALEN)
+	copy(p.HWAddr, data[n:])
+	n += ETH_ALEN
+	p.Config = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Mask = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Advertise = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	return err
+}
+
+// ofp_port_status 1.0
+type PortStatus struct {
+	common.Header
+	Reason uint8
+	pad    []uint8 // Size 7
+	Desc   PhyPort
+}
+
+func NewPortStatus() *PortStatus {
+	p := new(PortStatus)
+	p.Header = NewOfp10Header()
+	p.Header.Type = Type_PortStatus
+	p.pad = make([]byte, 7)
+	p.Desc = *NewPhyPort()
+	return p
+}
+
+func (s *PortStatus) Len() (n uint16) {
+	n = s.Header.Len()
+	n += 8
+	n += s.Desc.Len()
+	return
+}
+
+func (s *PortStatus) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	s.Header.Length = s.Len()
+	b, err := s.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := int(s.Header.Len())
+	data[n] = s.Reason
+	n += 8 // Reason and pad
+	if b, err = s.Desc.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	return
+}
+
+func (s *PortStatus) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PortStatus message")
+	}
+	err := s.Header.UnmarshalBinary(data)
+	n := int(s.Header.Len())
+	s.Reason = data[n]
+	n += 8 // Reason and pad
+	if err = s.Desc.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ofp_port_reason 1.0
+const (
+	PR_ADD = iota
+	PR_DELETE
+	PR_MODIFY
+)
+
+const (
+	ETH_ALEN          = 6
+	MAX_PORT_NAME_LEN = 16
+)
+
+// ofp_port_config 1.0
+const (
+	PC_PORT_DOWN    = 1 << 0 /* Port is administratively down. */
+	PC_NO_STP       = 1 << 1 /* Disable 802.1D spanning tree on port. */
+	PC_NO_RECV      = 1 << 2 /* Drop all packets except 802.1D spanning tree packets. */
+	PC_NO_RECV_STP  = 1 << 3 /* Drop received 802.1D STP packets. */
+	PC_NO_FLOOD     = 1 << 4 /* Do not include this port when flooding. */
+	PC_NO_FWD       = 1 << 5 /* Drop packets forwarded to port. */
+	PC_NO_PACKET_IN = 1 << 6 /* Do not send packet-in msgs for port. */
+)
+
+// ofp_port_state 1.0
+const (
+	PS_LINK_DOWN = 1 << 0 /* No physical link present. */
+
+	PS_STP_LISTEN  = 0 << 8 /* Not learning or relaying frames. */
+	PS_STP_LEARN   = 1 << 8 /* Learning but not relaying frames. */
+	PS_STP_FORWARD = 2 << 8 /* Learning and relaying frames. */
+	PS_STP_BLOCK   = 3 << 8 /* Not part of spanning tree. */
+	PS_STP_MASK    = 3 << 8 /* Bit mask for OFPPS_STP_* values. */
+)
+
+// ofp_port 1.0
+const (
+	P_MAX = 0xff00
+
+	P_IN_PORT = 0xfff8
+	P_TABLE   = 0xfff9
+
+	P_NORMAL = 0xfffa
+	P_FLOOD  = 0xfffb
+
+	P_ALL        = 0xfffc
+	P_CONTROLLER = 0xfffd
+	P_LOCAL      = 0xfffe
+	P_NONE       = 0xffff
+)
+
+// ofp_port_features 1.0
+const (
+	PF_10MB_HD  = 1 << 0
+	PF_10MB_FD  = 1 << 1
+	PF_100MB_HD = 1 << 2
+	PF_100MB_FD = 1 << 3
+	PF_1GB_HD   = 1 << 4
+	PF_1GB_FD   = 1 << 5
+	PF_10GB_FD  = 1 << 6
+
+	PF_COPPER     = 1 << 7
+	PF_FIBER      = 1 << 8
+	PF_AUTONEG    = 1 << 9
+	PF_PAUSE      = 1 << 10
+	PF_PAUSE_ASYM = 1 << 11
+)
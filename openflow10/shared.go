@@ -0,0 +1,30 @@
+package openflow10
+
+import (
+	"antrea.io/libOpenflow/openflow13"
+)
+
+// The Nicira extensions of OpenFlow 1.0 encode the nx_match fields, the NX actions and the bodies of the
+// NXT_SET_FLOW_FORMAT, NXT_FLOW_MOD_TABLE_ID and NXT_SET_PACKET_IN_FORMAT messages as OpenFlow 1.3, so they are built
+// and decoded with the openflow13 package. The actions of OpenFlow 1.0 share the action header of openflow13, then the
+// NX actions decoded by openflow13.DecodeNxAction are used in the OpenFlow 1.0 messages.
+
+type (
+	Action         = openflow13.Action
+	ActionHeader   = openflow13.ActionHeader
+	MatchField     = openflow13.MatchField
+	FlowFormat     = openflow13.FlowFormat
+	FlowModTableID = openflow13.FlowModTableID
+	PacketInFormat = openflow13.PacketInFormat
+)
+
+const (
+	NxVendorID = openflow13.NxExperimenterID
+
+	Type_SetFlowFormat     = openflow13.Type_SetFlowFormat
+	Type_FlowModTableId    = openflow13.Type_FlowModTableId
+	Type_SetPacketInFormat = openflow13.Type_SetPacketInFormat
+
+	NXFF_OPENFLOW10 = openflow13.NXFF_OPENFLOW10
+	NXFF_NXM        = openflow13.NXFF_NXM
+)
@@ -0,0 +1,747 @@
+package openflow10
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"antrea.io/libOpenflow/common"
+	"antrea.io/libOpenflow/util"
+)
+
+// ofp_stats_types 1.0
+const (
+	/* Description of this OpenFlow switch.
+	 * The request body is empty.
+	 * The reply body is struct ofp_desc_stats. */
+	StatsType_Desc = iota
+
+	/* Individual flow statistics.
+	 * The request body is struct ofp_flow_stats_request.
+	 * The reply body is an array of struct ofp_flow_stats. */
+	StatsType_Flow
+
+	/* Aggregate flow statistics.
+	 * The request body is struct ofp_aggregate_stats_request.
+	 * The reply body is struct ofp_aggregate_stats_reply. */
+	StatsType_Aggregate
+
+	/* Flow table statistics.
+	 * The request body is empty.
+	 * The reply body is an array of struct ofp_table_stats. */
+	StatsType_Table
+
+	/* Physical port statistics.
+	 * The request body is struct ofp_port_stats_request.
+	 * The reply body is an array of struct ofp_port_stats. */
+	StatsType_Port
+
+	/* Queue statistics for a port
+	 * The request body is struct ofp_queue_stats_request.
+	 * The reply body is an array of struct ofp_queue_stats */
+	StatsType_Queue
+
+	/* Vendor extension.
+	 * The request and reply bodies begin with a 32-bit vendor ID, which takes
+	 * the same form as in "struct ofp_vendor_header". The request and reply
+	 * bodies are otherwise vendor-defined. */
+	StatsType_Vendor = 0xffff
+)
+
+// ofp_stats_reply_flags 1.0
+const (
+	SF_REPLY_MORE = 1 << 0 /* More replies to follow. */
+)
+
+// ofp_stats_request 1.0
+type StatsRequest struct {
+	common.Header
+	Type  uint16
+	Flags uint16
+	Body  util.Message
+}
+
+// NewStatsRequest returns a stats request of the StatsType_* type with the body.
+func NewStatsRequest(statsType uint16, body util.Message) *StatsRequest {
+	s := new(StatsRequest)
+	s.Header = NewOfp10Header()
+	s.Header.Type = Type_StatsRequest
+	s.Type = statsType
+	s.Body = body
+	return s
+}
+
+func (s *StatsRequest) Len() (n uint16) {
+	n = s.Header.Len() + 4
+	if s.Body != nil {
+		n += s.Body.Len()
+	}
+	return
+}
+
+func (s *StatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	s.Header.Length = s.Len()
+	b, err := s.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := len(b)
+	binary.BigEndian.PutUint16(data[n:], s.Type)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.Flags)
+	n += 2
+	if s.Body != nil {
+		if b, err = s.Body.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (s *StatsRequest) UnmarshalBinary(data []byte) error {
+	if err := s.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(s.Header.Length) || s.Header.Length < 12 {
+		return errors.New("the []byte is too short to unmarshal a full StatsRequest message")
+	}
+	n := s.Header.Len()
+	s.Type = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	switch s.Type {
+	case StatsType_Desc, StatsType_Table:
+		// The request body is empty.
+		return nil
+	case StatsType_Flow:
+		s.Body = NewFlowStatsRequest()
+	case StatsType_Aggregate:
+		s.Body = NewAggregateStatsRequest()
+	case StatsType_Port:
+		s.Body = NewPortStatsRequest(P_NONE)
+	case StatsType_Queue:
+		s.Body = NewQueueStatsRequest(P_ALL, Q_ALL)
+	case StatsType_Vendor:
+		s.Body = new(VendorStats)
+	default:
+		return fmt.Errorf("unsupported StatsRequest type: %d", s.Type)
+	}
+	return s.Body.UnmarshalBinary(data[n:s.Header.Length])
+}
+
+// ofp_stats_reply 1.0
+type StatsReply struct {
+	common.Header
+	Type  uint16
+	Flags uint16
+	Body  []util.Message
+}
+
+// NewStatsReply returns a stats reply of the StatsType_* type.
+func NewStatsReply(statsType uint16) *StatsReply {
+	s := new(StatsReply)
+	s.Header = NewOfp10Header()
+	s.Header.Type = Type_StatsReply
+	s.Type = statsType
+	return s
+}
+
+func (s *StatsReply) Len() (n uint16) {
+	n = s.Header.Len() + 4
+	for _, r := range s.Body {
+		n += r.Len()
+	}
+	return
+}
+
+func (s *StatsReply) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	s.Header.Length = s.Len()
+	b, err := s.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := len(b)
+	binary.BigEndian.PutUint16(data[n:], s.Type)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.Flags)
+	n += 2
+	for _, r := range s.Body {
+		if b, err = r.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (s *StatsReply) UnmarshalBinary(data []byte) error {
+	if err := s.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(s.Header.Length) || s.Header.Length < 12 {
+		return errors.New("the []byte is too short to unmarshal a full StatsReply message")
+	}
+	n := s.Header.Len()
+	s.Type = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	s.Body = nil
+	for n < s.Header.Length {
+		var repl util.Message
+		switch s.Type {
+		case StatsType_Desc:
+			repl = NewDescStats()
+		case StatsType_Flow:
+			repl = NewFlowStats()
+		case StatsType_Aggregate:
+			repl = new(AggregateStats)
+		case StatsType_Table:
+			repl = NewTableStats()
+		case StatsType_Port:
+			repl = new(PortStats)
+		case StatsType_Queue:
+			repl = new(QueueStats)
+		case StatsType_Vendor:
+			repl = new(VendorStats)
+		default:
+			return fmt.Errorf("unsupported StatsReply type: %d", s.Type)
+		}
+		if err := repl.UnmarshalBinary(data[n:s.Header.Length]); err != nil {
+			return err
+		}
+		if repl.Len() == 0 {
+			return errors.New("the StatsReply body has a zero length")
+		}
+		n += repl.Len()
+		s.Body = append(s.Body, repl)
+	}
+	return nil
+}
+
+// ofp_desc_stats 1.0
+type DescStats struct {
+	MfrDesc   []byte // Size DESC_STR_LEN
+	HWDesc    []byte // Size DESC_STR_LEN
+	SWDesc    []byte // Size DESC_STR_LEN
+	SerialNum []byte // Size SERIAL_NUM_LEN
+	DPDesc    []byte // Size DESC_STR_LEN
+}
+
+func NewDescStats() *DescStats {
+	s := new(DescStats)
+	s.MfrDesc = make([]byte, DESC_STR_LEN)
+	s.HWDesc = make([]byte, DESC_STR_LEN)
+	s.SWDesc = make([]byte, DESC_STR_LEN)
+	s.SerialNum = make([]byte, SERIAL_NUM_LEN)
+	s.DPDesc = make([]byte, DESC_STR_LEN)
+	return s
+}
+
+func (s *DescStats) Len() (n uint16) {
+	return uint16(DESC_STR_LEN*4 + SERIAL_NUM_LEN)
+}
+
+func (s *DescStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	n := 0
+	copy(data[n:n+DESC_STR_LEN], s.MfrDesc)
+	n += DESC_STR_LEN
+	copy(data[n:n+DESC_STR_LEN], s.HWDesc)
+	n += DESC_STR_LEN
+	copy(data[n:n+DESC_STR_LEN], s.SWDesc)
+	n += DESC_STR_LEN
+	copy(data[n:n+SERIAL_NUM_LEN], s.SerialNum)
+	n += SERIAL_NUM_LEN
+	copy(data[n:n+DESC_STR_LEN], s.DPDesc)
+	n += DESC_STR_LEN
+	return
+}
+
+func (s *DescStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full DescStats message")
+	}
+	n := 0
+	copy(s.MfrDesc, data[n:])
+	n += DESC_STR_LEN
+	copy(s.HWDesc, data[n:])
+	n += DESC_STR_LEN
+	copy(s.SWDesc, data[n:])
+	n += DESC_STR_LEN
+	copy(s.SerialNum, data[n:])
+	n += SERIAL_NUM_LEN
+	copy(s.DPDesc, data[n:])
+	n += DESC_STR_LEN
+	return nil
+}
+
+const (
+	DESC_STR_LEN   = 256
+	SERIAL_NUM_LEN = 32
+)
+
+const (
+	TT_ALL = 0xff /* Wildcard table used for flow stats and flow deletes. */
+)
+
+// ofp_flow_stats_request 1.0, which is also used as ofp_aggregate_stats_request 1.0.
+type FlowStatsRequest struct {
+	Match   Match
+	TableId uint8
+	OutPort uint16
+}
+
+// AggregateStatsRequest is ofp_aggregate_stats_request 1.0, which has the same fields as FlowStatsRequest.
+type AggregateStatsRequest = FlowStatsRequest
+
+func NewFlowStatsRequest() *FlowStatsRequest {
+	s := new(FlowStatsRequest)
+	s.Match = *NewMatch()
+	s.TableId = TT_ALL
+	s.OutPort = P_NONE
+	return s
+}
+
+func NewAggregateStatsRequest() *AggregateStatsRequest {
+	return NewFlowStatsRequest()
+}
+
+func (s *FlowStatsRequest) Len() (n uint16) {
+	return s.Match.Len() + 4
+}
+
+func (s *FlowStatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	b, err := s.Match.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := len(b)
+	data[n] = s.TableId
+	n += 1
+	n += 1 // pad
+	binary.BigEndian.PutUint16(data[n:], s.OutPort)
+	return
+}
+
+func (s *FlowStatsRequest) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowStatsRequest message")
+	}
+	if err := s.Match.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := s.Match.Len()
+	s.TableId = data[n]
+	n += 2 // TableId and pad
+	s.OutPort = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// ofp_flow_stats 1.0
+type FlowStats struct {
+	Length       uint16
+	TableId      uint8
+	Match        Match
+	DurationSec  uint32
+	DurationNSec uint32
+	Priority     uint16
+	IdleTimeout  uint16
+	HardTimeout  uint16
+	Cookie       uint64
+	PacketCount  uint64
+	ByteCount    uint64
+	Actions      []Action
+}
+
+func NewFlowStats() *FlowStats {
+	s := new(FlowStats)
+	s.Match = *NewMatch()
+	return s
+}
+
+func (s *FlowStats) AddAction(act Action) {
+	s.Actions = append(s.Actions, act)
+}
+
+func (s *FlowStats) Len() (n uint16) {
+	n = 48 + s.Match.Len()
+	for _, a := range s.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (s *FlowStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	s.Length = s.Len()
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], s.Length)
+	n += 2
+	data[n] = s.TableId
+	n += 1
+	n += 1 // pad
+	b, err := s.Match.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint32(data[n:], s.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], s.DurationNSec)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], s.Priority)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.HardTimeout)
+	n += 2
+	n += 6 // pad2
+	binary.BigEndian.PutUint64(data[n:], s.Cookie)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.ByteCount)
+	n += 8
+	for _, a := range s.Actions {
+		if b, err = a.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (s *FlowStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 88 {
+		return errors.New("the []byte is too short to unmarshal a full FlowStats message")
+	}
+	n := uint16(0)
+	s.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	if len(data) < int(s.Length) || s.Length < 88 {
+		return errors.New("the []byte is too short to unmarshal a full FlowStats message")
+	}
+	s.TableId = data[n]
+	n += 1
+	n += 1 // pad
+	if err := s.Match.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += s.Match.Len()
+	s.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 6 // pad2
+	s.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	actions, err := decodeActions(data[n:s.Length], s.Length-n)
+	if err != nil {
+		return err
+	}
+	s.Actions = actions
+	return nil
+}
+
+// ofp_aggregate_stats_reply 1.0
+type AggregateStats struct {
+	PacketCount uint64
+	ByteCount   uint64
+	FlowCount   uint32
+}
+
+func (s *AggregateStats) Len() (n uint16) {
+	return 24
+}
+
+func (s *AggregateStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint64(data, s.PacketCount)
+	binary.BigEndian.PutUint64(data[8:], s.ByteCount)
+	binary.BigEndian.PutUint32(data[16:], s.FlowCount)
+	// 4 bytes for padding
+	return
+}
+
+func (s *AggregateStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full AggregateStats message")
+	}
+	s.PacketCount = binary.BigEndian.Uint64(data)
+	s.ByteCount = binary.BigEndian.Uint64(data[8:])
+	s.FlowCount = binary.BigEndian.Uint32(data[16:])
+	return nil
+}
+
+// ofp_table_stats 1.0
+type TableStats struct {
+	TableId      uint8
+	Name         []byte // Size MAX_TABLE_NAME_LEN
+	Wildcards    uint32
+	MaxEntries   uint32
+	ActiveCount  uint32
+	LookupCount  uint64
+	MatchedCount uint64
+}
+
+const (
+	MAX_TABLE_NAME_LEN = 32
+)
+
+func NewTableStats() *TableStats {
+	s := new(TableStats)
+	s.Name = make([]byte, MAX_TABLE_NAME_LEN)
+	return s
+}
+
+func (s *TableStats) Len() (n uint16) {
+	return 4 + MAX_TABLE_NAME_LEN + 28
+}
+
+func (s *TableStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	n := 0
+	data[n] = s.TableId
+	n += 4 // TableId and pad
+	copy(data[n:n+MAX_TABLE_NAME_LEN], s.Name)
+	n += MAX_TABLE_NAME_LEN
+	binary.BigEndian.PutUint32(data[n:], s.Wildcards)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], s.MaxEntries)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], s.ActiveCount)
+	n += 4
+	binary.BigEndian.PutUint64(data[n:], s.LookupCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.MatchedCount)
+	n += 8
+	return
+}
+
+func (s *TableStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full TableStats message")
+	}
+	n := 0
+	s.TableId = data[n]
+	n += 4 // TableId and pad
+	s.Name = make([]byte, MAX_TABLE_NAME_LEN)
+	copy(s.Name, data[n:])
+	n += MAX_TABLE_NAME_LEN
+	s.Wildcards = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.MaxEntries = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.ActiveCount = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.LookupCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.MatchedCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	return nil
+}
+
+// ofp_port_stats_request 1.0
+type PortStatsRequest struct {
+	PortNo uint16
+}
+
+// NewPortStatsRequest returns the request of the stats of the port, or all the ports with P_NONE.
+func NewPortStatsRequest(port uint16) *PortStatsRequest {
+	return &PortStatsRequest{PortNo: port}
+}
+
+func (s *PortStatsRequest) Len() (n uint16) {
+	return 8
+}
+
+func (s *PortStatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint16(data, s.PortNo)
+	// 6 bytes for padding
+	return
+}
+
+func (s *PortStatsRequest) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PortStatsRequest message")
+	}
+	s.PortNo = binary.BigEndian.Uint16(data)
+	return nil
+}
+
+// ofp_port_stats 1.0
+type PortStats struct {
+	PortNo     uint16
+	RxPackets  uint64
+	TxPackets  uint64
+	RxBytes    uint64
+	TxBytes    uint64
+	RxDropped  uint64
+	TxDropped  uint64
+	RxErrors   uint64
+	TxErrors   uint64
+	RxFrameErr uint64
+	RxOverErr  uint64
+	RxCRCErr   uint64
+	Collisions uint64
+}
+
+func (s *PortStats) Len() (n uint16) {
+	return 104
+}
+
+func (s *PortStats) counters() []*uint64 {
+	return []*uint64{
+		&s.RxPackets, &s.TxPackets, &s.RxBytes, &s.TxBytes, &s.RxDropped, &s.TxDropped,
+		&s.RxErrors, &s.TxErrors, &s.RxFrameErr, &s.RxOverErr, &s.RxCRCErr, &s.Collisions,
+	}
+}
+
+func (s *PortStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint16(data, s.PortNo)
+	n := 8 // PortNo and pad
+	for _, c := range s.counters() {
+		binary.BigEndian.PutUint64(data[n:], *c)
+		n += 8
+	}
+	return
+}
+
+func (s *PortStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PortStats message")
+	}
+	s.PortNo = binary.BigEndian.Uint16(data)
+	n := 8 // PortNo and pad
+	for _, c := range s.counters() {
+		*c = binary.BigEndian.Uint64(data[n:])
+		n += 8
+	}
+	return nil
+}
+
+const (
+	Q_ALL = 0xffffffff /* All ones is used to indicate all queues in a port (for stats retrieval). */
+)
+
+// ofp_queue_stats_request 1.0
+type QueueStatsRequest struct {
+	PortNo  uint16
+	QueueId uint32
+}
+
+// NewQueueStatsRequest returns the request of the stats of the queue, P_ALL and Q_ALL request all the ports and
+// queues.
+func NewQueueStatsRequest(port uint16, queue uint32) *QueueStatsRequest {
+	return &QueueStatsRequest{PortNo: port, QueueId: queue}
+}
+
+func (s *QueueStatsRequest) Len() (n uint16) {
+	return 8
+}
+
+func (s *QueueStatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint16(data, s.PortNo)
+	// 2 bytes for padding
+	binary.BigEndian.PutUint32(data[4:], s.QueueId)
+	return
+}
+
+func (s *QueueStatsRequest) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full QueueStatsRequest message")
+	}
+	s.PortNo = binary.BigEndian.Uint16(data)
+	s.QueueId = binary.BigEndian.Uint32(data[4:])
+	return nil
+}
+
+// ofp_queue_stats 1.0
+type QueueStats struct {
+	PortNo    uint16
+	QueueId   uint32
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+}
+
+func (s *QueueStats) Len() (n uint16) {
+	return 32
+}
+
+func (s *QueueStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint16(data, s.PortNo)
+	// 2 bytes for padding
+	binary.BigEndian.PutUint32(data[4:], s.QueueId)
+	binary.BigEndian.PutUint64(data[8:], s.TxBytes)
+	binary.BigEndian.PutUint64(data[16:], s.TxPackets)
+	binary.BigEndian.PutUint64(data[24:], s.TxErrors)
+	return
+}
+
+func (s *QueueStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full QueueStats message")
+	}
+	s.PortNo = binary.BigEndian.Uint16(data)
+	s.QueueId = binary.BigEndian.Uint32(data[4:])
+	s.TxBytes = binary.BigEndian.Uint64(data[8:])
+	s.TxPackets = binary.BigEndian.Uint64(data[16:])
+	s.TxErrors = binary.BigEndian.Uint64(data[24:])
+	return nil
+}
+
+// VendorStats is the body of the OFPST_VENDOR request and reply, the vendor-defined body is kept in Data.
+type VendorStats struct {
+	Vendor uint32
+	Data   []byte
+}
+
+func (s *VendorStats) Len() (n uint16) {
+	return 4 + uint16(len(s.Data))
+}
+
+func (s *VendorStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint32(data, s.Vendor)
+	copy(data[4:], s.Data)
+	return
+}
+
+// UnmarshalBinary decodes all of data as the vendor body, data must be the remaining bytes of the message.
+func (s *VendorStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("the []byte is too short to unmarshal a full VendorStats message")
+	}
+	s.Vendor = binary.BigEndian.Uint32(data)
+	s.Data = append([]byte(nil), data[4:]...)
+	return nil
+}
@@ -3,6 +3,7 @@ package openflow13
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"antrea.io/libOpenflow/util"
 )
@@ -107,10 +108,12 @@ func DecodeAction(data []byte) (Action, error) {
 		if len(data) < NxActionHeaderLength {
 			return nil, errors.New("the []byte is too short to decode OpenFlow experimenter message")
 		}
-		v := binary.BigEndian.Uint32(data[4:8])
-		if v == NxExperimenterID {
-			a = DecodeNxAction(data)
+		var err error
+		if a, err = decodeExperimenterAction(binary.BigEndian.Uint32(data[4:8]), data); err != nil {
+			return nil, err
 		}
+	default:
+		return nil, fmt.Errorf("DecodeAction unknown type: %v", t)
 	}
 	err := a.UnmarshalBinary(data)
 	if err != nil {
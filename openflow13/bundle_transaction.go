@@ -0,0 +1,77 @@
+package openflow13
+
+import (
+	"fmt"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// DefaultBundleTimeout is the default time to wait for the reply of a bundle control request.
+const DefaultBundleTimeout = util.DefaultBundleTimeout
+
+// BundleMessageError is the error replied by the switch for a message added into a bundle transaction.
+type BundleMessageError = util.BundleMessageError[*ErrorMsg]
+
+// BundleTransactionError is returned by BundleTransaction when the switch rejects a bundle or any added message.
+type BundleTransactionError = util.BundleTransactionError[*ErrorMsg]
+
+// BundleTransaction adds messages into the bundles of the ONF bundle extension and commits or discards them. See
+// util.BundleTransaction for the details.
+type BundleTransaction = util.BundleTransaction[*ErrorMsg]
+
+// NewBundleTransaction creates a BundleTransaction with the bundle ID and the OFPBCT_ATOMIC and OFPBCT_ORDERED flags.
+func NewBundleTransaction(send func(msg util.Message) error, bundleID uint32, flags uint16) *BundleTransaction {
+	return util.NewBundleTransaction[*ErrorMsg](bundleProtocol{}, send, bundleID, flags)
+}
+
+// bundleProtocol builds the bundle messages of the ONF bundle extension.
+type bundleProtocol struct{}
+
+// NewControl ignores props, BundleControl doesn't carry properties.
+func (bundleProtocol) NewControl(bundleID uint32, ctrlType uint16, flags uint16, props []util.Message) (util.Message, uint32) {
+	msg := NewBundleControl(&BundleControl{
+		BundleID: bundleID,
+		Type:     ctrlType,
+		Flags:    flags,
+	})
+	return msg, msg.Header.Xid
+}
+
+func (bundleProtocol) NewAdd(bundleID uint32, flags uint16, msg util.Message, xid uint32) util.Message {
+	bundleAdd := NewBundleAdd(&BundleAdd{
+		BundleID: bundleID,
+		Flags:    flags,
+		Message:  msg,
+	})
+	bundleAdd.Header.Xid = xid
+	return bundleAdd
+}
+
+func (bundleProtocol) CheckControlReply(reply util.Message, ctrlType uint16) error {
+	switch reply := reply.(type) {
+	case *VendorHeader:
+		if ctrl, ok := reply.VendorData.(*BundleControl); ok && ctrl.Type == ctrlType+1 {
+			return nil
+		}
+	case *VendorError:
+		if err := ParseBundleError(reply.Code); err != nil {
+			return err
+		}
+		return reply.ErrorMsg
+	case *ErrorMsg:
+		return reply
+	}
+	return fmt.Errorf("unexpected reply of bundle control request %d", ctrlType)
+}
+
+func (bundleProtocol) ParseReply(msg util.Message) (uint32, *ErrorMsg, bool, bool) {
+	switch msg := msg.(type) {
+	case *VendorHeader:
+		return msg.Header.Xid, nil, false, true
+	case *VendorError:
+		return msg.Xid, msg.ErrorMsg, true, true
+	case *ErrorMsg:
+		return msg.Xid, msg, true, true
+	}
+	return 0, nil, false, false
+}
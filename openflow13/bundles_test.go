@@ -3,10 +3,15 @@ package openflow13
 import (
 	"bytes"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
 )
 
 func TestBundleControl(t *testing.T) {
@@ -142,3 +147,218 @@ func bundleAddEqual(bundleAdd, bundleAdd2 *BundleAdd) error {
 	}
 	return nil
 }
+
+// testBundleSwitch is a fake switch connected to a BundleTransaction, which replies the bundle control requests,
+// fails to commit failedBundle, fails to discard failedDiscard and rejects the flow mods with failedCookie in the
+// bundles. Zero values fail nothing.
+type testBundleSwitch struct {
+	failedBundle  uint32
+	failedDiscard uint32
+	failedCookie  uint64
+
+	mutex     sync.Mutex
+	opened    []uint32
+	discarded []uint32
+}
+
+func (s *testBundleSwitch) Opened() []uint32 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]uint32(nil), s.opened...)
+}
+
+func (s *testBundleSwitch) Discarded() []uint32 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]uint32(nil), s.discarded...)
+}
+
+func newTestBundleSwitch(t *testing.T, bundleID uint32, flags uint16, sw *testBundleSwitch) *BundleTransaction {
+	var txn *BundleTransaction
+	// The replies are handled in order as they are received on a connection.
+	replies := make(chan util.Message, 100)
+	go func() {
+		for reply := range replies {
+			txn.HandleMessage(reply)
+		}
+	}()
+	t.Cleanup(func() { close(replies) })
+	txn = NewBundleTransaction(func(msg util.Message) error {
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		parsed, err := Parse(data)
+		require.NoError(t, err)
+		vendor := parsed.(*VendorHeader)
+		var reply util.Message
+		switch body := vendor.VendorData.(type) {
+		case *BundleControl:
+			assert.Equal(t, flags, body.Flags)
+			sw.mutex.Lock()
+			switch body.Type {
+			case OFPBCT_OPEN_REQUEST:
+				sw.opened = append(sw.opened, body.BundleID)
+			case OFPBCT_DISCARD_REQUEST:
+				sw.discarded = append(sw.discarded, body.BundleID)
+			}
+			sw.mutex.Unlock()
+			if body.Type == OFPBCT_COMMIT_REQUEST && sw.failedBundle != 0 && body.BundleID == sw.failedBundle {
+				errMsg := NewBundleError()
+				errMsg.Code = BEC_MSG_FAILD
+				errMsg.Xid = vendor.Header.Xid
+				reply = errMsg
+				break
+			}
+			if body.Type == OFPBCT_DISCARD_REQUEST && sw.failedDiscard != 0 && body.BundleID == sw.failedDiscard {
+				errMsg := NewBundleError()
+				errMsg.Code = BEC_BAD_ID
+				errMsg.Xid = vendor.Header.Xid
+				reply = errMsg
+				break
+			}
+			replyMsg := NewBundleControl(&BundleControl{BundleID: body.BundleID, Type: body.Type + 1, Flags: body.Flags})
+			replyMsg.Header.Xid = vendor.Header.Xid
+			reply = replyMsg
+		case *BundleAdd:
+			assert.Equal(t, flags, body.Flags)
+			if sw.failedCookie == 0 || body.Message.(*FlowMod).Cookie != sw.failedCookie {
+				return nil
+			}
+			errMsg := NewErrorMsg()
+			errMsg.Type = ET_FLOW_MOD_FAILED
+			errMsg.Code = FMFC_OVERLAP
+			errMsg.Xid = vendor.Header.Xid
+			reply = errMsg
+		}
+		replies <- reply
+		return nil
+	}, bundleID, flags)
+	return txn
+}
+
+func TestBundleTransaction(t *testing.T) {
+	newFlowMod := func(cookie uint64) *FlowMod {
+		flowMod := NewFlowMod()
+		flowMod.Cookie = cookie
+		return flowMod
+	}
+	nextBundleID := func(bundleID uint32) func() uint32 {
+		return func() uint32 {
+			bundleID++
+			return bundleID
+		}
+	}
+
+	sw := &testBundleSwitch{}
+	txn := newTestBundleSwitch(t, 10, OFPBCT_ATOMIC|OFPBCT_ORDERED, sw)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2)))
+	require.NoError(t, txn.Commit())
+	assert.Equal(t, []uint32{10}, sw.Opened())
+	assert.Error(t, txn.Add(newFlowMod(3)), "Messages should not be added after the transaction is committed")
+
+	// The large bundles are split, and the failures are mapped to the added messages.
+	sw = &testBundleSwitch{failedBundle: 21, failedCookie: 3}
+	txn = newTestBundleSwitch(t, 20, OFPBCT_ORDERED, sw)
+	txn.MaxMessages = 2
+	txn.NextBundleID = nextBundleID(20)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2), newFlowMod(3)))
+	assert.Equal(t, []uint32{20, 21}, sw.Opened())
+	err := txn.Commit()
+	var txnErr *BundleTransactionError
+	require.True(t, errors.As(err, &txnErr))
+	assert.Equal(t, uint32(21), txnErr.BundleID)
+	assert.EqualError(t, txnErr.Err, "one message in bundle failed")
+	require.Len(t, txnErr.Messages, 1)
+	assert.Equal(t, 2, txnErr.Messages[0].Index)
+	assert.Equal(t, uint64(3), txnErr.Messages[0].Message.(*FlowMod).Cookie)
+	assert.Equal(t, uint16(FMFC_OVERLAP), txnErr.Messages[0].Error.Code)
+	assert.Empty(t, sw.Discarded())
+
+	// The bundles after the failing one are discarded.
+	sw = &testBundleSwitch{failedBundle: 30}
+	txn = newTestBundleSwitch(t, 30, OFPBCT_ORDERED, sw)
+	txn.MaxMessages = 1
+	txn.NextBundleID = nextBundleID(30)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2), newFlowMod(3)))
+	err = txn.Commit()
+	require.True(t, errors.As(err, &txnErr))
+	assert.Equal(t, uint32(30), txnErr.BundleID)
+	assert.Empty(t, txnErr.Committed)
+	assert.NoError(t, txnErr.DiscardErr)
+	assert.Equal(t, []uint32{31, 32}, sw.Discarded())
+
+	// The bundles committed before the failing one are reported, and so are the discard errors.
+	sw = &testBundleSwitch{failedBundle: 36, failedDiscard: 37}
+	txn = newTestBundleSwitch(t, 35, OFPBCT_ORDERED, sw)
+	txn.MaxMessages = 1
+	txn.NextBundleID = nextBundleID(35)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2), newFlowMod(3), newFlowMod(4)))
+	err = txn.Commit()
+	require.True(t, errors.As(err, &txnErr))
+	assert.Equal(t, uint32(36), txnErr.BundleID)
+	assert.Equal(t, []uint32{35}, txnErr.Committed)
+	assert.EqualError(t, txnErr.DiscardErr, "failed to discard bundle 37: bundle ID doesn't exist")
+	assert.Equal(t, []uint32{37, 38}, sw.Discarded())
+	assert.Contains(t, err.Error(), "bundles [35] are committed")
+
+	sw = &testBundleSwitch{}
+	txn = newTestBundleSwitch(t, 40, OFPBCT_ATOMIC|OFPBCT_ORDERED, sw)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1)))
+	require.NoError(t, txn.Discard())
+	assert.Equal(t, []uint32{40}, sw.Discarded())
+
+	// The control requests time out without replies.
+	txn = NewBundleTransaction(func(msg util.Message) error { return nil }, 50, 0)
+	txn.Timeout = 10 * time.Millisecond
+	assert.Error(t, txn.Open())
+}
+
+func TestBundleTransactionSplit(t *testing.T) {
+	// An atomic bundle is never split.
+	txn := newTestBundleSwitch(t, 10, OFPBCT_ATOMIC, &testBundleSwitch{})
+	txn.MaxMessages = 1
+	txn.NextBundleID = func() uint32 { return 11 }
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(NewFlowMod()))
+	assert.Error(t, txn.Add(NewFlowMod()))
+
+	// The bundle IDs must be allocated by the caller, and must not be used by the transaction.
+	txn = newTestBundleSwitch(t, 20, OFPBCT_ORDERED, &testBundleSwitch{})
+	txn.MaxMessages = 1
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(NewFlowMod()))
+	assert.Error(t, txn.Add(NewFlowMod()))
+	txn.NextBundleID = func() uint32 { return 20 }
+	assert.Error(t, txn.Add(NewFlowMod()))
+
+	// The messages must have different xids to report their errors.
+	txn = newTestBundleSwitch(t, 30, OFPBCT_ORDERED, &testBundleSwitch{})
+	require.NoError(t, txn.Open())
+	flowMod := NewFlowMod()
+	require.NoError(t, txn.Add(flowMod))
+	assert.Error(t, txn.Add(flowMod))
+
+	// The concurrent messages filling a bundle open only one new bundle.
+	sw := &testBundleSwitch{}
+	txn = newTestBundleSwitch(t, 40, OFPBCT_ORDERED, sw)
+	txn.MaxMessages = 2
+	var nextID atomic.Uint32
+	nextID.Store(40)
+	txn.NextBundleID = func() uint32 { return nextID.Add(1) }
+	require.NoError(t, txn.Open())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, txn.Add(NewFlowMod()))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []uint32{40, 41, 42, 43}, sw.Opened())
+	require.NoError(t, txn.Commit())
+}
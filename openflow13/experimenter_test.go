@@ -0,0 +1,239 @@
+package openflow13
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
+)
+
+const testExperimenterID = 0x00abcdef
+
+// testExperimenterAction is an action of a third-party experimenter, which carries a 32-bit value.
+type testExperimenterAction struct {
+	ActionHeader
+	Experimenter uint32
+	Value        uint32
+	pad          [4]byte
+}
+
+func (a *testExperimenterAction) Len() uint16 {
+	return 16
+}
+
+func (a *testExperimenterAction) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, a.Len())
+	binary.BigEndian.PutUint16(data, ActionType_Experimenter)
+	binary.BigEndian.PutUint16(data[2:], a.Len())
+	binary.BigEndian.PutUint32(data[4:], a.Experimenter)
+	binary.BigEndian.PutUint32(data[8:], a.Value)
+	return
+}
+
+func (a *testExperimenterAction) UnmarshalBinary(data []byte) error {
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full testExperimenterAction message")
+	}
+	a.Type = binary.BigEndian.Uint16(data)
+	a.Length = binary.BigEndian.Uint16(data[2:])
+	a.Experimenter = binary.BigEndian.Uint32(data[4:])
+	a.Value = binary.BigEndian.Uint32(data[8:])
+	return nil
+}
+
+// testExperimenterBody is the body of an experimenter multipart message, which carries a 32-bit value.
+type testExperimenterBody struct {
+	Experimenter uint32
+	ExpType      uint32
+	Value        uint32
+}
+
+func (b *testExperimenterBody) Len() uint16 {
+	return 12
+}
+
+func (b *testExperimenterBody) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, b.Len())
+	binary.BigEndian.PutUint32(data, b.Experimenter)
+	binary.BigEndian.PutUint32(data[4:], b.ExpType)
+	binary.BigEndian.PutUint32(data[8:], b.Value)
+	return
+}
+
+func (b *testExperimenterBody) UnmarshalBinary(data []byte) error {
+	if len(data) < int(b.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full testExperimenterBody message")
+	}
+	b.Experimenter = binary.BigEndian.Uint32(data)
+	b.ExpType = binary.BigEndian.Uint32(data[4:])
+	b.Value = binary.BigEndian.Uint32(data[8:])
+	return nil
+}
+
+// testExperimenterProperty is a table feature property of a third-party experimenter.
+type testExperimenterProperty struct {
+	TableExperimenterProperty
+}
+
+func decodeTestExperimenterBody(expType uint32, data []byte) (util.Message, error) {
+	b := new(testExperimenterBody)
+	if err := b.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func TestExperimenterAction(t *testing.T) {
+	act := &testExperimenterAction{Experimenter: testExperimenterID, Value: 100}
+	data, err := act.MarshalBinary()
+	require.NoError(t, err)
+	_, err = DecodeAction(data)
+	assert.Error(t, err, "Actions of unregistered experimenters should not be decoded")
+
+	RegisterExperimenterAction(testExperimenterID, func(data []byte) (Action, error) {
+		return new(testExperimenterAction), nil
+	})
+	defer RegisterExperimenterAction(testExperimenterID, nil)
+	newAct, err := DecodeAction(data)
+	require.NoError(t, err)
+	require.IsType(t, act, newAct)
+	assert.Equal(t, uint32(100), newAct.(*testExperimenterAction).Value)
+
+	// Extend the Nicira decoder with a new subtype.
+	RegisterExperimenterAction(NxExperimenterID, func(data []byte) (Action, error) {
+		if binary.BigEndian.Uint16(data[8:]) == 200 {
+			return new(NXActionDecNshTTL), nil
+		}
+		return DecodeNxAction(data)
+	})
+	defer RegisterExperimenterAction(NxExperimenterID, DecodeNxAction)
+	nxAct := NewNXActionDecNshTTL()
+	nxAct.Subtype = 200
+	data, _ = nxAct.MarshalBinary()
+	newAct, err = DecodeAction(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(200), newAct.(*NXActionDecNshTTL).Subtype)
+	data, _ = NewNXActionExit().MarshalBinary()
+	newAct, err = DecodeAction(data)
+	require.NoError(t, err)
+	assert.IsType(t, new(NXActionExit), newAct)
+}
+
+func TestExperimenterMatchField(t *testing.T) {
+	field := &MatchField{Class: OXM_CLASS_EXPERIMENTER, Field: 1, Length: 8, ExperimenterID: testExperimenterID, Value: &Uint32Message{Data: 7}}
+	data, err := field.MarshalBinary()
+	require.NoError(t, err)
+	assert.Error(t, new(MatchField).UnmarshalBinary(data), "Fields of unregistered experimenters should not be decoded")
+
+	RegisterExperimenterMatchField(testExperimenterID, func(class uint16, field uint8, length uint8, hasMask bool, data []byte) (util.Message, error) {
+		v := new(Uint32Message)
+		if err := v.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+	defer RegisterExperimenterMatchField(testExperimenterID, nil)
+	newField := new(MatchField)
+	require.NoError(t, newField.UnmarshalBinary(data))
+	assert.Equal(t, uint32(testExperimenterID), newField.ExperimenterID)
+	assert.Equal(t, uint32(7), newField.Value.(*Uint32Message).Data)
+}
+
+func TestExperimenterMessage(t *testing.T) {
+	msg := &VendorHeader{
+		Header:           NewOfp13Header(),
+		Vendor:           testExperimenterID,
+		ExperimenterType: 5,
+		VendorData:       &Uint32Message{Data: 9},
+	}
+	msg.Header.Type = Type_Experimenter
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	_, err = Parse(data)
+	assert.Error(t, err, "Messages of unregistered experimenters should not be decoded")
+
+	RegisterExperimenterMessage(testExperimenterID, func(expType uint32, data []byte) (util.Message, error) {
+		if expType != 5 {
+			return nil, errors.New("unknown experimenter type")
+		}
+		v := new(Uint32Message)
+		if err := v.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+	defer RegisterExperimenterMessage(testExperimenterID, nil)
+	newMsg, err := Parse(data)
+	require.NoError(t, err)
+	require.IsType(t, new(VendorHeader), newMsg)
+	assert.Equal(t, uint32(9), newMsg.(*VendorHeader).VendorData.(*Uint32Message).Data)
+}
+
+func TestExperimenterMultipart(t *testing.T) {
+	body := &testExperimenterBody{Experimenter: testExperimenterID, ExpType: 1, Value: 3}
+	reply := &MultipartReply{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{body, body},
+	}
+	reply.Header.Type = Type_MultiPartReply
+	data, err := reply.MarshalBinary()
+	require.NoError(t, err)
+	assert.Error(t, new(MultipartReply).UnmarshalBinary(data), "Bodies of unregistered experimenters should not be decoded")
+
+	RegisterExperimenterMultipart(testExperimenterID, decodeTestExperimenterBody, decodeTestExperimenterBody)
+	defer RegisterExperimenterMultipart(testExperimenterID, nil, nil)
+	newReply := new(MultipartReply)
+	require.NoError(t, newReply.UnmarshalBinary(data))
+	assert.Equal(t, []util.Message{body, body}, newReply.Body)
+
+	request := &MultipartRequest{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{body},
+	}
+	request.Header.Type = Type_MultiPartRequest
+	data, err = request.MarshalBinary()
+	require.NoError(t, err)
+	newRequest := new(MultipartRequest)
+	require.NoError(t, newRequest.UnmarshalBinary(data))
+	assert.Equal(t, []util.Message{body}, newRequest.Body)
+
+	// A decoder returning an empty body must not make the decoding loop forever.
+	RegisterExperimenterMultipart(testExperimenterID, nil, func(expType uint32, data []byte) (util.Message, error) {
+		return new(util.Buffer), nil
+	})
+	data, err = reply.MarshalBinary()
+	require.NoError(t, err)
+	assert.Error(t, new(MultipartReply).UnmarshalBinary(data))
+}
+
+func TestExperimenterProperty(t *testing.T) {
+	feature := newTableFeatures()
+	feature.Properties = []util.Message{&TableExperimenterProperty{
+		OFTablePropertyHeader: OFTablePropertyHeader{Type: OFPTFPT13_EXPERIMENTER, Length: 16},
+		Experimenter:          testExperimenterID,
+		ExperimenterType:      2,
+		ExperimenterData:      []uint32{0x1234},
+	}}
+	feature.Length = feature.Len()
+	data, err := feature.MarshalBinary()
+	require.NoError(t, err)
+
+	RegisterExperimenterProperty(testExperimenterID, func(propType uint16, expType uint32) util.Message {
+		if propType == OFPTFPT13_EXPERIMENTER && expType == 2 {
+			return new(testExperimenterProperty)
+		}
+		return nil
+	})
+	defer RegisterExperimenterProperty(testExperimenterID, nil)
+	newFeature := new(OFPTableFeatures)
+	require.NoError(t, newFeature.UnmarshalBinary(data))
+	require.Len(t, newFeature.Properties, 1)
+	require.IsType(t, new(testExperimenterProperty), newFeature.Properties[0])
+	assert.Equal(t, []uint32{0x1234}, newFeature.Properties[0].(*testExperimenterProperty).ExperimenterData)
+}
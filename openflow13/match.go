@@ -135,6 +135,11 @@ func (m *MatchField) MarshalBinary() (data []byte, err error) {
 	data[n] = m.Length
 	n += 1
 
+	if m.Class == OXM_CLASS_EXPERIMENTER && m.ExperimenterID != 0 {
+		binary.BigEndian.PutUint32(data[n:], m.ExperimenterID)
+		n += 4
+	}
+
 	b, err := m.Value.MarshalBinary()
 	copy(data[n:], b)
 	n += len(b)
@@ -150,6 +155,9 @@ func (m *MatchField) MarshalBinary() (data []byte, err error) {
 func (m *MatchField) UnmarshalBinary(data []byte) error {
 	var n uint16
 	var err error
+	if len(data) < 4 {
+		return errors.New("the []byte is too short to unmarshal a full MatchField message")
+	}
 	m.Class = binary.BigEndian.Uint16(data[n:])
 	n += 2
 
@@ -165,23 +173,26 @@ func (m *MatchField) UnmarshalBinary(data []byte) error {
 	m.Length = data[n]
 	n += 1
 
+	decode := DecodeMatchField
 	if m.Class == OXM_CLASS_EXPERIMENTER {
+		if len(data) < 8 {
+			return errors.New("the []byte is too short to unmarshal a full experimenter MatchField message")
+		}
 		experimenterID := binary.BigEndian.Uint32(data[n:])
-		if experimenterID == ONF_EXPERIMENTER_ID {
-			n += 4
-			m.ExperimenterID = experimenterID
-		} else {
+		if decode = experimenterMatchFieldDecoder(experimenterID); decode == nil {
 			return fmt.Errorf("Unsupported experimenter id: %d in class: %d ", experimenterID, m.Class)
 		}
+		n += 4
+		m.ExperimenterID = experimenterID
 	}
 
-	if m.Value, err = DecodeMatchField(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
+	if m.Value, err = decode(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
 		return err
 	}
 	n += m.Value.Len()
 
 	if m.HasMask {
-		if m.Mask, err = DecodeMatchField(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
+		if m.Mask, err = decode(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
 			return err
 		}
 		n += m.Mask.Len()
@@ -297,6 +308,8 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 		case OXM_FIELD_IPV6_EXTHDR:
 		case OXM_FIELD_TCP_FLAGS:
 			val = new(TcpFlagsField)
+		case OXM_FIELD_PACKET_TYPE:
+			val = new(PacketTypeField)
 		default:
 			log.Printf("Unhandled Field: %d in Class: %d", field, class)
 		}
@@ -306,6 +319,47 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
 		}
 
+		err := val.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	} else if class == OXM_CLASS_NXM_0 {
+		var val util.Message
+		switch field {
+		case NXM_OF_IN_PORT:
+			val = new(Uint16Message)
+		case NXM_OF_ETH_DST:
+			val = new(EthDstField)
+		case NXM_OF_ETH_SRC:
+			val = new(EthSrcField)
+		case NXM_OF_ETH_TYPE:
+			val = new(EthTypeField)
+		case NXM_OF_VLAN_TCI:
+			val = new(Uint16Message)
+		case NXM_OF_IP_TOS:
+			val = new(Uint8Message)
+		case NXM_OF_IP_PROTO:
+			val = new(IpProtoField)
+		case NXM_OF_IP_SRC:
+			val = new(Ipv4SrcField)
+		case NXM_OF_IP_DST:
+			val = new(Ipv4DstField)
+		case NXM_OF_TCP_SRC, NXM_OF_TCP_DST, NXM_OF_UDP_SRC, NXM_OF_UDP_DST:
+			val = new(PortField)
+		case NXM_OF_ICMP_TYPE:
+			val = new(IcmpTypeField)
+		case NXM_OF_ICMP_CODE:
+			val = new(IcmpCodeField)
+		case NXM_OF_ARP_OP:
+			val = new(ArpOperField)
+		case NXM_OF_ARP_SPA, NXM_OF_ARP_TPA:
+			val = new(ArpXPaField)
+		default:
+			log.Printf("Unhandled Field: %d in Class: %d", field, class)
+			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
+		}
+
 		err := val.UnmarshalBinary(data)
 		if err != nil {
 			return nil, err
@@ -347,6 +401,7 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 		case NXM_NX_REG15:
 			val = new(Uint32Message)
 		case NXM_NX_TUN_ID:
+			val = new(Uint64Message)
 		case NXM_NX_ARP_SHA:
 			val = new(ArpXHaField)
 		case NXM_NX_ARP_THA:
@@ -379,11 +434,15 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			val = new(Uint32Message)
 		case NXM_NX_TCP_FLAGS:
 		case NXM_NX_DP_HASH:
+			val = new(Uint32Message)
 		case NXM_NX_RECIRC_ID:
+			val = new(Uint32Message)
 		case NXM_NX_CONJ_ID:
 			val = new(Uint32Message)
 		case NXM_NX_TUN_GBP_ID:
+			val = new(Uint16Message)
 		case NXM_NX_TUN_GBP_FLAGS:
+			val = new(Uint8Message)
 		case NXM_NX_TUN_METADATA0:
 			fallthrough
 		case NXM_NX_TUN_METADATA1:
@@ -407,6 +466,7 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			}
 			val = msg
 		case NXM_NX_TUN_FLAGS:
+			val = new(Uint16Message)
 		case NXM_NX_CT_STATE:
 			val = new(Uint32Message)
 		case NXM_NX_CT_ZONE:
@@ -440,23 +500,29 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 		case NXM_NX_XXREG2:
 			fallthrough
 		case NXM_NX_XXREG3:
-			msg := new(ByteArrayField)
-			if !hasMask {
-				msg.Length = length
-			} else {
-				msg.Length = length / 2
-			}
-			val = msg
+			val = new(Uint128Message)
 		default:
 			log.Printf("Unhandled Field: %d in Class: %d", field, class)
 			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
 		}
 
+		if val == nil {
+			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
+		}
 		err := val.UnmarshalBinary(data)
 		if err != nil {
 			return nil, err
 		}
 		return val, nil
+	} else if class == OXM_CLASS_PACKET_REGS {
+		if field > OXM_PACKET_REG7 {
+			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
+		}
+		val := new(Uint64Message)
+		if err := val.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return val, nil
 	} else if class == OXM_CLASS_EXPERIMENTER {
 		var val util.Message
 		switch field {
@@ -464,17 +530,17 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			val = new(TcpFlagsField)
 		case OXM_FIELD_ACTSET_OUTPUT:
 			val = new(ActsetOutputField)
+		default:
+			return nil, fmt.Errorf("Bad pkt class: %v field: %v data: %v", class, field, data)
 		}
 		err := val.UnmarshalBinary(data)
 		if err != nil {
 			return nil, err
 		}
 		return val, nil
-	} else {
-		log.Panicf("Unsupported match field: %d in class: %d", field, class)
 	}
 
-	return nil, nil
+	return nil, fmt.Errorf("Unsupported match field: %d in class: %d", field, class)
 }
 
 // ofp_match_type 1.3
@@ -488,6 +554,7 @@ const (
 	OXM_CLASS_NXM_0          = 0x0000 /* Backward compatibility with NXM */
 	OXM_CLASS_NXM_1          = 0x0001 /* Backward compatibility with NXM */
 	OXM_CLASS_OPENFLOW_BASIC = 0x8000 /* Basic class for OpenFlow */
+	OXM_CLASS_PACKET_REGS    = 0x8001 /* Packet registers (pipeline fields), used by OVS for xreg. */
 	OXM_CLASS_EXPERIMENTER   = 0xFFFF /* Experimenter class */
 
 	ONF_EXPERIMENTER_ID = 0x4f4e4600 /* ONF Experimenter ID */
@@ -537,6 +604,7 @@ const (
 	OXM_FIELD_PBB_UCA        = 41 /* PBB UCA header field (from OpenFlow 1.4) */
 	OXM_FIELD_TCP_FLAGS      = 42 /* TCP flags (from OpenFlow 1.5) */
 	OXM_FIELD_ACTSET_OUTPUT  = 43 /* actset output port number (from OpenFlow 1.5) */
+	OXM_FIELD_PACKET_TYPE    = 44 /* Packet type value. (from OpenFlow 1.XXX) */
 )
 
 const (
@@ -608,6 +676,17 @@ const (
 	NXM_NX_CT_TP_DST     = 125 /* nicira extension: ct_tp_dst, transport layer destination port of the original direction tuple of the conntrack entry */
 )
 
+const (
+	OXM_PACKET_REG0 = 0 /* Packet register 0, xreg0 in OVS */
+	OXM_PACKET_REG1 = 1 /* Packet register 1, xreg1 in OVS */
+	OXM_PACKET_REG2 = 2 /* Packet register 2, xreg2 in OVS */
+	OXM_PACKET_REG3 = 3 /* Packet register 3, xreg3 in OVS */
+	OXM_PACKET_REG4 = 4 /* Packet register 4, xreg4 in OVS */
+	OXM_PACKET_REG5 = 5 /* Packet register 5, xreg5 in OVS */
+	OXM_PACKET_REG6 = 6 /* Packet register 6, xreg6 in OVS */
+	OXM_PACKET_REG7 = 7 /* Packet register 7, xreg7 in OVS */
+)
+
 // IN_PORT field
 type InPortField struct {
 	InPort uint32
@@ -1090,7 +1169,7 @@ func NewIpv6DstField(ipDst net.IP, ipDstMask *net.IP) *MatchField {
 
 // IP_PROTO field
 type IpProtoField struct {
-	protocol uint8
+	Protocol uint8
 }
 
 func (m *IpProtoField) Len() uint16 {
@@ -1098,12 +1177,12 @@ func (m *IpProtoField) Len() uint16 {
 }
 func (m *IpProtoField) MarshalBinary() (data []byte, err error) {
 	data = make([]byte, 1)
-	data[0] = m.protocol
+	data[0] = m.Protocol
 	return
 }
 
 func (m *IpProtoField) UnmarshalBinary(data []byte) error {
-	m.protocol = data[0]
+	m.Protocol = data[0]
 	return nil
 }
 
@@ -1115,7 +1194,7 @@ func NewIpProtoField(protocol uint8) *MatchField {
 	f.HasMask = false
 
 	ipProtoField := new(IpProtoField)
-	ipProtoField.protocol = protocol
+	ipProtoField.Protocol = protocol
 	f.Value = ipProtoField
 	f.Length = uint8(ipProtoField.Len())
 
@@ -1235,7 +1314,7 @@ func NewMetadataField(metadata uint64, metadataMask *uint64) *MatchField {
 
 // Common struct for all port fields
 type PortField struct {
-	port uint16
+	Port uint16
 }
 
 func (m *PortField) Len() uint16 {
@@ -1243,18 +1322,18 @@ func (m *PortField) Len() uint16 {
 }
 func (m *PortField) MarshalBinary() (data []byte, err error) {
 	data = make([]byte, m.Len())
-	binary.BigEndian.PutUint16(data, m.port)
+	binary.BigEndian.PutUint16(data, m.Port)
 	return
 }
 
 func (m *PortField) UnmarshalBinary(data []byte) error {
-	m.port = binary.BigEndian.Uint16(data)
+	m.Port = binary.BigEndian.Uint16(data)
 	return nil
 }
 
 func NewPortField(port uint16) *PortField {
 	f := new(PortField)
-	f.port = port
+	f.Port = port
 	return f
 }
 
@@ -1569,7 +1648,7 @@ func NewSctpDstField(port uint16) *MatchField {
 	f.HasMask = false
 
 	sctpDstField := new(PortField)
-	sctpDstField.port = port
+	sctpDstField.Port = port
 	f.Value = sctpDstField
 	f.Length = uint8(sctpDstField.Len())
 
@@ -1584,7 +1663,7 @@ func NewSctpSrcField(port uint16) *MatchField {
 	f.HasMask = false
 
 	sctpSrcField := new(PortField)
-	sctpSrcField.port = port
+	sctpSrcField.Port = port
 	f.Value = sctpSrcField
 	f.Length = uint8(sctpSrcField.Len())
 
@@ -1765,3 +1844,54 @@ func (f *IcmpCodeField) UnmarshalBinary(data []byte) error {
 	f.Code = data[0]
 	return nil
 }
+
+// PACKET_TYPE field
+type PacketTypeField struct {
+	Namespace uint16
+	NsType    uint16
+}
+
+func (f *PacketTypeField) Len() uint16 {
+	return 4
+}
+func (f *PacketTypeField) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 4)
+
+	binary.BigEndian.PutUint16(data[0:], f.Namespace)
+	binary.BigEndian.PutUint16(data[2:], f.NsType)
+	return
+}
+func (f *PacketTypeField) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("The byte array has wrong size to unmarshal PacketTypeField message")
+	}
+	f.Namespace = binary.BigEndian.Uint16(data[0:])
+	f.NsType = binary.BigEndian.Uint16(data[2:])
+	return nil
+}
+
+func NewPacketTypeField(namespace uint16, nsType uint16) *MatchField {
+	f := new(MatchField)
+	f.Class = OXM_CLASS_OPENFLOW_BASIC
+	f.Field = OXM_FIELD_PACKET_TYPE
+	f.HasMask = false
+
+	packetTypeField := new(PacketTypeField)
+	packetTypeField.Namespace = namespace
+	packetTypeField.NsType = nsType
+	f.Value = packetTypeField
+	f.Length = uint8(packetTypeField.Len())
+
+	return f
+}
+
+// PacketType returns the packet type encoded as a uint32 number, e.g., PT_NSH.
+func (f *PacketTypeField) PacketType() uint32 {
+	return uint32(f.Namespace)<<16 | uint32(f.NsType)
+}
+
+// NewPacketTypeMatchField creates a PACKET_TYPE MatchField from a packet type encoded as a uint32
+// number, e.g., PT_NSH.
+func NewPacketTypeMatchField(packetType uint32) *MatchField {
+	return NewPacketTypeField(uint16(packetType>>16), uint16(packetType))
+}
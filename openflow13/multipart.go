@@ -2,6 +2,7 @@ package openflow13
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -80,7 +81,28 @@ func (s *MultipartRequest) UnmarshalBinary(data []byte) error {
 		case MultipartType_Table:
 		case MultipartType_Queue:
 			req = new(QueueStatsRequest)
+		case MultipartType_Group:
+			req = new(GroupMultipartRequest)
+		case MultipartType_GroupDesc:
+			// The request body is empty.
+		case MultipartType_GroupFeatures:
+			// The request body is empty.
+		case MultipartType_Meter:
+			req = new(MeterMultipartRequest)
+		case MultipartType_MeterConfig:
+			req = new(MeterMultipartRequest)
+		case MultipartType_MeterFeatures:
+			// The request body is empty.
+		case MultipartType_PortDesc:
+			// The request body is empty.
 		case MultipartType_Experimenter:
+			// The experimenter decoder returns the decoded body.
+			if req, err = decodeExperimenterMultipart(data[n:s.Header.Length], false); err != nil {
+				return err
+			}
+			n += req.Len()
+			s.Body = append(s.Body, req)
+			continue
 		case MultipartType_TableFeatures:
 			req = new(OFPTableFeatures)
 		}
@@ -160,12 +182,34 @@ func (s *MultipartReply) UnmarshalBinary(data []byte) error {
 			repl = new(TableStats)
 		case MultipartType_Queue:
 			repl = new(QueueStats)
-		// FIXME: Support all types
+		case MultipartType_Group:
+			repl = new(GroupStats)
+		case MultipartType_GroupDesc:
+			repl = new(GroupDesc)
+		case MultipartType_GroupFeatures:
+			repl = new(GroupFeatures)
+		case MultipartType_Meter:
+			repl = new(MeterStats)
+		case MultipartType_MeterConfig:
+			repl = new(MeterDesc)
+		case MultipartType_MeterFeatures:
+			repl = new(MeterFeatures)
+		case MultipartType_PortDesc:
+			repl = NewPhyPort()
 		case MultipartType_Experimenter:
-			break
+			// The experimenter decoder returns the decoded body.
+			if repl, err = decodeExperimenterMultipart(data[n:s.Header.Length], true); err != nil {
+				return err
+			}
+			n += repl.Len()
+			req = append(req, repl)
+			continue
 		case MultipartType_TableFeatures:
 			repl = new(OFPTableFeatures)
 		}
+		if repl == nil {
+			return fmt.Errorf("unsupported MultipartReply type: %d", s.Type)
+		}
 
 		err = repl.UnmarshalBinary(data[n:])
 		if err != nil {
@@ -928,6 +972,609 @@ func (s *QueueStats) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// ofp_group_stats_request 1.3
+type GroupMultipartRequest struct {
+	GroupId uint32
+	Pad     []byte // 4 bytes
+}
+
+func NewGroupMultipartRequest(id uint32) *GroupMultipartRequest {
+	n := new(GroupMultipartRequest)
+	n.GroupId = id
+	n.Pad = make([]byte, 4)
+	return n
+}
+
+func (s *GroupMultipartRequest) Len() (n uint16) {
+	return 8
+}
+
+func (s *GroupMultipartRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	binary.BigEndian.PutUint32(data, s.GroupId)
+	return
+}
+
+func (s *GroupMultipartRequest) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full GroupMultipartRequest message")
+	}
+	s.GroupId = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+// ofp_group_stats 1.3
+type GroupStats struct {
+	Length       uint16
+	Pad          []byte // 2 bytes
+	GroupId      uint32
+	RefCount     uint32
+	Pad2         []byte // 4 bytes
+	PacketCount  uint64
+	ByteCount    uint64
+	DurationSec  uint32
+	DurationNSec uint32
+	Stats        []BucketCounter
+}
+
+func NewGroupStats() *GroupStats {
+	n := new(GroupStats)
+	n.Pad = make([]byte, 2)
+	n.Pad2 = make([]byte, 4)
+	return n
+}
+
+func (g *GroupStats) Len() (n uint16) {
+	n = 40
+	for _, s := range g.Stats {
+		n += s.Len()
+	}
+	return
+}
+
+func (g *GroupStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 40)
+	n := 0
+
+	g.Length = g.Len()
+	binary.BigEndian.PutUint16(data[n:], g.Length)
+	n += 2
+	n += 2 // Pad
+	binary.BigEndian.PutUint32(data[n:], g.GroupId)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], g.RefCount)
+	n += 4
+	n += 4 // Pad2
+	binary.BigEndian.PutUint64(data[n:], g.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], g.ByteCount)
+	n += 8
+	binary.BigEndian.PutUint32(data[n:], g.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], g.DurationNSec)
+	n += 4
+
+	for _, s := range g.Stats {
+		var b []byte
+		b, err = s.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (g *GroupStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 40 {
+		return errors.New("the []byte is too short to unmarshal a full GroupStats message")
+	}
+	var n uint16
+	g.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 2 // Pad
+	g.GroupId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	g.RefCount = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	n += 4 // Pad2
+	g.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	g.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	g.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	g.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	if len(data) < int(g.Length) {
+		return errors.New("the []byte is too short to unmarshal GroupStats's Stats")
+	}
+	for n < g.Length {
+		b := new(BucketCounter)
+		if err := b.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		g.Stats = append(g.Stats, *b)
+		n += b.Len()
+	}
+	return nil
+}
+
+// ofp_bucket_counter 1.3
+type BucketCounter struct {
+	PacketCount uint64
+	ByteCount   uint64
+}
+
+func (b *BucketCounter) Len() uint16 {
+	return 16
+}
+
+func (b *BucketCounter) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, b.Len())
+	n := 0
+	binary.BigEndian.PutUint64(data[n:], b.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], b.ByteCount)
+	return
+}
+
+func (b *BucketCounter) UnmarshalBinary(data []byte) error {
+	if len(data) < int(b.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full BucketCounter message")
+	}
+	n := 0
+	b.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	b.ByteCount = binary.BigEndian.Uint64(data[n:])
+	return nil
+}
+
+// ofp_group_desc_stats 1.3
+type GroupDesc struct {
+	Length  uint16
+	Type    uint8
+	Pad     uint8
+	GroupId uint32
+	Buckets []Bucket
+}
+
+func NewGroupDesc() *GroupDesc {
+	return new(GroupDesc)
+}
+
+// Add a bucket to group desc
+func (g *GroupDesc) AddBucket(bkt Bucket) {
+	g.Buckets = append(g.Buckets, bkt)
+}
+
+func (g *GroupDesc) Len() uint16 {
+	var n uint16 = 8
+	for _, b := range g.Buckets {
+		n += b.Len()
+	}
+	return n
+}
+
+func (g *GroupDesc) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 8)
+	n := 0
+
+	g.Length = g.Len()
+	binary.BigEndian.PutUint16(data[n:], g.Length)
+	n += 2
+	data[n] = g.Type
+	n++
+	n++ // Pad
+	binary.BigEndian.PutUint32(data[n:], g.GroupId)
+	n += 4
+
+	for _, bkt := range g.Buckets {
+		var b []byte
+		b, err = bkt.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (g *GroupDesc) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("the []byte is too short to unmarshal a full GroupDesc message")
+	}
+	var n uint16
+	g.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	g.Type = data[n]
+	n++
+	n++ // Pad
+	g.GroupId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	if len(data) < int(g.Length) {
+		return errors.New("the []byte is too short to unmarshal GroupDesc's Buckets")
+	}
+	for n < g.Length {
+		b := new(Bucket)
+		if err := b.UnmarshalBinary(data[n:g.Length]); err != nil {
+			return err
+		}
+		g.Buckets = append(g.Buckets, *b)
+		n += b.Length
+	}
+	return nil
+}
+
+// ofp_group_capabilities 1.3
+const (
+	GFC_SELECT_WEIGHT   = 1 << 0 /* Support weight for select groups */
+	GFC_SELECT_LIVENESS = 1 << 1 /* Support liveness for select groups */
+	GFC_CHAINING        = 1 << 2 /* Support chaining groups */
+	GFC_CHAINING_CHECKS = 1 << 3 /* Check chaining for loops and delete */
+)
+
+// ofp_group_features 1.3
+type GroupFeatures struct {
+	Types        uint32
+	Capabilities uint32
+	MaxGroups    []uint32 // size 4
+	Actions      []uint32 // size 4
+}
+
+func NewGroupFeatures() *GroupFeatures {
+	n := new(GroupFeatures)
+	n.MaxGroups = make([]uint32, 4)
+	n.Actions = make([]uint32, 4)
+	return n
+}
+
+func (g *GroupFeatures) Len() uint16 {
+	return 40
+}
+
+func (g *GroupFeatures) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, g.Len())
+	n := 0
+
+	binary.BigEndian.PutUint32(data[n:], g.Types)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], g.Capabilities)
+	n += 4
+	for i := 0; i < 4 && i < len(g.MaxGroups); i++ {
+		binary.BigEndian.PutUint32(data[n+i*4:], g.MaxGroups[i])
+	}
+	n += 16
+	for i := 0; i < 4 && i < len(g.Actions); i++ {
+		binary.BigEndian.PutUint32(data[n+i*4:], g.Actions[i])
+	}
+	return
+}
+
+func (g *GroupFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < int(g.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full GroupFeatures message")
+	}
+	n := 0
+
+	g.Types = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	g.Capabilities = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	g.MaxGroups = make([]uint32, 4)
+	for i := 0; i < 4; i++ {
+		g.MaxGroups[i] = binary.BigEndian.Uint32(data[n:])
+		n += 4
+	}
+	g.Actions = make([]uint32, 4)
+	for i := 0; i < 4; i++ {
+		g.Actions[i] = binary.BigEndian.Uint32(data[n:])
+		n += 4
+	}
+	return nil
+}
+
+// ofp_meter_multipart_request 1.3
+type MeterMultipartRequest struct {
+	MeterId uint32
+	Pad     []byte // 4 bytes
+}
+
+func NewMeterMultipartRequest(id uint32) *MeterMultipartRequest {
+	n := new(MeterMultipartRequest)
+	n.Pad = make([]byte, 4)
+	n.MeterId = id
+	return n
+}
+
+func (m *MeterMultipartRequest) Len() uint16 {
+	return 8
+}
+
+func (m *MeterMultipartRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	binary.BigEndian.PutUint32(data, m.MeterId)
+	return
+}
+
+func (m *MeterMultipartRequest) UnmarshalBinary(data []byte) error {
+	if len(data) < int(m.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full MeterMultipartRequest message")
+	}
+	m.MeterId = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+// ofp_meter_stats 1.3
+type MeterStats struct {
+	MeterId       uint32
+	Length        uint16
+	Pad           []byte // 6 bytes
+	FlowCount     uint32
+	PacketInCount uint64
+	ByteInCount   uint64
+	DurationSec   uint32
+	DurationNSec  uint32
+	BandStats     []MeterBandStats
+}
+
+func NewMeterStats(id uint32) *MeterStats {
+	n := new(MeterStats)
+	n.Pad = make([]byte, 6)
+	n.MeterId = id
+	return n
+}
+
+func (m *MeterStats) AddBandStats(s MeterBandStats) {
+	m.BandStats = append(m.BandStats, s)
+}
+
+func (m *MeterStats) Len() uint16 {
+	var n uint16 = 40
+	for _, b := range m.BandStats {
+		n += b.Len()
+	}
+	return n
+}
+
+func (m *MeterStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 40)
+	n := 0
+
+	m.Length = m.Len()
+	binary.BigEndian.PutUint32(data[n:], m.MeterId)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], m.Length)
+	n += 2
+	n += 6 // Pad
+	binary.BigEndian.PutUint32(data[n:], m.FlowCount)
+	n += 4
+	binary.BigEndian.PutUint64(data[n:], m.PacketInCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], m.ByteInCount)
+	n += 8
+	binary.BigEndian.PutUint32(data[n:], m.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], m.DurationNSec)
+	n += 4
+
+	for _, s := range m.BandStats {
+		var b []byte
+		b, err = s.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (m *MeterStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 40 {
+		return errors.New("the []byte is too short to unmarshal a full MeterStats message")
+	}
+	var n uint16
+	m.MeterId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 6 // Pad
+	m.FlowCount = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.PacketInCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	m.ByteInCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	m.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	if len(data) < int(m.Length) {
+		return errors.New("the []byte is too short to unmarshal MeterStats's BandStats")
+	}
+	for n < m.Length {
+		s := new(MeterBandStats)
+		if err := s.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		m.BandStats = append(m.BandStats, *s)
+		n += s.Len()
+	}
+	return nil
+}
+
+// ofp_meter_band_stats 1.3
+type MeterBandStats struct {
+	PacketBandCount uint64
+	ByteBandCount   uint64
+}
+
+func (m *MeterBandStats) Len() uint16 {
+	return 16
+}
+
+func (m *MeterBandStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	n := 0
+	binary.BigEndian.PutUint64(data[n:], m.PacketBandCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], m.ByteBandCount)
+	return
+}
+
+func (m *MeterBandStats) UnmarshalBinary(data []byte) error {
+	if len(data) < int(m.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full MeterBandStats message")
+	}
+	n := 0
+	m.PacketBandCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	m.ByteBandCount = binary.BigEndian.Uint64(data[n:])
+	return nil
+}
+
+// ofp_meter_config 1.3, it is named MeterDesc as in OpenFlow 1.5.
+type MeterDesc struct {
+	Length  uint16
+	Flags   uint16
+	MeterId uint32
+	Bands   []util.Message // MeterBandDrop, MeterBandDSCP or MeterBandExperimenter
+}
+
+func NewMeterDesc(id uint32) *MeterDesc {
+	n := new(MeterDesc)
+	n.MeterId = id
+	return n
+}
+
+func (m *MeterDesc) AddBand(b util.Message) {
+	m.Bands = append(m.Bands, b)
+}
+
+func (m *MeterDesc) Len() uint16 {
+	var n uint16 = 8
+	for _, b := range m.Bands {
+		n += b.Len()
+	}
+	return n
+}
+
+func (m *MeterDesc) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 8)
+	n := 0
+
+	m.Length = m.Len()
+	binary.BigEndian.PutUint16(data[n:], m.Length)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], m.Flags)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], m.MeterId)
+	n += 4
+
+	for _, band := range m.Bands {
+		var b []byte
+		b, err = band.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (m *MeterDesc) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("the []byte is too short to unmarshal a full MeterDesc message")
+	}
+	var n uint16
+	m.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.MeterId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	if len(data) < int(m.Length) {
+		return errors.New("the []byte is too short to unmarshal MeterDesc's Bands")
+	}
+	for n+METER_BAND_LEN <= m.Length {
+		var band util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case OFPMBT13_DROP:
+			band = new(MeterBandDrop)
+		case OFPMBT13_DSCP_REMARK:
+			band = new(MeterBandDSCP)
+		case OFPMBT13_EXPERIMENTER:
+			band = new(MeterBandExperimenter)
+		default:
+			return fmt.Errorf("unknown meter band type: %d", binary.BigEndian.Uint16(data[n:]))
+		}
+		if err := band.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		m.Bands = append(m.Bands, band)
+		n += band.Len()
+	}
+	return nil
+}
+
+// ofp_meter_features 1.3
+type MeterFeatures struct {
+	MaxMeter     uint32
+	BandTypes    uint32
+	Capabilities uint32
+	MaxBands     uint8
+	MaxColor     uint8
+	Pad          []byte // 2 bytes
+}
+
+func NewMeterFeatures() *MeterFeatures {
+	n := new(MeterFeatures)
+	n.Pad = make([]byte, 2)
+	return n
+}
+
+func (m *MeterFeatures) Len() uint16 {
+	return 16
+}
+
+func (m *MeterFeatures) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	n := 0
+
+	binary.BigEndian.PutUint32(data[n:], m.MaxMeter)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], m.BandTypes)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], m.Capabilities)
+	n += 4
+	data[n] = m.MaxBands
+	n++
+	data[n] = m.MaxColor
+	return
+}
+
+func (m *MeterFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < int(m.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full MeterFeatures message")
+	}
+	n := 0
+
+	m.MaxMeter = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.BandTypes = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.Capabilities = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.MaxBands = data[n]
+	n++
+	m.MaxColor = data[n]
+	return nil
+}
+
 // ofp_port_status
 type PortStatus struct {
 	common.Header
@@ -1433,7 +2080,7 @@ func (f *OFPTableFeatures) UnmarshalBinary(data []byte) error {
 		case OFPTFPT13_EXPERIMENTER:
 			fallthrough
 		case OFPTFPT13_EXPERIMENTER_MISS:
-			p = new(TableExperimenterProperty)
+			p = newExperimenterProp(data[n:], new(TableExperimenterProperty))
 		}
 		err := p.UnmarshalBinary(data[n:])
 		if err != nil {
@@ -136,3 +136,119 @@ func ofPTableFeaturesEquals(f, df *OFPTableFeatures) bool {
 	}
 	return true
 }
+
+func TestGroupMeterPortDescMultipart(t *testing.T) {
+	groupStats := NewGroupStats()
+	groupStats.GroupId = 10
+	groupStats.RefCount = 2
+	groupStats.PacketCount = 100
+	groupStats.ByteCount = 6400
+	groupStats.Stats = []BucketCounter{{PacketCount: 60, ByteCount: 3840}, {PacketCount: 40, ByteCount: 2560}}
+
+	bucket := NewBucket()
+	bucket.Weight = 100
+	bucket.AddAction(NewActionOutput(1))
+	groupDesc := NewGroupDesc()
+	groupDesc.Type = OFPGT_SELECT
+	groupDesc.GroupId = 10
+	groupDesc.AddBucket(*bucket)
+
+	groupFeatures := NewGroupFeatures()
+	groupFeatures.Types = 1<<OFPGT_ALL | 1<<OFPGT_SELECT
+	groupFeatures.Capabilities = GFC_SELECT_WEIGHT | GFC_CHAINING
+	groupFeatures.MaxGroups[OFPGT_SELECT] = 1024
+
+	meterStats := NewMeterStats(5)
+	meterStats.FlowCount = 3
+	meterStats.PacketInCount = 1000
+	meterStats.AddBandStats(MeterBandStats{PacketBandCount: 10, ByteBandCount: 640})
+
+	meterDesc := NewMeterDesc(5)
+	meterDesc.Flags = OFPMF13_PKTPS | OFPMF13_STATS
+	meterDesc.AddBand(&MeterBandDrop{MeterBandHeader{Type: OFPMBT13_DROP, Length: METER_BAND_LEN, Rate: 100}})
+	meterDesc.AddBand(&MeterBandDSCP{MeterBandHeader: MeterBandHeader{Type: OFPMBT13_DSCP_REMARK, Length: METER_BAND_LEN, Rate: 200}, PrecLevel: 1})
+
+	meterFeatures := NewMeterFeatures()
+	meterFeatures.MaxMeter = 200000
+	meterFeatures.BandTypes = 1<<OFPMBT13_DROP | 1<<OFPMBT13_DSCP_REMARK
+	meterFeatures.MaxBands = 1
+
+	port := NewPhyPort()
+	port.PortNo = 1
+	port.HWAddr = []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	copy(port.Name, "br-int")
+
+	for _, tc := range []struct {
+		name   string
+		mpType uint16
+		bodies []util.Message
+	}{
+		{name: "Group", mpType: MultipartType_Group, bodies: []util.Message{groupStats}},
+		{name: "GroupDesc", mpType: MultipartType_GroupDesc, bodies: []util.Message{groupDesc, groupDesc}},
+		{name: "GroupFeatures", mpType: MultipartType_GroupFeatures, bodies: []util.Message{groupFeatures}},
+		{name: "Meter", mpType: MultipartType_Meter, bodies: []util.Message{meterStats}},
+		{name: "MeterConfig", mpType: MultipartType_MeterConfig, bodies: []util.Message{meterDesc}},
+		{name: "MeterFeatures", mpType: MultipartType_MeterFeatures, bodies: []util.Message{meterFeatures}},
+		{name: "PortDesc", mpType: MultipartType_PortDesc, bodies: []util.Message{port, port}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reply := &MultipartReply{
+				Header: NewOfp13Header(),
+				Type:   tc.mpType,
+				Body:   tc.bodies,
+			}
+			reply.Header.Type = Type_MultiPartReply
+			data, err := reply.MarshalBinary()
+			require.NoError(t, err)
+			msg, err := Parse(data)
+			require.NoError(t, err)
+			newReply, ok := msg.(*MultipartReply)
+			require.True(t, ok)
+			require.Len(t, newReply.Body, len(tc.bodies))
+			newData, err := newReply.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, data, newData)
+		})
+	}
+
+	newGroupStats := new(GroupStats)
+	data, _ := groupStats.MarshalBinary()
+	require.NoError(t, newGroupStats.UnmarshalBinary(data))
+	assert.Equal(t, uint32(10), newGroupStats.GroupId)
+	assert.Equal(t, groupStats.Stats, newGroupStats.Stats)
+	newGroupDesc := new(GroupDesc)
+	data, _ = groupDesc.MarshalBinary()
+	require.NoError(t, newGroupDesc.UnmarshalBinary(data))
+	require.Len(t, newGroupDesc.Buckets, 1)
+	assert.Equal(t, uint16(100), newGroupDesc.Buckets[0].Weight)
+	assert.IsType(t, new(ActionOutput), newGroupDesc.Buckets[0].Actions[0])
+	newMeterDesc := new(MeterDesc)
+	data, _ = meterDesc.MarshalBinary()
+	require.NoError(t, newMeterDesc.UnmarshalBinary(data))
+	require.Len(t, newMeterDesc.Bands, 2)
+	assert.Equal(t, uint8(1), newMeterDesc.Bands[1].(*MeterBandDSCP).PrecLevel)
+
+	for _, tc := range []struct {
+		mpType uint16
+		body   util.Message
+	}{
+		{mpType: MultipartType_Group, body: NewGroupMultipartRequest(OFPG_ALL)},
+		{mpType: MultipartType_Meter, body: NewMeterMultipartRequest(OFPM13_ALL)},
+		{mpType: MultipartType_MeterConfig, body: NewMeterMultipartRequest(5)},
+	} {
+		request := &MultipartRequest{
+			Header: NewOfp13Header(),
+			Type:   tc.mpType,
+			Body:   []util.Message{tc.body},
+		}
+		request.Header.Type = Type_MultiPartRequest
+		data, err := request.MarshalBinary()
+		require.NoError(t, err)
+		newRequest := new(MultipartRequest)
+		require.NoError(t, newRequest.UnmarshalBinary(data))
+		require.Len(t, newRequest.Body, 1)
+		newData, _ := newRequest.Body[0].MarshalBinary()
+		data, _ = tc.body.MarshalBinary()
+		assert.Equal(t, data, newData)
+	}
+}
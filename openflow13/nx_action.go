@@ -3,7 +3,10 @@ package openflow13
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net"
+
+	"antrea.io/libOpenflow/util"
 )
 
 // NX Action constants
@@ -23,8 +26,8 @@ const (
 	NXAST_REG_LOAD         = 7  // Nicira extended action: load:data->dstField[m..n]
 	NXAST_NOTE             = 8  // Nicira extended action: note
 	NXAST_SET_TUNNEL_V6    = 9  // Nicira extended action: set_tunnel64
-	NXAST_MULTIPATH        = 10 // Nicira extended action: set_tunnel
-	NXAST_AUTOPATH         = 11 // Nicira extended action: multipath
+	NXAST_MULTIPATH        = 10 // Nicira extended action: multipath
+	NXAST_AUTOPATH         = 11 // Nicira extended action: autopath
 	NXAST_BUNDLE           = 12 // Nicira extended action: bundle
 	NXAST_BUNDLE_LOAD      = 13 // Nicira extended action: bundle_load
 	NXAST_RESUBMIT_TABLE   = 14 // Nicira extended action: resubmit(port, table)
@@ -110,28 +113,35 @@ func NewNxActionHeader(subtype uint16) *NXActionHeader {
 	return &NXActionHeader{ActionHeader: actionHeader, Vendor: NxExperimenterID, Subtype: subtype}
 }
 
-func DecodeNxAction(data []byte) Action {
+func DecodeNxAction(data []byte) (Action, error) {
 	var a Action
+	if len(data) < 10 {
+		return nil, errors.New("data too short to decode NxAction")
+	}
 	// Previous 8 bytes in the data includes type(2 byte), length(2 byte), and vendor(4 byte)
 	subtype := binary.BigEndian.Uint16(data[8:])
 	switch subtype {
 	case NXAST_RESUBMIT:
 		a = new(NXActionResubmit)
-	case NXAST_SET_TUNNEL:
+	case NXAST_SET_TUNNEL, NXAST_SET_TUNNEL_V6:
+		a = new(NXActionSetTunnel)
 	case NXAST_DROP_SPOOFED_ARP:
+		a = new(NXActionDropSpoofedARP)
 	case NXAST_SET_QUEUE:
+		a = new(NXActionSetQueue)
 	case NXAST_POP_QUEUE:
+		a = new(NXActionPopQueue)
 	case NXAST_REG_MOVE:
 		a = new(NXActionRegMove)
 	case NXAST_REG_LOAD:
 		a = new(NXActionRegLoad)
 	case NXAST_NOTE:
 		a = new(NXActionNote)
-	case NXAST_SET_TUNNEL_V6:
 	case NXAST_MULTIPATH:
+		a = new(NXActionMultipath)
 	case NXAST_AUTOPATH:
-	case NXAST_BUNDLE:
-	case NXAST_BUNDLE_LOAD:
+	case NXAST_BUNDLE, NXAST_BUNDLE_LOAD:
+		a = new(NXActionBundle)
 	case NXAST_RESUBMIT_TABLE:
 		a = new(NXActionResubmitTable)
 	case NXAST_OUTPUT_REG:
@@ -139,22 +149,33 @@ func DecodeNxAction(data []byte) Action {
 	case NXAST_LEARN:
 		a = new(NXActionLearn)
 	case NXAST_EXIT:
+		a = new(NXActionExit)
 	case NXAST_DEC_TTL:
 		a = new(NXActionDecTTL)
 	case NXAST_FIN_TIMEOUT:
+		a = new(NXActionFinTimeout)
 	case NXAST_CONTROLLER:
 		a = new(NXActionController)
 	case NXAST_DEC_TTL_CNT_IDS:
 		a = new(NXActionDecTTLCntIDs)
 	case NXAST_PUSH_MPLS:
+		a = new(NXActionPushMPLS)
 	case NXAST_POP_MPLS:
+		a = new(NXActionPopMPLS)
 	case NXAST_SET_MPLS_TTL:
+		a = new(NXActionSetMPLSTTL)
 	case NXAST_DEC_MPLS_TTL:
+		a = new(NXActionDecMPLSTTL)
 	case NXAST_STACK_PUSH:
+		a = new(NXActionStackPush)
 	case NXAST_STACK_POP:
+		a = new(NXActionStackPop)
 	case NXAST_SAMPLE:
+		a = new(NXActionSample)
 	case NXAST_SET_MPLS_LABEL:
+		a = new(NXActionSetMPLSLabel)
 	case NXAST_SET_MPLS_TC:
+		a = new(NXActionSetMPLSTC)
 	case NXAST_OUTPUT_REG2:
 		a = new(NXActionOutputReg)
 	case NXAST_REG_LOAD2:
@@ -168,16 +189,27 @@ func DecodeNxAction(data []byte) Action {
 	case NXAST_CONTROLLER2:
 		a = new(NXActionController2)
 	case NXAST_SAMPLE2:
+		a = new(NXActionSample)
 	case NXAST_OUTPUT_TRUNC:
+		a = new(NXActionOutputTrunc)
 	case NXAST_CT_CLEAR:
+		a = new(NXActionCTClear)
 	case NXAST_CT_RESUBMIT:
 		a = new(NXActionResubmitTable)
 		a.(*NXActionResubmitTable).withCT = true
 	case NXAST_RAW_ENCAP:
+		a = new(NXActionEncap)
 	case NXAST_RAW_DECAP:
+		a = new(NXActionDecap)
 	case NXAST_DEC_NSH_TTL:
+		a = new(NXActionDecNshTTL)
+	default:
+		return nil, fmt.Errorf("unknown NXActionHeader subtype: %v", subtype)
 	}
-	return a
+	if a == nil {
+		return nil, fmt.Errorf("unimplemented NXActionHeader subtype: %v", subtype)
+	}
+	return a, nil
 }
 
 // NXActionConjunction is NX action to configure conjunctive match flows.
@@ -363,6 +395,8 @@ func NewNXActionConnTrack() *NXActionConnTrack {
 }
 
 // NXActionRegLoad is NX action to load data to a specified field.
+// The ofs_nbits encoding limits the loaded data to 64 bits, use NXActionRegLoad2 to load a wider field, e.g., an
+// xxreg.
 type NXActionRegLoad struct {
 	*NXActionHeader
 	OfsNbits uint16
@@ -1917,3 +1951,1386 @@ func NewNXActionController2() *NXActionController2 {
 	a.NXActionHeader = NewNxActionHeader(NXAST_CONTROLLER2)
 	return a
 }
+
+// NXActionMultipath is NX action to hash the packet fields and store the selected link number in
+// a specified field.
+type NXActionMultipath struct {
+	*NXActionHeader
+	Fields    uint16 // One of NX_HASH_FIELDS_*
+	Basis     uint16 // Universal hash parameter
+	pad       [2]byte
+	Algorithm uint16 // One of NX_MP_ALG_*
+	MaxLink   uint16 // Number of output links minus 1
+	Arg       uint32 // Algorithm-specific argument
+	pad2      [2]byte
+	OfsNbits  uint16
+	DstField  *MatchField
+}
+
+func NewNXActionMultipath(fields, basis, algorithm, maxLink uint16, arg uint32, dstField *MatchField, dstRange *NXRange) *NXActionMultipath {
+	a := new(NXActionMultipath)
+	a.NXActionHeader = NewNxActionHeader(NXAST_MULTIPATH)
+	a.Length = a.NXActionHeader.Len() + 22
+	a.Fields = fields
+	a.Basis = basis
+	a.Algorithm = algorithm
+	a.MaxLink = maxLink
+	a.Arg = arg
+	a.OfsNbits = dstRange.ToOfsBits()
+	a.DstField = dstField
+	return a
+}
+
+func (a *NXActionMultipath) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionMultipath) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.Fields)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.Basis)
+	n += 2
+	// Skip padding bytes
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.Algorithm)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.MaxLink)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.Arg)
+	n += 4
+	// Skip padding bytes
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.OfsNbits)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.DstField.MarshalHeader())
+	return
+}
+
+func (a *NXActionMultipath) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || a.Len() < a.NXActionHeader.Len()+22 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionMultipath message")
+	}
+	a.Fields = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Basis = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	n += 2
+	a.Algorithm = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.MaxLink = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Arg = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	n += 2
+	a.OfsNbits = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.DstField = new(MatchField)
+	if err := a.DstField.UnmarshalHeader(data[n : n+4]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NXActionBundle is NX action to select a member port using the bundle algorithms. It is used for
+// both bundle action, which outputs the packet to the selected member, and bundle_load action, which
+// stores the selected member in a specified field. Members were named slaves in older OVS versions.
+type NXActionBundle struct {
+	*NXActionHeader
+	Algorithm  uint16 // One of NX_BD_ALG_*
+	Fields     uint16 // One of NX_HASH_FIELDS_*
+	Basis      uint16 // Universal hash parameter
+	MemberType *MatchField
+	OfsNbits   uint16      // Only used by bundle_load action
+	DstField   *MatchField // Only used by bundle_load action
+	zero       [4]byte
+	Members    []uint16
+}
+
+// NewNXActionBundle creates a bundle action which outputs the packet to one of the members.
+func NewNXActionBundle(algorithm, fields, basis uint16, members ...uint16) *NXActionBundle {
+	a := new(NXActionBundle)
+	a.NXActionHeader = NewNxActionHeader(NXAST_BUNDLE)
+	a.Algorithm = algorithm
+	a.Fields = fields
+	a.Basis = basis
+	a.MemberType, _ = FindFieldHeaderByName("NXM_OF_IN_PORT", false)
+	a.Members = members
+	a.Length = a.Len()
+	return a
+}
+
+// NewNXActionBundleLoad creates a bundle_load action which stores the selected member in dstField.
+func NewNXActionBundleLoad(algorithm, fields, basis uint16, dstField *MatchField, dstRange *NXRange, members ...uint16) *NXActionBundle {
+	a := NewNXActionBundle(algorithm, fields, basis, members...)
+	a.Subtype = NXAST_BUNDLE_LOAD
+	a.OfsNbits = dstRange.ToOfsBits()
+	a.DstField = dstField
+	return a
+}
+
+func (a *NXActionBundle) Len() (n uint16) {
+	length := a.NXActionHeader.Len() + 22 + uint16(2*len(a.Members))
+	return 8 * ((length + 7) / 8)
+}
+
+func (a *NXActionBundle) MarshalBinary() (data []byte, err error) {
+	a.Length = a.Len()
+	data = make([]byte, int(a.Length))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.Algorithm)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.Fields)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.Basis)
+	n += 2
+	if a.MemberType != nil {
+		binary.BigEndian.PutUint32(data[n:], a.MemberType.MarshalHeader())
+	}
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], uint16(len(a.Members)))
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.OfsNbits)
+	n += 2
+	if a.DstField != nil {
+		binary.BigEndian.PutUint32(data[n:], a.DstField.MarshalHeader())
+	}
+	n += 4
+	// Skip zero bytes
+	n += 4
+	for _, member := range a.Members {
+		binary.BigEndian.PutUint16(data[n:], member)
+		n += 2
+	}
+	return
+}
+
+func (a *NXActionBundle) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Length) || a.Length < a.NXActionHeader.Len()+22 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionBundle message")
+	}
+	a.Algorithm = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Fields = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Basis = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.MemberType = new(MatchField)
+	if err := a.MemberType.UnmarshalHeader(data[n : n+4]); err != nil {
+		return err
+	}
+	n += 4
+	nMembers := int(binary.BigEndian.Uint16(data[n:]))
+	n += 2
+	a.OfsNbits = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.DstField = nil
+	if binary.BigEndian.Uint32(data[n:]) != 0 {
+		a.DstField = new(MatchField)
+		if err := a.DstField.UnmarshalHeader(data[n : n+4]); err != nil {
+			return err
+		}
+	}
+	n += 4
+	n += 4
+	if n+2*nMembers > int(a.Length) {
+		return errors.New("the []byte is too short to unmarshal all members of NXActionBundle")
+	}
+	a.Members = make([]uint16, 0, nMembers)
+	for i := 0; i < nMembers; i++ {
+		a.Members = append(a.Members, binary.BigEndian.Uint16(data[n:]))
+		n += 2
+	}
+	return nil
+}
+
+// NXActionSample is NX action to sample packets and send them to the IPFIX collectors configured
+// in the specified collector set. It is used for both sample action and sample2 action, which also
+// supports the sampling port and the direction.
+type NXActionSample struct {
+	*NXActionHeader
+	Probability    uint16 // Number of packets sampled out of UINT16_MAX
+	CollectorSetID uint32
+	ObsDomainID    uint32
+	ObsPointID     uint32
+	SamplingPort   uint16 // Only used by sample2 action
+	Direction      uint8  // One of NX_ACTION_SAMPLE_*, only used by sample2 action
+	pad            [5]byte
+}
+
+// NewNXActionSample creates a sample action.
+func NewNXActionSample(probability uint16, collectorSetID, obsDomainID, obsPointID uint32) *NXActionSample {
+	a := new(NXActionSample)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SAMPLE)
+	a.Length = a.NXActionHeader.Len() + 14
+	a.Probability = probability
+	a.CollectorSetID = collectorSetID
+	a.ObsDomainID = obsDomainID
+	a.ObsPointID = obsPointID
+	return a
+}
+
+// NewNXActionSample2 creates a sample2 action with the sampling port and the direction.
+func NewNXActionSample2(probability uint16, collectorSetID, obsDomainID, obsPointID uint32, samplingPort uint16, direction uint8) *NXActionSample {
+	a := NewNXActionSample(probability, collectorSetID, obsDomainID, obsPointID)
+	a.Subtype = NXAST_SAMPLE2
+	a.Length = a.NXActionHeader.Len() + 22
+	a.SamplingPort = samplingPort
+	a.Direction = direction
+	return a
+}
+
+func (a *NXActionSample) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSample) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.Probability)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.CollectorSetID)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], a.ObsDomainID)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], a.ObsPointID)
+	n += 4
+	if a.Subtype == NXAST_SAMPLE2 {
+		binary.BigEndian.PutUint16(data[n:], a.SamplingPort)
+		n += 2
+		data[n] = a.Direction
+	}
+	return
+}
+
+func (a *NXActionSample) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	minLength := a.NXActionHeader.Len() + 14
+	if a.Subtype == NXAST_SAMPLE2 {
+		minLength = a.NXActionHeader.Len() + 22
+	}
+	if len(data) < int(a.Len()) || a.Len() < minLength {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSample message")
+	}
+	a.Probability = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.CollectorSetID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	a.ObsDomainID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	a.ObsPointID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	if a.Subtype == NXAST_SAMPLE2 {
+		a.SamplingPort = binary.BigEndian.Uint16(data[n:])
+		n += 2
+		a.Direction = data[n]
+	}
+	return nil
+}
+
+// NXActionStack is the common part of NX actions to push a field to the stack and pop the stack
+// to a field.
+type NXActionStack struct {
+	*NXActionHeader
+	Offset uint16
+	Field  *MatchField
+	NBits  uint16
+	zero   [6]byte
+}
+
+func newNXActionStack(subtype uint16, fieldName string, rng *NXRange) (*NXActionStack, error) {
+	field, err := FindFieldHeaderByName(fieldName, false)
+	if err != nil {
+		return nil, err
+	}
+	a := new(NXActionStack)
+	a.NXActionHeader = NewNxActionHeader(subtype)
+	a.Length = a.NXActionHeader.Len() + 14
+	a.Offset = rng.GetOfs()
+	a.Field = field
+	a.NBits = rng.GetNbits()
+	return a, nil
+}
+
+func (a *NXActionStack) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionStack) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.Offset)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.Field.MarshalHeader())
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], a.NBits)
+	return
+}
+
+func (a *NXActionStack) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || a.Len() < a.NXActionHeader.Len()+14 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionStack message")
+	}
+	a.Offset = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.Field = new(MatchField)
+	if err := a.Field.UnmarshalHeader(data[n : n+4]); err != nil {
+		return err
+	}
+	n += 4
+	a.NBits = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// NXActionStackPush is NX action to push a field to the register stack.
+type NXActionStackPush struct {
+	NXActionStack
+}
+
+// NewNXActionStackPush creates an action to push the range of the field to the stack.
+func NewNXActionStackPush(fieldName string, rng *NXRange) (*NXActionStackPush, error) {
+	a, err := newNXActionStack(NXAST_STACK_PUSH, fieldName, rng)
+	if err != nil {
+		return nil, err
+	}
+	return &NXActionStackPush{NXActionStack: *a}, nil
+}
+
+// NXActionStackPop is NX action to pop the register stack to a field.
+type NXActionStackPop struct {
+	NXActionStack
+}
+
+// NewNXActionStackPop creates an action to pop the stack to the range of the field.
+func NewNXActionStackPop(fieldName string, rng *NXRange) (*NXActionStackPop, error) {
+	a, err := newNXActionStack(NXAST_STACK_POP, fieldName, rng)
+	if err != nil {
+		return nil, err
+	}
+	return &NXActionStackPop{NXActionStack: *a}, nil
+}
+
+// NX encap/decap property classes and types.
+const (
+	OFPPPC_BASIC = 0 // ONF Basic class
+	OFPPPC_MPLS  = 1 // MPLS property class
+	OFPPPC_GRE   = 2 // GRE property class
+	OFPPPC_GTP   = 3 // GTP property class
+	OFPPPC_NSH   = 4 // NSH property class
+
+	OFPPPT_PROP_NSH_NONE   = 0 // unused
+	OFPPPT_PROP_NSH_MDTYPE = 1 // property MDTYPE in NSH
+	OFPPPT_PROP_NSH_TLV    = 2 // property TLV in NSH
+)
+
+// EDProperty is a property TLV of the encap action.
+type EDProperty interface {
+	EDHeader() *EDPropHeader
+	util.Message
+}
+
+// EDPropHeader is the header of the encap action properties. Length is the length of the property
+// excluding the padding, while the property is padded to a multiple of 8 bytes on the wire.
+type EDPropHeader struct {
+	PropClass uint16
+	Type      uint8
+	Length    uint8
+}
+
+func (h *EDPropHeader) EDHeader() *EDPropHeader {
+	return h
+}
+
+func (h *EDPropHeader) Len() uint16 {
+	return 4
+}
+
+func (h *EDPropHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(h.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], h.PropClass)
+	n += 2
+	data[n] = h.Type
+	n += 1
+	data[n] = h.Length
+	return
+}
+
+func (h *EDPropHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(h.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full EDPropHeader message")
+	}
+	n := 0
+	h.PropClass = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	h.Type = data[n]
+	n += 1
+	h.Length = data[n]
+	return nil
+}
+
+// EDPropNSHMdType is the encap property to set the MD type of the NSH header.
+type EDPropNSHMdType struct {
+	*EDPropHeader
+	MdType uint8
+	pad    [3]uint8
+}
+
+func NewEDPropNSHMdType(mdType uint8) *EDPropNSHMdType {
+	return &EDPropNSHMdType{
+		EDPropHeader: &EDPropHeader{PropClass: OFPPPC_NSH, Type: OFPPPT_PROP_NSH_MDTYPE, Length: 5},
+		MdType:       mdType,
+	}
+}
+
+func (p *EDPropNSHMdType) Len() uint16 {
+	return 8
+}
+
+func (p *EDPropNSHMdType) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	var b []byte
+	n := 0
+	p.EDPropHeader.Length = uint8(p.EDPropHeader.Len() + 1)
+	b, err = p.EDPropHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	data[n] = p.MdType
+	return
+}
+
+func (p *EDPropNSHMdType) UnmarshalBinary(data []byte) error {
+	p.EDPropHeader = new(EDPropHeader)
+	if err := p.EDPropHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.EDPropHeader.Length < 5 {
+		return errors.New("the []byte is too short to unmarshal a full EDPropNSHMdType message")
+	}
+	p.MdType = data[p.EDPropHeader.Len()]
+	return nil
+}
+
+// EDPropNSHTLV is the encap property to add a metadata TLV to the NSH header with MD type 2.
+type EDPropNSHTLV struct {
+	*EDPropHeader
+	TLVClass uint16
+	TLVType  uint8
+	Value    []byte
+}
+
+func NewEDPropNSHTLV(tlvClass uint16, tlvType uint8, value []byte) *EDPropNSHTLV {
+	p := &EDPropNSHTLV{
+		EDPropHeader: &EDPropHeader{PropClass: OFPPPC_NSH, Type: OFPPPT_PROP_NSH_TLV},
+		TLVClass:     tlvClass,
+		TLVType:      tlvType,
+		Value:        value,
+	}
+	p.EDPropHeader.Length = uint8(8 + len(value))
+	return p
+}
+
+func (p *EDPropNSHTLV) Len() uint16 {
+	n := p.EDPropHeader.Len() + 4 + uint16(len(p.Value))
+	// Round it to closest multiple of 8
+	return ((n + 7) / 8) * 8
+}
+
+func (p *EDPropNSHTLV) MarshalBinary() (data []byte, err error) {
+	if len(p.Value) > 127 {
+		return nil, fmt.Errorf("the NSH TLV value length %d exceeds 127 bytes", len(p.Value))
+	}
+	data = make([]byte, int(p.Len()))
+	var b []byte
+	n := 0
+	p.EDPropHeader.Length = uint8(p.EDPropHeader.Len() + 4 + uint16(len(p.Value)))
+	b, err = p.EDPropHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], p.TLVClass)
+	n += 2
+	data[n] = p.TLVType
+	n += 1
+	data[n] = uint8(len(p.Value))
+	n += 1
+	copy(data[n:], p.Value)
+	return
+}
+
+func (p *EDPropNSHTLV) UnmarshalBinary(data []byte) error {
+	p.EDPropHeader = new(EDPropHeader)
+	if err := p.EDPropHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := int(p.EDPropHeader.Len())
+	if len(data) < n+4 {
+		return errors.New("the []byte is too short to unmarshal a full EDPropNSHTLV message")
+	}
+	p.TLVClass = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.TLVType = data[n]
+	n += 1
+	valueLen := int(data[n])
+	n += 1
+	if len(data) < n+valueLen || int(p.EDPropHeader.Length) < n+valueLen {
+		return errors.New("the []byte is too short to unmarshal a full EDPropNSHTLV message")
+	}
+	p.Value = make([]byte, valueLen)
+	copy(p.Value, data[n:n+valueLen])
+	return nil
+}
+
+// DecodeEDProp decodes a property of the encap action.
+func DecodeEDProp(data []byte) (EDProperty, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to decode an EDProperty")
+	}
+	propClass := binary.BigEndian.Uint16(data)
+	propType := data[2]
+	var p EDProperty
+	switch {
+	case propClass == OFPPPC_NSH && propType == OFPPPT_PROP_NSH_MDTYPE:
+		p = new(EDPropNSHMdType)
+	case propClass == OFPPPC_NSH && propType == OFPPPT_PROP_NSH_TLV:
+		p = new(EDPropNSHTLV)
+	default:
+		return nil, fmt.Errorf("unknown EDProperty class %d and type %d", propClass, propType)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NXActionEncap is NX action to push a new header to the packet and change the packet type. The
+// context headers of the NSH header with MD type 1 are set with the NSH context fields after the
+// encap action.
+type NXActionEncap struct {
+	*NXActionHeader
+	HdrSize    uint16 // Header size in bytes, 0 means not specified
+	PacketType uint32 // Packet type of the new header, see PT_*
+	Props      []EDProperty
+}
+
+// NewNXActionEncap creates an encap action to push a header of the packetType, e.g., PT_NSH or
+// PT_ETH.
+func NewNXActionEncap(packetType uint32, props ...EDProperty) *NXActionEncap {
+	a := new(NXActionEncap)
+	a.NXActionHeader = NewNxActionHeader(NXAST_RAW_ENCAP)
+	a.PacketType = packetType
+	a.Props = props
+	a.Length = a.Len()
+	return a
+}
+
+// NewNXActionEncapNSH creates an encap action to push a NSH header with the MD type and the TLVs,
+// which are only used with MD type 2.
+func NewNXActionEncapNSH(mdType uint8, tlvs ...*EDPropNSHTLV) *NXActionEncap {
+	props := []EDProperty{NewEDPropNSHMdType(mdType)}
+	for _, tlv := range tlvs {
+		props = append(props, tlv)
+	}
+	return NewNXActionEncap(PT_NSH, props...)
+}
+
+func (a *NXActionEncap) Len() (n uint16) {
+	n = a.NXActionHeader.Len() + 6
+	for _, p := range a.Props {
+		n += p.Len()
+	}
+	return
+}
+
+func (a *NXActionEncap) MarshalBinary() (data []byte, err error) {
+	a.Length = a.Len()
+	data = make([]byte, int(a.Length))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.HdrSize)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.PacketType)
+	n += 4
+	for _, p := range a.Props {
+		if b, err = p.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (a *NXActionEncap) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Length) || a.Length < a.NXActionHeader.Len()+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionEncap message")
+	}
+	a.HdrSize = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.PacketType = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	a.Props = nil
+	for n < int(a.Length) {
+		p, err := DecodeEDProp(data[n:a.Length])
+		if err != nil {
+			return err
+		}
+		a.Props = append(a.Props, p)
+		n += int(p.Len())
+	}
+	return nil
+}
+
+// NXActionDecap is NX action to pop the outermost header of the packet and change the packet type.
+type NXActionDecap struct {
+	*NXActionHeader
+	pad        [2]byte
+	PacketType uint32 // New packet type, PT_USE_NEXT_PROTO to use the next protocol of the header
+}
+
+// NewNXActionDecap creates a decap action, the new packet type is decided by the next protocol of
+// the removed header if packetType is PT_USE_NEXT_PROTO.
+func NewNXActionDecap(packetType uint32) *NXActionDecap {
+	a := new(NXActionDecap)
+	a.NXActionHeader = NewNxActionHeader(NXAST_RAW_DECAP)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.PacketType = packetType
+	return a
+}
+
+func (a *NXActionDecap) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionDecap) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	// Skip padding bytes
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.PacketType)
+	return
+}
+
+func (a *NXActionDecap) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || a.Len() < a.NXActionHeader.Len()+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionDecap message")
+	}
+	n += 2
+	a.PacketType = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// NXActionDecNshTTL is NX action to decrement the TTL of the NSH header.
+type NXActionDecNshTTL struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionDecNshTTL() *NXActionDecNshTTL {
+	a := new(NXActionDecNshTTL)
+	a.NXActionHeader = NewNxActionHeader(NXAST_DEC_NSH_TTL)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionDecNshTTL) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionDecNshTTL) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionDecNshTTL) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionDecNshTTL message")
+	}
+	return nil
+}
+
+// NXActionExit is NX action to stop the execution of the action set and the pipeline.
+type NXActionExit struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionExit() *NXActionExit {
+	a := new(NXActionExit)
+	a.NXActionHeader = NewNxActionHeader(NXAST_EXIT)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionExit) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionExit) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionExit) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionExit message")
+	}
+	return nil
+}
+
+// NXActionCTClear is NX action to clear the connection tracking state of the packet.
+type NXActionCTClear struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionCTClear() *NXActionCTClear {
+	a := new(NXActionCTClear)
+	a.NXActionHeader = NewNxActionHeader(NXAST_CT_CLEAR)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionCTClear) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionCTClear) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionCTClear) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionCTClear message")
+	}
+	return nil
+}
+
+// NXActionPopQueue is NX action to restore the queue to the value it had before any set_queue action.
+type NXActionPopQueue struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionPopQueue() *NXActionPopQueue {
+	a := new(NXActionPopQueue)
+	a.NXActionHeader = NewNxActionHeader(NXAST_POP_QUEUE)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionPopQueue) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionPopQueue) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionPopQueue) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionPopQueue message")
+	}
+	return nil
+}
+
+// NXActionDropSpoofedARP is the obsolete NX action to drop ARP packets whose sender hardware address does not
+// match the Ethernet source address. It is only kept to decode flows added by old controllers.
+type NXActionDropSpoofedARP struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionDropSpoofedARP() *NXActionDropSpoofedARP {
+	a := new(NXActionDropSpoofedARP)
+	a.NXActionHeader = NewNxActionHeader(NXAST_DROP_SPOOFED_ARP)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionDropSpoofedARP) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionDropSpoofedARP) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionDropSpoofedARP) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionDropSpoofedARP message")
+	}
+	return nil
+}
+
+// NXActionFinTimeout is NX action to change the idle and hard timeouts of the flow entry when a TCP FIN or RST
+// is received, the action in flow entry is like fin_timeout(idle_timeout=xx,hard_timeout=xx).
+type NXActionFinTimeout struct {
+	*NXActionHeader
+	FinIdleTimeout uint16
+	FinHardTimeout uint16
+	pad            [2]byte
+}
+
+func NewNXActionFinTimeout(idleTimeout, hardTimeout uint16) *NXActionFinTimeout {
+	a := new(NXActionFinTimeout)
+	a.NXActionHeader = NewNxActionHeader(NXAST_FIN_TIMEOUT)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.FinIdleTimeout = idleTimeout
+	a.FinHardTimeout = hardTimeout
+	return a
+}
+
+func (a *NXActionFinTimeout) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionFinTimeout) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.FinIdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], a.FinHardTimeout)
+	return
+}
+
+func (a *NXActionFinTimeout) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+4 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionFinTimeout message")
+	}
+	a.FinIdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.FinHardTimeout = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// NXActionOutputTrunc is NX action to output a packet truncated to MaxLen bytes, the action in flow entry is
+// like output(port=xx,max_len=xx).
+type NXActionOutputTrunc struct {
+	*NXActionHeader
+	OutPort uint16
+	MaxLen  uint32
+}
+
+func NewNXActionOutputTrunc(port uint16, maxLen uint32) *NXActionOutputTrunc {
+	a := new(NXActionOutputTrunc)
+	a.NXActionHeader = NewNxActionHeader(NXAST_OUTPUT_TRUNC)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.OutPort = port
+	a.MaxLen = maxLen
+	return a
+}
+
+func (a *NXActionOutputTrunc) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionOutputTrunc) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.OutPort)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.MaxLen)
+	return
+}
+
+func (a *NXActionOutputTrunc) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionOutputTrunc message")
+	}
+	a.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	a.MaxLen = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// NXActionSetQueue is NX action to set the queue used when outputting the packet, the action in flow entry is
+// like set_queue:xx.
+type NXActionSetQueue struct {
+	*NXActionHeader
+	pad     [2]byte
+	QueueID uint32
+}
+
+func NewNXActionSetQueue(queueID uint32) *NXActionSetQueue {
+	a := new(NXActionSetQueue)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_QUEUE)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.QueueID = queueID
+	return a
+}
+
+func (a *NXActionSetQueue) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSetQueue) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	// Skip padding copy, move the index.
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.QueueID)
+	return
+}
+
+func (a *NXActionSetQueue) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSetQueue message")
+	}
+	// Skip padding.
+	n += 2
+	a.QueueID = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// NXActionSetTunnel is NX action to set the tunnel ID of the packet. The same structure is used for the
+// set_tunnel action (NXAST_SET_TUNNEL) which carries a 32-bit tunnel ID, and the set_tunnel64 action
+// (NXAST_SET_TUNNEL_V6) which carries a 64-bit tunnel ID.
+type NXActionSetTunnel struct {
+	*NXActionHeader
+	TunnelID uint64
+}
+
+// NewNXActionSetTunnel creates NXActionSetTunnel with a 32-bit tunnel ID, the action in flow entry is like
+// set_tunnel:xx.
+func NewNXActionSetTunnel(tunnelID uint32) *NXActionSetTunnel {
+	a := new(NXActionSetTunnel)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_TUNNEL)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.TunnelID = uint64(tunnelID)
+	return a
+}
+
+// NewNXActionSetTunnel64 creates NXActionSetTunnel with a 64-bit tunnel ID, the action in flow entry is like
+// set_tunnel64:xx.
+func NewNXActionSetTunnel64(tunnelID uint64) *NXActionSetTunnel {
+	a := new(NXActionSetTunnel)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_TUNNEL_V6)
+	a.Length = a.NXActionHeader.Len() + 14
+	a.TunnelID = tunnelID
+	return a
+}
+
+func (a *NXActionSetTunnel) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSetTunnel) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	if a.Subtype == NXAST_SET_TUNNEL_V6 {
+		// Skip padding copy, move the index.
+		n += 6
+		binary.BigEndian.PutUint64(data[n:], a.TunnelID)
+		return
+	}
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], uint32(a.TunnelID))
+	return
+}
+
+func (a *NXActionSetTunnel) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if a.Subtype == NXAST_SET_TUNNEL_V6 {
+		if len(data) < int(a.Len()) || len(data) < n+14 {
+			return errors.New("the []byte is too short to unmarshal a full NXActionSetTunnel message")
+		}
+		n += 6
+		a.TunnelID = binary.BigEndian.Uint64(data[n:])
+		return nil
+	}
+	if len(data) < int(a.Len()) || len(data) < n+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSetTunnel message")
+	}
+	n += 2
+	a.TunnelID = uint64(binary.BigEndian.Uint32(data[n:]))
+	return nil
+}
+
+// NXActionMPLSEtherType is the common structure of the NX actions to push and pop MPLS headers.
+type NXActionMPLSEtherType struct {
+	*NXActionHeader
+	EtherType uint16
+	pad       [4]byte
+}
+
+func (a *NXActionMPLSEtherType) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionMPLSEtherType) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint16(data[n:], a.EtherType)
+	return
+}
+
+func (a *NXActionMPLSEtherType) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+2 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionMPLSEtherType message")
+	}
+	a.EtherType = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// NXActionPushMPLS is NX action to push a new MPLS header, the action in flow entry is like push_mpls:ethertype.
+type NXActionPushMPLS struct {
+	NXActionMPLSEtherType
+}
+
+func NewNXActionPushMPLS(etherType uint16) *NXActionPushMPLS {
+	a := new(NXActionPushMPLS)
+	a.NXActionHeader = NewNxActionHeader(NXAST_PUSH_MPLS)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.EtherType = etherType
+	return a
+}
+
+// NXActionPopMPLS is NX action to pop the outermost MPLS header, EtherType is the Ethernet type of the payload,
+// the action in flow entry is like pop_mpls:ethertype.
+type NXActionPopMPLS struct {
+	NXActionMPLSEtherType
+}
+
+func NewNXActionPopMPLS(etherType uint16) *NXActionPopMPLS {
+	a := new(NXActionPopMPLS)
+	a.NXActionHeader = NewNxActionHeader(NXAST_POP_MPLS)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.EtherType = etherType
+	return a
+}
+
+// NXActionSetMPLSTTL is NX action to set the TTL of the outermost MPLS header, the action in flow entry is like
+// set_mpls_ttl:ttl.
+type NXActionSetMPLSTTL struct {
+	*NXActionHeader
+	TTL uint8
+	pad [5]byte
+}
+
+func NewNXActionSetMPLSTTL(ttl uint8) *NXActionSetMPLSTTL {
+	a := new(NXActionSetMPLSTTL)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_MPLS_TTL)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.TTL = ttl
+	return a
+}
+
+func (a *NXActionSetMPLSTTL) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSetMPLSTTL) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	data[n] = a.TTL
+	return
+}
+
+func (a *NXActionSetMPLSTTL) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+1 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSetMPLSTTL message")
+	}
+	a.TTL = data[n]
+	return nil
+}
+
+// NXActionDecMPLSTTL is NX action to decrement the TTL of the outermost MPLS header, the action in flow entry is
+// like dec_mpls_ttl.
+type NXActionDecMPLSTTL struct {
+	*NXActionHeader
+	pad [6]byte
+}
+
+func NewNXActionDecMPLSTTL() *NXActionDecMPLSTTL {
+	a := new(NXActionDecMPLSTTL)
+	a.NXActionHeader = NewNxActionHeader(NXAST_DEC_MPLS_TTL)
+	a.Length = a.NXActionHeader.Len() + 6
+	return a
+}
+
+func (a *NXActionDecMPLSTTL) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionDecMPLSTTL) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data, b)
+	return
+}
+
+func (a *NXActionDecMPLSTTL) UnmarshalBinary(data []byte) error {
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if len(data) < int(a.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXActionDecMPLSTTL message")
+	}
+	return nil
+}
+
+// NXActionSetMPLSLabel is NX action to set the label of the outermost MPLS header, the action in flow entry is
+// like set_mpls_label:label.
+type NXActionSetMPLSLabel struct {
+	*NXActionHeader
+	pad   [2]byte
+	Label uint32
+}
+
+func NewNXActionSetMPLSLabel(label uint32) *NXActionSetMPLSLabel {
+	a := new(NXActionSetMPLSLabel)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_MPLS_LABEL)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.Label = label
+	return a
+}
+
+func (a *NXActionSetMPLSLabel) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSetMPLSLabel) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	// Skip padding copy, move the index.
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], a.Label)
+	return
+}
+
+func (a *NXActionSetMPLSLabel) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+6 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSetMPLSLabel message")
+	}
+	// Skip padding.
+	n += 2
+	a.Label = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// NXActionSetMPLSTC is NX action to set the traffic class of the outermost MPLS header, the action in flow entry
+// is like set_mpls_tc:tc.
+type NXActionSetMPLSTC struct {
+	*NXActionHeader
+	TC  uint8
+	pad [5]byte
+}
+
+func NewNXActionSetMPLSTC(tc uint8) *NXActionSetMPLSTC {
+	a := new(NXActionSetMPLSTC)
+	a.NXActionHeader = NewNxActionHeader(NXAST_SET_MPLS_TC)
+	a.Length = a.NXActionHeader.Len() + 6
+	a.TC = tc
+	return a
+}
+
+func (a *NXActionSetMPLSTC) Len() (n uint16) {
+	return a.Length
+}
+
+func (a *NXActionSetMPLSTC) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(a.Len()))
+	var b []byte
+	n := 0
+
+	b, err = a.NXActionHeader.MarshalBinary()
+	copy(data[n:], b)
+	n += len(b)
+	data[n] = a.TC
+	return
+}
+
+func (a *NXActionSetMPLSTC) UnmarshalBinary(data []byte) error {
+	n := 0
+	a.NXActionHeader = new(NXActionHeader)
+	if err := a.NXActionHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(a.NXActionHeader.Len())
+	if len(data) < int(a.Len()) || len(data) < n+1 {
+		return errors.New("the []byte is too short to unmarshal a full NXActionSetMPLSTC message")
+	}
+	a.TC = data[n]
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+
+	"antrea.io/libOpenflow/util"
 )
 
 type Uint16Message struct {
@@ -59,6 +61,59 @@ func (m *Uint32Message) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+type Uint64Message struct {
+	Data uint64
+}
+
+func newUint64Message(data uint64) *Uint64Message {
+	return &Uint64Message{Data: data}
+}
+
+func (m *Uint64Message) Len() uint16 {
+	return 8
+}
+
+func (m *Uint64Message) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	binary.BigEndian.PutUint64(data, m.Data)
+	return
+}
+
+func (m *Uint64Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("the []byte is too short to unmarshal a full Uint64Message")
+	}
+	m.Data = binary.BigEndian.Uint64(data[:8])
+	return nil
+}
+
+// Uint128Message is a 128-bit value in network byte order, it is used by the xxreg fields.
+type Uint128Message struct {
+	Data [16]byte
+}
+
+func newUint128Message(data [16]byte) *Uint128Message {
+	return &Uint128Message{Data: data}
+}
+
+func (m *Uint128Message) Len() uint16 {
+	return 16
+}
+
+func (m *Uint128Message) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	copy(data, m.Data[:])
+	return
+}
+
+func (m *Uint128Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("the []byte is too short to unmarshal a full Uint128Message")
+	}
+	copy(m.Data[:], data[:16])
+	return nil
+}
+
 type ByteArrayField struct {
 	Data   []byte
 	Length uint8
@@ -322,6 +377,80 @@ func NewCTLabelMatchField(label [16]byte, mask *[16]byte) *MatchField {
 	return field
 }
 
+// NewCTNwSrcMatchField creates a MatchField for ct_nw_src, the source IPv4 address of the original direction tuple
+// of the conntrack entry.
+func NewCTNwSrcMatchField(ip net.IP, mask *net.IP) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_NW_SRC", mask != nil)
+	field.Value = &Ipv4SrcField{Ipv4Src: ip}
+	if mask != nil {
+		field.Mask = &Ipv4SrcField{Ipv4Src: *mask}
+	}
+	return field
+}
+
+// NewCTNwDstMatchField creates a MatchField for ct_nw_dst, the destination IPv4 address of the original direction
+// tuple of the conntrack entry.
+func NewCTNwDstMatchField(ip net.IP, mask *net.IP) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_NW_DST", mask != nil)
+	field.Value = &Ipv4DstField{Ipv4Dst: ip}
+	if mask != nil {
+		field.Mask = &Ipv4DstField{Ipv4Dst: *mask}
+	}
+	return field
+}
+
+// NewCTIPv6SrcMatchField creates a MatchField for ct_ipv6_src, the source IPv6 address of the original direction
+// tuple of the conntrack entry.
+func NewCTIPv6SrcMatchField(ip net.IP, mask *net.IP) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_IPV6_SRC", mask != nil)
+	field.Value = &Ipv6SrcField{Ipv6Src: ip}
+	if mask != nil {
+		field.Mask = &Ipv6SrcField{Ipv6Src: *mask}
+	}
+	return field
+}
+
+// NewCTIPv6DstMatchField creates a MatchField for ct_ipv6_dst, the destination IPv6 address of the original
+// direction tuple of the conntrack entry.
+func NewCTIPv6DstMatchField(ip net.IP, mask *net.IP) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_IPV6_DST", mask != nil)
+	field.Value = &Ipv6DstField{Ipv6Dst: ip}
+	if mask != nil {
+		field.Mask = &Ipv6DstField{Ipv6Dst: *mask}
+	}
+	return field
+}
+
+// NewCTNwProtoMatchField creates a MatchField for ct_nw_proto, the IP protocol of the original direction tuple of
+// the conntrack entry.
+func NewCTNwProtoMatchField(protocol uint8) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_NW_PROTO", false)
+	field.Value = &IpProtoField{Protocol: protocol}
+	return field
+}
+
+// NewCTTpSrcMatchField creates a MatchField for ct_tp_src, the transport source port of the original direction
+// tuple of the conntrack entry.
+func NewCTTpSrcMatchField(port uint16, mask *uint16) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_TP_SRC", mask != nil)
+	field.Value = &PortField{Port: port}
+	if mask != nil {
+		field.Mask = &PortField{Port: *mask}
+	}
+	return field
+}
+
+// NewCTTpDstMatchField creates a MatchField for ct_tp_dst, the transport destination port of the original direction
+// tuple of the conntrack entry.
+func NewCTTpDstMatchField(port uint16, mask *uint16) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_CT_TP_DST", mask != nil)
+	field.Value = &PortField{Port: port}
+	if mask != nil {
+		field.Mask = &PortField{Port: *mask}
+	}
+	return field
+}
+
 func NewConjIDMatchField(conjID uint32) *MatchField {
 	field, _ := FindFieldHeaderByName("NXM_NX_CONJ_ID", false)
 	field.Value = newUint32Message(conjID)
@@ -329,6 +458,148 @@ func NewConjIDMatchField(conjID uint32) *MatchField {
 	return field
 }
 
+// NewPktMarkMatchField creates a MatchField for pkt_mark, the packet mark of the Linux kernel.
+func NewPktMarkMatchField(mark uint32, mask *uint32) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_PKT_MARK", mask != nil)
+	field.Value = newUint32Message(mark)
+	if mask != nil {
+		field.Mask = newUint32Message(*mask)
+	}
+	return field
+}
+
+// NewDpHashMatchField creates a MatchField for dp_hash, the hash computed by the datapath.
+func NewDpHashMatchField(hash uint32, mask *uint32) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_DP_HASH", mask != nil)
+	field.Value = newUint32Message(hash)
+	if mask != nil {
+		field.Mask = newUint32Message(*mask)
+	}
+	return field
+}
+
+// NewRecircIDMatchField creates a MatchField for recirc_id, the ID of the datapath recirculation.
+func NewRecircIDMatchField(recircID uint32) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_RECIRC_ID", false)
+	field.Value = newUint32Message(recircID)
+	return field
+}
+
+const (
+	NX_TUN_FLAG_OAM = 1 << 0 /* The tunnel packet is an OAM frame */
+)
+
+// NewTunFlagsMatchField creates a MatchField for tun_flags, the flags of the tunnel, e.g., NX_TUN_FLAG_OAM.
+func NewTunFlagsMatchField(flags uint16, mask *uint16) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_TUN_FLAGS", mask != nil)
+	field.Value = newUint16Message(flags)
+	if mask != nil {
+		field.Mask = newUint16Message(*mask)
+	}
+	return field
+}
+
+// NewTunGbpIDMatchField creates a MatchField for tun_gbp_id, the policy ID of the VXLAN Group Based Policy extension.
+func NewTunGbpIDMatchField(id uint16, mask *uint16) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_TUN_GBP_ID", mask != nil)
+	field.Value = newUint16Message(id)
+	if mask != nil {
+		field.Mask = newUint16Message(*mask)
+	}
+	return field
+}
+
+// NewTunGbpFlagsMatchField creates a MatchField for tun_gbp_flags, the flags of the VXLAN Group Based Policy
+// extension.
+func NewTunGbpFlagsMatchField(flags uint8, mask *uint8) *MatchField {
+	field, _ := FindFieldHeaderByName("NXM_NX_TUN_GBP_FLAGS", mask != nil)
+	field.Value = newUint8Message(flags)
+	if mask != nil {
+		field.Mask = newUint8Message(*mask)
+	}
+	return field
+}
+
+const (
+	// NXM_NX_XREG_COUNT is the number of the 64-bit registers xreg0 to xreg7.
+	NXM_NX_XREG_COUNT = 8
+	// NXM_NX_XXREG_COUNT is the number of the 128-bit registers xxreg0 to xxreg3.
+	NXM_NX_XXREG_COUNT = 4
+)
+
+func newXRegHeader(idx int, hasMask bool) (*MatchField, error) {
+	if idx < 0 || idx >= NXM_NX_XREG_COUNT {
+		return nil, fmt.Errorf("invalid xreg index %d, it must be in [0, %d)", idx, NXM_NX_XREG_COUNT)
+	}
+	return FindFieldHeaderByName(fmt.Sprintf("OXM_OF_PKT_REG%d", idx), hasMask)
+}
+
+// NewXRegMatchField creates a MatchField for the 64-bit register xreg[idx], which is the concatenation of
+// reg[2*idx] and reg[2*idx+1]. The mask is generated from dataRng if it is not nil. It returns an error if idx is
+// not in [0, NXM_NX_XREG_COUNT).
+func NewXRegMatchField(idx int, data uint64, dataRng *NXRange) (*MatchField, error) {
+	field, err := newXRegHeader(idx, dataRng != nil)
+	if err != nil {
+		return nil, err
+	}
+	field.Value = newUint64Message(data)
+	if dataRng != nil {
+		field.Mask = newUint64Message(dataRng.ToUint64Mask())
+	}
+	return field, nil
+}
+
+// NewXRegMatchFieldWithMask creates a MatchField for xreg[idx] with an arbitrary bitwise mask.
+func NewXRegMatchFieldWithMask(idx int, data uint64, mask uint64) (*MatchField, error) {
+	field, err := newXRegHeader(idx, mask != 0)
+	if err != nil {
+		return nil, err
+	}
+	field.Value = newUint64Message(data)
+	if mask != 0 {
+		field.Mask = newUint64Message(mask)
+	}
+	return field, nil
+}
+
+func newXXRegHeader(idx int, hasMask bool) (*MatchField, error) {
+	if idx < 0 || idx >= NXM_NX_XXREG_COUNT {
+		return nil, fmt.Errorf("invalid xxreg index %d, it must be in [0, %d)", idx, NXM_NX_XXREG_COUNT)
+	}
+	return FindFieldHeaderByName(fmt.Sprintf("NXM_NX_XXREG%d", idx), hasMask)
+}
+
+// NewXXRegMatchField creates a MatchField for the 128-bit register xxreg[idx], which is the concatenation of
+// reg[4*idx] to reg[4*idx+3]. The data is in network byte order, and the mask is generated from dataRng if it is not
+// nil. It returns an error if idx is not in [0, NXM_NX_XXREG_COUNT).
+//
+// NXActionRegLoad carries at most 64 bits, use NXActionRegLoad2 with the field returned here to load all the 128
+// bits of an xxreg.
+func NewXXRegMatchField(idx int, data [16]byte, dataRng *NXRange) (*MatchField, error) {
+	field, err := newXXRegHeader(idx, dataRng != nil)
+	if err != nil {
+		return nil, err
+	}
+	field.Value = newUint128Message(data)
+	if dataRng != nil {
+		field.Mask = newUint128Message(dataRng.ToUint128Mask())
+	}
+	return field, nil
+}
+
+// NewXXRegMatchFieldWithMask creates a MatchField for xxreg[idx] with an arbitrary bitwise mask.
+func NewXXRegMatchFieldWithMask(idx int, data [16]byte, mask *[16]byte) (*MatchField, error) {
+	field, err := newXXRegHeader(idx, mask != nil)
+	if err != nil {
+		return nil, err
+	}
+	field.Value = newUint128Message(data)
+	if mask != nil {
+		field.Mask = newUint128Message(*mask)
+	}
+	return field, nil
+}
+
 func NewNxARPShaMatchField(addr net.HardwareAddr, mask net.HardwareAddr) *MatchField {
 	var field *MatchField
 	field, _ = FindFieldHeaderByName("NXM_NX_ARP_SHA", mask != nil)
@@ -376,3 +647,99 @@ func NewNxARPTpaMatchField(addr net.IP, mask net.IP) *MatchField {
 
 	return field
 }
+
+type Uint8Message struct {
+	Data uint8
+}
+
+func newUint8Message(data uint8) *Uint8Message {
+	return &Uint8Message{Data: data}
+}
+
+func (m *Uint8Message) Len() uint16 {
+	return 1
+}
+
+func (m *Uint8Message) MarshalBinary() (data []byte, err error) {
+	data = []byte{m.Data}
+	return
+}
+
+func (m *Uint8Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("the []byte is too short to unmarshal a full Uint8Message")
+	}
+	m.Data = data[0]
+	return nil
+}
+
+// decodeNSHMatchField decodes the value or the mask of a NXOXM_NSH experimenter field.
+func decodeNSHMatchField(class uint16, field uint8, length uint8, hasMask bool, data []byte) (util.Message, error) {
+	var val util.Message
+	switch field {
+	case NXOXM_NSH_FLAGS, NXOXM_NSH_MDTYPE, NXOXM_NSH_NP, NXOXM_NSH_SI, NXOXM_NSH_TTL:
+		val = new(Uint8Message)
+	case NXOXM_NSH_SPI, NXOXM_NSH_C1, NXOXM_NSH_C2, NXOXM_NSH_C3, NXOXM_NSH_C4:
+		val = new(Uint32Message)
+	default:
+		return nil, fmt.Errorf("unknown field for NSH: %v", field)
+	}
+	if err := val.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func newNSHUint8MatchField(name string, value uint8, mask *uint8) *MatchField {
+	field, _ := FindFieldHeaderByName(name, mask != nil)
+	field.Value = newUint8Message(value)
+	if mask != nil {
+		field.Mask = newUint8Message(*mask)
+	}
+	return field
+}
+
+func newNSHUint32MatchField(name string, value uint32, mask *uint32) *MatchField {
+	field, _ := FindFieldHeaderByName(name, mask != nil)
+	field.Value = newUint32Message(value)
+	if mask != nil {
+		field.Mask = newUint32Message(*mask)
+	}
+	return field
+}
+
+// NewNSHFlagsMatchField creates a MatchField for nsh_flags.
+func NewNSHFlagsMatchField(flags uint8, mask *uint8) *MatchField {
+	return newNSHUint8MatchField("NXOXM_NSH_FLAGS", flags, mask)
+}
+
+// NewNSHTTLMatchField creates a MatchField for nsh_ttl.
+func NewNSHTTLMatchField(ttl uint8, mask *uint8) *MatchField {
+	return newNSHUint8MatchField("NXOXM_NSH_TTL", ttl, mask)
+}
+
+// NewNSHMdTypeMatchField creates a MatchField for nsh_mdtype.
+func NewNSHMdTypeMatchField(mdType uint8) *MatchField {
+	return newNSHUint8MatchField("NXOXM_NSH_MDTYPE", mdType, nil)
+}
+
+// NewNSHNextProtoMatchField creates a MatchField for nsh_np.
+func NewNSHNextProtoMatchField(nextProto uint8) *MatchField {
+	return newNSHUint8MatchField("NXOXM_NSH_NP", nextProto, nil)
+}
+
+// NewNSHSPIMatchField creates a MatchField for nsh_spi, the Service Path Identifier is 24 bits.
+func NewNSHSPIMatchField(spi uint32) *MatchField {
+	return newNSHUint32MatchField("NXOXM_NSH_SPI", spi, nil)
+}
+
+// NewNSHSIMatchField creates a MatchField for nsh_si.
+func NewNSHSIMatchField(si uint8) *MatchField {
+	return newNSHUint8MatchField("NXOXM_NSH_SI", si, nil)
+}
+
+// NewNSHContextMatchField creates a MatchField for the context header nsh_c<idx> of NSH with MD
+// type 1, idx is from 1 to 4. It could also be used to set the context header with set_field.
+func NewNSHContextMatchField(idx int, value uint32, mask *uint32) *MatchField {
+	return newNSHUint32MatchField(fmt.Sprintf("NXOXM_NSH_C%d", idx), value, mask)
+}
@@ -0,0 +1,875 @@
+package openflow13
+
+// This file has the Nicira extended multipart messages, which are sent in OFPMP_EXPERIMENTER multipart messages.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// Nicira extended multipart types, used as the experimenter type of the OFPMP_EXPERIMENTER multipart messages.
+const (
+	NXST_FLOW         = 0 /* The request is NXFlowStatsRequest, the reply is NXFlowStatsReply. */
+	NXST_AGGREGATE    = 1 /* The request is NXFlowStatsRequest, the reply is NXAggregateStatsReply. */
+	NXST_FLOW_MONITOR = 2 /* The request is NXFlowMonitorRequest, the reply is NXFlowMonitorReply. */
+)
+
+// NXOFPP_NONE is the OpenFlow 1.0 port number used in NXFlowStatsRequest to not filter the flows by output port.
+const NXOFPP_NONE = 0xffff
+
+// NXStatsHeader is the ofp_experimenter_multipart_header with the Nicira experimenter ID, which starts the bodies of
+// the Nicira extended multipart messages.
+type NXStatsHeader struct {
+	Vendor  uint32
+	Subtype uint32
+}
+
+func NewNXStatsHeader(subtype uint32) *NXStatsHeader {
+	return &NXStatsHeader{Vendor: NxExperimenterID, Subtype: subtype}
+}
+
+func (h *NXStatsHeader) Len() uint16 {
+	return 8
+}
+
+func (h *NXStatsHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, h.Len())
+	n := 0
+	binary.BigEndian.PutUint32(data[n:], h.Vendor)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], h.Subtype)
+	return
+}
+
+func (h *NXStatsHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(h.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXStatsHeader message")
+	}
+	n := 0
+	h.Vendor = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	h.Subtype = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// nxMatchLen returns the length of the NXM fields, and the length with the padding to 8 bytes.
+func nxMatchLen(fields []MatchField) (matchLen uint16, paddedLen uint16) {
+	for i := range fields {
+		matchLen += fields[i].Len()
+	}
+	return matchLen, (matchLen + 7) / 8 * 8
+}
+
+func marshalNXMatch(data []byte, fields []MatchField) error {
+	n := 0
+	for i := range fields {
+		b, err := fields[i].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return nil
+}
+
+func unmarshalNXMatch(data []byte) ([]MatchField, error) {
+	var fields []MatchField
+	n := 0
+	for n < len(data) {
+		field := new(MatchField)
+		if err := field.UnmarshalBinary(data[n:]); err != nil {
+			return nil, err
+		}
+		fields = append(fields, *field)
+		n += int(field.Len())
+	}
+	return fields, nil
+}
+
+// nx_flow_stats_request, the body of NXST_FLOW and NXST_AGGREGATE requests. The flows are matched with the NXM
+// fields in Match, which is not strict.
+type NXFlowStatsRequest struct {
+	*NXStatsHeader
+	OutPort  uint16
+	MatchLen uint16
+	TableID  uint8
+	Match    []MatchField
+}
+
+// NewNXFlowStatsRequest creates the body of the NXST_FLOW request, a TableID of OFPTT_ALL matches all tables.
+func NewNXFlowStatsRequest(tableID uint8, match []MatchField) *NXFlowStatsRequest {
+	return &NXFlowStatsRequest{
+		NXStatsHeader: NewNXStatsHeader(NXST_FLOW),
+		OutPort:       NXOFPP_NONE,
+		TableID:       tableID,
+		Match:         match,
+	}
+}
+
+// NewNXAggregateStatsRequest creates the body of the NXST_AGGREGATE request.
+func NewNXAggregateStatsRequest(tableID uint8, match []MatchField) *NXFlowStatsRequest {
+	r := NewNXFlowStatsRequest(tableID, match)
+	r.Subtype = NXST_AGGREGATE
+	return r
+}
+
+func (r *NXFlowStatsRequest) Len() uint16 {
+	_, paddedLen := nxMatchLen(r.Match)
+	return r.NXStatsHeader.Len() + 8 + paddedLen
+}
+
+func (r *NXFlowStatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, r.Len())
+	var b []byte
+	n := 0
+
+	b, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(r.NXStatsHeader.Len())
+
+	r.MatchLen, _ = nxMatchLen(r.Match)
+	binary.BigEndian.PutUint16(data[n:], r.OutPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], r.MatchLen)
+	n += 2
+	data[n] = r.TableID
+	n += 1
+	n += 3 // for padding
+
+	if err = marshalNXMatch(data[n:], r.Match); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *NXFlowStatsRequest) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	if len(data) < int(r.NXStatsHeader.Len())+8 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowStatsRequest message")
+	}
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	r.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	r.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	r.TableID = data[n]
+	n += 1
+	n += 3 // for padding
+
+	if len(data) < n+int(r.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowStatsRequest's Match")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(r.MatchLen)])
+	if err != nil {
+		return err
+	}
+	r.Match = match
+	return nil
+}
+
+// nx_flow_stats, an entry in the NXST_FLOW reply. IdleAge and HardAge are the seconds since the last packet and
+// the last modification of the flow plus one, or 0 if they are unknown. The flow importance is not included in
+// NXST_FLOW, it is only reported by the OpenFlow 1.4+ flow stats.
+type NXFlowStats struct {
+	Length       uint16
+	TableID      uint8
+	DurationSec  uint32
+	DurationNSec uint32
+	Priority     uint16
+	IdleTimeout  uint16
+	HardTimeout  uint16
+	MatchLen     uint16
+	IdleAge      uint16
+	HardAge      uint16
+	Cookie       uint64
+	PacketCount  uint64
+	ByteCount    uint64
+	Match        []MatchField
+	Actions      []Action
+}
+
+func (s *NXFlowStats) Len() (n uint16) {
+	_, n = nxMatchLen(s.Match)
+	n += 48
+	for _, a := range s.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (s *NXFlowStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, s.Len())
+	n := 0
+
+	s.Length = s.Len()
+	s.MatchLen, _ = nxMatchLen(s.Match)
+	binary.BigEndian.PutUint16(data[n:], s.Length)
+	n += 2
+	data[n] = s.TableID
+	n += 1
+	n += 1 // for padding
+	binary.BigEndian.PutUint32(data[n:], s.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], s.DurationNSec)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], s.Priority)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.HardTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.MatchLen)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.IdleAge)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.HardAge)
+	n += 2
+	binary.BigEndian.PutUint64(data[n:], s.Cookie)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.ByteCount)
+	n += 8
+
+	if err = marshalNXMatch(data[n:], s.Match); err != nil {
+		return nil, err
+	}
+	n += int((s.MatchLen + 7) / 8 * 8)
+
+	for _, a := range s.Actions {
+		b, err := a.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (s *NXFlowStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 48 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowStats message")
+	}
+	n := 0
+	s.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.TableID = data[n]
+	n += 1
+	n += 1 // for padding
+	s.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.IdleAge = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.HardAge = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+
+	if len(data) < int(s.Length) || int(s.Length) < n+int(s.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowStats's Match and Actions")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(s.MatchLen)])
+	if err != nil {
+		return err
+	}
+	s.Match = match
+	n += int((s.MatchLen + 7) / 8 * 8)
+
+	for n < int(s.Length) {
+		a, err := DecodeAction(data[n:s.Length])
+		if err != nil {
+			return err
+		}
+		s.Actions = append(s.Actions, a)
+		n += int(a.Len())
+	}
+	return nil
+}
+
+// NXFlowStatsReply is the body of the NXST_FLOW reply, which has the stats of all the flows in the multipart
+// message.
+type NXFlowStatsReply struct {
+	*NXStatsHeader
+	Stats []*NXFlowStats
+}
+
+func NewNXFlowStatsReply() *NXFlowStatsReply {
+	return &NXFlowStatsReply{NXStatsHeader: NewNXStatsHeader(NXST_FLOW)}
+}
+
+func (r *NXFlowStatsReply) Len() (n uint16) {
+	n = r.NXStatsHeader.Len()
+	for _, s := range r.Stats {
+		n += s.Len()
+	}
+	return
+}
+
+func (r *NXFlowStatsReply) MarshalBinary() (data []byte, err error) {
+	data, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range r.Stats {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (r *NXFlowStatsReply) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	for n < len(data) {
+		s := new(NXFlowStats)
+		if err := s.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		r.Stats = append(r.Stats, s)
+		n += int(s.Length)
+	}
+	return nil
+}
+
+// nx_aggregate_stats_reply, the body of the NXST_AGGREGATE reply.
+type NXAggregateStatsReply struct {
+	*NXStatsHeader
+	PacketCount uint64
+	ByteCount   uint64
+	FlowCount   uint32
+}
+
+func NewNXAggregateStatsReply() *NXAggregateStatsReply {
+	return &NXAggregateStatsReply{NXStatsHeader: NewNXStatsHeader(NXST_AGGREGATE)}
+}
+
+func (r *NXAggregateStatsReply) Len() uint16 {
+	return r.NXStatsHeader.Len() + 24
+}
+
+func (r *NXAggregateStatsReply) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, r.Len())
+	var b []byte
+	n := 0
+
+	b, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(r.NXStatsHeader.Len())
+
+	binary.BigEndian.PutUint64(data[n:], r.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], r.ByteCount)
+	n += 8
+	binary.BigEndian.PutUint32(data[n:], r.FlowCount)
+	return
+}
+
+func (r *NXAggregateStatsReply) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	if len(data) < int(r.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXAggregateStatsReply message")
+	}
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	r.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	r.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	r.FlowCount = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// nx_flow_monitor_flags
+const (
+	/* When to send updates. */
+	NXFMF_INITIAL = 1 << 0 /* Initially matching flows. */
+	NXFMF_ADD     = 1 << 1 /* New matching flows as they are added. */
+	NXFMF_DELETE  = 1 << 2 /* Old matching flows as they are removed. */
+	NXFMF_MODIFY  = 1 << 3 /* Matching flows as they are changed. */
+	/* What to include in updates. */
+	NXFMF_ACTIONS = 1 << 4 /* If set, actions are included. */
+	NXFMF_OWN     = 1 << 5 /* If set, include own changes in full. */
+)
+
+// nx_flow_update_event
+const (
+	/* struct nx_flow_update_full. */
+	NXFME_ADDED    = 0 /* Flow was added. */
+	NXFME_DELETED  = 1 /* Flow was deleted. */
+	NXFME_MODIFIED = 2 /* Flow (generally its actions) was changed. */
+	/* struct nx_flow_update_abbrev. */
+	NXFME_ABBREV = 3 /* Abbreviated reply. */
+)
+
+// nx_flow_monitor_request, a flow monitor in the NXST_FLOW_MONITOR request. The monitor ID must be unique in the
+// connection, it is used to cancel the monitor with NXT_FLOW_MONITOR_CANCEL.
+type NXFlowMonitor struct {
+	ID       uint32
+	Flags    uint16
+	OutPort  uint16
+	MatchLen uint16
+	TableID  uint8
+	Match    []MatchField
+}
+
+func NewNXFlowMonitor(id uint32, flags uint16, tableID uint8, match []MatchField) *NXFlowMonitor {
+	return &NXFlowMonitor{
+		ID:      id,
+		Flags:   flags,
+		OutPort: NXOFPP_NONE,
+		TableID: tableID,
+		Match:   match,
+	}
+}
+
+func (m *NXFlowMonitor) Len() uint16 {
+	_, paddedLen := nxMatchLen(m.Match)
+	return 16 + paddedLen
+}
+
+func (m *NXFlowMonitor) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, m.Len())
+	n := 0
+
+	m.MatchLen, _ = nxMatchLen(m.Match)
+	binary.BigEndian.PutUint32(data[n:], m.ID)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], m.Flags)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], m.OutPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], m.MatchLen)
+	n += 2
+	data[n] = m.TableID
+	n += 1
+	n += 5 // for padding
+
+	if err = marshalNXMatch(data[n:], m.Match); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (m *NXFlowMonitor) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowMonitor message")
+	}
+	n := 0
+	m.ID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.TableID = data[n]
+	n += 1
+	n += 5 // for padding
+
+	if len(data) < n+int(m.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowMonitor's Match")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(m.MatchLen)])
+	if err != nil {
+		return err
+	}
+	m.Match = match
+	return nil
+}
+
+// NXFlowMonitorRequest is the body of the NXST_FLOW_MONITOR request, which creates the flow monitors. The switch
+// replies the flows matching the monitors with NXFMF_INITIAL, and then sends NXST_FLOW_MONITOR replies with the flow
+// updates until the monitors are canceled.
+type NXFlowMonitorRequest struct {
+	*NXStatsHeader
+	Monitors []*NXFlowMonitor
+}
+
+func NewNXFlowMonitorRequest(monitors ...*NXFlowMonitor) *NXFlowMonitorRequest {
+	return &NXFlowMonitorRequest{
+		NXStatsHeader: NewNXStatsHeader(NXST_FLOW_MONITOR),
+		Monitors:      monitors,
+	}
+}
+
+func (r *NXFlowMonitorRequest) Len() (n uint16) {
+	n = r.NXStatsHeader.Len()
+	for _, m := range r.Monitors {
+		n += m.Len()
+	}
+	return
+}
+
+func (r *NXFlowMonitorRequest) MarshalBinary() (data []byte, err error) {
+	data, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range r.Monitors {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (r *NXFlowMonitorRequest) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	for n < len(data) {
+		m := new(NXFlowMonitor)
+		if err := m.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		r.Monitors = append(r.Monitors, m)
+		n += int(m.Len())
+	}
+	return nil
+}
+
+// nx_flow_update_header
+type NXFlowUpdateHeader struct {
+	Length uint16
+	Event  uint16
+}
+
+func (h *NXFlowUpdateHeader) Len() uint16 {
+	return 4
+}
+
+func (h *NXFlowUpdateHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, h.Len())
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], h.Length)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], h.Event)
+	return
+}
+
+func (h *NXFlowUpdateHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(h.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowUpdateHeader message")
+	}
+	n := 0
+	h.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	h.Event = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// nx_flow_update_full, the update of NXFME_ADDED, NXFME_DELETED or NXFME_MODIFIED events. Reason is one of
+// OFPRR_* for NXFME_DELETED. Actions are included only if the monitor has NXFMF_ACTIONS.
+type NXFlowUpdateFull struct {
+	NXFlowUpdateHeader
+	Reason      uint16
+	Priority    uint16
+	IdleTimeout uint16
+	HardTimeout uint16
+	MatchLen    uint16
+	TableID     uint8
+	Cookie      uint64
+	Match       []MatchField
+	Actions     []Action
+}
+
+func NewNXFlowUpdateFull(event uint16) *NXFlowUpdateFull {
+	n := new(NXFlowUpdateFull)
+	n.Event = event
+	return n
+}
+
+func (u *NXFlowUpdateFull) Len() (n uint16) {
+	_, n = nxMatchLen(u.Match)
+	n += 24
+	for _, a := range u.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (u *NXFlowUpdateFull) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, u.Len())
+	var b []byte
+	n := 0
+
+	u.Length = u.Len()
+	u.MatchLen, _ = nxMatchLen(u.Match)
+	b, err = u.NXFlowUpdateHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(u.NXFlowUpdateHeader.Len())
+
+	binary.BigEndian.PutUint16(data[n:], u.Reason)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], u.Priority)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], u.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], u.HardTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], u.MatchLen)
+	n += 2
+	data[n] = u.TableID
+	n += 1
+	n += 1 // for padding
+	binary.BigEndian.PutUint64(data[n:], u.Cookie)
+	n += 8
+
+	if err = marshalNXMatch(data[n:], u.Match); err != nil {
+		return nil, err
+	}
+	n += int((u.MatchLen + 7) / 8 * 8)
+
+	for _, a := range u.Actions {
+		b, err = a.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (u *NXFlowUpdateFull) UnmarshalBinary(data []byte) error {
+	if len(data) < 24 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowUpdateFull message")
+	}
+	n := 0
+	if err := u.NXFlowUpdateHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(u.NXFlowUpdateHeader.Len())
+
+	u.Reason = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	u.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	u.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	u.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	u.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	u.TableID = data[n]
+	n += 1
+	n += 1 // for padding
+	u.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+
+	if len(data) < int(u.Length) || int(u.Length) < n+int(u.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowUpdateFull's Match and Actions")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(u.MatchLen)])
+	if err != nil {
+		return err
+	}
+	u.Match = match
+	n += int((u.MatchLen + 7) / 8 * 8)
+
+	for n < int(u.Length) {
+		a, err := DecodeAction(data[n:u.Length])
+		if err != nil {
+			return err
+		}
+		u.Actions = append(u.Actions, a)
+		n += int(a.Len())
+	}
+	return nil
+}
+
+// nx_flow_update_abbrev, the update of NXFME_ABBREV event, which is sent for the flow changes made by the
+// connection itself if the monitor does not have NXFMF_OWN. Xid is the xid of the request making the change.
+type NXFlowUpdateAbbrev struct {
+	NXFlowUpdateHeader
+	Xid uint32
+}
+
+func NewNXFlowUpdateAbbrev(xid uint32) *NXFlowUpdateAbbrev {
+	n := new(NXFlowUpdateAbbrev)
+	n.Event = NXFME_ABBREV
+	n.Xid = xid
+	return n
+}
+
+func (u *NXFlowUpdateAbbrev) Len() uint16 {
+	return 8
+}
+
+func (u *NXFlowUpdateAbbrev) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, u.Len())
+	var b []byte
+	n := 0
+
+	u.Length = u.Len()
+	b, err = u.NXFlowUpdateHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(u.NXFlowUpdateHeader.Len())
+	binary.BigEndian.PutUint32(data[n:], u.Xid)
+	return
+}
+
+func (u *NXFlowUpdateAbbrev) UnmarshalBinary(data []byte) error {
+	if len(data) < int(u.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowUpdateAbbrev message")
+	}
+	n := 0
+	if err := u.NXFlowUpdateHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(u.NXFlowUpdateHeader.Len())
+	u.Xid = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// NXFlowMonitorReply is the body of the NXST_FLOW_MONITOR reply, the Updates are NXFlowUpdateFull or
+// NXFlowUpdateAbbrev.
+type NXFlowMonitorReply struct {
+	*NXStatsHeader
+	Updates []util.Message
+}
+
+func NewNXFlowMonitorReply() *NXFlowMonitorReply {
+	return &NXFlowMonitorReply{NXStatsHeader: NewNXStatsHeader(NXST_FLOW_MONITOR)}
+}
+
+func (r *NXFlowMonitorReply) Len() (n uint16) {
+	n = r.NXStatsHeader.Len()
+	for _, u := range r.Updates {
+		n += u.Len()
+	}
+	return
+}
+
+func (r *NXFlowMonitorReply) MarshalBinary() (data []byte, err error) {
+	data, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range r.Updates {
+		b, err := u.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (r *NXFlowMonitorReply) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	for n+4 <= len(data) {
+		var u util.Message
+		switch event := binary.BigEndian.Uint16(data[n+2:]); event {
+		case NXFME_ADDED, NXFME_DELETED, NXFME_MODIFIED:
+			u = new(NXFlowUpdateFull)
+		case NXFME_ABBREV:
+			u = new(NXFlowUpdateAbbrev)
+		default:
+			return fmt.Errorf("unknown NXST_FLOW_MONITOR event: %d", event)
+		}
+		if err := u.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		r.Updates = append(r.Updates, u)
+		n += int(u.Len())
+	}
+	return nil
+}
+
+// DecodeNXStatsRequest decodes the body of the Nicira extended multipart requests.
+func DecodeNXStatsRequest(subtype uint32, data []byte) (util.Message, error) {
+	var req util.Message
+	switch subtype {
+	case NXST_FLOW, NXST_AGGREGATE:
+		req = new(NXFlowStatsRequest)
+	case NXST_FLOW_MONITOR:
+		req = new(NXFlowMonitorRequest)
+	default:
+		return nil, fmt.Errorf("unsupported Nicira multipart request type: %d", subtype)
+	}
+	if err := req.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// DecodeNXStatsReply decodes the body of the Nicira extended multipart replies.
+func DecodeNXStatsReply(subtype uint32, data []byte) (util.Message, error) {
+	var reply util.Message
+	switch subtype {
+	case NXST_FLOW:
+		reply = new(NXFlowStatsReply)
+	case NXST_AGGREGATE:
+		reply = new(NXAggregateStatsReply)
+	case NXST_FLOW_MONITOR:
+		reply = new(NXFlowMonitorReply)
+	default:
+		return nil, fmt.Errorf("unsupported Nicira multipart reply type: %d", subtype)
+	}
+	if err := reply.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
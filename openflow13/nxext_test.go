@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"testing"
+
+	"antrea.io/libOpenflow/util"
 )
 
 func TestNXActionResubmit(t *testing.T) {
@@ -257,6 +260,38 @@ func TestNXActions(t *testing.T) {
 	translateMessages(t, NewNXActionDecTTL(), new(NXActionDecTTL), nxDecTTLEquals)
 	translateMessages(t, NewNXActionDecTTLCntIDs(2, uint16(1), uint16(2)), new(NXActionDecTTLCntIDs), nxDecTTLCntIDsEquals)
 
+	multipathDst, _ := FindFieldHeaderByName("NXM_NX_REG2", false)
+	translateMessages(t, NewNXActionMultipath(NX_HASH_FIELDS_SYMMETRIC_L4, 50, NX_MP_ALG_HRW, 3, 0, multipathDst, NewNXRange(0, 15)), new(NXActionMultipath), nxMultipathEquals)
+	translateMessages(t, NewNXActionMultipath(NX_HASH_FIELDS_ETH_SRC, 0, NX_MP_ALG_ITER_HASH, 1, 8, multipathDst, NewNXRange(16, 31)), new(NXActionMultipath), nxMultipathEquals)
+
+	translateMessages(t, NewNXActionBundle(NX_BD_ALG_ACTIVE_BACKUP, NX_HASH_FIELDS_ETH_SRC, 0, 1, 2, 3), new(NXActionBundle), nxBundleEquals)
+	bundleDst, _ := FindFieldHeaderByName("NXM_NX_REG3", false)
+	translateMessages(t, NewNXActionBundleLoad(NX_BD_ALG_HRW, NX_HASH_FIELDS_SYMMETRIC_L3L4, 10, bundleDst, NewNXRange(0, 15), 4, 8), new(NXActionBundle), nxBundleEquals)
+
+	translateMessages(t, NewNXActionSample(65535, 1, 2, 3), new(NXActionSample), nxSampleEquals)
+	translateMessages(t, NewNXActionSample2(100, 1, 2, 3, 10, NX_ACTION_SAMPLE_EGRESS), new(NXActionSample), nxSampleEquals)
+
+	stackPush, err := NewNXActionStackPush("NXM_NX_REG4", NewNXRange(0, 15))
+	if err != nil {
+		t.Fatalf("Failed to create NXActionStackPush: %v", err)
+	}
+	translateMessages(t, stackPush, new(NXActionStackPush), nxStackEquals)
+	stackPop, err := NewNXActionStackPop("NXM_OF_ETH_SRC", NewNXRange(8, 47))
+	if err != nil {
+		t.Fatalf("Failed to create NXActionStackPop: %v", err)
+	}
+	translateMessages(t, stackPop, new(NXActionStackPop), nxStackEquals)
+	if _, err = NewNXActionStackPush("NXM_NX_UNKNOWN", NewNXRange(0, 15)); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+
+	translateMessages(t, NewNXActionEncapNSH(2, NewEDPropNSHTLV(0x1234, 5, []byte{1, 2, 3, 4, 5})), new(NXActionEncap), nxEncapEquals)
+	translateMessages(t, NewNXActionEncap(PT_ETH), new(NXActionEncap), nxEncapEquals)
+	translateMessages(t, NewNXActionDecap(PT_USE_NEXT_PROTO), new(NXActionDecap), nxDecapEquals)
+	translateMessages(t, NewNXActionDecNshTTL(), new(NXActionDecNshTTL), func(o1, o2 Action, subtype uint16) bool {
+		return subtype == NXAST_DEC_NSH_TTL && o1.Len() == o2.Len()
+	})
+
 }
 
 func TestNXActionNote(t *testing.T) {
@@ -667,12 +702,27 @@ func prepareLearnSpecs() []*NXLearnSpec {
 	dstSpecField4 := &NXLearnSpecField{dstField4, 0}
 	srcField5, _ := FindFieldHeaderByName("NXM_OF_IN_PORT", false)
 	srcSpecField5 := &NXLearnSpecField{srcField5, 0}
+	srcField6, _ := FindFieldHeaderByName("NXM_NX_CT_NW_SRC", false)
+	srcSpecField6 := &NXLearnSpecField{srcField6, 0}
+	dstField6, _ := FindFieldHeaderByName("NXM_NX_REG2", false)
+	dstSpecField6 := &NXLearnSpecField{dstField6, 0}
+	srcField7, _ := FindFieldHeaderByName("NXM_OF_TCP_DST", false)
+	srcSpecField7 := &NXLearnSpecField{srcField7, 0}
+	dstField7, _ := FindFieldHeaderByName("NXM_NX_CT_TP_DST", false)
+	dstSpecField7 := &NXLearnSpecField{dstField7, 0}
+	srcField8, _ := FindFieldHeaderByName("NXM_NX_XXREG0", false)
+	srcSpecField8 := &NXLearnSpecField{srcField8, 64}
+	dstField8, _ := FindFieldHeaderByName("OXM_OF_PKT_REG1", false)
+	dstSpecField8 := &NXLearnSpecField{dstField8, 0}
 	return []*NXLearnSpec{
 		{Header: NewLearnHeaderMatchFromValue(16), SrcValue: srcValue1, DstField: dstSpecField1},
 		{Header: NewLearnHeaderMatchFromField(48), SrcField: srcSpecField2, DstField: dstSpecField2},
 		{Header: NewLearnHeaderLoadFromField(16), SrcField: srcSpecField3, DstField: dstSpecField3},
 		{Header: NewLearnHeaderLoadFromValue(48), SrcValue: srcValue4, DstField: dstSpecField4},
 		{Header: NewLearnHeaderOutputFromField(16), SrcField: srcSpecField5},
+		{Header: NewLearnHeaderLoadFromField(32), SrcField: srcSpecField6, DstField: dstSpecField6},
+		{Header: NewLearnHeaderMatchFromField(16), SrcField: srcSpecField7, DstField: dstSpecField7},
+		{Header: NewLearnHeaderLoadFromField(64), SrcField: srcSpecField8, DstField: dstSpecField8},
 	}
 }
 
@@ -908,6 +958,266 @@ func nxDecTTLCntIDsEquals(o1 Action, o2 Action, subtype uint16) bool {
 	return true
 }
 
+func nxMultipathEquals(o1, o2 Action, subtype uint16) bool {
+	if subtype != NXAST_MULTIPATH {
+		return false
+	}
+	obj1 := o1.(*NXActionMultipath)
+	obj2 := o2.(*NXActionMultipath)
+	if obj1.Fields != obj2.Fields || obj1.Basis != obj2.Basis {
+		return false
+	}
+	if obj1.Algorithm != obj2.Algorithm || obj1.MaxLink != obj2.MaxLink || obj1.Arg != obj2.Arg {
+		return false
+	}
+	if obj1.OfsNbits != obj2.OfsNbits {
+		return false
+	}
+	if obj1.DstField.MarshalHeader() != obj2.DstField.MarshalHeader() {
+		return false
+	}
+	return true
+}
+
+func nxBundleEquals(o1, o2 Action, subtype uint16) bool {
+	if subtype != NXAST_BUNDLE && subtype != NXAST_BUNDLE_LOAD {
+		return false
+	}
+	obj1 := o1.(*NXActionBundle)
+	obj2 := o2.(*NXActionBundle)
+	if obj1.Subtype != obj2.Subtype || obj1.Length != obj2.Length {
+		return false
+	}
+	if obj1.Algorithm != obj2.Algorithm || obj1.Fields != obj2.Fields || obj1.Basis != obj2.Basis {
+		return false
+	}
+	if obj1.MemberType.MarshalHeader() != obj2.MemberType.MarshalHeader() {
+		return false
+	}
+	if obj1.OfsNbits != obj2.OfsNbits {
+		return false
+	}
+	if (obj1.DstField == nil) != (obj2.DstField == nil) {
+		return false
+	}
+	if obj1.DstField != nil && obj1.DstField.MarshalHeader() != obj2.DstField.MarshalHeader() {
+		return false
+	}
+	if len(obj1.Members) != len(obj2.Members) {
+		return false
+	}
+	for i := range obj1.Members {
+		if obj1.Members[i] != obj2.Members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func nxSampleEquals(o1, o2 Action, subtype uint16) bool {
+	if subtype != NXAST_SAMPLE && subtype != NXAST_SAMPLE2 {
+		return false
+	}
+	obj1 := o1.(*NXActionSample)
+	obj2 := o2.(*NXActionSample)
+	if obj1.Subtype != obj2.Subtype || obj1.Length != obj2.Length {
+		return false
+	}
+	if obj1.Probability != obj2.Probability || obj1.CollectorSetID != obj2.CollectorSetID {
+		return false
+	}
+	if obj1.ObsDomainID != obj2.ObsDomainID || obj1.ObsPointID != obj2.ObsPointID {
+		return false
+	}
+	if obj1.SamplingPort != obj2.SamplingPort || obj1.Direction != obj2.Direction {
+		return false
+	}
+	return true
+}
+
+func nxStackEquals(o1, o2 Action, subtype uint16) bool {
+	var obj1, obj2 NXActionStack
+	switch subtype {
+	case NXAST_STACK_PUSH:
+		obj1, obj2 = o1.(*NXActionStackPush).NXActionStack, o2.(*NXActionStackPush).NXActionStack
+	case NXAST_STACK_POP:
+		obj1, obj2 = o1.(*NXActionStackPop).NXActionStack, o2.(*NXActionStackPop).NXActionStack
+	default:
+		return false
+	}
+	if obj1.Offset != obj2.Offset || obj1.NBits != obj2.NBits {
+		return false
+	}
+	if obj1.Field.MarshalHeader() != obj2.Field.MarshalHeader() {
+		return false
+	}
+	return true
+}
+
+func TestContinuationPropStack(t *testing.T) {
+	stackProps := []*ContinuationPropStack{
+		NewContinuationPropStack([]uint8{0x12, 0x34}),
+		NewContinuationPropStack([]uint8{0x00, 0x00, 0x00, 0x01, 0x02, 0x03}),
+	}
+	// The nested properties follow 4 bytes of padding.
+	continuation := make([]byte, 4)
+	for _, prop := range stackProps {
+		data, err := prop.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to Marshal ContinuationPropStack: %v", err)
+		}
+		continuation = append(continuation, data...)
+	}
+	prop := &PacketIn2PropContinuation{
+		PropHeader:   &PropHeader{Type: NXPINT_CONTINUATION, Length: uint16(4 + len(continuation))},
+		Continuation: continuation,
+	}
+	data, err := prop.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to Marshal PacketIn2PropContinuation: %v", err)
+	}
+	newProp := new(PacketIn2PropContinuation)
+	if err = newProp.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to Unmarshal PacketIn2PropContinuation: %v", err)
+	}
+	props, err := newProp.Props()
+	if err != nil {
+		t.Fatalf("Failed to decode Continuation properties: %v", err)
+	}
+	if len(props) != len(stackProps) {
+		t.Fatalf("Expected %d properties, got %d", len(stackProps), len(props))
+	}
+	for i := range props {
+		stack, ok := props[i].(*ContinuationPropStack)
+		if !ok {
+			t.Fatalf("Unexpected property type %T", props[i])
+		}
+		if !bytes.Equal(stack.Stack, stackProps[i].Stack) {
+			t.Errorf("Stack %x is not equal to %x", stack.Stack, stackProps[i].Stack)
+		}
+	}
+}
+
+func nxEncapEquals(o1, o2 Action, subtype uint16) bool {
+	if subtype != NXAST_RAW_ENCAP {
+		return false
+	}
+	obj1 := o1.(*NXActionEncap)
+	obj2 := o2.(*NXActionEncap)
+	if obj1.Length != obj2.Length || obj1.HdrSize != obj2.HdrSize || obj1.PacketType != obj2.PacketType {
+		return false
+	}
+	if len(obj1.Props) != len(obj2.Props) {
+		return false
+	}
+	for i := range obj1.Props {
+		data1, _ := obj1.Props[i].MarshalBinary()
+		data2, _ := obj2.Props[i].MarshalBinary()
+		if !bytes.Equal(data1, data2) {
+			return false
+		}
+	}
+	return true
+}
+
+func nxDecapEquals(o1, o2 Action, subtype uint16) bool {
+	if subtype != NXAST_RAW_DECAP {
+		return false
+	}
+	return o1.(*NXActionDecap).PacketType == o2.(*NXActionDecap).PacketType
+}
+
+func TestNXActionEncapDecode(t *testing.T) {
+	// encap(nsh(md_type=1))
+	data, _ := hex.DecodeString("ffff001800002320002e00000001894f0004010501000000")
+	act, err := DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionEncap: %v", err)
+	}
+	encap, ok := act.(*NXActionEncap)
+	if !ok {
+		t.Fatalf("Unexpected action type %T", act)
+	}
+	if encap.PacketType != PT_NSH || len(encap.Props) != 1 {
+		t.Fatalf("Unexpected packet type %x or properties %v", encap.PacketType, encap.Props)
+	}
+	mdType, ok := encap.Props[0].(*EDPropNSHMdType)
+	if !ok || mdType.MdType != 1 {
+		t.Errorf("Unexpected property %v", encap.Props[0])
+	}
+	newData, err := NewNXActionEncapNSH(1).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal NXActionEncap: %v", err)
+	}
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+}
+
+func TestNSHMatchFields(t *testing.T) {
+	c1Mask := uint32(0xffff0000)
+	ttlMask := uint8(0x3f)
+	for _, tc := range []struct {
+		field    *MatchField
+		expected string
+	}{
+		{field: NewNSHSPIMatchField(0x123456), expected: "ffff0808005ad65000123456"},
+		{field: NewNSHSIMatchField(255), expected: "ffff0a05005ad650ff"},
+		{field: NewNSHMdTypeMatchField(1), expected: "ffff0405005ad65001"},
+		{field: NewNSHNextProtoMatchField(3), expected: "ffff0605005ad65003"},
+		{field: NewNSHFlagsMatchField(2, nil), expected: "ffff0205005ad65002"},
+		{field: NewNSHTTLMatchField(63, &ttlMask), expected: "ffff1506005ad6503f3f"},
+		{field: NewNSHContextMatchField(1, 0x11220000, &c1Mask), expected: "ffff0d0c005ad65011220000ffff0000"},
+		{field: NewNSHContextMatchField(4, 0x1, nil), expected: "ffff1208005ad65000000001"},
+		{field: NewPacketTypeMatchField(PT_NSH), expected: "800058040001894f"},
+	} {
+		data, err := tc.field.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal MatchField: %v", err)
+		}
+		if hex.EncodeToString(data) != tc.expected {
+			t.Errorf("Marshaled bytes %x are not equal to %s", data, tc.expected)
+		}
+		newField := new(MatchField)
+		if err = newField.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal MatchField: %v", err)
+		}
+		newData, _ := newField.MarshalBinary()
+		if !bytes.Equal(data, newData) {
+			t.Errorf("Unmarshaled MatchField %x is not equal to %x", newData, data)
+		}
+	}
+}
+
+func TestNXActionBundleDecode(t *testing.T) {
+	// bundle(eth_src,0,hrw,ofport,members:4,8)
+	data, _ := hex.DecodeString("ffff002800002320000c000100000000000000020002000000000000000000000004000800000000")
+	act, err := DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionBundle: %v", err)
+	}
+	bundle, ok := act.(*NXActionBundle)
+	if !ok {
+		t.Fatalf("Unexpected action type %T", act)
+	}
+	if bundle.Algorithm != NX_BD_ALG_HRW || bundle.Fields != NX_HASH_FIELDS_ETH_SRC {
+		t.Errorf("Unexpected algorithm %d or fields %d", bundle.Algorithm, bundle.Fields)
+	}
+	if bundle.DstField != nil {
+		t.Errorf("Bundle action should not have a destination field")
+	}
+	if len(bundle.Members) != 2 || bundle.Members[0] != 4 || bundle.Members[1] != 8 {
+		t.Errorf("Unexpected members %v", bundle.Members)
+	}
+	newData, err := bundle.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal NXActionBundle: %v", err)
+	}
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+}
+
 func testMatchFieldHeaderMarshalUnMarshal(tgtField *MatchField, t *testing.T) {
 	headerInt := tgtField.MarshalHeader()
 	testMFHeader := new(MatchField)
@@ -930,3 +1240,670 @@ func testMatchFieldHeaderMarshalUnMarshal(tgtField *MatchField, t *testing.T) {
 		t.Errorf("Unmarshalled header has incorrect 'Length' field, expect: %d, actual: %d", testMFHeader.Length, tgtField.Length)
 	}
 }
+
+func TestNXActionMPLS(t *testing.T) {
+	// The expected bytes are the output of "ovs-ofctl parse-actions" for the NX encoding of each action.
+	for _, tc := range []struct {
+		name     string
+		action   Action
+		expected string
+		check    func(act Action) bool
+	}{
+		{
+			name: "push_mpls:0x8847", action: NewNXActionPushMPLS(0x8847), expected: "ffff0010000023200017884700000000",
+			check: func(act Action) bool {
+				return act.(*NXActionPushMPLS).EtherType == 0x8847
+			},
+		},
+		{
+			name: "pop_mpls:0x0800", action: NewNXActionPopMPLS(0x0800), expected: "ffff0010000023200018080000000000",
+			check: func(act Action) bool {
+				return act.(*NXActionPopMPLS).EtherType == 0x0800
+			},
+		},
+		{
+			name: "set_mpls_ttl:10", action: NewNXActionSetMPLSTTL(10), expected: "ffff00100000232000190a0000000000",
+			check: func(act Action) bool {
+				return act.(*NXActionSetMPLSTTL).TTL == 10
+			},
+		},
+		{name: "dec_mpls_ttl", action: NewNXActionDecMPLSTTL(), expected: "ffff001000002320001a000000000000"},
+		{
+			name: "set_mpls_label:10", action: NewNXActionSetMPLSLabel(10), expected: "ffff001000002320001e00000000000a",
+			check: func(act Action) bool {
+				return act.(*NXActionSetMPLSLabel).Label == 10
+			},
+		},
+		{
+			name: "set_mpls_tc:3", action: NewNXActionSetMPLSTC(3), expected: "ffff001000002320001f030000000000",
+			check: func(act Action) bool {
+				return act.(*NXActionSetMPLSTC).TC == 3
+			},
+		},
+	} {
+		data, err := tc.action.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal %s action: %v", tc.name, err)
+		}
+		if hex.EncodeToString(data) != tc.expected {
+			t.Errorf("Marshaled %s action %x is not equal to %s", tc.name, data, tc.expected)
+		}
+		act, err := DecodeAction(data)
+		if err != nil {
+			t.Fatalf("Failed to decode %s action: %v", tc.name, err)
+		}
+		if reflect.TypeOf(act) != reflect.TypeOf(tc.action) {
+			t.Errorf("Decoded %s action has unexpected type %T", tc.name, act)
+		}
+		if tc.check != nil && !tc.check(act) {
+			t.Errorf("Decoded %s action %+v is not equal to %+v", tc.name, act, tc.action)
+		}
+		newData, _ := act.MarshalBinary()
+		if !bytes.Equal(data, newData) {
+			t.Errorf("Re-marshaled %s action %x is not equal to %x", tc.name, newData, data)
+		}
+	}
+}
+
+func TestCTTupleMatchFields(t *testing.T) {
+	ipMask := net.ParseIP("255.255.255.0").To4()
+	ipv6Mask := net.ParseIP("ffff:ffff:ffff:ffff::")
+	portMask := uint16(0xff00)
+	for _, tc := range []struct {
+		field    *MatchField
+		expected string
+	}{
+		{field: NewCTNwSrcMatchField(net.ParseIP("10.10.0.1"), nil), expected: "0001f0040a0a0001"},
+		{field: NewCTNwDstMatchField(net.ParseIP("10.96.0.10"), &ipMask), expected: "0001f3080a60000affffff00"},
+		{field: NewCTIPv6SrcMatchField(net.ParseIP("fd00::1"), nil), expected: "0001f410fd000000000000000000000000000001"},
+		{field: NewCTIPv6DstMatchField(net.ParseIP("fd00::"), &ipv6Mask), expected: "0001f720fd000000000000000000000000000000ffffffffffffffff0000000000000000"},
+		{field: NewCTNwProtoMatchField(6), expected: "0001ee0106"},
+		{field: NewCTTpSrcMatchField(30000, nil), expected: "0001f8027530"},
+		{field: NewCTTpDstMatchField(0x1f90, &portMask), expected: "0001fb041f90ff00"},
+	} {
+		data, err := tc.field.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal MatchField: %v", err)
+		}
+		if hex.EncodeToString(data) != tc.expected {
+			t.Errorf("Marshaled bytes %x are not equal to %s", data, tc.expected)
+		}
+		newField := new(MatchField)
+		if err = newField.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal MatchField: %v", err)
+		}
+		if reflect.TypeOf(newField.Value) != reflect.TypeOf(tc.field.Value) || newField.HasMask != tc.field.HasMask {
+			t.Errorf("Unmarshaled MatchField %+v is not equal to %+v", newField, tc.field)
+		}
+		newData, _ := newField.MarshalBinary()
+		if !bytes.Equal(data, newData) {
+			t.Errorf("Unmarshaled MatchField %x is not equal to %x", newData, data)
+		}
+	}
+
+	// move:NXM_NX_CT_NW_DST[]->NXM_NX_REG0[]
+	data, _ := hex.DecodeString("ffff00180000232000060020000000000001f20400010004")
+	act, err := DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionRegMove: %v", err)
+	}
+	move := act.(*NXActionRegMove)
+	if move.SrcField.Field != NXM_NX_CT_NW_DST || move.Nbits != 32 {
+		t.Errorf("Unexpected source field %d or nbits %d", move.SrcField.Field, move.Nbits)
+	}
+	srcField, _ := FindFieldHeaderByName("NXM_NX_CT_NW_DST", false)
+	dstField, _ := FindFieldHeaderByName("NXM_NX_REG0", false)
+	newData, _ := NewNXActionRegMove(32, 0, 0, srcField, dstField).MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+}
+
+func TestNXActionSimpleActions(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		action   Action
+		expected string
+		check    func(act Action) bool
+	}{
+		{name: "exit", action: NewNXActionExit(), expected: "ffff0010000023200011000000000000"},
+		{name: "ct_clear", action: NewNXActionCTClear(), expected: "ffff001000002320002b000000000000"},
+		{name: "pop_queue", action: NewNXActionPopQueue(), expected: "ffff0010000023200005000000000000"},
+		{name: "drop_spoofed_arp", action: NewNXActionDropSpoofedARP(), expected: "ffff0010000023200003000000000000"},
+		{
+			name: "fin_timeout", action: NewNXActionFinTimeout(10, 20), expected: "ffff0010000023200013000a00140000",
+			check: func(act Action) bool {
+				a := act.(*NXActionFinTimeout)
+				return a.FinIdleTimeout == 10 && a.FinHardTimeout == 20
+			},
+		},
+		{
+			name: "output_trunc", action: NewNXActionOutputTrunc(1, 100), expected: "ffff0010000023200027000100000064",
+			check: func(act Action) bool {
+				a := act.(*NXActionOutputTrunc)
+				return a.OutPort == 1 && a.MaxLen == 100
+			},
+		},
+		{
+			name: "set_queue", action: NewNXActionSetQueue(5), expected: "ffff0010000023200004000000000005",
+			check: func(act Action) bool {
+				return act.(*NXActionSetQueue).QueueID == 5
+			},
+		},
+		{
+			name: "set_tunnel", action: NewNXActionSetTunnel(0x12345678), expected: "ffff0010000023200002000012345678",
+			check: func(act Action) bool {
+				a := act.(*NXActionSetTunnel)
+				return a.Subtype == NXAST_SET_TUNNEL && a.TunnelID == 0x12345678
+			},
+		},
+		{
+			name: "set_tunnel64", action: NewNXActionSetTunnel64(0x123456789), expected: "ffff00180000232000090000000000000000000123456789",
+			check: func(act Action) bool {
+				a := act.(*NXActionSetTunnel)
+				return a.Subtype == NXAST_SET_TUNNEL_V6 && a.TunnelID == 0x123456789
+			},
+		},
+	} {
+		data, err := tc.action.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal %s action: %v", tc.name, err)
+		}
+		if hex.EncodeToString(data) != tc.expected {
+			t.Errorf("Marshaled %s action %x is not equal to %s", tc.name, data, tc.expected)
+		}
+		act, err := DecodeAction(data)
+		if err != nil {
+			t.Fatalf("Failed to decode %s action: %v", tc.name, err)
+		}
+		if reflect.TypeOf(act) != reflect.TypeOf(tc.action) {
+			t.Errorf("Decoded %s action has unexpected type %T", tc.name, act)
+		}
+		if tc.check != nil && !tc.check(act) {
+			t.Errorf("Decoded %s action %+v is not equal to %+v", tc.name, act, tc.action)
+		}
+		newData, _ := act.MarshalBinary()
+		if !bytes.Equal(data, newData) {
+			t.Errorf("Re-marshaled %s action %x is not equal to %x", tc.name, newData, data)
+		}
+	}
+
+	// Subtypes which are not implemented must be reported as an error instead of a nil action.
+	data, _ := hex.DecodeString("ffff001000002320000b000000000000")
+	if _, err := DecodeAction(data); err == nil {
+		t.Errorf("Expected an error when decoding an unimplemented NX action")
+	}
+	data, _ = hex.DecodeString("ffff0010000023200fff000000000000")
+	if _, err := DecodeAction(data); err == nil {
+		t.Errorf("Expected an error when decoding an unknown NX action")
+	}
+}
+
+func TestDatapathMetadataMatchFields(t *testing.T) {
+	markMask := uint32(0xffff)
+	hashMask := uint32(0xff)
+	tunFlagsMask := uint16(NX_TUN_FLAG_OAM)
+	gbpFlagsMask := uint8(0xc0)
+	xxregMask := [16]byte{0xff, 0xff, 0xff, 0xff}
+	mustField := func(field *MatchField, err error) *MatchField {
+		if err != nil {
+			t.Fatalf("Failed to create MatchField: %v", err)
+		}
+		return field
+	}
+	for _, tc := range []struct {
+		field    *MatchField
+		expected string
+	}{
+		{field: NewPktMarkMatchField(100, nil), expected: "0001420400000064"},
+		{field: NewPktMarkMatchField(1, &markMask), expected: "00014308000000010000ffff"},
+		{field: NewDpHashMatchField(0x2a, &hashMask), expected: "000147080000002a000000ff"},
+		{field: NewRecircIDMatchField(5), expected: "0001480400000005"},
+		{field: NewConjIDMatchField(10), expected: "00014a040000000a"},
+		{field: NewTunFlagsMatchField(NX_TUN_FLAG_OAM, &tunFlagsMask), expected: "0001d10400010001"},
+		{field: NewTunGbpIDMatchField(0x1234, nil), expected: "00014c021234"},
+		{field: NewTunGbpFlagsMatchField(0x40, &gbpFlagsMask), expected: "00014f0240c0"},
+		{field: mustField(NewXRegMatchField(1, 0x1122334455667788, nil)), expected: "800102081122334455667788"},
+		{field: mustField(NewXRegMatchField(3, 0xa00000000, NewNXRange(32, 63))), expected: "800107100000000a00000000ffffffff00000000"},
+		{field: mustField(NewXRegMatchFieldWithMask(7, 0x1, 0x3)), expected: "80010f1000000000000000010000000000000003"},
+		{field: mustField(NewXXRegMatchField(0, [16]byte{15: 1}, nil)), expected: "0001de1000000000000000000000000000000001"},
+		{field: mustField(NewXXRegMatchField(2, [16]byte{0: 0xfd}, NewNXRange(64, 127))), expected: "0001e320fd000000000000000000000000000000ffffffffffffffff0000000000000000"},
+		{field: mustField(NewXXRegMatchFieldWithMask(3, [16]byte{0: 0x0a}, &xxregMask)), expected: "0001e5200a000000000000000000000000000000ffffffff000000000000000000000000"},
+	} {
+		data, err := tc.field.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal MatchField: %v", err)
+		}
+		if hex.EncodeToString(data) != tc.expected {
+			t.Errorf("Marshaled bytes %x are not equal to %s", data, tc.expected)
+		}
+		newField := new(MatchField)
+		if err = newField.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal MatchField: %v", err)
+		}
+		if !reflect.DeepEqual(newField.Value, tc.field.Value) || !reflect.DeepEqual(newField.Mask, tc.field.Mask) {
+			t.Errorf("Unmarshaled MatchField %+v is not equal to %+v", newField, tc.field)
+		}
+	}
+
+	if mask := NewNXRange(32, 63).ToUint64Mask(); mask != 0xffffffff00000000 {
+		t.Errorf("Unexpected uint64 mask %x", mask)
+	}
+	if mask := NewNXRange(4, 11).ToUint128Mask(); mask != [16]byte{14: 0x0f, 15: 0xf0} {
+		t.Errorf("Unexpected uint128 mask %x", mask)
+	}
+
+	// load:0xfd00000000000000->NXM_NX_XXREG0[64..127]
+	xxreg0, _ := FindFieldHeaderByName("NXM_NX_XXREG0", false)
+	data, _ := hex.DecodeString("ffff0018000023200007103f0001de10fd00000000000000")
+	newData, _ := NewNXActionRegLoad(NewNXRange(64, 127).ToOfsBits(), xxreg0, 0xfd00000000000000).MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+	act, err := DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionRegLoad: %v", err)
+	}
+	load := act.(*NXActionRegLoad)
+	if load.DstReg.Field != NXM_NX_XXREG0 || load.OfsNbits != 0x103f || load.Value != 0xfd00000000000000 {
+		t.Errorf("Unexpected NXActionRegLoad %+v", load)
+	}
+
+	// move:NXM_NX_XXREG0[]->NXM_NX_XXREG1[]
+	data, _ = hex.DecodeString("ffff00180000232000060080000000000001de100001e010")
+	xxreg1, _ := FindFieldHeaderByName("NXM_NX_XXREG1", false)
+	newData, _ = NewNXActionRegMove(128, 0, 0, xxreg0, xxreg1).MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+	act, err = DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionRegMove: %v", err)
+	}
+	if move := act.(*NXActionRegMove); move.SrcField.Field != NXM_NX_XXREG0 || move.DstField.Field != NXM_NX_XXREG1 || move.Nbits != 128 {
+		t.Errorf("Unexpected NXActionRegMove %+v", move)
+	}
+
+	// set_field:0xfd000000000000000000000000000001->xxreg0 loads all the 128 bits, which NXActionRegLoad can't carry.
+	field := mustField(NewXXRegMatchField(0, [16]byte{0: 0xfd, 15: 1}, nil))
+	data, _ = hex.DecodeString("ffff00200000232000210001de10fd0000000000000000000000000000010000")
+	newData, _ = NewNXActionRegLoad2(field).MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Marshaled bytes %x are not equal to %x", newData, data)
+	}
+	act, err = DecodeAction(data)
+	if err != nil {
+		t.Fatalf("Failed to decode NXActionRegLoad2: %v", err)
+	}
+	if load2 := act.(*NXActionRegLoad2); !reflect.DeepEqual(load2.DstField.Value, field.Value) {
+		t.Errorf("Unexpected NXActionRegLoad2 %+v", load2)
+	}
+
+	for _, idx := range []int{-1, NXM_NX_XREG_COUNT} {
+		if _, err = NewXRegMatchField(idx, 0, nil); err == nil {
+			t.Errorf("Expected an error for xreg index %d", idx)
+		}
+		if _, err = NewXRegMatchFieldWithMask(idx, 0, 1); err == nil {
+			t.Errorf("Expected an error for xreg index %d", idx)
+		}
+	}
+	for _, idx := range []int{-1, NXM_NX_XXREG_COUNT} {
+		if _, err = NewXXRegMatchField(idx, [16]byte{}, nil); err == nil {
+			t.Errorf("Expected an error for xxreg index %d", idx)
+		}
+		if _, err = NewXXRegMatchFieldWithMask(idx, [16]byte{}, &xxregMask); err == nil {
+			t.Errorf("Expected an error for xxreg index %d", idx)
+		}
+	}
+}
+
+func TestCTFlushZone(t *testing.T) {
+	message := NewCTFlushZone(5)
+	data, err := message.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to Marshal message: %v", err)
+	}
+	expected := "000023200000001d0000000000000005"
+	if hex.EncodeToString(data[8:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[8:], expected)
+	}
+	newMessage := new(VendorHeader)
+	if err = newMessage.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to UnMarshal message: %v", err)
+	}
+	flush, ok := newMessage.VendorData.(*CTFlushZone)
+	if !ok {
+		t.Fatalf("Failed to cast CTFlushZone from result")
+	}
+	if flush.ZoneID != 5 {
+		t.Errorf("Zone ID %d is not equal to 5", flush.ZoneID)
+	}
+}
+
+func TestCTFlush(t *testing.T) {
+	// ovs-ofctl flush-conntrack br0 zone=5 'ct_nw_src=10.1.1.2,ct_nw_proto=6,ct_tp_dst=80' 'ct_nw_dst=10.1.1.2,ct_tp_src=80'
+	// OVS encodes the original tuple, the reply tuple and then the zone, see nx_ct_flush in nicira-ext.h.
+	message := NewCTFlush(6, CT_FLUSH_AF_INET, []Property{
+		NewCTFlushPropTuple(false, []Property{
+			NewCTFlushPropSrc(net.ParseIP("10.1.1.2")),
+			NewCTFlushPropDstPort(80),
+		}),
+		NewCTFlushPropTuple(true, []Property{
+			NewCTFlushPropDst(net.ParseIP("10.1.1.2")),
+			NewCTFlushPropSrcPort(80),
+		}),
+		NewCTFlushPropZoneID(5),
+	})
+	data, err := message.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to Marshal message: %v", err)
+	}
+	expected := "0000232000000020" + "0602000000000000" +
+		"0000002800000000" + "0000001400000000000000000000ffff0a01010200000000" + "0003000600500000" +
+		"0001002800000000" + "0001001400000000000000000000ffff0a01010200000000" + "0002000600500000" +
+		"0002000600050000"
+	if hex.EncodeToString(data[8:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[8:], expected)
+	}
+	if int(message.Len()) != len(data) {
+		t.Errorf("Message length %d is not equal to %d", message.Len(), len(data))
+	}
+	newMessage, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	flush, ok := newMessage.(*VendorHeader).VendorData.(*CTFlush)
+	if !ok {
+		t.Fatalf("Failed to cast CTFlush from result")
+	}
+	if flush.IPProto != 6 || flush.Family != CT_FLUSH_AF_INET || len(flush.Props) != 3 {
+		t.Fatalf("Unexpected CTFlush %+v", flush)
+	}
+	if zone := flush.Props[2].(*CTFlushPropUint16); zone.Type != NXT_CT_ZONE_ID || zone.Value != 5 {
+		t.Errorf("Unexpected zone property %+v", zone)
+	}
+	if orig := flush.Props[0].(*CTFlushPropTuple); orig.Type != NXT_CT_ORIG_TUPLE || len(orig.Props) != 2 {
+		t.Errorf("Unexpected original tuple property %+v", orig)
+	}
+	reply := flush.Props[1].(*CTFlushPropTuple)
+	if reply.Type != NXT_CT_REPLY_TUPLE || len(reply.Props) != 2 {
+		t.Fatalf("Unexpected reply tuple property %+v", reply)
+	}
+	if addr := reply.Props[0].(*CTFlushPropAddr); addr.Type != NXT_CT_TUPLE_DST || !addr.Addr.Equal(net.ParseIP("10.1.1.2")) {
+		t.Errorf("Unexpected address property %+v", addr)
+	}
+	newData, _ := newMessage.MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Unmarshaled message %x is not equal to %x", newData, data)
+	}
+
+	// icmp_id=1,icmp_type=8,icmp_code=0 in the original tuple.
+	icmpTuple := NewCTFlushPropTuple(false, []Property{
+		NewCTFlushPropICMPID(1),
+		NewCTFlushPropICMPType(8),
+		NewCTFlushPropICMPCode(0),
+	})
+	data, _ = icmpTuple.MarshalBinary()
+	expected = "0000002000000000" + "0004000600010000" + "0005000508000000" + "0006000500000000"
+	if hex.EncodeToString(data) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data, expected)
+	}
+	prop, err := DecodeCTFlushProp(data)
+	if err != nil {
+		t.Fatalf("Failed to decode CTFlushPropTuple: %v", err)
+	}
+	if icmpType := prop.(*CTFlushPropTuple).Props[1].(*CTFlushPropUint8); icmpType.Value != 8 {
+		t.Errorf("Unexpected ICMP type property %+v", icmpType)
+	}
+	if _, err = DecodeCTFlushProp([]byte{0x00, 0x10, 0x00, 0x08, 0, 0, 0, 0}); err == nil {
+		t.Error("Unknown CTFlush property should not be decoded")
+	}
+}
+
+func TestNXFlowStats(t *testing.T) {
+	// ovs-ofctl dump-flows br0 reg0=1
+	request := &MultipartRequest{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{NewNXFlowStatsRequest(OFPTT_ALL, []MatchField{*NewRegMatchField(0, 1, nil)})},
+	}
+	request.Header.Type = Type_MultiPartRequest
+	data, err := request.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal MultipartRequest: %v", err)
+	}
+	expected := "0000232000000000ffff0008ff0000000001000400000001"
+	if hex.EncodeToString(data[16:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[16:], expected)
+	}
+	msg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse MultipartRequest: %v", err)
+	}
+	req, ok := msg.(*MultipartRequest).Body[0].(*NXFlowStatsRequest)
+	if !ok {
+		t.Fatalf("Failed to cast NXFlowStatsRequest from result")
+	}
+	if req.Subtype != NXST_FLOW || req.TableID != OFPTT_ALL || req.OutPort != NXOFPP_NONE || len(req.Match) != 1 {
+		t.Errorf("Unexpected NXFlowStatsRequest %+v", req)
+	}
+
+	// priority=100,tcp,reg0=0x1 actions=ct(table=1)
+	stats := &NXFlowStats{
+		TableID:     2,
+		DurationSec: 10,
+		Priority:    100,
+		IdleAge:     3,
+		HardAge:     11,
+		Cookie:      0x1234,
+		PacketCount: 5,
+		ByteCount:   320,
+		Match:       []MatchField{*NewEthTypeField(0x0800), *NewIpProtoField(6), *NewRegMatchField(0, 1, nil)},
+		Actions:     []Action{NewNXActionConnTrack().Table(1)},
+	}
+	reply := &MultipartReply{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{&NXFlowStatsReply{NXStatsHeader: NewNXStatsHeader(NXST_FLOW), Stats: []*NXFlowStats{stats, stats}}},
+	}
+	reply.Header.Type = Type_MultiPartReply
+	data, err = reply.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal MultipartReply: %v", err)
+	}
+	expected = "0000232000000000" + "00600200" + "0000000a0000000000640000000000130003000b" +
+		"00000000000012340000000000000005" + "0000000000000140" +
+		"80000a020800" + "80001401" + "06" + "0001000400000001" + "00"
+	if hex.EncodeToString(data[16:16+len(expected)/2]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[16:16+len(expected)/2], expected)
+	}
+	msg, err = Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse MultipartReply: %v", err)
+	}
+	flowReply, ok := msg.(*MultipartReply).Body[0].(*NXFlowStatsReply)
+	if !ok {
+		t.Fatalf("Failed to cast NXFlowStatsReply from result")
+	}
+	if len(flowReply.Stats) != 2 {
+		t.Fatalf("Unexpected count of NXFlowStats %d", len(flowReply.Stats))
+	}
+	newStats := flowReply.Stats[1]
+	if newStats.IdleAge != 3 || newStats.HardAge != 11 || newStats.Cookie != 0x1234 || newStats.MatchLen != 19 {
+		t.Errorf("Unexpected NXFlowStats %+v", newStats)
+	}
+	if len(newStats.Match) != 3 || newStats.Match[2].Field != NXM_NX_REG0 || len(newStats.Actions) != 1 {
+		t.Errorf("Unexpected match %+v or actions %+v in NXFlowStats", newStats.Match, newStats.Actions)
+	}
+	newData, _ := msg.MarshalBinary()
+	if !bytes.Equal(data, newData) {
+		t.Errorf("Unmarshaled message %x is not equal to %x", newData, data)
+	}
+
+	// ovs-ofctl dump-aggregate br0 table=2
+	request.Body = []util.Message{NewNXAggregateStatsRequest(2, nil)}
+	data, _ = request.MarshalBinary()
+	expected = "0000232000000001ffff000002000000"
+	if hex.EncodeToString(data[16:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[16:], expected)
+	}
+	aggReply := NewNXAggregateStatsReply()
+	aggReply.PacketCount = 10
+	aggReply.ByteCount = 640
+	aggReply.FlowCount = 2
+	reply.Body = []util.Message{aggReply}
+	data, _ = reply.MarshalBinary()
+	msg, err = Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse MultipartReply: %v", err)
+	}
+	if newAggReply, ok := msg.(*MultipartReply).Body[0].(*NXAggregateStatsReply); !ok || *newAggReply.NXStatsHeader != *aggReply.NXStatsHeader || newAggReply.FlowCount != 2 || newAggReply.ByteCount != 640 {
+		t.Errorf("Unexpected NXAggregateStatsReply %+v", msg.(*MultipartReply).Body[0])
+	}
+}
+
+func TestNXFlowMonitor(t *testing.T) {
+	// ovs-ofctl monitor br0 watch:table=255
+	request := &MultipartRequest{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{NewNXFlowMonitorRequest(NewNXFlowMonitor(1, NXFMF_INITIAL|NXFMF_ADD|NXFMF_DELETE|NXFMF_MODIFY|NXFMF_ACTIONS, OFPTT_ALL, nil))},
+	}
+	request.Header.Type = Type_MultiPartRequest
+	data, err := request.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal MultipartRequest: %v", err)
+	}
+	expected := "0000232000000002" + "00000001001fffff0000ff0000000000"
+	if hex.EncodeToString(data[16:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[16:], expected)
+	}
+	msg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse MultipartRequest: %v", err)
+	}
+	monitorRequest, ok := msg.(*MultipartRequest).Body[0].(*NXFlowMonitorRequest)
+	if !ok {
+		t.Fatalf("Failed to cast NXFlowMonitorRequest from result")
+	}
+	if len(monitorRequest.Monitors) != 1 || monitorRequest.Monitors[0].Flags != 0x1f || monitorRequest.Monitors[0].TableID != OFPTT_ALL {
+		t.Errorf("Unexpected NXFlowMonitorRequest %+v", monitorRequest)
+	}
+
+	added := NewNXFlowUpdateFull(NXFME_ADDED)
+	added.Priority = 100
+	added.TableID = 1
+	added.Cookie = 0x10
+	added.Match = []MatchField{*NewRegMatchField(0, 1, nil)}
+	added.Actions = []Action{NewNXActionResubmitTableAction(OFPP_IN_PORT, 2)}
+	deleted := NewNXFlowUpdateFull(NXFME_DELETED)
+	deleted.Reason = RR_DELETE
+	reply := &MultipartReply{
+		Header: NewOfp13Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{&NXFlowMonitorReply{NXStatsHeader: NewNXStatsHeader(NXST_FLOW_MONITOR), Updates: []util.Message{added, deleted, NewNXFlowUpdateAbbrev(0x20)}}},
+	}
+	reply.Header.Type = Type_MultiPartReply
+	data, err = reply.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal MultipartReply: %v", err)
+	}
+	expected = "0000232000000002" + "00300000000000640000000000080100" + "0000000000000010" + "0001000400000001" +
+		"ffff001000002320000efff802000000" + "00180001000200000000000000000000" + "0000000000000000" + "0008000300000020"
+	if hex.EncodeToString(data[16:]) != expected {
+		t.Errorf("Marshaled bytes %x are not equal to %s", data[16:], expected)
+	}
+	msg, err = Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse MultipartReply: %v", err)
+	}
+	monitorReply, ok := msg.(*MultipartReply).Body[0].(*NXFlowMonitorReply)
+	if !ok {
+		t.Fatalf("Failed to cast NXFlowMonitorReply from result")
+	}
+	if len(monitorReply.Updates) != 3 {
+		t.Fatalf("Unexpected count of flow updates %d", len(monitorReply.Updates))
+	}
+	if update := monitorReply.Updates[0].(*NXFlowUpdateFull); update.Event != NXFME_ADDED || update.Priority != 100 || len(update.Match) != 1 || len(update.Actions) != 1 {
+		t.Errorf("Unexpected NXFlowUpdateFull %+v", update)
+	}
+	if update := monitorReply.Updates[1].(*NXFlowUpdateFull); update.Event != NXFME_DELETED || update.Reason != RR_DELETE {
+		t.Errorf("Unexpected NXFlowUpdateFull %+v", update)
+	}
+	if update := monitorReply.Updates[2].(*NXFlowUpdateAbbrev); update.Xid != 0x20 {
+		t.Errorf("Unexpected NXFlowUpdateAbbrev %+v", update)
+	}
+
+	for _, tc := range []struct {
+		message     *VendorHeader
+		expected    string
+		expectedLen uint16
+	}{
+		{message: NewFlowMonitorCancel(1), expected: "000023200000001500000001", expectedLen: 20},
+		{message: NewFlowMonitorPaused(), expected: "0000232000000016", expectedLen: 16},
+		{message: NewFlowMonitorResumed(), expected: "0000232000000017", expectedLen: 16},
+	} {
+		data, err = tc.message.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal message: %v", err)
+		}
+		if hex.EncodeToString(data[8:]) != tc.expected || tc.message.Len() != tc.expectedLen {
+			t.Errorf("Marshaled bytes %x are not equal to %s", data[8:], tc.expected)
+		}
+		msg, err = Parse(data)
+		if err != nil {
+			t.Fatalf("Failed to parse message: %v", err)
+		}
+		newMessage := msg.(*VendorHeader)
+		if newMessage.ExperimenterType != tc.message.ExperimenterType || !reflect.DeepEqual(newMessage.VendorData, tc.message.VendorData) {
+			t.Errorf("Unexpected message %+v", newMessage)
+		}
+	}
+}
+
+func TestNXTControllerConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		message  *VendorHeader
+		expected string
+	}{
+		{
+			name:     "set flow format",
+			message:  NewSetFlowFormat(NXFF_NXM),
+			expected: "000023200000000c00000002",
+		},
+		{
+			name:     "flow mod table id",
+			message:  NewFlowModTableID(true),
+			expected: "000023200000000f0100000000000000",
+		},
+		{
+			name: "set async config2",
+			message: NewSetAsyncConfig2(
+				NewAsyncConfigPropReasons(ACPT_PACKET_IN_MASTER, 1<<R_ACTION),
+				NewAsyncConfigPropReasons(ACPT_TABLE_STATUS_MASTER, 3),
+			),
+			expected: "000023200000001b00010008000000020009000800000003",
+		},
+	} {
+		data, err := tc.message.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to Marshal %s message: %v", tc.name, err)
+		}
+		if hex.EncodeToString(data[8:]) != tc.expected {
+			t.Errorf("Marshaled bytes %x of %s message are not equal to %s", data[8:], tc.name, tc.expected)
+		}
+		msg, err := Parse(data)
+		if err != nil {
+			t.Fatalf("Failed to parse %s message: %v", tc.name, err)
+		}
+		newMessage, ok := msg.(*VendorHeader)
+		if !ok {
+			t.Fatalf("Failed to cast VendorHeader from %s message", tc.name)
+		}
+		newData, _ := newMessage.MarshalBinary()
+		if hex.EncodeToString(newData) != hex.EncodeToString(data) {
+			t.Errorf("Parsed %s message %x is not equal to %x", tc.name, newData, data)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package openflow13
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"net"
 
 	"antrea.io/libOpenflow/protocol"
 	"antrea.io/libOpenflow/util"
@@ -10,16 +12,21 @@ import (
 
 // Nicira extension messages.
 const (
-	Type_SetFlowFormat     = 12
-	Type_FlowModTableId    = 15
-	Type_SetPacketInFormat = 16
-	Type_SetControllerId   = 20
-	Type_TlvTableMod       = 24
-	Type_TlvTableRequest   = 25
-	Type_TlvTableReply     = 26
-	Type_Resume            = 28
-	Type_CtFlushZone       = 29
-	Type_PacketIn2         = 30
+	Type_SetFlowFormat      = 12
+	Type_FlowModTableId     = 15
+	Type_SetPacketInFormat  = 16
+	Type_SetControllerId    = 20
+	Type_FlowMonitorCancel  = 21
+	Type_FlowMonitorPaused  = 22
+	Type_FlowMonitorResumed = 23
+	Type_TlvTableMod        = 24
+	Type_TlvTableRequest    = 25
+	Type_TlvTableReply      = 26
+	Type_SetAsyncConfig2    = 27
+	Type_Resume             = 28
+	Type_CtFlushZone        = 29
+	Type_PacketIn2          = 30
+	Type_CtFlush            = 32
 )
 
 // ofpet_tlv_table_mod_failed_code 1.3
@@ -66,6 +73,9 @@ func (p *PacketInFormat) MarshalBinary() (data []byte, err error) {
 }
 
 func (p *PacketInFormat) UnmarshalBinary(data []byte) error {
+	if len(data) < int(p.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full PacketInFormat message")
+	}
 	n := 0
 	p.Spif = binary.BigEndian.Uint32(data[n:])
 	return nil
@@ -111,6 +121,286 @@ func NewSetControllerID(id uint16) *VendorHeader {
 	return msg
 }
 
+// nx_flow_format
+const (
+	NXFF_OPENFLOW10 = 0 /* Standard OpenFlow 1.0 compatible. */
+	NXFF_NXM        = 2 /* Nicira extended match. */
+)
+
+// FlowFormat is the body of NXT_SET_FLOW_FORMAT, which sets the format of the match in the flow mods and the
+// replies of the Nicira messages sent and received on the connection.
+type FlowFormat struct {
+	Format uint32
+}
+
+func (f *FlowFormat) Len() uint16 {
+	return 4
+}
+
+func (f *FlowFormat) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	binary.BigEndian.PutUint32(data, f.Format)
+	return data, nil
+}
+
+func (f *FlowFormat) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowFormat message")
+	}
+	f.Format = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+// NewSetFlowFormat creates the NXT_SET_FLOW_FORMAT message with a NXFF_* format.
+func NewSetFlowFormat(format uint32) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetFlowFormat)
+	msg.VendorData = &FlowFormat{
+		Format: format,
+	}
+	return msg
+}
+
+// FlowModTableID is the body of NXT_FLOW_MOD_TABLE_ID. When it is enabled, the switch takes the table ID from the
+// high 8 bits of the command of the OpenFlow 1.0 flow mods sent on the connection.
+type FlowModTableID struct {
+	Enable bool
+}
+
+func (f *FlowModTableID) Len() uint16 {
+	return 8
+}
+
+func (f *FlowModTableID) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	if f.Enable {
+		data[0] = 1
+	}
+	// 7 bytes for padding
+	return data, nil
+}
+
+func (f *FlowModTableID) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowModTableID message")
+	}
+	f.Enable = data[0] != 0
+	return nil
+}
+
+func NewFlowModTableID(enable bool) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_FlowModTableId)
+	msg.VendorData = &FlowModTableID{
+		Enable: enable,
+	}
+	return msg
+}
+
+// ofp_async_config_prop_type of OpenFlow 1.4, which is used by NXT_SET_ASYNC_CONFIG2.
+const (
+	ACPT_PACKET_IN_SLAVE       = 0      /* Packet-in mask for slave. */
+	ACPT_PACKET_IN_MASTER      = 1      /* Packet-in mask for master. */
+	ACPT_PORT_STATUS_SLAVE     = 2      /* Port-status mask for slave. */
+	ACPT_PORT_STATUS_MASTER    = 3      /* Port-status mask for master. */
+	ACPT_FLOW_REMOVED_SLAVE    = 4      /* Flow removed mask for slave. */
+	ACPT_FLOW_REMOVED_MASTER   = 5      /* Flow removed mask for master. */
+	ACPT_ROLE_STATUS_SLAVE     = 6      /* Role status mask for slave. */
+	ACPT_ROLE_STATUS_MASTER    = 7      /* Role status mask for master. */
+	ACPT_TABLE_STATUS_SLAVE    = 8      /* Table status mask for slave. */
+	ACPT_TABLE_STATUS_MASTER   = 9      /* Table status mask for master. */
+	ACPT_REQUESTFORWARD_SLAVE  = 10     /* RequestForward mask for slave. */
+	ACPT_REQUESTFORWARD_MASTER = 11     /* RequestForward mask for master. */
+	ACPT_EXPERIMENTER_SLAVE    = 0xFFFE /* Experimenter for slave. */
+	ACPT_EXPERIMENTER_MASTER   = 0xFFFF /* Experimenter for master. */
+)
+
+type AsyncConfigPropHeader struct {
+	Type   uint16
+	Length uint16
+}
+
+func (h *AsyncConfigPropHeader) Len() uint16 {
+	return 4
+}
+
+func (h *AsyncConfigPropHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(h.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], h.Type)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], h.Length)
+	return data, nil
+}
+
+func (h *AsyncConfigPropHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(h.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full AsyncConfigPropHeader message")
+	}
+	n := 0
+	h.Type = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	h.Length = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// ofp_async_config_prop_reasons
+type AsyncConfigPropReasons struct {
+	Header AsyncConfigPropHeader
+	Mask   uint32
+}
+
+// NewAsyncConfigPropReasons creates the ACPT_* property enabling the reasons in mask, each bit of mask is a reason
+// of the message, e.g., 1 << R_ACTION for ACPT_PACKET_IN_MASTER.
+func NewAsyncConfigPropReasons(propType uint16, mask uint32) *AsyncConfigPropReasons {
+	p := new(AsyncConfigPropReasons)
+	p.Header.Type = propType
+	p.Header.Length = p.Len()
+	p.Mask = mask
+	return p
+}
+
+func (p *AsyncConfigPropReasons) Len() uint16 {
+	return p.Header.Len() + 4
+}
+
+func (p *AsyncConfigPropReasons) MarshalBinary() (data []byte, err error) {
+	p.Header.Length = p.Len()
+	data, err = p.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(data[p.Header.Len():], p.Mask)
+	return data, nil
+}
+
+func (p *AsyncConfigPropReasons) UnmarshalBinary(data []byte) error {
+	if len(data) < int(p.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full AsyncConfigPropReasons message")
+	}
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	p.Mask = binary.BigEndian.Uint32(data[p.Header.Len():])
+	return nil
+}
+
+// ofp_async_config_prop_experimenter
+type AsyncConfigPropExperimenter struct {
+	Header           AsyncConfigPropHeader
+	Experimenter     uint32
+	ExperimenterType uint32
+	Data             []byte
+}
+
+func (p *AsyncConfigPropExperimenter) Len() uint16 {
+	n := p.Header.Len() + 8 + uint16(len(p.Data))
+	// Round it to closest multiple of 8
+	return ((n + 7) / 8) * 8
+}
+
+func (p *AsyncConfigPropExperimenter) MarshalBinary() (data []byte, err error) {
+	p.Header.Length = p.Header.Len() + 8 + uint16(len(p.Data))
+	data = make([]byte, int(p.Len()))
+	b, err := p.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	n := copy(data, b)
+	binary.BigEndian.PutUint32(data[n:], p.Experimenter)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.ExperimenterType)
+	n += 4
+	copy(data[n:], p.Data)
+	return data, nil
+}
+
+func (p *AsyncConfigPropExperimenter) UnmarshalBinary(data []byte) error {
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if p.Header.Length < 12 || len(data) < int(p.Header.Length) {
+		return errors.New("the []byte is too short to unmarshal a full AsyncConfigPropExperimenter message")
+	}
+	n := int(p.Header.Len())
+	p.Experimenter = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.ExperimenterType = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.Data = make([]byte, int(p.Header.Length)-n)
+	copy(p.Data, data[n:])
+	return nil
+}
+
+// NewAsyncConfigProp returns a new property to unmarshal the asynchronous configuration property encoded in data. The
+// properties are the same in OFPT_SET_ASYNC of OpenFlow 1.4+.
+func NewAsyncConfigProp(data []byte) (util.Message, error) {
+	propType := binary.BigEndian.Uint16(data)
+	switch {
+	case propType <= ACPT_REQUESTFORWARD_MASTER:
+		return new(AsyncConfigPropReasons), nil
+	case propType == ACPT_EXPERIMENTER_SLAVE || propType == ACPT_EXPERIMENTER_MASTER:
+		return new(AsyncConfigPropExperimenter), nil
+	default:
+		return nil, fmt.Errorf("unsupported async config property type: %v", propType)
+	}
+}
+
+// AsyncConfig2 is the body of NXT_SET_ASYNC_CONFIG2, which carries the asynchronous configuration properties of
+// OFPT_SET_ASYNC in OpenFlow 1.4+. The properties not included keep their current value.
+type AsyncConfig2 struct {
+	Properties []util.Message
+}
+
+func (a *AsyncConfig2) Len() uint16 {
+	n := uint16(0)
+	for _, p := range a.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (a *AsyncConfig2) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 0, a.Len())
+	for _, p := range a.Properties {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+func (a *AsyncConfig2) UnmarshalBinary(data []byte) error {
+	a.Properties = nil
+	n := 0
+	for n < len(data) {
+		if len(data[n:]) < 4 {
+			return errors.New("the []byte is too short to unmarshal a full AsyncConfig2 message")
+		}
+		p, err := NewAsyncConfigProp(data[n:])
+		if err != nil {
+			return err
+		}
+		if err := p.UnmarshalBinary(data[n:]); err != nil {
+			return err
+		}
+		n += int(p.Len())
+		a.Properties = append(a.Properties, p)
+	}
+	return nil
+}
+
+// NewSetAsyncConfig2 creates the NXT_SET_ASYNC_CONFIG2 message with the properties, e.g., the properties created by
+// NewAsyncConfigPropReasons.
+func NewSetAsyncConfig2(props ...util.Message) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetAsyncConfig2)
+	msg.VendorData = &AsyncConfig2{
+		Properties: props,
+	}
+	return msg
+}
+
 type TLVTableMap struct {
 	OptClass  uint16
 	OptType   uint8
@@ -374,13 +664,20 @@ func (p *ContinuationPropStack) UnmarshalBinary(data []byte) error {
 		return errors.New("the []byte is too short to unmarshal a full ContinuationPropStack message")
 	}
 	n += int(p.PropHeader.Len())
-	for _, eachStack := range p.Stack {
-		data[n] = eachStack
-		n++
-	}
+	p.Stack = make([]uint8, int(p.Length)-n)
+	copy(p.Stack, data[n:p.Length])
 	return nil
 }
 
+// NewContinuationPropStack creates a property with one entry of the register stack, which is the
+// value pushed by a NXActionStackPush action.
+func NewContinuationPropStack(stack []uint8) *ContinuationPropStack {
+	return &ContinuationPropStack{
+		PropHeader: &PropHeader{Type: NXCPT_STACK},
+		Stack:      stack,
+	}
+}
+
 type ContinuationPropMirrors struct {
 	*PropHeader /* Type: NXCPT_MIRRORS */
 	Mirrors     uint32
@@ -733,6 +1030,8 @@ func DecodeContinuationProp(data []byte) (Property, error) {
 		p = new(ContinuationPropActionSet)
 	case NXCPT_ODP_PORT:
 		p = new(ContinuationPropOdpPort)
+	default:
+		return nil, fmt.Errorf("unknown ContinuationProp type: %v", t)
 	}
 	err := p.UnmarshalBinary(data)
 	if err != nil {
@@ -1174,6 +1473,23 @@ func (p *PacketIn2PropContinuation) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// Props decodes the Continuation properties, e.g., the ContinuationPropStack properties which save
+// the register stack of the paused pipeline, one property per entry from the bottom of the stack.
+func (p *PacketIn2PropContinuation) Props() ([]Property, error) {
+	var props []Property
+	// The nested properties are aligned to 8 bytes, following 4 bytes of padding after the header.
+	n := 4
+	for n+4 <= len(p.Continuation) {
+		prop, err := DecodeContinuationProp(p.Continuation[n:])
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, prop)
+		n += int(prop.Len())
+	}
+	return props, nil
+}
+
 // Decode PacketIn2 Property types.
 func DecodePacketIn2Prop(data []byte) (Property, error) {
 	t := binary.BigEndian.Uint16(data[:2])
@@ -1306,12 +1622,475 @@ func NewResume(props []Property) *VendorHeader {
 	return msg
 }
 
-func decodeVendorData(experimenterType uint32, data []byte) (msg util.Message, err error) {
+// FlowMonitorCancel is the body of NXT_FLOW_MONITOR_CANCEL, which cancels the flow monitor created by the
+// NXST_FLOW_MONITOR request.
+type FlowMonitorCancel struct {
+	ID uint32
+}
+
+func (c *FlowMonitorCancel) Len() uint16 {
+	return 4
+}
+
+func (c *FlowMonitorCancel) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(c.Len()))
+	binary.BigEndian.PutUint32(data, c.ID)
+	return data, nil
+}
+
+func (c *FlowMonitorCancel) UnmarshalBinary(data []byte) error {
+	if len(data) < int(c.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowMonitorCancel message")
+	}
+	c.ID = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+func NewFlowMonitorCancel(id uint32) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_FlowMonitorCancel)
+	msg.VendorData = &FlowMonitorCancel{
+		ID: id,
+	}
+	return msg
+}
+
+// NewFlowMonitorPaused creates NXT_FLOW_MONITOR_PAUSED, which the switch sends without a body when it stops sending
+// the flow updates because the connection is too slow to consume them.
+func NewFlowMonitorPaused() *VendorHeader {
+	return NewNXTVendorHeader(Type_FlowMonitorPaused)
+}
+
+// NewFlowMonitorResumed creates NXT_FLOW_MONITOR_RESUMED, which the switch sends without a body after it has sent the
+// updates of the flows changed while the updates were paused.
+func NewFlowMonitorResumed() *VendorHeader {
+	return NewNXTVendorHeader(Type_FlowMonitorResumed)
+}
+
+// CTFlushZone is the body of NXT_CT_FLUSH_ZONE, which flushes all the conntrack entries in the zone.
+type CTFlushZone struct {
+	ZoneID uint16
+}
+
+func (c *CTFlushZone) Len() uint16 {
+	return 8
+}
+
+func (c *CTFlushZone) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(c.Len()))
+	// 6 bytes for padding
+	n := 6
+	binary.BigEndian.PutUint16(data[n:], c.ZoneID)
+	return data, nil
+}
+
+func (c *CTFlushZone) UnmarshalBinary(data []byte) error {
+	if len(data) < int(c.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushZone message")
+	}
+	n := 6
+	c.ZoneID = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+func NewCTFlushZone(zoneID uint16) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_CtFlushZone)
+	msg.VendorData = &CTFlushZone{
+		ZoneID: zoneID,
+	}
+	return msg
+}
+
+// The address families used in CTFlush, they are the values on Linux.
+const (
+	CT_FLUSH_AF_UNSPEC = 0
+	CT_FLUSH_AF_INET   = 2
+	CT_FLUSH_AF_INET6  = 10
+)
+
+// nx_ct_flush_tlv_type
+const (
+	NXT_CT_ORIG_TUPLE  = 0 /* Nested NXT_CT_TUPLE_* */
+	NXT_CT_REPLY_TUPLE = 1 /* Nested NXT_CT_TUPLE_* */
+	NXT_CT_ZONE_ID     = 2 /* be16 */
+)
+
+// nx_ct_flush_tuple_tlv_type
+const (
+	NXT_CT_TUPLE_SRC       = 0 /* be128 */
+	NXT_CT_TUPLE_DST       = 1 /* be128 */
+	NXT_CT_TUPLE_SRC_PORT  = 2 /* be16 */
+	NXT_CT_TUPLE_DST_PORT  = 3 /* be16 */
+	NXT_CT_TUPLE_ICMP_ID   = 4 /* be16 */
+	NXT_CT_TUPLE_ICMP_TYPE = 5 /* u8 */
+	NXT_CT_TUPLE_ICMP_CODE = 6 /* u8 */
+)
+
+// CTFlush is the body of NXT_CT_FLUSH, which flushes the conntrack entries matching the IP protocol, the address
+// family and the properties, e.g., the zone and the tuples of the original and reply directions. The entries in
+// all zones are flushed if the zone property is not set.
+type CTFlush struct {
+	IPProto uint8
+	Family  uint8
+	Props   []Property
+}
+
+func (c *CTFlush) Len() (n uint16) {
+	n = 8
+	for _, prop := range c.Props {
+		n += prop.Len()
+	}
+	return
+}
+
+func (c *CTFlush) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, c.Len())
+	n := 0
+	data[n] = c.IPProto
+	n += 1
+	data[n] = c.Family
+	n += 1
+	// 6 bytes for padding
+	n += 6
+
+	for _, prop := range c.Props {
+		b, err := prop.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += int(prop.Len())
+	}
+	return
+}
+
+func (c *CTFlush) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlush message")
+	}
+	n := 0
+	c.IPProto = data[n]
+	n += 1
+	c.Family = data[n]
+	n += 7
+
+	for n < len(data) {
+		prop, err := DecodeCTFlushProp(data[n:])
+		if err != nil {
+			return err
+		}
+		c.Props = append(c.Props, prop)
+		n += int(prop.Len())
+	}
+	return nil
+}
+
+func NewCTFlush(ipProto uint8, family uint8, props []Property) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_CtFlush)
+	msg.VendorData = &CTFlush{
+		IPProto: ipProto,
+		Family:  family,
+		Props:   props,
+	}
+	return msg
+}
+
+// CTFlushPropUint16 is a property with a 16-bit value, it is used by NXT_CT_ZONE_ID in CTFlush, and
+// NXT_CT_TUPLE_SRC_PORT, NXT_CT_TUPLE_DST_PORT and NXT_CT_TUPLE_ICMP_ID in CTFlushPropTuple.
+type CTFlushPropUint16 struct {
+	*PropHeader
+	Value uint16
+}
+
+func (p *CTFlushPropUint16) Len() (n uint16) {
+	return p.PropHeader.Len() + 4
+}
+
+func (p *CTFlushPropUint16) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 2
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	binary.BigEndian.PutUint16(data[n:], p.Value)
+	return
+}
+
+func (p *CTFlushPropUint16) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+2 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropUint16 message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Value = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// CTFlushPropUint8 is a property with an 8-bit value, it is used by NXT_CT_TUPLE_ICMP_TYPE and
+// NXT_CT_TUPLE_ICMP_CODE in CTFlushPropTuple.
+type CTFlushPropUint8 struct {
+	*PropHeader
+	Value uint8
+}
+
+func (p *CTFlushPropUint8) Len() (n uint16) {
+	return p.PropHeader.Len() + 4
+}
+
+func (p *CTFlushPropUint8) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 1
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	data[n] = p.Value
+	return
+}
+
+func (p *CTFlushPropUint8) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+1 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropUint8 message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Value = data[n]
+	return nil
+}
+
+// CTFlushPropAddr is the address property NXT_CT_TUPLE_SRC or NXT_CT_TUPLE_DST in CTFlushPropTuple. An IPv4
+// address is encoded as an IPv4-mapped IPv6 address.
+type CTFlushPropAddr struct {
+	*PropHeader
+	Addr net.IP
+}
+
+func (p *CTFlushPropAddr) Len() (n uint16) {
+	return p.PropHeader.Len() + 20
+}
+
+func (p *CTFlushPropAddr) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 16
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	copy(data[n:], p.Addr.To16())
+	return
+}
+
+func (p *CTFlushPropAddr) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+16 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropAddr message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Addr = make(net.IP, 16)
+	copy(p.Addr, data[n:n+16])
+	return nil
+}
+
+// CTFlushPropTuple is the property NXT_CT_ORIG_TUPLE or NXT_CT_REPLY_TUPLE in CTFlush, which nests the
+// NXT_CT_TUPLE_* properties of the tuple.
+type CTFlushPropTuple struct {
+	*PropHeader
+	Props []Property
+}
+
+func (p *CTFlushPropTuple) Len() (n uint16) {
+	// The nested properties are aligned to 8 bytes, following 4 bytes of padding after the header.
+	n = p.PropHeader.Len() + 4
+	for _, prop := range p.Props {
+		n += prop.Len()
+	}
+	return
+}
+
+func (p *CTFlushPropTuple) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.Len()
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+	// 4 bytes for padding
+	n += 4
+
+	for _, prop := range p.Props {
+		b, err = prop.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += int(prop.Len())
+	}
+	return
+}
+
+func (p *CTFlushPropTuple) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Length) || p.Length < 8 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropTuple message")
+	}
+	n += int(p.PropHeader.Len()) + 4
+
+	for n < int(p.Length) {
+		prop, err := DecodeCTFlushTupleProp(data[n:p.Length])
+		if err != nil {
+			return err
+		}
+		p.Props = append(p.Props, prop)
+		n += int(prop.Len())
+	}
+	return nil
+}
+
+func NewCTFlushPropZoneID(zoneID uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_ZONE_ID}, Value: zoneID}
+}
+
+// NewCTFlushPropTuple creates the property of the original tuple if reply is false, otherwise the property of the
+// reply tuple.
+func NewCTFlushPropTuple(reply bool, props []Property) *CTFlushPropTuple {
+	propType := uint16(NXT_CT_ORIG_TUPLE)
+	if reply {
+		propType = NXT_CT_REPLY_TUPLE
+	}
+	return &CTFlushPropTuple{PropHeader: &PropHeader{Type: propType}, Props: props}
+}
+
+func NewCTFlushPropSrc(addr net.IP) *CTFlushPropAddr {
+	return &CTFlushPropAddr{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_SRC}, Addr: addr}
+}
+
+func NewCTFlushPropDst(addr net.IP) *CTFlushPropAddr {
+	return &CTFlushPropAddr{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_DST}, Addr: addr}
+}
+
+func NewCTFlushPropSrcPort(port uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_SRC_PORT}, Value: port}
+}
+
+func NewCTFlushPropDstPort(port uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_DST_PORT}, Value: port}
+}
+
+func NewCTFlushPropICMPID(id uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_ID}, Value: id}
+}
+
+func NewCTFlushPropICMPType(icmpType uint8) *CTFlushPropUint8 {
+	return &CTFlushPropUint8{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_TYPE}, Value: icmpType}
+}
+
+func NewCTFlushPropICMPCode(icmpCode uint8) *CTFlushPropUint8 {
+	return &CTFlushPropUint8{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_CODE}, Value: icmpCode}
+}
+
+// Decode CTFlush Property types.
+func DecodeCTFlushProp(data []byte) (Property, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to unmarshal a CTFlush property")
+	}
+	t := binary.BigEndian.Uint16(data[:2])
+	var p Property
+	switch t {
+	case NXT_CT_ZONE_ID:
+		p = new(CTFlushPropUint16)
+	case NXT_CT_ORIG_TUPLE, NXT_CT_REPLY_TUPLE:
+		p = new(CTFlushPropTuple)
+	default:
+		return nil, fmt.Errorf("unknown CTFlush property type: %d", t)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Decode CTFlushPropTuple Property types.
+func DecodeCTFlushTupleProp(data []byte) (Property, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to unmarshal a CTFlushPropTuple property")
+	}
+	t := binary.BigEndian.Uint16(data[:2])
+	var p Property
+	switch t {
+	case NXT_CT_TUPLE_SRC, NXT_CT_TUPLE_DST:
+		p = new(CTFlushPropAddr)
+	case NXT_CT_TUPLE_SRC_PORT, NXT_CT_TUPLE_DST_PORT, NXT_CT_TUPLE_ICMP_ID:
+		p = new(CTFlushPropUint16)
+	case NXT_CT_TUPLE_ICMP_TYPE, NXT_CT_TUPLE_ICMP_CODE:
+		p = new(CTFlushPropUint8)
+	default:
+		return nil, fmt.Errorf("unknown CTFlushPropTuple property type: %d", t)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DecodeVendorData decodes the body of the Nicira and ONF experimenter messages with the experimenter type.
+func DecodeVendorData(experimenterType uint32, data []byte) (msg util.Message, err error) {
 	switch experimenterType {
 	case Type_SetPacketInFormat:
 		msg = new(PacketInFormat)
 	case Type_SetControllerId:
 		msg = new(ControllerID)
+	case Type_SetFlowFormat:
+		msg = new(FlowFormat)
+	case Type_FlowModTableId:
+		msg = new(FlowModTableID)
+	case Type_SetAsyncConfig2:
+		msg = new(AsyncConfig2)
 	case Type_TlvTableMod:
 		msg = new(TLVTableMod)
 	case Type_TlvTableReply:
@@ -1322,6 +2101,14 @@ func decodeVendorData(experimenterType uint32, data []byte) (msg util.Message, e
 		msg = new(BundleAdd)
 	case Type_PacketIn2:
 		msg = new(PacketIn2)
+	case Type_FlowMonitorCancel:
+		msg = new(FlowMonitorCancel)
+	case Type_CtFlushZone:
+		msg = new(CTFlushZone)
+	case Type_CtFlush:
+		msg = new(CTFlush)
+	default:
+		return nil, fmt.Errorf("unsupported experimenter type: %v", experimenterType)
 	}
 	err = msg.UnmarshalBinary(data)
 	if err != nil {
@@ -11,6 +11,7 @@ package openflow13
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net"
 
 	"antrea.io/libOpenflow/common"
@@ -479,6 +480,12 @@ func NewErrorMsg() *ErrorMsg {
 	return e
 }
 
+// Error returns the type and the code of the error, so that an ErrorMsg replied by the switch can be returned as an
+// error.
+func (e *ErrorMsg) Error() string {
+	return fmt.Sprintf("error type %d code %d", e.Type, e.Code)
+}
+
 func (e *ErrorMsg) Len() (n uint16) {
 	n = e.Header.Len()
 	n += 2
@@ -838,7 +845,7 @@ func (v *VendorHeader) UnmarshalBinary(data []byte) error {
 	n += 4
 	if n < int(v.Header.Length) {
 		var err error
-		v.VendorData, err = decodeVendorData(v.ExperimenterType, data[n:v.Header.Length])
+		v.VendorData, err = decodeExperimenterMessage(v.Vendor, v.ExperimenterType, data[n:v.Header.Length])
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,60 @@
+package openflow14
+
+import (
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/util"
+)
+
+// ExperimenterMessageDecoder decodes the body of an experimenter message, which follows the experimenter ID and
+// the experimenter type in VendorHeader.
+type ExperimenterMessageDecoder = util.ExperimenterMessageDecoder
+
+// ExperimenterMultipartDecoder decodes the body of an OFPMP_EXPERIMENTER multipart request or reply. The data starts
+// with the experimenter ID and the experimenter type, and the length of the returned message must include them.
+type ExperimenterMultipartDecoder = util.ExperimenterMultipartDecoder
+
+// ExperimenterPropertyFactory returns a new property to unmarshal an experimenter property with the given property
+// type and experimenter type. It returns nil to decode the property with the generic experimenter property.
+type ExperimenterPropertyFactory = util.ExperimenterPropertyFactory
+
+// experimenters stores the decoders of the experimenter messages, multipart bodies and properties of OpenFlow 1.4,
+// indexed by experimenter ID. The experimenter actions and match fields are decoded by openflow13, register them
+// with openflow13.RegisterExperimenterAction and openflow13.RegisterExperimenterMatchField.
+var experimenters = util.NewExperimenterRegistry[Action]()
+
+func init() {
+	RegisterExperimenterMessage(NxExperimenterID, openflow13.DecodeVendorData)
+	RegisterExperimenterMessage(ONF_EXPERIMENTER_ID, openflow13.DecodeVendorData)
+	RegisterExperimenterMultipart(NxExperimenterID, openflow13.DecodeNXStatsRequest, openflow13.DecodeNXStatsReply)
+}
+
+// RegisterExperimenterMessage registers the decoder used by Parse for the body of the experimenter messages with
+// the experimenter ID. A nil decoder removes the registration.
+func RegisterExperimenterMessage(experimenterID uint32, decoder ExperimenterMessageDecoder) {
+	experimenters.RegisterMessage(experimenterID, decoder)
+}
+
+// RegisterExperimenterMultipart registers the decoders of the OFPMP_EXPERIMENTER multipart request and reply
+// bodies with the experimenter ID, including the built-in ones for the Nicira extended stats. A nil decoder removes
+// the registration.
+func RegisterExperimenterMultipart(experimenterID uint32, request, reply ExperimenterMultipartDecoder) {
+	experimenters.RegisterMultipart(experimenterID, request, reply)
+}
+
+// RegisterExperimenterProperty registers the factory of the experimenter properties with the experimenter ID. A nil
+// factory removes the registration.
+func RegisterExperimenterProperty(experimenterID uint32, factory ExperimenterPropertyFactory) {
+	experimenters.RegisterProperty(experimenterID, factory)
+}
+
+func decodeExperimenterMessage(experimenterID uint32, expType uint32, data []byte) (util.Message, error) {
+	return experimenters.DecodeMessage(experimenterID, expType, data)
+}
+
+func decodeExperimenterMultipart(data []byte, isReply bool) (util.Message, error) {
+	return experimenters.DecodeMultipart(data, isReply)
+}
+
+func newExperimenterProp(data []byte, defaultProp util.Message) util.Message {
+	return experimenters.NewProperty(data, defaultProp)
+}
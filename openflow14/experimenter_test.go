@@ -0,0 +1,96 @@
+package openflow14
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/util"
+)
+
+func TestNiciraMessage(t *testing.T) {
+	msg := NewVendorHeader(openflow13.NewSetPacketInFormet(1))
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "05040014", hex.EncodeToString(data[:4]))
+	assert.Equal(t, "000023200000001000000001", hex.EncodeToString(data[8:]))
+
+	parsed := assertParse(t, msg).(*VendorHeader)
+	assert.Equal(t, uint32(NxExperimenterID), parsed.Vendor)
+	assert.Equal(t, &openflow13.PacketInFormat{Spif: 1}, parsed.VendorData)
+}
+
+func TestVendorError(t *testing.T) {
+	// OFPET_EXPERIMENTER error with the ONF bundle timeout code and 4 bytes of the failed request.
+	data, err := hex.DecodeString("0501001400000007ffff090a4f4e460001020304")
+	require.NoError(t, err)
+	msg, err := Parse(data)
+	require.NoError(t, err)
+	vendorErr, ok := msg.(*VendorError)
+	require.True(t, ok, "Failed to cast VendorError from result")
+	assert.Equal(t, uint32(7), vendorErr.Xid)
+	assert.Equal(t, uint16(ET_EXPERIMENTER), vendorErr.Type)
+	assert.Equal(t, openflow13.BEC_TIMEOUT, vendorErr.Code)
+	assert.Equal(t, uint32(ONF_EXPERIMENTER_ID), vendorErr.ExperimenterID)
+	assert.Equal(t, []byte{1, 2, 3, 4}, vendorErr.Data.Bytes())
+	newData, err := vendorErr.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+}
+
+func TestNiciraFlowMod(t *testing.T) {
+	flowMod := NewFlowMod()
+	flowMod.Match.AddField(*openflow13.NewRegMatchField(0, 1, nil))
+	instr := openflow13.NewInstrApplyActions()
+	regLoad := openflow13.NewNXActionRegLoad(openflow13.NewNXRange(0, 15).ToOfsBits(), openflow13.NewRegMatchField(1, 0, nil), 0x10)
+	require.NoError(t, instr.AddAction(regLoad, false))
+	flowMod.AddInstruction(instr)
+
+	data, err := flowMod.MarshalBinary()
+	require.NoError(t, err)
+	// The NXM reg0 match field and the NXAST_REG_LOAD action are encoded as OpenFlow 1.3.
+	assert.Equal(t, "0001000c0001000400000001", hex.EncodeToString(data[48:60]))
+	assert.Equal(t, "0004002000000000ffff0018000023200007000f000102040000000000000010", hex.EncodeToString(data[64:]))
+
+	parsed := assertParse(t, flowMod).(*FlowMod)
+	require.Len(t, parsed.Instructions, 1)
+	actions := parsed.Instructions[0].(*openflow13.InstrActions).Actions
+	require.Len(t, actions, 1)
+	assert.IsType(t, new(openflow13.NXActionRegLoad), actions[0])
+}
+
+func TestNiciraStatsMultipart(t *testing.T) {
+	request := &MultipartRequest{
+		Header: NewOfp14Header(),
+		Type:   MultipartType_Experimenter,
+		Body:   []util.Message{openflow13.NewNXFlowStatsRequest(OFPTT_ALL, []MatchField{*openflow13.NewRegMatchField(0, 1, nil)})},
+	}
+	request.Header.Type = Type_MultiPartRequest
+	data, err := request.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "0512", hex.EncodeToString(data[:2]))
+	assert.Equal(t, "0000232000000000ffff0008ff0000000001000400000001", hex.EncodeToString(data[16:]))
+
+	parsed := assertParse(t, request).(*MultipartRequest)
+	require.Len(t, parsed.Body, 1)
+	statsRequest, ok := parsed.Body[0].(*openflow13.NXFlowStatsRequest)
+	require.True(t, ok, "Failed to cast NXFlowStatsRequest from result")
+	assert.Equal(t, uint32(openflow13.NXST_FLOW), statsRequest.Subtype)
+	assert.Len(t, statsRequest.Match, 1)
+}
+
+func TestGroupModVersion(t *testing.T) {
+	groupMod := NewGroupMod()
+	groupMod.GroupId = 10
+	bucket := openflow13.NewBucket()
+	bucket.AddAction(openflow13.NewNXActionResubmitTableAction(openflow13.OFPP_IN_PORT, 2))
+	groupMod.AddBucket(*bucket)
+
+	parsed := assertParse(t, groupMod).(*GroupMod)
+	assert.Equal(t, uint8(VERSION), parsed.Header.Version)
+	require.Len(t, parsed.Buckets, 1)
+	assert.IsType(t, new(openflow13.NXActionResubmitTable), parsed.Buckets[0].Actions[0])
+}
@@ -0,0 +1,290 @@
+package openflow14
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/common"
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/util"
+)
+
+func TestMultipartMessage(t *testing.T) {
+	feature := newTableFeatures()
+	mpartRequest := &MultipartRequest{
+		Header: NewOfp14Header(),
+		Type:   MultipartType_TableFeatures,
+		Flags:  0,
+		Body:   []util.Message{feature},
+	}
+	reqBytes, err := mpartRequest.MarshalBinary()
+	require.Nil(t, err)
+	deReq := new(MultipartRequest)
+	err = deReq.UnmarshalBinary(reqBytes)
+	require.Nil(t, err)
+	assert.True(t, mpartRequestEquals(mpartRequest, deReq), "Original MultipartRequest not equal to the decoded object")
+}
+
+func mpartRequestEquals(oriReq, deReq *MultipartRequest) bool {
+	if !headerEquals(oriReq.Header, deReq.Header) {
+		return false
+	}
+	if oriReq.Type != deReq.Type {
+		return false
+	}
+	if oriReq.Flags != deReq.Flags {
+		return false
+	}
+	if oriReq.Body != nil && deReq.Body == nil || oriReq.Body == nil && deReq.Body != nil {
+		return false
+	}
+	if oriReq.Body != nil {
+		switch oriReq.Type {
+		case MultipartType_TableFeatures:
+			if len(oriReq.Body) != len(deReq.Body) {
+				return false
+			}
+			for i := range oriReq.Body {
+				if !ofPTableFeaturesEquals(oriReq.Body[i].(*OFPTableFeatures), deReq.Body[i].(*OFPTableFeatures)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func headerEquals(oriHeader, newHeader common.Header) bool {
+	if oriHeader.Version != newHeader.Version {
+		return false
+	}
+	if oriHeader.Xid != newHeader.Xid {
+		return false
+	}
+	if oriHeader.Length != newHeader.Length {
+		return false
+	}
+	if oriHeader.Type != newHeader.Type {
+		return false
+	}
+	return true
+}
+
+func TestOFPTableFeatures(t *testing.T) {
+	feature := newTableFeatures()
+
+	fbytes, err := feature.MarshalBinary()
+	require.Nil(t, err)
+	deFeature := new(OFPTableFeatures)
+	err = deFeature.UnmarshalBinary(fbytes)
+	require.Nil(t, err)
+	assert.True(t, ofPTableFeaturesEquals(feature, deFeature))
+}
+
+func newTableFeatures() *OFPTableFeatures {
+	nameBytes := []byte("table-10")
+	feature := &OFPTableFeatures{
+		Length:     64,
+		TableID:    10,
+		Command:    0,
+		Name:       [32]byte{},
+		MaxEntries: 100000,
+	}
+	copy(feature.Name[0:], nameBytes)
+	return feature
+}
+
+func ofPTableFeaturesEquals(f, df *OFPTableFeatures) bool {
+	if f.Length != df.Length {
+		return false
+	}
+	if f.TableID != df.TableID {
+		return false
+	}
+	if f.Command != df.Command {
+		return false
+	}
+	if f.Name != df.Name {
+		return false
+	}
+	if f.Capabilities != df.Capabilities {
+		return false
+	}
+	if f.MetadataMatch != df.MetadataMatch {
+		return false
+	}
+	if f.MetadataWrite != df.MetadataWrite {
+		return false
+	}
+	if f.MaxEntries != df.MaxEntries {
+		return false
+	}
+	if len(f.Properties) != len(df.Properties) {
+		return false
+	}
+	if len(f.Properties) > 0 {
+		for i, p := range f.Properties {
+			dfP := df.Properties[i]
+			pd, _ := p.MarshalBinary()
+			dfpd, _ := dfP.MarshalBinary()
+			if !bytes.Equal(pd, dfpd) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestGroupMeterPortDescMultipart(t *testing.T) {
+	groupStats := NewGroupStats()
+	groupStats.GroupId = 10
+	groupStats.RefCount = 2
+	groupStats.PacketCount = 100
+	groupStats.ByteCount = 6400
+	groupStats.Stats = []BucketCounter{{PacketCount: 60, ByteCount: 3840}, {PacketCount: 40, ByteCount: 2560}}
+
+	bucket := openflow13.NewBucket()
+	bucket.Weight = 100
+	bucket.AddAction(openflow13.NewActionOutput(1))
+	groupDesc := NewGroupDesc()
+	groupDesc.Type = openflow13.OFPGT_SELECT
+	groupDesc.GroupId = 10
+	groupDesc.AddBucket(*bucket)
+
+	groupFeatures := NewGroupFeatures()
+	groupFeatures.Types = 1<<openflow13.OFPGT_ALL | 1<<openflow13.OFPGT_SELECT
+	groupFeatures.Capabilities = GFC_SELECT_WEIGHT | GFC_CHAINING
+	groupFeatures.MaxGroups[openflow13.OFPGT_SELECT] = 1024
+
+	meterStats := NewMeterStats(5)
+	meterStats.FlowCount = 3
+	meterStats.PacketInCount = 1000
+	meterStats.AddBandStats(MeterBandStats{PacketBandCount: 10, ByteBandCount: 640})
+
+	meterDesc := NewMeterDesc(5)
+	meterDesc.Flags = OFPMF14_PKTPS | OFPMF14_STATS
+	meterDesc.AddBand(&MeterBandDrop{MeterBandHeader{Type: OFPMBT14_DROP, Length: METER_BAND_LEN, Rate: 100}})
+	meterDesc.AddBand(&MeterBandDSCP{MeterBandHeader: MeterBandHeader{Type: OFPMBT14_DSCP_REMARK, Length: METER_BAND_LEN, Rate: 200}, PrecLevel: 1})
+
+	meterFeatures := NewMeterFeatures()
+	meterFeatures.MaxMeter = 200000
+	meterFeatures.BandTypes = 1<<OFPMBT14_DROP | 1<<OFPMBT14_DSCP_REMARK
+	meterFeatures.MaxBands = 1
+
+	port := NewPort(1)
+	port.HWAddr = []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	copy(port.Name, "br-int")
+	ethernet := NewPortDescPropEthernet()
+	ethernet.CurrSpeed = 10000000
+	port.Properties = append(port.Properties, ethernet)
+
+	portStats := NewPortStats(1)
+	portStats.RxPackets = 100
+	portStats.Properties = append(portStats.Properties, NewPortStatsPropEthernet())
+
+	queueStats := NewQueueStats()
+	queueStats.PortNo = 1
+	queueStats.QueueId = 2
+	queueStats.TxBytes = 1000
+
+	tableDesc := NewTableDesc(3)
+	tableDesc.Properties = append(tableDesc.Properties, NewTableModPropVacancy())
+
+	queueDesc := NewQueueDesc(2)
+	queueDesc.PortNo = 1
+	minRate := NewQueueDescPropMinRate()
+	minRate.Rate = 500
+	queueDesc.Properties = append(queueDesc.Properties, minRate)
+
+	flowUpdate := NewFlowUpdateFull(FME_ADDED)
+	flowUpdate.TableId = 1
+	flowUpdate.Match.AddField(*openflow13.NewInPortField(3))
+	flowUpdate.AddInstruction(openflow13.NewInstrGotoTable(2))
+
+	for _, tc := range []struct {
+		name   string
+		mpType uint16
+		bodies []util.Message
+	}{
+		{name: "Group", mpType: MultipartType_Group, bodies: []util.Message{groupStats}},
+		{name: "GroupDesc", mpType: MultipartType_GroupDesc, bodies: []util.Message{groupDesc, groupDesc}},
+		{name: "GroupFeatures", mpType: MultipartType_GroupFeatures, bodies: []util.Message{groupFeatures}},
+		{name: "Meter", mpType: MultipartType_Meter, bodies: []util.Message{meterStats}},
+		{name: "MeterConfig", mpType: MultipartType_MeterConfig, bodies: []util.Message{meterDesc}},
+		{name: "MeterFeatures", mpType: MultipartType_MeterFeatures, bodies: []util.Message{meterFeatures}},
+		{name: "PortDesc", mpType: MultipartType_PortDesc, bodies: []util.Message{port, port}},
+		{name: "Port", mpType: MultipartType_Port, bodies: []util.Message{portStats}},
+		{name: "Queue", mpType: MultipartType_Queue, bodies: []util.Message{queueStats}},
+		{name: "TableDesc", mpType: MultipartType_TableDesc, bodies: []util.Message{tableDesc}},
+		{name: "QueueDesc", mpType: MultipartType_QueueDesc, bodies: []util.Message{queueDesc}},
+		{name: "FlowMonitor", mpType: MultipartType_FlowMonitor, bodies: []util.Message{
+			flowUpdate, NewFlowUpdateAbbrev(), NewFlowUpdatePaused(FME_PAUSED),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reply := &MultipartReply{
+				Header: NewOfp14Header(),
+				Type:   tc.mpType,
+				Body:   tc.bodies,
+			}
+			reply.Header.Type = Type_MultiPartReply
+			data, err := reply.MarshalBinary()
+			require.NoError(t, err)
+			msg, err := Parse(data)
+			require.NoError(t, err)
+			newReply, ok := msg.(*MultipartReply)
+			require.True(t, ok)
+			require.Len(t, newReply.Body, len(tc.bodies))
+			newData, err := newReply.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, data, newData)
+		})
+	}
+
+	newGroupStats := new(GroupStats)
+	data, _ := groupStats.MarshalBinary()
+	require.NoError(t, newGroupStats.UnmarshalBinary(data))
+	assert.Equal(t, uint32(10), newGroupStats.GroupId)
+	assert.Equal(t, groupStats.Stats, newGroupStats.Stats)
+	newGroupDesc := new(GroupDesc)
+	data, _ = groupDesc.MarshalBinary()
+	require.NoError(t, newGroupDesc.UnmarshalBinary(data))
+	require.Len(t, newGroupDesc.Buckets, 1)
+	assert.Equal(t, uint16(100), newGroupDesc.Buckets[0].Weight)
+	assert.IsType(t, new(openflow13.ActionOutput), newGroupDesc.Buckets[0].Actions[0])
+	newMeterDesc := new(MeterDesc)
+	data, _ = meterDesc.MarshalBinary()
+	require.NoError(t, newMeterDesc.UnmarshalBinary(data))
+	require.Len(t, newMeterDesc.Bands, 2)
+	assert.Equal(t, uint8(1), newMeterDesc.Bands[1].(*MeterBandDSCP).PrecLevel)
+
+	for _, tc := range []struct {
+		mpType uint16
+		body   util.Message
+	}{
+		{mpType: MultipartType_Group, body: NewGroupMultipartRequest(OFPG_ALL)},
+		{mpType: MultipartType_Meter, body: NewMeterMultipartRequest(OFPM14_ALL)},
+		{mpType: MultipartType_MeterConfig, body: NewMeterMultipartRequest(5)},
+		{mpType: MultipartType_Port, body: NewPortStatsRequest(P_ANY)},
+		{mpType: MultipartType_QueueDesc, body: NewQueueStatsRequest()},
+		{mpType: MultipartType_FlowMonitor, body: NewFlowMonitorRequest(1)},
+	} {
+		request := &MultipartRequest{
+			Header: NewOfp14Header(),
+			Type:   tc.mpType,
+			Body:   []util.Message{tc.body},
+		}
+		request.Header.Type = Type_MultiPartRequest
+		data, err := request.MarshalBinary()
+		require.NoError(t, err)
+		newRequest := new(MultipartRequest)
+		require.NoError(t, newRequest.UnmarshalBinary(data))
+		require.Len(t, newRequest.Body, 1)
+		newData, _ := newRequest.Body[0].MarshalBinary()
+		data, _ = tc.body.MarshalBinary()
+		assert.Equal(t, data, newData)
+	}
+}
@@ -0,0 +1,2053 @@
+package openflow14
+
+// Package openflow14 provides OpenFlow 1.4 structs along with Read
+// and Write methods for each.
+// OpenFlow Wire Protocol 0x05
+//
+// Struct documentation is taken from the OpenFlow Switch
+// Specification Version 1.4.1.
+// https://opennetworking.org/wp-content/uploads/2014/10/openflow-switch-v1.4.1.pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+
+	"antrea.io/libOpenflow/common"
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/protocol"
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	VERSION = 5
+)
+
+// Returns a new OpenFlow header with version field set to v1.4.
+var NewOfp14Header func() common.Header = common.NewHeaderGenerator(VERSION)
+
+// Echo request/reply messages can be sent from either the
+// switch or the controller, and must return an echo reply. They
+// can be used to indicate the latency, bandwidth, and/or
+// liveness of a controller-switch connection.
+func NewEchoRequest() *common.Header {
+	h := NewOfp14Header()
+	h.Type = Type_EchoRequest
+	return &h
+}
+
+// Echo request/reply messages can be sent from either the
+// switch or the controller, and must return an echo reply. They
+// can be used to indicate the latency, bandwidth, and/or
+// liveness of a controller-switch connection.
+func NewEchoReply() *common.Header {
+	h := NewOfp14Header()
+	h.Type = Type_EchoReply
+	return &h
+}
+
+// ofp_type 1.4
+const (
+	/* Immutable messages. */
+	Type_Hello        = 0
+	Type_Error        = 1
+	Type_EchoRequest  = 2
+	Type_EchoReply    = 3
+	Type_Experimenter = 4
+
+	/* Switch configuration messages. */
+	Type_FeaturesRequest  = 5
+	Type_FeaturesReply    = 6
+	Type_GetConfigRequest = 7
+	Type_GetConfigReply   = 8
+	Type_SetConfig        = 9
+
+	/* Asynchronous messages. */
+	Type_PacketIn    = 10
+	Type_FlowRemoved = 11
+	Type_PortStatus  = 12
+
+	/* Controller command messages. */
+	Type_PacketOut = 13
+	Type_FlowMod   = 14
+	Type_GroupMod  = 15
+	Type_PortMod   = 16
+	Type_TableMod  = 17
+
+	/* Multipart messages. */
+	Type_MultiPartRequest = 18
+	Type_MultiPartReply   = 19
+
+	/* Barrier messages. */
+	Type_BarrierRequest = 20
+	Type_BarrierReply   = 21
+
+	/* Controller role change request messages. */
+	Type_RoleRequest = 24
+	Type_RoleReply   = 25
+
+	/* Asynchronous message configuration. */
+	Type_GetAsyncRequest = 26
+	Type_GetAsyncReply   = 27
+	Type_SetAsync        = 28
+
+	/* Meters and rate limiters configuration messages. */
+	Type_MeterMod = 29
+
+	/* Controller role change event messages. */
+	Type_RoleStatus = 30
+
+	/* Asynchronous messages. */
+	Type_TableStatus = 31
+
+	/* Request forwarding by the switch. */
+	Type_RequestForward = 32
+
+	/* Bundle operations (multiple messages as a single operation). */
+	Type_BundleControl    = 33
+	Type_BundleAddMessage = 34
+)
+
+func Parse(b []byte) (message util.Message, err error) {
+	if len(b) < 8 {
+		return nil, errors.New("the []byte is too short to unmarshal a full OpenFlow 1.4 message")
+	}
+	switch b[1] {
+	case Type_Error:
+		errMsg := NewErrorMsg()
+		err = errMsg.UnmarshalBinary(b)
+		if err != nil {
+			return
+		}
+		switch errMsg.Type {
+		case ET_EXPERIMENTER:
+			message = new(VendorError)
+			err = message.UnmarshalBinary(b)
+		default:
+			message = errMsg
+		}
+		return
+	case Type_Hello:
+		message = new(common.Hello)
+	case Type_EchoRequest:
+		message = new(common.Header)
+	case Type_EchoReply:
+		message = new(common.Header)
+	case Type_Experimenter:
+		message = new(VendorHeader)
+	case Type_FeaturesRequest:
+		message = NewFeaturesRequest()
+	case Type_FeaturesReply:
+		message = NewFeaturesReply()
+	case Type_GetConfigRequest:
+		message = new(common.Header)
+	case Type_GetConfigReply:
+		message = NewGetConfigReply()
+	case Type_SetConfig:
+		message = NewSetConfig()
+	case Type_PacketIn:
+		message = NewPacketIn()
+	case Type_FlowRemoved:
+		message = NewFlowRemoved()
+	case Type_PortStatus:
+		message = NewPortStatus()
+	case Type_PacketOut:
+		message = NewPacketOut()
+	case Type_FlowMod:
+		message = NewFlowMod()
+	case Type_GroupMod:
+		message = NewGroupMod()
+	case Type_PortMod:
+		message = NewPortMod(0)
+	case Type_TableMod:
+		message = NewTableMod()
+	case Type_BarrierRequest:
+		message = new(common.Header)
+	case Type_BarrierReply:
+		message = new(common.Header)
+	case Type_MultiPartRequest:
+		message = new(MultipartRequest)
+	case Type_MultiPartReply:
+		message = new(MultipartReply)
+	case Type_RoleRequest:
+		message = NewRoleRequest()
+	case Type_RoleReply:
+		message = NewRoleReply()
+	case Type_GetAsyncRequest:
+		message = NewGetAsyncRequest()
+	case Type_GetAsyncReply:
+		message = NewGetAsyncReply()
+	case Type_SetAsync:
+		message = NewSetAsync()
+	case Type_MeterMod:
+		message = NewMeterMod()
+	case Type_RoleStatus:
+		message = NewRoleStatus()
+	case Type_TableStatus:
+		message = NewTableStatus()
+	case Type_RequestForward:
+		message = NewRequestForward()
+	case Type_BundleControl:
+		message = NewBundleCtrl(0, 0, 0)
+	case Type_BundleAddMessage:
+		message = NewBndleAdd(0, 0)
+	default:
+		return nil, errors.New("An unknown v1.4 packet type was received. Parse function will discard data.")
+	}
+	err = message.UnmarshalBinary(b)
+	return
+}
+
+// When the controller wishes to send a packet out through the
+// datapath, it uses the OFPT_PACKET_OUT message: The buffer_id
+// is the same given in the ofp_packet_in message. If the
+// buffer_id is -1, then the packet data is included in the data
+// array. If OFPP_TABLE is specified as the output port of an
+// action, the in_port in the packet_out message is used in the
+// flow table lookup.
+type PacketOut struct {
+	common.Header
+	BufferId   uint32
+	InPort     uint32
+	ActionsLen uint16
+	pad        []byte
+	Actions    []Action
+	Data       util.Message
+}
+
+func NewPacketOut() *PacketOut {
+	p := new(PacketOut)
+	p.Header = NewOfp14Header()
+	p.Header.Type = Type_PacketOut
+	p.BufferId = 0xffffffff
+	p.InPort = P_ANY
+	p.ActionsLen = 0
+	p.pad = make([]byte, 6)
+	p.Actions = make([]Action, 0)
+	return p
+}
+
+func (p *PacketOut) AddAction(act Action) {
+	p.Actions = append(p.Actions, act)
+	p.ActionsLen += act.Len()
+}
+
+func (p *PacketOut) Len() (n uint16) {
+	n += p.Header.Len()
+	n += 16
+	for _, a := range p.Actions {
+		n += a.Len()
+	}
+	n += p.Data.Len()
+	// if n < 72 { return 72 }
+	return
+}
+
+func (p *PacketOut) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	var b []byte
+	n := 0
+
+	p.Header.Length = p.Len()
+	if b, err = p.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	binary.BigEndian.PutUint32(data[n:], p.BufferId)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.InPort)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], p.ActionsLen)
+	n += 2
+	n += 6 // for pad
+
+	for _, a := range p.Actions {
+		if b, err = a.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+
+	if b, err = p.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+	return
+}
+
+func (p *PacketOut) UnmarshalBinary(data []byte) error {
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := p.Header.Len()
+
+	p.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.InPort = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.ActionsLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	n += 6 // for pad
+
+	end := n + p.ActionsLen
+	for n < end {
+		a, err := DecodeAction(data[n:])
+		if err != nil {
+			return err
+		}
+		p.Actions = append(p.Actions, a)
+		n += a.Len()
+	}
+
+	if p.Data == nil {
+		p.Data = new(util.Buffer)
+	}
+	return p.Data.UnmarshalBinary(data[n:p.Header.Length])
+}
+
+// ofp_packet_in 1.4
+type PacketIn struct {
+	common.Header
+	BufferId uint32
+	TotalLen uint16
+	Reason   uint8
+	TableId  uint8
+	Cookie   uint64
+	Match    Match
+	pad      []uint8
+	Data     protocol.Ethernet
+}
+
+func NewPacketIn() *PacketIn {
+	p := new(PacketIn)
+	p.Header = NewOfp14Header()
+	p.Header.Type = Type_PacketIn
+	p.BufferId = 0xffffffff
+	p.Reason = 0
+	p.TableId = 0
+	p.Cookie = 0
+	p.Match = *NewMatch()
+	p.pad = make([]byte, 2)
+	return p
+}
+
+func (p *PacketIn) Len() (n uint16) {
+	n += p.Header.Len()
+	n += 16
+	n += p.Match.Len()
+	n += 2
+	n += p.Data.Len()
+	return
+}
+
+func (p *PacketIn) MarshalBinary() (data []byte, err error) {
+	p.Header.Length = p.Len()
+	if data, err = p.Header.MarshalBinary(); err != nil {
+		return
+	}
+
+	b := make([]byte, 16)
+	n := 0
+	binary.BigEndian.PutUint32(b, p.BufferId)
+	n += 4
+	binary.BigEndian.PutUint16(b[n:], p.TotalLen)
+	n += 2
+	b[n] = p.Reason
+	n += 1
+	b[n] = p.TableId
+	n += 1
+	binary.BigEndian.PutUint64(b[n:], p.Cookie)
+	n += 8
+	data = append(data, b...)
+
+	if b, err = p.Match.MarshalBinary(); err != nil {
+		return
+	}
+	data = append(data, b...)
+
+	b = make([]byte, 2)
+	copy(b[0:], p.pad)
+	data = append(data, b...)
+
+	if b, err = p.Data.MarshalBinary(); err != nil {
+		return
+	}
+	data = append(data, b...)
+	return
+}
+
+func (p *PacketIn) UnmarshalBinary(data []byte) error {
+	if err := p.Header.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	n := p.Header.Len()
+
+	p.BufferId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	p.TotalLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	p.Reason = data[n]
+	n += 1
+	p.TableId = data[n]
+	n += 1
+	p.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+
+	if err := p.Match.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += p.Match.Len()
+
+	copy(p.pad, data[n:])
+	n += 2
+
+	return p.Data.UnmarshalBinary(data[n:])
+}
+
+// ofp_packet_in_reason 1.4
+const (
+	R_NO_MATCH    = iota /* No matching flow (table-miss flow entry). */
+	R_ACTION             /* Action explicitly output to controller. */
+	R_INVALID_TTL        /* Packet has invalid TTL */
+	R_ACTION_SET         /* Output to controller in action set */
+	R_GROUP              /* Output to controller in group bucket */
+	R_PACKET_OUT         /* Output to controller in packet-out */
+)
+
+func NewConfigRequest() *common.Header {
+	h := NewOfp14Header()
+	h.Type = Type_GetConfigRequest
+	return &h
+}
+
+// ofp_config_flags 1.4
+const (
+	C_FRAG_NORMAL = 0
+	C_FRAG_DROP   = 1
+	C_FRAG_REASM  = 2
+	C_FRAG_MASK   = 3
+)
+
+// ofp_switch_config 1.4
+type SwitchConfig struct {
+	common.Header
+	Flags       uint16 // OFPC_* flags
+	MissSendLen uint16
+}
+
+func NewSetConfig() *SwitchConfig {
+	c := new(SwitchConfig)
+	c.Header = NewOfp14Header()
+	c.Header.Type = Type_SetConfig
+	c.Flags = 0
+	c.MissSendLen = 0
+	return c
+}
+
+func NewGetConfigReply() *SwitchConfig {
+	c := new(SwitchConfig)
+	c.Header = NewOfp14Header()
+	c.Header.Type = Type_GetConfigReply
+	c.Flags = 0
+	c.MissSendLen = 0
+	return c
+}
+
+func (c *SwitchConfig) Len() (n uint16) {
+	n = c.Header.Len()
+	n += 4
+	return
+}
+
+func (c *SwitchConfig) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(c.Len()))
+	var bytes []byte
+	next := 0
+
+	c.Header.Length = c.Len()
+	if bytes, err = c.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	binary.BigEndian.PutUint16(data[next:], c.Flags)
+	next += 2
+	binary.BigEndian.PutUint16(data[next:], c.MissSendLen)
+	next += 2
+	return
+}
+
+func (c *SwitchConfig) UnmarshalBinary(data []byte) error {
+	var err error
+	next := 0
+
+	err = c.Header.UnmarshalBinary(data[next:])
+	next += int(c.Header.Len())
+	c.Flags = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	c.MissSendLen = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	return err
+}
+
+// BEGIN: ofp14 - 7.5.4
+// ofp_error_msg 1.4
+type ErrorMsg struct {
+	common.Header
+	Type uint16
+	Code uint16
+	Data util.Buffer
+}
+
+func NewErrorMsg() *ErrorMsg {
+	e := new(ErrorMsg)
+	e.Data = *util.NewBuffer(make([]byte, 0))
+	return e
+}
+
+func (e *ErrorMsg) Len() (n uint16) {
+	n = e.Header.Len()
+	n += 2
+	n += 2
+	n += e.Data.Len()
+	return
+}
+
+func (e *ErrorMsg) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(e.Len()))
+	var bytes []byte
+	next := 0
+
+	if bytes, err = e.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	binary.BigEndian.PutUint16(data[next:], e.Type)
+	next += 2
+	binary.BigEndian.PutUint16(data[next:], e.Code)
+	next += 2
+	if bytes, err = e.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	return
+}
+
+func (e *ErrorMsg) UnmarshalBinary(data []byte) error {
+	next := 0
+	e.Header.UnmarshalBinary(data[next:])
+	next += int(e.Header.Len())
+	e.Type = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	e.Code = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	e.Data.UnmarshalBinary(data[next:])
+	next += int(e.Data.Len())
+	return nil
+}
+
+// VendorError is the OFPET_EXPERIMENTER error message, the experimenter ID follows the experimenter code.
+type VendorError struct {
+	*ErrorMsg
+	ExperimenterID uint32
+}
+
+func (e *VendorError) Len() uint16 {
+	return e.ErrorMsg.Len() + 4
+}
+
+func (e *VendorError) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(e.Len()))
+	var bytes []byte
+	next := 0
+
+	if bytes, err = e.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	binary.BigEndian.PutUint16(data[next:], e.Type)
+	next += 2
+	binary.BigEndian.PutUint16(data[next:], e.Code)
+	next += 2
+	binary.BigEndian.PutUint32(data[next:], e.ExperimenterID)
+	next += 4
+	if bytes, err = e.Data.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	return
+}
+
+func (e *VendorError) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("the []byte is too short to unmarshal a full VendorError message")
+	}
+	e.ErrorMsg = NewErrorMsg()
+	next := 0
+	if err := e.Header.UnmarshalBinary(data[next:]); err != nil {
+		return err
+	}
+	next += int(e.Header.Len())
+	e.Type = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	e.Code = binary.BigEndian.Uint16(data[next:])
+	next += 2
+	e.ExperimenterID = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	return e.Data.UnmarshalBinary(data[next:])
+}
+
+// ofp_error_type 1.4
+const (
+	ET_HELLO_FAILED          = 0      /* Hello protocol failed. */
+	ET_BAD_REQUEST           = 1      /* Request was not understood. */
+	ET_BAD_ACTION            = 2      /* Error in action description. */
+	ET_BAD_INSTRUCTION       = 3      /* Error in instruction list. */
+	PET_BAD_MATCH            = 4      /* Error in match. */
+	ET_FLOW_MOD_FAILED       = 5      /* Problem modifying flow entry. */
+	ET_GROUP_MOD_FAILED      = 6      /* Problem modifying group entry. */
+	ET_PORT_MOD_FAILED       = 7      /* Port mod request failed. */
+	ET_TABLE_MOD_FAILED      = 8      /* Table mod request failed. */
+	ET_QUEUE_OP_FAILED       = 9      /* Queue operation failed. */
+	ET_SWITCH_CONFIG_FAILED  = 10     /* Switch config request failed. */
+	ET_ROLE_REQUEST_FAILED   = 11     /* Controller Role request failed. */
+	ET_METER_MOD_FAILED      = 12     /* Error in meter. */
+	ET_TABLE_FEATURES_FAILED = 13     /* Setting table features failed. */
+	ET_BAD_PROPERTY          = 14     /* Some property is invalid. */
+	ET_ASYNC_CONFIG_FAILED   = 15     /* Asynchronous config request failed. */
+	ET_FLOW_MONITOR_FAILED   = 16     /* Setting flow monitor failed. */
+	ET_BUNDLE_FAILED         = 17     /* Bundle operation failed. */
+	ET_EXPERIMENTER          = 0xffff /* Experimenter error messages. */
+)
+
+// ofp_hello_failed_code 1.4
+const (
+	HFC_INCOMPATIBLE = iota
+	HFC_EPERM
+)
+
+// ofp_bad_request_code 1.4
+const (
+	BRC_BAD_VERSION = iota
+	BRC_BAD_TYPE
+	BRC_BAD_MULTIPART
+	BRC_BAD_EXPERIMENTER
+
+	BRC_BAD_EXP_TYPE
+	BRC_EPERM
+	BRC_BAD_LEN
+	BRC_BUFFER_EMPTY
+	BRC_BUFFER_UNKNOWN
+	BRC_BAD_TABLE_ID
+	BRC_IS_SLAVE
+	BRC_BAD_PORT
+	BRC_BAD_PACKET
+	BRC_MULTIPART_BUFFER_OVERFLOW
+)
+
+// ofp_bad_action_code 1.4
+const (
+	BAC_BAD_TYPE = iota
+	BAC_BAD_LEN
+	BAC_BAD_EXPERIMENTER
+	BAC_BAD_EXP_TYPE
+	BAC_BAD_OUT_PORT
+	BAC_BAD_ARGUMENT
+	BAC_EPERM
+	BAC_TOO_MANY
+	BAC_BAD_QUEUE
+	BAC_BAD_OUT_GROUP
+	BAC_MATCH_INCONSISTENT
+	BAC_UNSUPPORTED_ORDER
+	BAC_BAD_TAG
+	BAC_BAD_SET_TYPE
+	BAC_BAD_SET_LEN
+	BAC_BAD_SET_ARGUMENT
+)
+
+// ofp_bad_instruction_code 1.4
+const (
+	BIC_UNKNOWN_INST        = 0 /* Unknown instruction. */
+	BIC_UNSUP_INST          = 1 /* Switch or table does not support the instruction. */
+	BIC_BAD_TABLE_ID        = 2 /* Invalid Table-ID specified. */
+	BIC_UNSUP_METADATA      = 3 /* Metadata value unsupported by datapath. */
+	BIC_UNSUP_METADATA_MASK = 4 /* Metadata mask value unsupported by datapath. */
+	BIC_BAD_EXPERIMENTER    = 5 /* Unknown experimenter id specified. */
+	BIC_BAD_EXP_TYPE        = 6 /* Unknown instruction for experimenter id. */
+	BIC_BAD_LEN             = 7 /* Length problem in instructions. */
+	BIC_EPERM               = 8 /* Permissions error. */
+	BIC_DUP_INST            = 9 /* Duplicate instruction. */
+)
+
+// ofp_flow_mod_failed_code 1.4
+const (
+	FMFC_UNKNOWN      = 0  /* Unspecified error. */
+	FMFC_TABLE_FULL   = 1  /* Flow not added because table was full. */
+	FMFC_BAD_TABLE_ID = 2  /* Table does not exist */
+	FMFC_OVERLAP      = 3  /* Attempted to add overlapping flow with CHECK_OVERLAP flag set. */
+	FMFC_EPERM        = 4  /* Permissions error. */
+	FMFC_BAD_TIMEOUT  = 5  /* Flow not added because of unsupported idle/hard timeout. */
+	FMFC_BAD_COMMAND  = 6  /* Unsupported or unknown command. */
+	FMFC_BAD_FLAGS    = 7  /* Unsupported or unknown flags. */
+	FMFC_CANT_SYNC    = 8  /* Problem in table synchronisation. */
+	FMFC_BAD_PRIORITY = 9  /* Unsupported priority value. */
+	FMFC_IS_SYNC      = 10 /* Synchronised flow entry is read only. */
+)
+
+// ofp_bad_match_code 1.4
+const (
+	BMC_BAD_TYPE         = 0  /* Unsupported match type specified by the match */
+	BMC_BAD_LEN          = 1  /* Length problem in match. */
+	BMC_BAD_TAG          = 2  /* Match uses an unsupported tag/encap. */
+	BMC_BAD_DL_ADDR_MASK = 3  /* Unsupported datalink addr mask - switch does not support arbitrary datalink address mask. */
+	BMC_BAD_NW_ADDR_MASK = 4  /* Unsupported network addr mask - switch does not support arbitrary network address mask. */
+	BMC_BAD_WILDCARDS    = 5  /* Unsupported combination of fields masked or omitted in the match. */
+	BMC_BAD_FIELD        = 6  /* Unsupported field type in the match. */
+	BMC_BAD_VALUE        = 7  /* Unsupported value in a match field. */
+	BMC_BAD_MASK         = 8  /* Unsupported mask specified in the match, field is not dl-address or nw-address. */
+	BMC_BAD_PREREQ       = 9  /* A prerequisite was not met. */
+	BMC_DUP_FIELD        = 10 /* A field type was duplicated. */
+	BMC_EPERM            = 11 /* Permissions error. */
+)
+
+// ofp_group_mod_failed_code 1.4
+const (
+	GMFC_GROUP_EXISTS         = 0  /* Group not added because a group ADD attempted to replace an already-present group. */
+	GMFC_INVALID_GROUP        = 1  /* Group not added because Group specified is invalid. */
+	GMFC_WEIGHT_UNSUPPORTED   = 2  /* Switch does not support unequal load sharing with select groups. */
+	GMFC_OUT_OF_GROUPS        = 3  /* The group table is full. */
+	GMFC_OUT_OF_BUCKETS       = 4  /* The maximum number of action buckets for a group has been exceeded. */
+	GMFC_CHAINING_UNSUPPORTED = 5  /* Switch does not support groups that forward to groups. */
+	GMFC_WATCH_UNSUPPORTED    = 6  /* This group cannot watch the watch_port or watch_group specified. */
+	GMFC_LOOP                 = 7  /* Group entry would cause a loop. */
+	GMFC_UNKNOWN_GROUP        = 8  /* Group not modified because a group MODIFY attempted to modify a non-existent group. */
+	GMFC_CHAINED_GROUP        = 9  /* Group not deleted because another group is forwarding to it. */
+	GMFC_BAD_TYPE             = 10 /* Unsupported or unknown group type. */
+	GMFC_BAD_COMMAND          = 11 /* Unsupported or unknown command. */
+	GMFC_BAD_BUCKET           = 12 /* Error in bucket. */
+	GMFC_BAD_WATCH            = 13 /* Error in watch port/group. */
+	GMFC_EPERM                = 14 /* Permissions error. */
+)
+
+// ofp_port_mod_failed_code 1.4
+const (
+	PMFC_BAD_PORT = iota
+	PMFC_BAD_HW_ADDR
+	PMFC_BAD_CONFIG
+	PMFC_BAD_ADVERTISE
+	PMFC_EPERM
+)
+
+// ofp_table_mod_failed_code 1.4
+const (
+	TMFC_BAD_TABLE  = 0 /* Specified table does not exist. */
+	TMFC_BAD_CONFIG = 1 /* Specified config is invalid. */
+	TMFC_EPERM      = 2 /* Permissions error. */
+)
+
+// ofp_queue_op_failed_code 1.4
+const (
+	QOFC_BAD_PORT = iota
+	QOFC_BAD_QUEUE
+	QOFC_EPERM
+)
+
+// ofp_switch_config_failed_code 1.4
+const (
+	SCFC_BAD_FLAGS = iota
+	SCFC_BAD_LEN
+	SCFC_EPERM
+)
+
+// ofp_role_request_failed_code 1.4
+const (
+	RRFC_STALE = iota
+	RRFC_UNSUP
+	RRFC_BAD_ROLE
+)
+
+// ofp_meter_mod_failed_code 1.4
+const (
+	MMFC_UNKNOWN = iota
+	MMFC_METER_EXISTS
+	MMFC_INVALID_METER
+	MMFC_UNKNOWN_METER
+	MMFC_BAD_COMMAND
+	MMFC_BAD_FLAGS
+	MMFC_BAD_RATE
+	MMFC_BAD_BURST
+	MMFC_BAD_BAND
+	MMFC_BAD_BAND_VALUE
+	MMFC_OUT_OF_METERS
+	MMFC_OUT_OF_BANDS
+)
+
+// ofp_table_features_failed_code 1.4
+const (
+	TFFC_BAD_TABLE    = 0 /* Specified table does not exist. */
+	TFFC_BAD_METADATA = 1 /* Invalid metadata mask. */
+	TFFC_EPERM        = 5 /* Permissions error. */
+)
+
+// ofp_bad_property_code 1.4
+const (
+	BPC_BAD_TYPE = iota
+	BPC_BAD_LEN
+	BPC_BAD_VALUE
+	BPC_TOO_MANY
+	BPC_DUP_TYPE
+	BPC_BAD_EXPERIMENTER
+	BPC_BAD_EXP_TYPE
+	BPC_BAD_EXP_VALUE
+	BPC_EPERM
+)
+
+// ofp_async_config_failed_code 1.4
+const (
+	ACFC_INVALID = iota
+	ACFC_UNSUPPORTED
+	ACFC_EPERM
+)
+
+// ofp_flow_monitor_failed_code 1.4
+const (
+	MOFC_UNKNOWN = iota
+	MOFC_MONITOR_EXISTS
+	MOFC_INVALID_MONITOR
+	MOFC_UNKNOWN_MONITOR
+	MOFC_BAD_COMMAND
+	MOFC_BAD_FLAGS
+	MOFC_BAD_TABLE_ID
+	MOFC_BAD_OUT
+)
+
+// ofp_bundle_failed_code 1.4
+const (
+	BFC_UNKNOWN = iota
+	BFC_EPERM
+	BFC_BAD_ID
+	BFC_BUNDLE_EXIST
+	BFC_BUNDLE_CLOSED
+	BFC_OUT_OF_BUNDLES
+	BFC_BAD_TYPE
+	BFC_BAD_FLAGS
+	BFC_MSG_BAD_LEN
+	BFC_MSG_BAD_XID
+	BFC_MSG_UNSUP
+	BFC_MSG_CONFLICT
+	BFC_MSG_TOO_MANY
+	BFC_MSG_FAILED
+	BFC_TIMEOUT
+	BFC_BUNDLE_IN_PROGRESS
+)
+
+// END: ofp14 - 7.5.4
+// END: ofp14 - 7.5
+
+// ofp_switch_features 1.4
+type SwitchFeatures struct {
+	common.Header
+	DPID         net.HardwareAddr // Size 8
+	Buffers      uint32
+	NumTables    uint8
+	AuxilaryId   uint8
+	pad          []uint8 // Size 2
+	Capabilities uint32
+	Reserved     uint32
+}
+
+// FeaturesRequest constructor
+func NewFeaturesRequest() *common.Header {
+	req := NewOfp14Header()
+	req.Type = Type_FeaturesRequest
+	return &req
+}
+
+// FeaturesReply constructor
+func NewFeaturesReply() *SwitchFeatures {
+	res := new(SwitchFeatures)
+	res.Header = NewOfp14Header()
+	res.Header.Type = Type_FeaturesReply
+	res.DPID = make([]byte, 8)
+	res.pad = make([]byte, 2)
+	return res
+}
+
+func (s *SwitchFeatures) Len() (n uint16) {
+	n = s.Header.Len()
+	n += uint16(len(s.DPID))
+	n += 16
+	return
+}
+
+func (s *SwitchFeatures) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	var bytes []byte
+	next := 0
+
+	s.Header.Length = s.Len()
+	if bytes, err = s.Header.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[next:], bytes)
+	next += len(bytes)
+	copy(data[next:], s.DPID)
+	next += len(s.DPID)
+	binary.BigEndian.PutUint32(data[next:], s.Buffers)
+	next += 4
+	data[next] = s.NumTables
+	next += 1
+	data[next] = s.AuxilaryId
+	next += 1
+	copy(data[next:], s.pad)
+	next += len(s.pad)
+	binary.BigEndian.PutUint32(data[next:], s.Capabilities)
+	next += 4
+	binary.BigEndian.PutUint32(data[next:], s.Reserved)
+	next += 4
+	return
+}
+
+func (s *SwitchFeatures) UnmarshalBinary(data []byte) error {
+	if len(data) < int(s.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full SwitchFeatures message")
+	}
+	err := s.Header.UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	next := int(s.Header.Len())
+	copy(s.DPID, data[next:])
+	next += len(s.DPID)
+	s.Buffers = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	s.NumTables = data[next]
+	next += 1
+	s.AuxilaryId = data[next]
+	next += 1
+	copy(s.pad, data[next:])
+	next += len(s.pad)
+	s.Capabilities = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	s.Reserved = binary.BigEndian.Uint32(data[next:])
+	next += 4
+	return nil
+}
+
+// ofp_capabilities 1.4
+const (
+	C_FLOW_STATS   = 1 << 0
+	C_TABLE_STATS  = 1 << 1
+	C_PORT_STATS   = 1 << 2
+	C_GROUP_STATS  = 1 << 3
+	C_IP_REASM     = 1 << 5
+	C_QUEUE_STATS  = 1 << 6
+	C_PORT_BLOCKED = 1 << 8
+)
+
+// ofp_vendor 1.4
+type VendorHeader struct {
+	Header           common.Header /*Type OFPT_VENDOR*/
+	Vendor           uint32
+	ExperimenterType uint32
+	VendorData       util.Message
+}
+
+func (v *VendorHeader) Len() (n uint16) {
+	length := uint16(16)
+	if v.VendorData != nil {
+		length += v.VendorData.Len()
+	}
+	return length
+}
+
+func (v *VendorHeader) MarshalBinary() (data []byte, err error) {
+	v.Header.Length = v.Len()
+	data = make([]byte, v.Len())
+	b, err := v.Header.MarshalBinary()
+	n := 0
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint32(data[n:], v.Vendor)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], v.ExperimenterType)
+	n += 4
+	if v.VendorData != nil {
+		vd, err := v.VendorData.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], vd)
+		n += len(vd)
+	}
+	return
+}
+
+func (v *VendorHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("The []byte the wrong size to unmarshal an " +
+			"VendorHeader message.")
+	}
+	v.Header.UnmarshalBinary(data)
+	n := int(v.Header.Len())
+	v.Vendor = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	v.ExperimenterType = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	if n < int(v.Header.Length) {
+		var err error
+		v.VendorData, err = decodeExperimenterMessage(v.Vendor, v.ExperimenterType, data[n:v.Header.Length])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ofp_role_request 1.4
+type RoleRequest struct {
+	common.Header
+	Role         uint32
+	GenerationId uint64
+}
+type RoleReply = RoleRequest
+
+// ofp_controller_role 1.4
+const (
+	CR_ROLE_NOCHANGE = iota /* Don’t change current role. */
+	CR_ROLE_EQUAL           /* Default role, full access. */
+	CR_ROLE_MASTER          /* Full access, at most one master. */
+	CR_ROLE_SLAVE           /* Read-only access. */
+)
+
+func NewRoleRequest() *RoleRequest {
+	f := new(RoleRequest)
+	f.Header = NewOfp14Header()
+	f.Header.Type = Type_RoleRequest
+	return f
+}
+
+func NewRoleReply() *RoleReply {
+	f := new(RoleReply)
+	f.Header = NewOfp14Header()
+	f.Header.Type = Type_RoleReply
+	return f
+}
+
+func (m *RoleRequest) Len() uint16 {
+	n := m.Header.Len()
+	n += 16
+	return n
+}
+
+func (m *RoleRequest) MarshalBinary() (data []byte, err error) {
+	m.Header.Length = m.Len()
+	data = make([]byte, m.Len())
+	b, err := m.Header.MarshalBinary()
+	n := 0
+	copy(data[n:], b)
+	n += len(b)
+	binary.BigEndian.PutUint32(data[n:], m.Role)
+	n += 4
+	n += 4 //pad
+	binary.BigEndian.PutUint64(data[n:], m.GenerationId)
+	n += 8
+	return
+}
+
+func (m *RoleRequest) UnmarshalBinary(data []byte) (err error) {
+	n := 0
+
+	err = m.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = int(m.Header.Len())
+
+	m.Role = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	n += 4 //pad
+	m.GenerationId = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	return
+}
+
+func NewGetAsyncRequest() *common.Header {
+	h := NewOfp14Header()
+	h.Type = Type_GetAsyncRequest
+	return &h
+}
+
+type Async_Config struct {
+	common.Header
+	Properties []util.Message
+}
+
+type GetAsyncReply = Async_Config
+type SetAsync = Async_Config
+
+func NewGetAsyncReply() *GetAsyncReply {
+	r := new(Async_Config)
+	r.Header = NewOfp14Header()
+	r.Header.Type = Type_GetAsyncReply
+	return r
+}
+
+func NewSetAsync() *SetAsync {
+	s := new(SetAsync)
+	s.Header = NewOfp14Header()
+	s.Header.Type = Type_SetAsync
+	return s
+}
+
+func (a *Async_Config) Len() uint16 {
+	n := a.Header.Len()
+	for _, p := range a.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (a *Async_Config) MarshalBinary() (data []byte, err error) {
+	a.Header.Length = a.Len()
+	data, err = a.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	for _, p := range a.Properties {
+		var b []byte
+		b, err = p.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (a *Async_Config) UnmarshalBinary(data []byte) (err error) {
+	n := uint16(0)
+	err = a.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = a.Header.Len()
+
+	for n < a.Header.Length {
+		var p util.Message
+		p, err = openflow13.NewAsyncConfigProp(data[n:])
+		if err != nil {
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		a.Properties = append(a.Properties, p)
+	}
+	return
+}
+
+// ofp_role_status 1.4
+type RoleStatus struct {
+	common.Header
+	Role         uint32
+	Reason       uint8
+	Pad          []byte // 3 bytes
+	GenerationId uint64
+	Properties   []util.Message
+}
+
+// ofp_controller_role_reason 1.4
+const (
+	CRR_MASTER_REQUEST = iota /* Another controller asked to be master. */
+	CRR_CONFIG                /* Configuration changed on the switch. */
+	CRR_EXPERIMENTER          /* Experimenter data changed. */
+)
+
+func NewRoleStatus() *RoleStatus {
+	r := new(RoleStatus)
+	r.Header = NewOfp14Header()
+	r.Header.Type = Type_RoleStatus
+	r.Pad = make([]byte, 3)
+	return r
+}
+
+func (r *RoleStatus) Len() uint16 {
+	n := r.Header.Len()
+	n += 16
+	for _, p := range r.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (r *RoleStatus) MarshalBinary() (data []byte, err error) {
+	r.Header.Length = r.Len()
+	data = make([]byte, r.Len())
+
+	var b []byte
+	b, err = r.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	n := 0
+	copy(data[n:], b)
+	n = int(r.Header.Len())
+
+	binary.BigEndian.PutUint32(data[n:], r.Role)
+	n += 4
+
+	data[n] = r.Reason
+	n++
+
+	copy(data[n:], r.Pad)
+	n += len(r.Pad)
+
+	binary.BigEndian.PutUint64(data[n:], r.GenerationId)
+	n += 8
+
+	for _, p := range r.Properties {
+		var b []byte
+		b, err = p.MarshalBinary()
+		if err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (r *RoleStatus) UnmarshalBinary(data []byte) (err error) {
+	n := uint16(0)
+	err = r.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = r.Header.Len()
+
+	r.Role = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	r.Reason = data[n]
+	n++
+
+	n += 3 //Pad
+
+	r.GenerationId = binary.BigEndian.Uint64(data[n:])
+	n += 8
+
+	for n < r.Header.Length {
+		var p util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case RPT_EXPERIMENTER:
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
+		default:
+			err = errors.New("An unknown property type was received")
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		r.Properties = append(r.Properties, p)
+	}
+	return
+}
+
+// ofp_role_prop_type 1.4
+const (
+	RPT_EXPERIMENTER = 0xFFFF /* Experimenter property. */
+)
+
+// ofp_role_prop_experimenter 1.4
+type PropExperimenter struct {
+	Header       PropHeader
+	Experimenter uint32
+	ExpType      uint32
+	Data         []uint32
+	Pad          []byte
+}
+
+func (p *PropExperimenter) Len() uint16 {
+	n := p.Header.Len() + 8 + uint16(len(p.Data)*4)
+	// Round it to closest multiple of 8
+	return ((n + 7) / 8) * 8
+}
+
+func (p *PropExperimenter) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(p.Len()))
+	p.Header.Length = p.Header.Len() + 8 + uint16(len(p.Data)*4)
+	b, err := p.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := p.Header.Len()
+
+	binary.BigEndian.PutUint32(data[n:], p.Experimenter)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], p.ExpType)
+	n += 4
+
+	for _, d := range p.Data {
+		binary.BigEndian.PutUint32(data[n:], d)
+		n += 4
+	}
+
+	return
+}
+
+func (p *PropExperimenter) UnmarshalBinary(data []byte) (err error) {
+	if err = p.Header.UnmarshalBinary(data); err != nil {
+		return
+	}
+	if p.Header.Length < 12 || len(data) < int(p.Header.Length) {
+		return errors.New("the []byte is too short to unmarshal a full PropExperimenter message")
+	}
+	n := p.Header.Len()
+
+	p.Experimenter = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	p.ExpType = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	for n+4 <= p.Header.Length {
+		d := binary.BigEndian.Uint32(data[n:])
+		p.Data = append(p.Data, d)
+		n += 4
+	}
+	return
+}
+
+// ofp_table_desc 1.4
+type TableDesc struct {
+	Length     uint16
+	TableId    uint8
+	Pad        uint8
+	Config     uint32
+	Properties []util.Message
+}
+
+// ofp_table_config 1.4
+const (
+	TC_DEPRECATED_MASK = 3      /* Deprecated bits */
+	TC_EVICTION        = 1 << 2 /* Authorise table to evict flows. */
+	TC_VACANCY_EVENTS  = 1 << 3 /* Enable vacancy events. */
+)
+
+func NewTableDesc(id uint8) *TableDesc {
+	t := new(TableDesc)
+	t.TableId = id
+	return t
+}
+
+func (t *TableDesc) Len() uint16 {
+	var n uint16 = 8
+	for _, p := range t.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (t *TableDesc) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(t.Len()))
+	t.Length = t.Len()
+	var n uint16 = 0
+	binary.BigEndian.PutUint16(data[n:], t.Length)
+	n += 2
+
+	data[n] = t.TableId
+	n += 2 // skipping Pad
+
+	binary.BigEndian.PutUint32(data[n:], t.Config)
+	n += 4
+
+	for _, p := range t.Properties {
+		var b []byte
+		b, err = p.MarshalBinary()
+		if err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += p.Len()
+	}
+	return
+}
+
+func (t *TableDesc) UnmarshalBinary(data []byte) (err error) {
+	var n uint16 = 0
+	t.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	t.TableId = data[n]
+	n += 2 // skipping Pad
+
+	t.Config = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	for n < t.Length {
+		var p util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case OFPTMPT_EVICTION:
+			p = new(TableModPropEviction)
+		case OFPTMPT_VACANCY:
+			p = new(TableModPropVacancy)
+		case OFPTMPT_EXPERIMENTER:
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
+		default:
+			err = errors.New("An unknown property type was received")
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		t.Properties = append(t.Properties, p)
+	}
+	return
+}
+
+// ofp_table_mod_prop_type 1.4
+const (
+	OFPTMPT_EVICTION     = 0x2    /* Eviction property. */
+	OFPTMPT_VACANCY      = 0x3    /* Vacancy property. */
+	OFPTMPT_EXPERIMENTER = 0xFFFF /* Experimenter property. */
+)
+
+// ofp_table_mod_prop_eviction 1.4
+type TableModPropEviction struct {
+	Header PropHeader
+	Flags  uint32
+}
+
+// ofp_table_mod_prop_eviction_flag 1.4
+const (
+	TMPEF_OTHER      = 1 << 0 /* Using other factors. */
+	TMPEF_IMPORTANCE = 1 << 1 /* Using flow entry importance. */
+	TMPEF_LIFETIME   = 1 << 2 /* Using flow entry lifetime. */
+)
+
+func NewTableModPropEviction() *TableModPropEviction {
+	n := new(TableModPropEviction)
+	n.Header.Type = OFPTMPT_EVICTION
+	return n
+}
+
+func (t *TableModPropEviction) Len() uint16 {
+	n := t.Header.Len()
+	n += 4
+	return n
+}
+
+func (t *TableModPropEviction) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(t.Len()))
+	t.Header.Length = t.Header.Len() + 4
+	var b []byte
+	b, err = t.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := t.Header.Len()
+
+	binary.BigEndian.PutUint32(data[n:], t.Flags)
+	n += 4
+	return
+}
+
+func (t *TableModPropEviction) UnmarshalBinary(data []byte) (err error) {
+	var n uint16 = 0
+	err = t.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n += t.Header.Len()
+	t.Flags = binary.BigEndian.Uint32(data[n:])
+	return
+}
+
+// ofp_table_mod_prop_vacancy 1.4
+type TableModPropVacancy struct {
+	Header      PropHeader
+	VacancyDown uint8
+	VacancyUp   uint8
+	Vacancy     uint8
+	Pad         uint8
+}
+
+func NewTableModPropVacancy() *TableModPropVacancy {
+	n := new(TableModPropVacancy)
+	n.Header.Type = OFPTMPT_VACANCY
+	return n
+}
+
+func (t *TableModPropVacancy) Len() uint16 {
+	n := t.Header.Len()
+	n += 4
+	return n
+}
+
+func (t *TableModPropVacancy) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(t.Len()))
+	t.Header.Length = t.Header.Len() + 4
+	var b []byte
+	b, err = t.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data, b)
+	n := t.Header.Len()
+
+	data[n] = t.VacancyDown
+	n++
+	data[n] = t.VacancyUp
+	n++
+	data[n] = t.Vacancy
+	n++
+
+	return
+}
+
+func (t *TableModPropVacancy) UnmarshalBinary(data []byte) (err error) {
+	var n uint16 = 0
+	err = t.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n += t.Header.Len()
+
+	t.VacancyDown = data[n]
+	n++
+
+	t.VacancyUp = data[n]
+	n++
+
+	t.Vacancy = data[n]
+	n++
+	return
+}
+
+type TableStatus struct {
+	common.Header
+	Reason uint8
+	Pad    []byte // 7 bytes
+	Table  TableDesc
+}
+
+// ofp_table_reason 1.4
+const (
+	TR_VACANCY_DOWN = 3 /* Vacancy down threshold event. */
+	TR_VACANCY_UP   = 4 /* Vacancy up threshold event. */
+)
+
+func NewTableStatus() *TableStatus {
+	t := new(TableStatus)
+	t.Header = NewOfp14Header()
+	t.Header.Type = Type_TableStatus
+	t.Pad = make([]byte, 7)
+	return t
+}
+
+func (t *TableStatus) Len() uint16 {
+	n := t.Header.Len()
+	n += 8
+	n += t.Table.Len()
+	return n
+}
+
+func (t *TableStatus) MarshalBinary() (data []byte, err error) {
+	t.Header.Length = t.Len()
+	data = make([]byte, t.Len())
+
+	var b []byte
+	b, err = t.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	n := 0
+	copy(data[n:], b)
+	n = int(t.Header.Len())
+
+	data[n] = t.Reason
+	n += 8
+
+	b, err = t.Table.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += len(b)
+
+	return
+}
+
+func (t *TableStatus) UnmarshalBinary(data []byte) (err error) {
+	n := uint16(0)
+	err = t.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = t.Header.Len()
+
+	t.Reason = data[n]
+	n++
+	n += 7 //Pad
+
+	err = t.Table.UnmarshalBinary(data[n:])
+	if err != nil {
+	}
+	return
+}
+
+// ofp_table_mod 1.4
+type TableMod struct {
+	common.Header
+	TableId    uint8
+	Pad        []byte // 3 bytes
+	Config     uint32
+	Properties []util.Message
+}
+
+func NewTableMod() *TableMod {
+	n := new(TableMod)
+	n.Header = NewOfp14Header()
+	n.Header.Type = Type_TableMod
+	n.Pad = make([]byte, 3)
+	return n
+}
+
+func (t *TableMod) Len() uint16 {
+	var n uint16 = t.Header.Len()
+	n += 8
+	for _, prop := range t.Properties {
+		n += prop.Len()
+	}
+	return n
+}
+
+func (t *TableMod) MarshalBinary() (data []byte, err error) {
+	t.Header.Length = t.Len()
+	data = make([]byte, t.Len())
+
+	var b []byte
+	b, err = t.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	var n uint16
+	copy(data[n:], b)
+	n = t.Header.Len()
+
+	data[n] = t.TableId
+	n++
+	n += 3 // Pad
+
+	binary.BigEndian.PutUint32(data[n:], t.Config)
+	n += 4
+
+	for _, prop := range t.Properties {
+		b, err = prop.MarshalBinary()
+		if err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += prop.Len()
+	}
+	return
+}
+
+func (t *TableMod) UnmarshalBinary(data []byte) (err error) {
+	var n uint16 = 0
+	err = t.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = t.Header.Len()
+
+	t.TableId = data[n]
+	n++
+	n += 3 // Pad
+
+	t.Config = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	for n < uint16(len(data)) {
+		var p util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case OFPTMPT_EVICTION:
+			p = new(TableModPropEviction)
+		case OFPTMPT_VACANCY:
+			p = new(TableModPropVacancy)
+		case OFPTMPT_EXPERIMENTER:
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
+		default:
+			err = errors.New("An unknown property type was received")
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		t.Properties = append(t.Properties, p)
+	}
+	return
+}
+
+// ofp_requestforward_header, Request is the GroupMod or MeterMod forwarded by the switch.
+type RequestForward struct {
+	common.Header
+	Request util.Message
+}
+
+func NewRequestForward() *RequestForward {
+	r := new(RequestForward)
+	r.Header = NewOfp14Header()
+	r.Header.Type = Type_RequestForward
+	return r
+}
+
+func (r *RequestForward) Len() uint16 {
+	n := r.Header.Len()
+	if r.Request != nil {
+		n += r.Request.Len()
+	}
+	return n
+}
+
+func (r *RequestForward) MarshalBinary() (data []byte, err error) {
+	r.Header.Length = r.Len()
+	data, err = r.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	if r.Request != nil {
+		var b []byte
+		b, err = r.Request.MarshalBinary()
+		if err != nil {
+			return
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (r *RequestForward) UnmarshalBinary(data []byte) (err error) {
+	err = r.Header.UnmarshalBinary(data)
+	if err != nil {
+		return
+	}
+	n := r.Header.Len()
+	if len(data) < int(r.Header.Length) {
+		return errors.New("the []byte is too short to unmarshal a full RequestForward message")
+	}
+
+	r.Request, err = Parse(data[n:r.Header.Length])
+	return
+}
+
+// ofp_bundle_ctrl_msg 1.4
+type BundleCtrl struct {
+	common.Header
+	BundleId   uint32
+	Type       uint16
+	Flags      uint16
+	Properties []util.Message
+}
+
+// ofp_bundle_ctrl_type 1.4
+const (
+	BCT_OPEN_REQUEST = iota
+	BCT_OPEN_REPLY
+	BCT_CLOSE_REQUEST
+	BCT_CLOSE_REPLY
+	BCT_COMMIT_REQUEST
+	BCT_COMMIT_REPLY
+	BCT_DISCARD_REQUEST
+	BCT_DISCARD_REPLY
+)
+
+// ofp_bundle_flags 1.4
+const (
+	BF_ATOMIC  = 1 << 0 /* Execute atomically. */
+	BF_ORDERED = 1 << 1 /* Execute in specified order. */
+)
+
+// ofp_bundle_prop_type 1.4
+const (
+	BPT_EXPERIMENTER = 0xFFFF /* Experimenter property. */
+)
+
+func NewBundleCtrl(id uint32, bundleType uint16, flags uint16) *BundleCtrl {
+	c := new(BundleCtrl)
+	c.Header = NewOfp14Header()
+	c.Header.Type = Type_BundleControl
+	c.BundleId = id
+	c.Type = bundleType
+	c.Flags = flags
+	return c
+}
+
+func (c *BundleCtrl) Len() uint16 {
+	n := c.Header.Len()
+	n += 8
+	for _, p := range c.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (c *BundleCtrl) MarshalBinary() (data []byte, err error) {
+	c.Header.Length = c.Len()
+	data = make([]byte, c.Len())
+
+	var b []byte
+	b, err = c.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	var n uint16
+	copy(data[n:], b)
+	n = c.Header.Len()
+
+	binary.BigEndian.PutUint32(data[n:], c.BundleId)
+	n += 4
+
+	binary.BigEndian.PutUint16(data[n:], c.Type)
+	n += 2
+
+	binary.BigEndian.PutUint16(data[n:], c.Flags)
+	n += 2
+
+	for _, p := range c.Properties {
+		var b []byte
+		b, err = p.MarshalBinary()
+		if err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += p.Len()
+	}
+	return
+}
+
+func (c *BundleCtrl) UnmarshalBinary(data []byte) (err error) {
+	var n uint16
+	err = c.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = c.Header.Len()
+
+	c.BundleId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	c.Type = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	c.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	for n < c.Length {
+		var p util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case BPT_EXPERIMENTER:
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
+		default:
+			err = errors.New("An unknown property type was received")
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		c.Properties = append(c.Properties, p)
+	}
+	return
+}
+
+// ofp_bundle_add_msg 1.4
+type BndleAdd struct {
+	common.Header
+	BundleId   uint32
+	Pad        uint16
+	Flags      uint16
+	Message    util.Message
+	Properties []util.Message
+}
+
+func NewBndleAdd(id uint32, flags uint16) *BndleAdd {
+	c := new(BndleAdd)
+	c.Header = NewOfp14Header()
+	c.Header.Type = Type_BundleAddMessage
+	c.BundleId = id
+	c.Flags = flags
+	return c
+}
+
+func (c *BndleAdd) Len() uint16 {
+	n := c.Header.Len()
+	n += 8
+
+	n += c.Message.Len()
+	for _, p := range c.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (c *BndleAdd) MarshalBinary() (data []byte, err error) {
+	c.Header.Length = c.Len()
+	data = make([]byte, c.Len())
+
+	var b []byte
+	b, err = c.Header.MarshalBinary()
+	if err != nil {
+		return
+	}
+	var n uint16
+	copy(data[n:], b)
+	n = c.Header.Len()
+
+	binary.BigEndian.PutUint32(data[n:], c.BundleId)
+	n += 4
+
+	n += 2 // Pad
+
+	binary.BigEndian.PutUint16(data[n:], c.Flags)
+	n += 2
+
+	b, err = c.Message.MarshalBinary()
+	if err != nil {
+		return
+	}
+	copy(data[n:], b)
+	n += uint16(len(b))
+
+	for _, p := range c.Properties {
+		b, err = p.MarshalBinary()
+		if err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += p.Len()
+	}
+
+	return
+}
+
+func (c *BndleAdd) UnmarshalBinary(data []byte) (err error) {
+	var n uint16
+	err = c.Header.UnmarshalBinary(data[n:])
+	if err != nil {
+		return
+	}
+	n = c.Header.Len()
+
+	c.BundleId = binary.BigEndian.Uint32(data[n:])
+	n += 4
+
+	n += 2 // Pad
+
+	c.Flags = binary.BigEndian.Uint16(data[n:])
+	n += 2
+
+	c.Message, err = Parse(data[n:])
+	if err != nil {
+		return
+	}
+	n += c.Message.Len()
+
+	for n < c.Length {
+		var p util.Message
+		switch binary.BigEndian.Uint16(data[n:]) {
+		case BPT_EXPERIMENTER:
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
+		default:
+			err = errors.New("An unknown property type was received")
+			return
+		}
+		err = p.UnmarshalBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		n += p.Len()
+		c.Properties = append(c.Properties, p)
+	}
+
+	return
+}
+
+// BarrierRequest constructor
+func NewBarrierRequest() *common.Header {
+	req := NewOfp14Header()
+	req.Type = Type_BarrierRequest
+	return &req
+}
+
+// BarrierReply constructor
+func NewBarrierReply() *common.Header {
+	req := NewOfp14Header()
+	req.Type = Type_BarrierReply
+	return &req
+}
+
+type Property interface {
+	Header() *PropHeader
+	util.Message
+}
+
+/* Generic OpenFlow property header, as used by various messages in OF1.3+, and
+ * especially in OF1.4.
+ *
+ * The OpenFlow specs prefer to define a new structure with a specialized name
+ * each time this property structure comes up: struct
+ * ofp_port_desc_prop_header, struct ofp_controller_status_prop_header, struct
+ * ofp_table_mod_prop_header, and more.  They're all the same, so it's easier
+ * to unify them.
+ */
+type PropHeader struct {
+	Type   uint16
+	Length uint16
+}
+
+func (p *PropHeader) Header() *PropHeader {
+	return p
+}
+
+func (p *PropHeader) Len() (n uint16) {
+	return 4
+}
+
+func (p *PropHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	binary.BigEndian.PutUint16(data[:2], p.Type)
+	binary.BigEndian.PutUint16(data[2:4], p.Length)
+	return
+}
+
+func (p *PropHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(p.Len()) {
+		return errors.New("the []byte the wrong size to unmarshal an " +
+			"PropHeader message")
+	}
+	p.Type = binary.BigEndian.Uint16(data[:2])
+	p.Length = binary.BigEndian.Uint16(data[2:4])
+	return nil
+}
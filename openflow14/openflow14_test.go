@@ -0,0 +1,104 @@
+package openflow14
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/util"
+)
+
+// assertParse encodes msg, decodes the data with Parse, and checks the decoded message is encoded the same as msg.
+func assertParse(t *testing.T, msg util.Message) util.Message {
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, uint8(VERSION), data[0])
+	parsed, err := Parse(data)
+	require.NoError(t, err)
+	require.IsType(t, msg, parsed)
+	newData, err := parsed.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+	return parsed
+}
+
+func TestPortMessages(t *testing.T) {
+	portStatus := NewPortStatus()
+	portStatus.Reason = PR_MODIFY
+	portStatus.Desc = *NewPort(3)
+	copy(portStatus.Desc.Name, "eth0")
+	ethernet := NewPortDescPropEthernet()
+	ethernet.CurrSpeed = 10000000
+	portStatus.Desc.Properties = append(portStatus.Desc.Properties, ethernet)
+	parsedStatus := assertParse(t, portStatus).(*PortStatus)
+	assert.Equal(t, uint32(3), parsedStatus.Desc.PortNo)
+	require.Len(t, parsedStatus.Desc.Properties, 1)
+	assert.Equal(t, uint32(10000000), parsedStatus.Desc.Properties[0].(*PortDescPropEthernet).CurrSpeed)
+
+	portMod := NewPortMod(3)
+	portMod.Config = PC_NO_FWD
+	portMod.Mask = PC_NO_FWD
+	portMod.Properties = append(portMod.Properties, NewPortModPropEthernet(PF_10GB_FD))
+	parsedMod := assertParse(t, portMod).(*PortMod)
+	require.Len(t, parsedMod.Properties, 1)
+}
+
+func TestTableMessages(t *testing.T) {
+	tableMod := NewTableMod()
+	tableMod.TableId = 1
+	tableMod.Config = TC_EVICTION | TC_VACANCY_EVENTS
+	eviction := NewTableModPropEviction()
+	eviction.Flags = TMPEF_IMPORTANCE
+	vacancy := NewTableModPropVacancy()
+	vacancy.VacancyDown = 10
+	vacancy.VacancyUp = 90
+	tableMod.Properties = append(tableMod.Properties, eviction, vacancy)
+	parsedMod := assertParse(t, tableMod).(*TableMod)
+	require.Len(t, parsedMod.Properties, 2)
+	assert.Equal(t, uint8(90), parsedMod.Properties[1].(*TableModPropVacancy).VacancyUp)
+
+	tableStatus := NewTableStatus()
+	tableStatus.Reason = TR_VACANCY_DOWN
+	tableStatus.Table = *NewTableDesc(1)
+	vacancy.Vacancy = 5
+	tableStatus.Table.Properties = append(tableStatus.Table.Properties, vacancy)
+	parsedStatus := assertParse(t, tableStatus).(*TableStatus)
+	assert.Equal(t, uint8(1), parsedStatus.Table.TableId)
+	assert.Equal(t, uint8(5), parsedStatus.Table.Properties[0].(*TableModPropVacancy).Vacancy)
+}
+
+func TestControllerMessages(t *testing.T) {
+	roleStatus := NewRoleStatus()
+	roleStatus.Role = CR_ROLE_SLAVE
+	roleStatus.Reason = CRR_MASTER_REQUEST
+	roleStatus.GenerationId = 10
+	parsedRole := assertParse(t, roleStatus).(*RoleStatus)
+	assert.Equal(t, uint64(10), parsedRole.GenerationId)
+
+	groupMod := NewGroupMod()
+	groupMod.GroupId = 5
+	bucket := openflow13.NewBucket()
+	bucket.AddAction(openflow13.NewActionOutput(2))
+	groupMod.AddBucket(*bucket)
+	requestForward := NewRequestForward()
+	requestForward.Request = groupMod
+	parsedForward := assertParse(t, requestForward).(*RequestForward)
+	assert.Equal(t, uint32(5), parsedForward.Request.(*GroupMod).GroupId)
+}
+
+func TestBundleMessages(t *testing.T) {
+	bundleCtrl := NewBundleCtrl(1, BCT_OPEN_REQUEST, BF_ATOMIC|BF_ORDERED)
+	parsedCtrl := assertParse(t, bundleCtrl).(*BundleCtrl)
+	assert.Equal(t, uint16(BF_ATOMIC|BF_ORDERED), parsedCtrl.Flags)
+
+	flowMod := NewFlowMod()
+	flowMod.Importance = 7
+	flowMod.Match.AddField(*openflow13.NewInPortField(3))
+	flowMod.AddInstruction(openflow13.NewInstrGotoTable(1))
+	bundleAdd := NewBndleAdd(1, BF_ATOMIC)
+	bundleAdd.Message = flowMod
+	parsedAdd := assertParse(t, bundleAdd).(*BndleAdd)
+	assert.Equal(t, uint16(7), parsedAdd.Message.(*FlowMod).Importance)
+}
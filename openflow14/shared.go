@@ -0,0 +1,55 @@
+package openflow14
+
+import (
+	"antrea.io/libOpenflow/openflow13"
+)
+
+// OpenFlow 1.4 encodes the OXM match, the actions, the instructions, the group buckets and the Nicira and ONF
+// extensions as OpenFlow 1.3, so they are built and decoded with the openflow13 package. The experimenter actions
+// and match fields are registered in openflow13.
+
+type (
+	Match        = openflow13.Match
+	MatchField   = openflow13.MatchField
+	Instruction  = openflow13.Instruction
+	InstrHeader  = openflow13.InstrHeader
+	Action       = openflow13.Action
+	ActionHeader = openflow13.ActionHeader
+	Bucket       = openflow13.Bucket
+	GroupMod     = openflow13.GroupMod
+)
+
+const (
+	NxExperimenterID    = openflow13.NxExperimenterID
+	ONF_EXPERIMENTER_ID = openflow13.ONF_EXPERIMENTER_ID
+
+	OFPG_ALL = openflow13.OFPG_ALL
+	OFPG_ANY = openflow13.OFPG_ANY
+)
+
+var (
+	NewMatch     = openflow13.NewMatch
+	DecodeInstr  = openflow13.DecodeInstr
+	DecodeAction = openflow13.DecodeAction
+)
+
+// NewGroupMod returns an OFPT_GROUP_MOD message with the OpenFlow 1.4 header.
+func NewGroupMod() *GroupMod {
+	g := openflow13.NewGroupMod()
+	g.Header = NewOfp14Header()
+	g.Header.Type = Type_GroupMod
+	return g
+}
+
+// NewVendorHeader returns the OpenFlow 1.4 experimenter message with the experimenter ID, the experimenter type and
+// the body of msg, which is built by the Nicira or ONF message constructors of openflow13.
+func NewVendorHeader(msg *openflow13.VendorHeader) *VendorHeader {
+	h := NewOfp14Header()
+	h.Type = Type_Experimenter
+	return &VendorHeader{
+		Header:           h,
+		Vendor:           msg.Vendor,
+		ExperimenterType: msg.ExperimenterType,
+		VendorData:       msg.VendorData,
+	}
+}
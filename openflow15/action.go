@@ -117,13 +117,10 @@ func DecodeAction(data []byte) (Action, error) {
 		if len(data) < NxActionHeaderLength {
 			return nil, errors.New("the []byte is too short to decode OpenFlow experimenter message")
 		}
-		v := binary.BigEndian.Uint32(data[4:8])
-		if v == NxExperimenterID {
-			a, err = DecodeNxAction(data)
-			if err != nil {
-				klog.ErrorS(err, "Failed to decode NxAction", "data", data)
-				return nil, err
-			}
+		a, err = decodeExperimenterAction(binary.BigEndian.Uint32(data[4:8]), data)
+		if err != nil {
+			klog.ErrorS(err, "Failed to decode experimenter action", "data", data)
+			return nil, err
 		}
 	default:
 		return nil, fmt.Errorf("DecodeAction unknown type: %v", t)
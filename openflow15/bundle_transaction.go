@@ -0,0 +1,127 @@
+package openflow15
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// DefaultBundleTimeout is the default time to wait for the reply of a bundle control request.
+const DefaultBundleTimeout = util.DefaultBundleTimeout
+
+// BundleMessageError is the error replied by the switch for a message added into a bundle transaction.
+type BundleMessageError = util.BundleMessageError[*ErrorMsg]
+
+// BundleTransactionError is returned by BundleTransaction when the switch rejects a bundle or any added message.
+type BundleTransactionError = util.BundleTransactionError[*ErrorMsg]
+
+// BundleTransaction adds messages into bundles with OFPT_BUNDLE_ADD_MESSAGE and opens, commits or discards them with
+// OFPT_BUNDLE_CONTROL. See util.BundleTransaction for the details. A transaction created with the BF_TIME flag is
+// committed with CommitAt.
+type BundleTransaction struct {
+	*util.BundleTransaction[*ErrorMsg]
+}
+
+// NewBundleTransaction creates a BundleTransaction with the bundle ID and the BF_ATOMIC, BF_ORDERED and BF_TIME flags.
+// The flags are the same in all the messages of the transaction, so BF_TIME must be set to commit the bundles with
+// CommitAt.
+func NewBundleTransaction(send func(msg util.Message) error, bundleID uint32, flags uint16) *BundleTransaction {
+	return &BundleTransaction{util.NewBundleTransaction[*ErrorMsg](bundleProtocol{}, send, bundleID, flags)}
+}
+
+// Commit commits the bundles in order, see util.BundleTransaction.Commit.
+func (t *BundleTransaction) Commit() error {
+	if t.Flags()&BF_TIME != 0 {
+		return errors.New("the bundle transaction with BF_TIME must be committed with CommitAt")
+	}
+	return t.BundleTransaction.Commit()
+}
+
+// BundleFeatures queries the bundle features of the switch.
+func (t *BundleTransaction) BundleFeatures() (*BundleFeatures, error) {
+	msg := NewMpRequest(MultipartType_BundleFeatures)
+	msg.Body = append(msg.Body, NewBundleFeaturesRequest())
+	reply, err := t.Request(msg, msg.Xid)
+	if err != nil {
+		return nil, err
+	}
+	switch reply := reply.(type) {
+	case *MultipartReply:
+		for _, body := range reply.Body {
+			if features, ok := body.(*BundleFeatures); ok {
+				return features, nil
+			}
+		}
+	case *ErrorMsg:
+		return nil, reply
+	}
+	return nil, errors.New("unexpected reply of bundle features request")
+}
+
+// CommitAt commits the bundles in order at schedTime with the BPT_TIME property. The transaction must be created with
+// the BF_TIME flag. It queries the bundle features to check the switch supports scheduled bundles and schedTime is
+// within the scheduling range of the switch, then commits the bundles as Commit.
+func (t *BundleTransaction) CommitAt(schedTime time.Time) error {
+	if t.Flags()&BF_TIME == 0 {
+		return errors.New("the bundle transaction must be created with BF_TIME to be committed at a time")
+	}
+	features, err := t.BundleFeatures()
+	if err != nil {
+		return err
+	}
+	capability := features.TimeCapability()
+	if capability == nil {
+		return errors.New("the switch does not support scheduled bundles")
+	}
+	if err := capability.ValidateScheduledTime(schedTime); err != nil {
+		return err
+	}
+	return t.CommitWithProperties([]util.Message{NewBundlePropTime(schedTime)})
+}
+
+// bundleProtocol builds the OFPT_BUNDLE_CONTROL and OFPT_BUNDLE_ADD_MESSAGE messages.
+type bundleProtocol struct{}
+
+func (bundleProtocol) NewControl(bundleID uint32, ctrlType uint16, flags uint16, props []util.Message) (util.Message, uint32) {
+	msg := NewBundleCtrl(bundleID, ctrlType, flags)
+	msg.Properties = props
+	return msg, msg.Xid
+}
+
+func (bundleProtocol) NewAdd(bundleID uint32, flags uint16, msg util.Message, xid uint32) util.Message {
+	bundleAdd := NewBndleAdd(bundleID, flags)
+	bundleAdd.Message = msg
+	bundleAdd.Xid = xid
+	return bundleAdd
+}
+
+func (bundleProtocol) CheckControlReply(reply util.Message, ctrlType uint16) error {
+	switch reply := reply.(type) {
+	case *BundleCtrl:
+		if reply.Type == ctrlType+1 {
+			return nil
+		}
+	case *ErrorMsg:
+		// The codes of OFPET_BUNDLE_FAILED before BFC_SCHED_NOT_SUPPORTED are in the order of the ONF bundle error
+		// codes.
+		if reply.Type == ET_BUNDLE_FAILED && reply.Code < BFC_SCHED_NOT_SUPPORTED {
+			return ParseBundleError(BEC_UNKNOWN + reply.Code)
+		}
+		return reply
+	}
+	return fmt.Errorf("unexpected reply of bundle control request %d", ctrlType)
+}
+
+func (bundleProtocol) ParseReply(msg util.Message) (uint32, *ErrorMsg, bool, bool) {
+	switch msg := msg.(type) {
+	case *BundleCtrl:
+		return msg.Xid, nil, false, true
+	case *MultipartReply:
+		return msg.Xid, nil, false, true
+	case *ErrorMsg:
+		return msg.Xid, msg, true, true
+	}
+	return 0, nil, false, false
+}
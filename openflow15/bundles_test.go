@@ -3,10 +3,14 @@ package openflow15
 import (
 	"bytes"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
 )
 
 func TestBundleControl(t *testing.T) {
@@ -142,3 +146,211 @@ func bundleAddEqual(bundleAdd, bundleAdd2 *BundleAdd) error {
 	}
 	return nil
 }
+
+// testBundleSwitch is a fake switch connected to a BundleTransaction, which replies the bundle control requests,
+// fails to commit failedBundle and rejects the flow mods with failedCookie in the bundles. Zero values fail nothing.
+// The switch supports to schedule the bundles in one minute.
+type testBundleSwitch struct {
+	failedBundle uint32
+	failedCookie uint64
+
+	mutex sync.Mutex
+	// requests are the messages received by the switch.
+	requests []util.Message
+}
+
+// Controls returns the bundle control requests of type ctrlType received by the switch.
+func (s *testBundleSwitch) Controls(ctrlType uint16) []*BundleCtrl {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var ctrls []*BundleCtrl
+	for _, msg := range s.requests {
+		if ctrl, ok := msg.(*BundleCtrl); ok && ctrl.Type == ctrlType {
+			ctrls = append(ctrls, ctrl)
+		}
+	}
+	return ctrls
+}
+
+// BundleIDs returns the bundle IDs of the control requests of type ctrlType received by the switch.
+func (s *testBundleSwitch) BundleIDs(ctrlType uint16) []uint32 {
+	var ids []uint32
+	for _, ctrl := range s.Controls(ctrlType) {
+		ids = append(ids, ctrl.BundleId)
+	}
+	return ids
+}
+
+func newTestBundleSwitch(t *testing.T, bundleID uint32, flags uint16, sw *testBundleSwitch) *BundleTransaction {
+	var txn *BundleTransaction
+	// The replies are handled in order as they are received on a connection.
+	replies := make(chan util.Message, 100)
+	go func() {
+		for reply := range replies {
+			txn.HandleMessage(reply)
+		}
+	}()
+	t.Cleanup(func() { close(replies) })
+	txn = NewBundleTransaction(func(msg util.Message) error {
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		parsed, err := Parse(data)
+		require.NoError(t, err)
+		sw.mutex.Lock()
+		sw.requests = append(sw.requests, parsed)
+		sw.mutex.Unlock()
+		switch parsed := parsed.(type) {
+		case *MultipartRequest:
+			prop := NewBundleFeaturesPropTime()
+			prop.SchedMaxFuture = OfpTime{Seconds: 60}
+			features := NewBundleFeatures()
+			features.Capabilities = BF_TIME
+			features.Properties = []util.Message{prop}
+			mp := NewMpReply(MultipartType_BundleFeatures)
+			mp.Xid = parsed.Xid
+			mp.Body = []util.Message{features}
+			replies <- mp
+		case *BundleCtrl:
+			assert.Equal(t, flags, parsed.Flags)
+			if parsed.Type == BCT_COMMIT_REQUEST && sw.failedBundle != 0 && parsed.BundleId == sw.failedBundle {
+				errMsg := NewErrorMsg()
+				errMsg.Type = ET_BUNDLE_FAILED
+				errMsg.Code = BFC_MSG_FAILED
+				errMsg.Xid = parsed.Xid
+				replies <- errMsg
+				break
+			}
+			ctrl := NewBundleCtrl(parsed.BundleId, parsed.Type+1, parsed.Flags)
+			ctrl.Xid = parsed.Xid
+			replies <- ctrl
+		case *BndleAdd:
+			assert.Equal(t, flags, parsed.Flags)
+			if sw.failedCookie == 0 || parsed.Message.(*FlowMod).Cookie != sw.failedCookie {
+				break
+			}
+			errMsg := NewErrorMsg()
+			errMsg.Type = ET_FLOW_MOD_FAILED
+			errMsg.Code = FMFC_OVERLAP
+			errMsg.Xid = parsed.Xid
+			replies <- errMsg
+		default:
+			t.Errorf("Unexpected message %T in the bundle transaction", parsed)
+		}
+		return nil
+	}, bundleID, flags)
+	return txn
+}
+
+func TestBundleTransaction(t *testing.T) {
+	newFlowMod := func(cookie uint64) *FlowMod {
+		flowMod := NewFlowMod()
+		flowMod.Cookie = cookie
+		return flowMod
+	}
+	nextBundleID := func(bundleID uint32) func() uint32 {
+		return func() uint32 {
+			bundleID++
+			return bundleID
+		}
+	}
+
+	sw := &testBundleSwitch{}
+	txn := newTestBundleSwitch(t, 10, BF_ATOMIC|BF_ORDERED, sw)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2)))
+	require.NoError(t, txn.Commit())
+	assert.Equal(t, []uint32{10}, sw.BundleIDs(BCT_OPEN_REQUEST))
+	assert.Error(t, txn.Add(newFlowMod(3)), "Messages should not be added after the transaction is committed")
+
+	// The large bundles are split, and the failures are mapped to the added messages.
+	sw = &testBundleSwitch{failedBundle: 21, failedCookie: 3}
+	txn = newTestBundleSwitch(t, 20, BF_ORDERED, sw)
+	txn.MaxMessages = 2
+	txn.NextBundleID = nextBundleID(20)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2), newFlowMod(3)))
+	assert.Equal(t, []uint32{20, 21}, sw.BundleIDs(BCT_OPEN_REQUEST))
+	err := txn.Commit()
+	var txnErr *BundleTransactionError
+	require.True(t, errors.As(err, &txnErr))
+	assert.Equal(t, uint32(21), txnErr.BundleID)
+	assert.EqualError(t, txnErr.Err, "one message in bundle failed")
+	require.Len(t, txnErr.Messages, 1)
+	assert.Equal(t, 2, txnErr.Messages[0].Index)
+	assert.Equal(t, uint64(3), txnErr.Messages[0].Message.(*FlowMod).Cookie)
+	assert.Equal(t, uint16(FMFC_OVERLAP), txnErr.Messages[0].Error.Code)
+
+	// The bundles after the failing one are discarded.
+	sw = &testBundleSwitch{failedBundle: 30}
+	txn = newTestBundleSwitch(t, 30, BF_ORDERED, sw)
+	txn.MaxMessages = 1
+	txn.NextBundleID = nextBundleID(30)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1), newFlowMod(2), newFlowMod(3)))
+	err = txn.Commit()
+	require.True(t, errors.As(err, &txnErr))
+	assert.Equal(t, uint32(30), txnErr.BundleID)
+	assert.Equal(t, []uint32{31, 32}, sw.BundleIDs(BCT_DISCARD_REQUEST))
+
+	// An atomic bundle is never split.
+	txn = newTestBundleSwitch(t, 40, BF_ATOMIC, &testBundleSwitch{})
+	txn.MaxMessages = 1
+	txn.NextBundleID = nextBundleID(40)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(newFlowMod(1)))
+	assert.Error(t, txn.Add(newFlowMod(2)))
+	require.NoError(t, txn.Discard())
+
+	// The control requests time out without replies.
+	txn = NewBundleTransaction(func(msg util.Message) error { return nil }, 50, 0)
+	txn.Timeout = 10 * time.Millisecond
+	assert.Error(t, txn.Open())
+}
+
+func TestBundleTransactionCommitAt(t *testing.T) {
+	flowMod := NewFlowMod()
+	flowMod.Cookie = 1
+
+	txn := newTestBundleSwitch(t, 10, BF_ATOMIC|BF_ORDERED, &testBundleSwitch{})
+	require.NoError(t, txn.Open())
+	assert.Error(t, txn.CommitAt(time.Now().Add(30*time.Second)), "The bundle must be opened with BF_TIME")
+	require.NoError(t, txn.Discard())
+
+	flags := uint16(BF_ATOMIC | BF_ORDERED | BF_TIME)
+	txn = newTestBundleSwitch(t, 20, flags, &testBundleSwitch{})
+	require.NoError(t, txn.Open())
+	assert.Error(t, txn.Commit(), "The bundle opened with BF_TIME must be committed at a time")
+	require.NoError(t, txn.Discard())
+
+	// The bundles are scheduled within the range of the switch.
+	sw := &testBundleSwitch{}
+	txn = newTestBundleSwitch(t, 30, flags, sw)
+	require.NoError(t, txn.Open())
+	require.NoError(t, txn.Add(flowMod))
+	assert.Error(t, txn.CommitAt(time.Now().Add(time.Hour)))
+	assert.Error(t, txn.CommitAt(time.Now().Add(-time.Minute)))
+	schedTime := time.Unix(time.Now().Add(30*time.Second).Unix(), 0)
+	require.NoError(t, txn.CommitAt(schedTime))
+
+	// All the messages of the transaction are OpenFlow 1.5 bundle messages with the same flags.
+	requests := sw.requests
+	require.Len(t, requests, 6)
+	open := requests[0].(*BundleCtrl)
+	assert.Equal(t, uint16(BCT_OPEN_REQUEST), open.Type)
+	assert.Equal(t, uint32(30), open.BundleId)
+	assert.Empty(t, open.Properties)
+	add := requests[1].(*BndleAdd)
+	assert.Equal(t, uint32(30), add.BundleId)
+	assert.Equal(t, flags, add.Flags)
+	assert.Equal(t, flowMod.Xid, add.Xid)
+	assert.Equal(t, flowMod.Cookie, add.Message.(*FlowMod).Cookie)
+	for _, req := range requests[2:5] {
+		assert.IsType(t, &MultipartRequest{}, req)
+	}
+	commit := requests[5].(*BundleCtrl)
+	assert.Equal(t, uint16(BCT_COMMIT_REQUEST), commit.Type)
+	assert.Equal(t, uint32(30), commit.BundleId)
+	assert.Equal(t, flags, commit.Flags)
+	require.Len(t, commit.Properties, 1)
+	assert.Equal(t, schedTime, commit.Properties[0].(*BundlePropTime).SchedTime.Time())
+}
@@ -0,0 +1,89 @@
+package openflow15
+
+import (
+	"antrea.io/libOpenflow/util"
+)
+
+// ExperimenterActionDecoder returns a new Action to unmarshal the experimenter action encoded in data. The data
+// starts with the action header, including the experimenter ID. DecodeNxAction is the decoder for Nicira actions.
+type ExperimenterActionDecoder = util.ExperimenterActionDecoder[Action]
+
+// ExperimenterMatchFieldDecoder decodes the value or the mask of an OXM experimenter match field. It has the same
+// signature as DecodeMatchField, the data starts after the experimenter ID.
+type ExperimenterMatchFieldDecoder = util.ExperimenterMatchFieldDecoder
+
+// ExperimenterMessageDecoder decodes the body of an experimenter message, which follows the experimenter ID and
+// the experimenter type in VendorHeader.
+type ExperimenterMessageDecoder = util.ExperimenterMessageDecoder
+
+// ExperimenterMultipartDecoder decodes the body of an OFPMP_EXPERIMENTER multipart request or reply. The data starts
+// with the experimenter ID and the experimenter type, and the length of the returned message must include them.
+type ExperimenterMultipartDecoder = util.ExperimenterMultipartDecoder
+
+// ExperimenterPropertyFactory returns a new property to unmarshal an experimenter property with the given property
+// type and experimenter type. It returns nil to decode the property with the generic experimenter property.
+type ExperimenterPropertyFactory = util.ExperimenterPropertyFactory
+
+// experimenters stores the decoders of the experimenter extensions, indexed by experimenter ID.
+var experimenters = util.NewExperimenterRegistry[Action]()
+
+func init() {
+	RegisterExperimenterAction(NxExperimenterID, DecodeNxAction)
+	RegisterExperimenterMatchField(ONF_EXPERIMENTER_ID, DecodeMatchField)
+	RegisterExperimenterMatchField(NXOXM_NSH_EXPERIMENTER_ID, decodeNSHMatchField)
+	RegisterExperimenterMessage(NxExperimenterID, decodeVendorData)
+	RegisterExperimenterMessage(ONF_EXPERIMENTER_ID, decodeVendorData)
+	RegisterExperimenterMultipart(NxExperimenterID, decodeNXStatsRequest, decodeNXStatsReply)
+}
+
+// RegisterExperimenterAction registers the decoder used by DecodeAction for the actions of the experimenter. It
+// replaces the decoder registered before for the same experimenter, including the built-in one for Nicira actions,
+// a nil decoder removes the registration.
+func RegisterExperimenterAction(experimenterID uint32, decoder ExperimenterActionDecoder) {
+	experimenters.RegisterAction(experimenterID, decoder)
+}
+
+// RegisterExperimenterMatchField registers the decoder used by MatchField for the OXM fields of the experimenter
+// class with the experimenter ID. A nil decoder removes the registration.
+func RegisterExperimenterMatchField(experimenterID uint32, decoder ExperimenterMatchFieldDecoder) {
+	experimenters.RegisterMatchField(experimenterID, decoder)
+}
+
+// RegisterExperimenterMessage registers the decoder used by Parse for the body of the experimenter messages with
+// the experimenter ID. A nil decoder removes the registration.
+func RegisterExperimenterMessage(experimenterID uint32, decoder ExperimenterMessageDecoder) {
+	experimenters.RegisterMessage(experimenterID, decoder)
+}
+
+// RegisterExperimenterMultipart registers the decoders of the OFPMP_EXPERIMENTER multipart request and reply
+// bodies with the experimenter ID, including the built-in ones for the Nicira extended stats. A nil decoder removes
+// the registration.
+func RegisterExperimenterMultipart(experimenterID uint32, request, reply ExperimenterMultipartDecoder) {
+	experimenters.RegisterMultipart(experimenterID, request, reply)
+}
+
+// RegisterExperimenterProperty registers the factory of the experimenter properties with the experimenter ID. A nil
+// factory removes the registration.
+func RegisterExperimenterProperty(experimenterID uint32, factory ExperimenterPropertyFactory) {
+	experimenters.RegisterProperty(experimenterID, factory)
+}
+
+func decodeExperimenterAction(experimenterID uint32, data []byte) (Action, error) {
+	return experimenters.DecodeAction(experimenterID, data)
+}
+
+func experimenterMatchFieldDecoder(experimenterID uint32) ExperimenterMatchFieldDecoder {
+	return experimenters.MatchFieldDecoder(experimenterID)
+}
+
+func decodeExperimenterMessage(experimenterID uint32, expType uint32, data []byte) (util.Message, error) {
+	return experimenters.DecodeMessage(experimenterID, expType, data)
+}
+
+func decodeExperimenterMultipart(data []byte, isReply bool) (util.Message, error) {
+	return experimenters.DecodeMultipart(data, isReply)
+}
+
+func newExperimenterProp(data []byte, defaultProp util.Message) util.Message {
+	return experimenters.NewProperty(data, defaultProp)
+}
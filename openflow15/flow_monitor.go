@@ -0,0 +1,422 @@
+package openflow15
+
+import (
+	"errors"
+	"sync"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// FlowEventType is the type of the FlowEvent delivered to a FlowMonitorSubscription.
+type FlowEventType int
+
+const (
+	// FlowEventInitial reports a flow matching the subscription when the monitor is added or re-synced.
+	FlowEventInitial FlowEventType = iota
+	FlowEventAdded
+	FlowEventModified
+	FlowEventRemoved
+	// FlowEventAbbrev reports a change made by this connection without FMF_NO_ABBREV, Xid is the request of the
+	// change.
+	FlowEventAbbrev
+	// FlowEventPaused reports the switch stopped sending updates because it ran out of buffer space.
+	FlowEventPaused
+	// FlowEventResumed reports the switch resumed sending updates. The subscription is re-synced with a flow dump,
+	// a FlowEventInitial is delivered for each matching flow, then a FlowEventSynced.
+	FlowEventResumed
+	// FlowEventSynced reports all the initial flows of the subscription have been delivered.
+	FlowEventSynced
+)
+
+// FlowEvent is a flow update delivered to a FlowMonitorSubscription.
+type FlowEvent struct {
+	Type         FlowEventType
+	TableID      uint8
+	Reason       uint8
+	Priority     uint16
+	IdleTimeout  uint16
+	HardTimeout  uint16
+	Cookie       uint64
+	Match        Match
+	Instructions []Instruction
+	Xid          uint32
+}
+
+// FlowMonitorFilter selects the flows of a FlowMonitorSubscription. TableID OFPTT_ALL, OutPort P_ANY and OutGroup
+// OFPG_ANY match all flows, the flows must match all the Fields with the same or a more specific mask.
+type FlowMonitorFilter struct {
+	TableID  uint8
+	OutPort  uint32
+	OutGroup uint32
+	Fields   []MatchField
+}
+
+// NewFlowMonitorFilter returns a filter matching all flows.
+func NewFlowMonitorFilter() *FlowMonitorFilter {
+	return &FlowMonitorFilter{
+		TableID:  OFPTT_ALL,
+		OutPort:  P_ANY,
+		OutGroup: OFPG_ANY,
+	}
+}
+
+// FlowMonitor manages the flow monitors of an OpenFlow connection. The messages are sent with the send function,
+// and the multipart replies received on the connection must be passed to HandleMessage.
+type FlowMonitor struct {
+	send func(msg util.Message) error
+
+	mutex  sync.Mutex
+	nextID uint32
+	subs   map[uint32]*FlowMonitorSubscription
+	// syncs stores the subscriptions waiting for the initial flows, indexed by the xid of the request.
+	syncs map[uint32]*FlowMonitorSubscription
+}
+
+// FlowMonitorSubscription is a flow monitor added to the switch.
+type FlowMonitorSubscription struct {
+	monitor *FlowMonitor
+	id      uint32
+	filter  FlowMonitorFilter
+	flags   uint16
+
+	events     chan *FlowEvent
+	done       chan struct{}
+	eventMutex sync.Mutex
+	closed     bool
+}
+
+// NewFlowMonitor creates a FlowMonitor sending the OpenFlow messages with send.
+func NewFlowMonitor(send func(msg util.Message) error) *FlowMonitor {
+	return &FlowMonitor{
+		send:  send,
+		subs:  make(map[uint32]*FlowMonitorSubscription),
+		syncs: make(map[uint32]*FlowMonitorSubscription),
+	}
+}
+
+// Subscribe adds a flow monitor with the filter and the FMF_* flags, and returns the subscription delivering the
+// events in a channel with bufferSize. With FMF_INITIAL, the current flows are delivered as FlowEventInitial events
+// followed by a FlowEventSynced.
+func (m *FlowMonitor) Subscribe(filter *FlowMonitorFilter, flags uint16, bufferSize int) (*FlowMonitorSubscription, error) {
+	m.mutex.Lock()
+	m.nextID++
+	sub := &FlowMonitorSubscription{
+		monitor: m,
+		id:      m.nextID,
+		filter:  *filter,
+		flags:   flags,
+		events:  make(chan *FlowEvent, bufferSize),
+		done:    make(chan struct{}),
+	}
+	m.subs[sub.id] = sub
+	m.mutex.Unlock()
+
+	if err := m.sendRequest(sub, FMC_ADD); err != nil {
+		m.mutex.Lock()
+		delete(m.subs, sub.id)
+		m.mutex.Unlock()
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ID returns the monitor ID of the subscription.
+func (s *FlowMonitorSubscription) ID() uint32 {
+	return s.id
+}
+
+// Events returns the channel of the events, which is closed when the subscription is cancelled.
+func (s *FlowMonitorSubscription) Events() <-chan *FlowEvent {
+	return s.events
+}
+
+// Modify replaces the filter and the flags of the flow monitor. With FMF_INITIAL, the flows matching the new filter
+// are delivered as FlowEventInitial events followed by a FlowEventSynced.
+func (s *FlowMonitorSubscription) Modify(filter *FlowMonitorFilter, flags uint16) error {
+	m := s.monitor
+	m.mutex.Lock()
+	if _, ok := m.subs[s.id]; !ok {
+		m.mutex.Unlock()
+		return errors.New("the flow monitor is cancelled")
+	}
+	s.filter = *filter
+	s.flags = flags
+	m.mutex.Unlock()
+	return m.sendRequest(s, FMC_MODIFY)
+}
+
+// Cancel deletes the flow monitor from the switch and closes the channel of the events.
+func (s *FlowMonitorSubscription) Cancel() error {
+	m := s.monitor
+	m.mutex.Lock()
+	if _, ok := m.subs[s.id]; !ok {
+		m.mutex.Unlock()
+		return nil
+	}
+	delete(m.subs, s.id)
+	for xid, sub := range m.syncs {
+		if sub == s {
+			delete(m.syncs, xid)
+		}
+	}
+	m.mutex.Unlock()
+
+	close(s.done)
+	s.eventMutex.Lock()
+	s.closed = true
+	close(s.events)
+	s.eventMutex.Unlock()
+	return m.sendRequest(s, FMC_DELETE)
+}
+
+func (m *FlowMonitor) sendRequest(sub *FlowMonitorSubscription, command uint8) error {
+	m.mutex.Lock()
+	req := NewFlowMonitorRequest(sub.id)
+	req.Command = command
+	req.TableId = sub.filter.TableID
+	req.OutPort = sub.filter.OutPort
+	req.OutGroup = sub.filter.OutGroup
+	req.Flags = sub.flags
+	for _, field := range sub.filter.Fields {
+		req.Match.AddField(field)
+	}
+	mp := NewMpRequest(MultipartType_FlowMonitor)
+	mp.Body = append(mp.Body, req)
+	if command != FMC_DELETE && sub.flags&FMF_INITIAL != 0 {
+		m.syncs[mp.Header.Xid] = sub
+	}
+	m.mutex.Unlock()
+
+	if err := m.send(mp); err != nil {
+		m.mutex.Lock()
+		delete(m.syncs, mp.Header.Xid)
+		m.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// resync dumps the flows matching the subscription to deliver them as FlowEventInitial events.
+func (m *FlowMonitor) resync(sub *FlowMonitorSubscription) error {
+	m.mutex.Lock()
+	req := NewFlowStatsRequest()
+	req.TableId = sub.filter.TableID
+	req.OutPort = sub.filter.OutPort
+	req.OutGroup = sub.filter.OutGroup
+	for _, field := range sub.filter.Fields {
+		req.Match.AddField(field)
+	}
+	mp := NewMpRequest(MultipartType_FlowDesc)
+	mp.Body = append(mp.Body, req)
+	m.syncs[mp.Header.Xid] = sub
+	m.mutex.Unlock()
+
+	if err := m.send(mp); err != nil {
+		m.mutex.Lock()
+		delete(m.syncs, mp.Header.Xid)
+		m.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// HandleMessage delivers the flow updates and the re-sync flow dumps in msg to the subscriptions, and returns false
+// if msg is not a message of the FlowMonitor. It blocks until the events are received or the subscriptions are
+// cancelled, so the channels should be drained continuously.
+func (m *FlowMonitor) HandleMessage(msg util.Message) (bool, error) {
+	reply, ok := msg.(*MultipartReply)
+	if !ok {
+		return false, nil
+	}
+	m.mutex.Lock()
+	syncSub, syncing := m.syncs[reply.Xid]
+	if syncing && reply.Flags&OFPMPF_REPLY_MORE == 0 {
+		delete(m.syncs, reply.Xid)
+	}
+	subs := make([]*FlowMonitorSubscription, 0, len(m.subs))
+	for _, sub := range m.subs {
+		subs = append(subs, sub)
+	}
+	m.mutex.Unlock()
+
+	switch reply.Type {
+	case MultipartType_FlowMonitor:
+	case MultipartType_FlowDesc:
+		if !syncing {
+			return false, nil
+		}
+		for _, body := range reply.Body {
+			if f, ok := body.(*FlowDesc); ok {
+				syncSub.deliver(&FlowEvent{
+					Type:         FlowEventInitial,
+					TableID:      f.TableId,
+					Priority:     f.Priority,
+					IdleTimeout:  f.IdleTimeout,
+					HardTimeout:  f.HardTimeout,
+					Cookie:       f.Cookie,
+					Match:        f.Match,
+					Instructions: f.Instructions,
+				})
+			}
+		}
+		if reply.Flags&OFPMPF_REPLY_MORE == 0 {
+			syncSub.deliver(&FlowEvent{Type: FlowEventSynced})
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+
+	var err error
+	for _, body := range reply.Body {
+		switch update := body.(type) {
+		case *FlowUpdateFull:
+			event := &FlowEvent{
+				TableID:      update.TableId,
+				Reason:       update.Reason,
+				Priority:     update.Priority,
+				IdleTimeout:  update.IdleTimeout,
+				HardTimeout:  update.HardTimeout,
+				Cookie:       update.Cookie,
+				Match:        update.Match,
+				Instructions: update.Instructions,
+			}
+			switch update.Event {
+			case FME_INITIAL:
+				event.Type = FlowEventInitial
+			case FME_ADDED:
+				event.Type = FlowEventAdded
+			case FME_MODIFIED:
+				event.Type = FlowEventModified
+			case FME_REMOVED:
+				event.Type = FlowEventRemoved
+			}
+			// The initial flows are replied to the request of the monitor, the other updates carry no monitor ID
+			// and are delivered to all the subscriptions whose filter covers the flow.
+			if syncing {
+				syncSub.deliver(event)
+				continue
+			}
+			for _, sub := range subs {
+				if sub.covers(update) {
+					sub.deliver(event)
+				}
+			}
+		case *FlowUpdateAbbrev:
+			for _, sub := range subs {
+				sub.deliver(&FlowEvent{Type: FlowEventAbbrev, Xid: update.Xid})
+			}
+		case *FlowUpdatePaused:
+			eventType := FlowEventPaused
+			if update.Event == FME_RESUMED {
+				eventType = FlowEventResumed
+			}
+			for _, sub := range subs {
+				sub.deliver(&FlowEvent{Type: eventType})
+				if eventType == FlowEventResumed {
+					if resyncErr := m.resync(sub); resyncErr != nil && err == nil {
+						err = resyncErr
+					}
+				}
+			}
+		}
+	}
+	if syncing && reply.Flags&OFPMPF_REPLY_MORE == 0 {
+		syncSub.deliver(&FlowEvent{Type: FlowEventSynced})
+	}
+	return true, err
+}
+
+func (s *FlowMonitorSubscription) deliver(event *FlowEvent) {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	case <-s.done:
+	}
+}
+
+// covers returns whether the flow update matches the filter of the subscription.
+func (s *FlowMonitorSubscription) covers(update *FlowUpdateFull) bool {
+	s.monitor.mutex.Lock()
+	filter := s.filter
+	s.monitor.mutex.Unlock()
+	if filter.TableID != OFPTT_ALL && filter.TableID != update.TableId {
+		return false
+	}
+	// The instructions are absent without FMF_INSTRUCTIONS, the switch has checked the output of the flow then.
+	if len(update.Instructions) > 0 && !instructionsOutputTo(update.Instructions, filter.OutPort, filter.OutGroup) {
+		return false
+	}
+	for i := range filter.Fields {
+		if !matchFieldCovered(&filter.Fields[i], update.Match.Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// instructionsOutputTo returns whether the instructions output to the port and the group. P_ANY and OFPG_ANY match
+// any instructions.
+func instructionsOutputTo(instructions []Instruction, port, group uint32) bool {
+	portFound, groupFound := port == P_ANY, group == OFPG_ANY
+	for _, instr := range instructions {
+		actions, ok := instr.(*InstrActions)
+		if !ok {
+			continue
+		}
+		for _, act := range actions.Actions {
+			switch a := act.(type) {
+			case *ActionOutput:
+				portFound = portFound || a.Port == port
+			case *ActionGroup:
+				groupFound = groupFound || a.GroupId == group
+			}
+		}
+	}
+	return portFound && groupFound
+}
+
+// matchFieldCovered returns whether one of the flow fields matches the filter field with the same or a more
+// specific mask, and the same value under the mask of the filter field.
+func matchFieldCovered(filter *MatchField, fields []MatchField) bool {
+	for i := range fields {
+		field := &fields[i]
+		if field.Class != filter.Class || field.Field != filter.Field || field.ExperimenterID != filter.ExperimenterID {
+			continue
+		}
+		if field.Value == nil || filter.Value == nil {
+			return false
+		}
+		filterValue, _ := filter.Value.MarshalBinary()
+		value, _ := field.Value.MarshalBinary()
+		if len(filterValue) != len(value) {
+			return false
+		}
+		filterMask := matchFieldMask(filter, len(filterValue))
+		mask := matchFieldMask(field, len(value))
+		for j := range value {
+			if filterMask[j]&^mask[j] != 0 || (filterValue[j]^value[j])&filterMask[j] != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func matchFieldMask(field *MatchField, length int) []byte {
+	if field.HasMask && field.Mask != nil {
+		if mask, _ := field.Mask.MarshalBinary(); len(mask) == length {
+			return mask
+		}
+	}
+	mask := make([]byte, length)
+	for i := range mask {
+		mask[i] = 0xff
+	}
+	return mask
+}
@@ -0,0 +1,139 @@
+package openflow15
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
+)
+
+func newTestFlowUpdate(event uint16, tableID uint8, inPort uint32, outPort uint32) *FlowUpdateFull {
+	update := NewFlowUpdateFull(event)
+	update.TableId = tableID
+	update.Match.AddField(*NewInPortField(inPort))
+	instr := NewInstrApplyActions()
+	instr.AddAction(NewActionOutput(outPort), false)
+	update.AddInstruction(instr)
+	return update
+}
+
+func newTestMonitorReply(xid uint32, flags uint16, bodies ...util.Message) *MultipartReply {
+	reply := NewMpReply(MultipartType_FlowMonitor)
+	reply.Xid = xid
+	reply.Flags = flags
+	reply.Body = bodies
+	return reply
+}
+
+func TestFlowMonitor(t *testing.T) {
+	var sent []*MultipartRequest
+	monitor := NewFlowMonitor(func(msg util.Message) error {
+		// Send the message on the wire to check it can be decoded.
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err)
+		parsed, err := Parse(data)
+		require.NoError(t, err)
+		sent = append(sent, parsed.(*MultipartRequest))
+		return nil
+	})
+
+	filter1 := NewFlowMonitorFilter()
+	filter1.TableID = 1
+	filter1.Fields = []MatchField{*NewInPortField(10)}
+	sub1, err := monitor.Subscribe(filter1, FMF_INITIAL|FMF_ADD|FMF_REMOVED|FMF_MODIFY|FMF_INSTRUCTIONS, 10)
+	require.NoError(t, err)
+	filter2 := NewFlowMonitorFilter()
+	filter2.OutPort = 20
+	sub2, err := monitor.Subscribe(filter2, FMF_ADD|FMF_INSTRUCTIONS, 10)
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	req := sent[0].Body[0].(*FlowMonitorRequest)
+	assert.Equal(t, sub1.ID(), req.MonitorId)
+	assert.Equal(t, uint8(FMC_ADD), req.Command)
+	assert.Equal(t, uint8(1), req.TableId)
+	assert.Equal(t, uint32(P_ANY), req.OutPort)
+	require.Len(t, req.Match.Fields, 1)
+	assert.Equal(t, sub2.ID(), sent[1].Body[0].(*FlowMonitorRequest).MonitorId)
+
+	// The initial flows are delivered to the subscription of the request only.
+	handled, err := monitor.HandleMessage(newTestMonitorReply(sent[0].Xid, 0, newTestFlowUpdate(FME_INITIAL, 1, 10, 20)))
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, FlowEventInitial, (<-sub1.Events()).Type)
+	assert.Equal(t, FlowEventSynced, (<-sub1.Events()).Type)
+	assert.Len(t, sub2.Events(), 0)
+
+	// The updates are delivered to the subscriptions covering the flow.
+	_, err = monitor.HandleMessage(newTestMonitorReply(0, 0,
+		newTestFlowUpdate(FME_ADDED, 1, 10, 20),
+		newTestFlowUpdate(FME_MODIFIED, 2, 10, 30),
+		newTestFlowUpdate(FME_REMOVED, 1, 11, 30),
+	))
+	require.NoError(t, err)
+	event := <-sub1.Events()
+	assert.Equal(t, FlowEventAdded, event.Type)
+	assert.Equal(t, uint8(1), event.TableID)
+	assert.Equal(t, FlowEventAdded, (<-sub2.Events()).Type)
+	assert.Len(t, sub1.Events(), 0)
+	assert.Len(t, sub2.Events(), 0)
+
+	// The subscriptions are re-synced with a flow dump when the monitoring resumes.
+	sent = nil
+	_, err = monitor.HandleMessage(newTestMonitorReply(0, 0, NewFlowUpdatePaused(FME_PAUSED)))
+	require.NoError(t, err)
+	_, err = monitor.HandleMessage(newTestMonitorReply(0, 0, NewFlowUpdatePaused(FME_RESUMED)))
+	require.NoError(t, err)
+	assert.Equal(t, FlowEventPaused, (<-sub1.Events()).Type)
+	assert.Equal(t, FlowEventResumed, (<-sub1.Events()).Type)
+	require.Len(t, sent, 2)
+	var dump *MultipartRequest
+	for _, req := range sent {
+		assert.Equal(t, uint16(MultipartType_FlowDesc), req.Type)
+		if req.Body[0].(*FlowStatsRequest).TableId == 1 {
+			dump = req
+		}
+	}
+	require.NotNil(t, dump)
+	flow := NewFlowDesc()
+	flow.TableId = 1
+	flow.Priority = 100
+	descReply := NewMpReply(MultipartType_FlowDesc)
+	descReply.Xid = dump.Xid
+	descReply.Flags = OFPMPF_REPLY_MORE
+	descReply.Body = []util.Message{flow}
+	handled, err = monitor.HandleMessage(descReply)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	descReply.Flags = 0
+	descReply.Body = nil
+	_, err = monitor.HandleMessage(descReply)
+	require.NoError(t, err)
+	event = <-sub1.Events()
+	assert.Equal(t, FlowEventInitial, event.Type)
+	assert.Equal(t, uint16(100), event.Priority)
+	assert.Equal(t, FlowEventSynced, (<-sub1.Events()).Type)
+
+	// The modified filter applies to the following updates.
+	sent = nil
+	filter1.TableID = OFPTT_ALL
+	require.NoError(t, sub1.Modify(filter1, FMF_ADD|FMF_MODIFY))
+	require.Len(t, sent, 1)
+	assert.Equal(t, uint8(FMC_MODIFY), sent[0].Body[0].(*FlowMonitorRequest).Command)
+	_, err = monitor.HandleMessage(newTestMonitorReply(0, 0, newTestFlowUpdate(FME_MODIFIED, 2, 10, 30)))
+	require.NoError(t, err)
+	assert.Equal(t, FlowEventModified, (<-sub1.Events()).Type)
+
+	sent = nil
+	require.NoError(t, sub1.Cancel())
+	require.Len(t, sent, 1)
+	assert.Equal(t, uint8(FMC_DELETE), sent[0].Body[0].(*FlowMonitorRequest).Command)
+	_, ok := <-sub1.Events()
+	assert.False(t, ok)
+	assert.Error(t, sub1.Modify(filter1, 0))
+
+	handled, err = monitor.HandleMessage(NewEchoRequest())
+	require.NoError(t, err)
+	assert.False(t, handled)
+}
@@ -177,7 +177,7 @@ func (g *GroupMod) UnmarshalBinary(data []byte) (err error) {
 		var p util.Message
 		switch binary.BigEndian.Uint16(data[n:]) {
 		case GPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -312,7 +312,7 @@ func (b *Bucket) UnmarshalBinary(data []byte) (err error) {
 		case GBPT_WATCH_GROUP:
 			p = new(GroupBucketPropWatchGroup)
 		case GBPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -138,6 +138,11 @@ func (m *MatchField) MarshalBinary() (data []byte, err error) {
 	data[n] = m.Length
 	n += 1
 
+	if m.Class == OXM_CLASS_EXPERIMENTER && m.ExperimenterID != 0 {
+		binary.BigEndian.PutUint32(data[n:], m.ExperimenterID)
+		n += 4
+	}
+
 	b, err := m.Value.MarshalBinary()
 	if err != nil {
 		return
@@ -174,24 +179,24 @@ func (m *MatchField) UnmarshalBinary(data []byte) error {
 	m.Length = data[n]
 	n += 1
 
+	decode := DecodeMatchField
 	if m.Class == OXM_CLASS_EXPERIMENTER {
 		experimenterID := binary.BigEndian.Uint32(data[n:])
-		if experimenterID == ONF_EXPERIMENTER_ID {
-			n += 4
-			m.ExperimenterID = experimenterID
-		} else {
+		if decode = experimenterMatchFieldDecoder(experimenterID); decode == nil {
 			return fmt.Errorf("Unsupported experimenter id: %d in class: %d ", experimenterID, m.Class)
 		}
+		n += 4
+		m.ExperimenterID = experimenterID
 	}
 
-	if m.Value, err = DecodeMatchField(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
+	if m.Value, err = decode(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
 		klog.ErrorS(err, "Failed to decode MatchField", "data", data[n:])
 		return err
 	}
 	n += m.Value.Len()
 
 	if m.HasMask {
-		if m.Mask, err = DecodeMatchField(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
+		if m.Mask, err = decode(m.Class, m.Field, m.Length, m.HasMask, data[n:]); err != nil {
 			klog.ErrorS(err, "Failed to decode MatchField mask", "data", data[n:])
 			return err
 		}
@@ -312,12 +317,11 @@ func (o *OxmId) UnmarshalBinary(data []byte) error {
 
 	if o.Class == OXM_CLASS_EXPERIMENTER {
 		experimenterID := binary.BigEndian.Uint32(data[n:])
-		if experimenterID == ONF_EXPERIMENTER_ID {
-			n += 4
-			o.ExperimenterID = experimenterID
-		} else {
+		if experimenterMatchFieldDecoder(experimenterID) == nil {
 			return fmt.Errorf("Unsupported experimenter id: %d in class: %d ", experimenterID, o.Class)
 		}
+		n += 4
+		o.ExperimenterID = experimenterID
 	}
 
 	return err
@@ -412,6 +416,8 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			val = new(TcpFlagsField)
 		case OXM_FIELD_ACTSET_OUTPUT:
 			val = new(ActsetOutputField)
+		case OXM_FIELD_PACKET_TYPE:
+			val = new(PacketTypeField)
 		default:
 			err := fmt.Errorf("unhandled Field: %d in Class: %d", field, class)
 			klog.ErrorS(err, "Received bad pkt class", "data", data)
@@ -492,11 +498,15 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			val = new(Uint32Message)
 		case NXM_NX_TCP_FLAGS:
 		case NXM_NX_DP_HASH:
+			val = new(Uint32Message)
 		case NXM_NX_RECIRC_ID:
+			val = new(Uint32Message)
 		case NXM_NX_CONJ_ID:
 			val = new(Uint32Message)
 		case NXM_NX_TUN_GBP_ID:
+			val = new(Uint16Message)
 		case NXM_NX_TUN_GBP_FLAGS:
+			val = new(Uint8Message)
 		case NXM_NX_TUN_METADATA0:
 			fallthrough
 		case NXM_NX_TUN_METADATA1:
@@ -520,6 +530,7 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 			}
 			val = msg
 		case NXM_NX_TUN_FLAGS:
+			val = new(Uint16Message)
 		case NXM_NX_CT_STATE:
 			val = new(Uint32Message)
 		case NXM_NX_CT_ZONE:
@@ -553,13 +564,7 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 		case NXM_NX_XXREG2:
 			fallthrough
 		case NXM_NX_XXREG3:
-			msg := new(ByteArrayField)
-			if !hasMask {
-				msg.Length = length
-			} else {
-				msg.Length = length / 2
-			}
-			val = msg
+			val = new(Uint128Message)
 		default:
 			err := fmt.Errorf("unknown field for nxm_1: %v", field)
 			klog.ErrorS(err, "Received invalid field", "data", data)
@@ -590,13 +595,7 @@ func DecodeMatchField(class uint16, field uint8, length uint8, hasMask bool, dat
 		case OXM_PACKET_REG6:
 			fallthrough
 		case OXM_PACKET_REG7:
-			msg := new(ByteArrayField)
-			if !hasMask {
-				msg.Length = length
-			} else {
-				msg.Length = length / 2
-			}
-			val = msg
+			val = new(Uint64Message)
 		default:
 			err := fmt.Errorf("unknown field for packet_regs: %v", field)
 			klog.ErrorS(err, "Received invalid field", "data", data)
@@ -2211,3 +2210,14 @@ func NewPacketTypeField(namespace uint16, nsType uint16) *MatchField {
 
 	return f
 }
+
+// PacketType returns the packet type encoded as a uint32 number, e.g., PT_NSH.
+func (f *PacketTypeField) PacketType() uint32 {
+	return uint32(f.Namespace)<<16 | uint32(f.NsType)
+}
+
+// NewPacketTypeMatchField creates a PACKET_TYPE MatchField from a packet type encoded as a uint32
+// number, e.g., PT_NSH.
+func NewPacketTypeMatchField(packetType uint32) *MatchField {
+	return NewPacketTypeField(uint16(packetType>>16), uint16(packetType))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -129,6 +130,14 @@ func (s *MultipartRequest) UnmarshalBinary(data []byte) error {
 			// The request body is ofp_bundle_features_request.
 			req = new(BundleFeaturesRequest)
 		case MultipartType_Experimenter:
+			// The request body is decoded by the decoder registered for the experimenter.
+			if req, err = decodeExperimenterMultipart(data[n:s.Header.Length], false); err != nil {
+				klog.ErrorS(err, "Failed to decode MultipartRequest's experimenter Body", "data", data[n:])
+				return err
+			}
+			n += req.Len()
+			s.Body = append(s.Body, req)
+			continue
 		}
 
 		if req != nil {
@@ -279,17 +288,24 @@ func (s *MultipartReply) UnmarshalBinary(data []byte) error {
 			// The reply body is struct ofp_bundle_features.
 			repl = NewBundleFeatures()
 		case MultipartType_Experimenter:
-			break
+			// The reply body is decoded by the decoder registered for the experimenter.
+			if repl, err = decodeExperimenterMultipart(data[n:s.Header.Length], true); err != nil {
+				klog.ErrorS(err, "Failed to decode MultipartReply's experimenter Body", "data", data[n:])
+				return err
+			}
+			n += repl.Len()
+			req = append(req, repl)
+			continue
 		}
 
+		if repl == nil {
+			return fmt.Errorf("reply structure is nil in MultipartReply UnmarshalBinary")
+		}
 		err = repl.UnmarshalBinary(data[n:])
 		if err != nil {
 			klog.ErrorS(err, "Failed to unmarshal MultipartReply's Body", "data", data[n:])
 			return err
 		}
-		if repl == nil {
-			return fmt.Errorf("reply structure is nil in MultipartReply UnmarshalBinary")
-		}
 		n += repl.Len()
 		req = append(req, repl)
 	}
@@ -1002,7 +1018,7 @@ func (s *PortStats) UnmarshalBinary(data []byte) (err error) {
 		case PSPT_OPTICAL:
 			p = new(PortStatsPropOptical)
 		case PSPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -1330,7 +1346,7 @@ func (s *QueueStats) UnmarshalBinary(data []byte) (err error) {
 		var p util.Message
 		switch binary.BigEndian.Uint16(data[n:]) {
 		case QSPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -2039,7 +2055,7 @@ func (f *TableFeatures) UnmarshalBinary(data []byte) error {
 		case TFPT_EXPERIMENTER:
 			fallthrough
 		case TFPT_EXPERIMENTER_MISS:
-			p = new(TableExperimenterProperty)
+			p = newExperimenterProp(data[n:], new(TableExperimenterProperty))
 		}
 		err := p.UnmarshalBinary(data[n:])
 		if err != nil {
@@ -2472,7 +2488,7 @@ func (g *GroupDesc) UnmarshalBinary(data []byte) (err error) {
 		var p util.Message
 		switch binary.BigEndian.Uint16(data[n:]) {
 		case GPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -2962,7 +2978,7 @@ func (q *QueueDesc) UnmarshalBinary(data []byte) (err error) {
 		case QDPT_MAX_RATE:
 			p = new(QueueDescPropMaxRate)
 		case QDPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -3066,6 +3082,7 @@ const (
 
 func NewFlowMonitorRequest(id uint32) *FlowMonitorRequest {
 	n := new(FlowMonitorRequest)
+	n.MonitorId = id
 	n.Match = *NewMatch()
 	return n
 }
@@ -3436,7 +3453,7 @@ func (b *BundleFeaturesRequest) UnmarshalBinary(data []byte) (err error) {
 		case TMPBF_TIME_CAPABILITY:
 			p = new(BundleFeaturesPropTime)
 		case TMPBF_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -3570,6 +3587,23 @@ func (prop *BundleFeaturesPropTime) UnmarshalBinary(data []byte) (err error) {
 	return
 }
 
+// ValidateScheduledTime returns an error if the switch cannot commit a bundle at schedTime, which is later than
+// SchedMaxFuture or earlier than SchedMaxPast since the Timestamp of the switch. The local time is used if the
+// switch does not reply its Timestamp.
+func (prop *BundleFeaturesPropTime) ValidateScheduledTime(schedTime time.Time) error {
+	now := time.Now()
+	if prop.Timestamp.Seconds != 0 || prop.Timestamp.NanoSeconds != 0 {
+		now = prop.Timestamp.Time()
+	}
+	if future := schedTime.Sub(now); future > prop.SchedMaxFuture.Duration() {
+		return fmt.Errorf("the scheduled time is %v in the future, more than the maximum %v", future, prop.SchedMaxFuture.Duration())
+	}
+	if past := now.Sub(schedTime); past > prop.SchedMaxPast.Duration() {
+		return fmt.Errorf("the scheduled time is %v in the past, more than the maximum %v", past, prop.SchedMaxPast.Duration())
+	}
+	return nil
+}
+
 // ofp_bundle_features
 type BundleFeatures struct {
 	Capabilities uint16
@@ -3583,6 +3617,19 @@ func NewBundleFeatures() *BundleFeatures {
 	return n
 }
 
+// TimeCapability returns the time property of the switch, or nil if the switch does not support scheduled bundles.
+func (b *BundleFeatures) TimeCapability() *BundleFeaturesPropTime {
+	if b.Capabilities&BF_TIME == 0 {
+		return nil
+	}
+	for _, p := range b.Properties {
+		if prop, ok := p.(*BundleFeaturesPropTime); ok {
+			return prop
+		}
+	}
+	return nil
+}
+
 func (b *BundleFeatures) Len() uint16 {
 	var n uint16 = 8
 	for _, p := range b.Properties {
@@ -3623,7 +3670,7 @@ func (b *BundleFeatures) UnmarshalBinary(data []byte) (err error) {
 		case TMPBF_TIME_CAPABILITY:
 			p = new(BundleFeaturesPropTime)
 		case TMPBF_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -0,0 +1,459 @@
+package openflow15
+
+// This file has the Nicira extended multipart messages, which are sent in OFPMP_EXPERIMENTER multipart messages.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// Nicira extended multipart types, used as the experimenter type of the OFPMP_EXPERIMENTER multipart messages.
+const (
+	NXST_FLOW      = 0 /* The request is NXFlowStatsRequest, the reply is NXFlowStatsReply. */
+	NXST_AGGREGATE = 1 /* The request is NXFlowStatsRequest, the reply is NXAggregateStatsReply. */
+)
+
+// NXOFPP_NONE is the OpenFlow 1.0 port number used in NXFlowStatsRequest to not filter the flows by output port.
+const NXOFPP_NONE = 0xffff
+
+// NXStatsHeader is the ofp_experimenter_multipart_header with the Nicira experimenter ID, which starts the bodies of
+// the Nicira extended multipart messages.
+type NXStatsHeader struct {
+	Vendor  uint32
+	Subtype uint32
+}
+
+func NewNXStatsHeader(subtype uint32) *NXStatsHeader {
+	return &NXStatsHeader{Vendor: NxExperimenterID, Subtype: subtype}
+}
+
+func (h *NXStatsHeader) Len() uint16 {
+	return 8
+}
+
+func (h *NXStatsHeader) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, h.Len())
+	n := 0
+	binary.BigEndian.PutUint32(data[n:], h.Vendor)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], h.Subtype)
+	return
+}
+
+func (h *NXStatsHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < int(h.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXStatsHeader message")
+	}
+	n := 0
+	h.Vendor = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	h.Subtype = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// nxMatchLen returns the length of the NXM fields, and the length with the padding to 8 bytes.
+func nxMatchLen(fields []MatchField) (matchLen uint16, paddedLen uint16) {
+	for i := range fields {
+		matchLen += fields[i].Len()
+	}
+	return matchLen, (matchLen + 7) / 8 * 8
+}
+
+func marshalNXMatch(data []byte, fields []MatchField) error {
+	n := 0
+	for i := range fields {
+		b, err := fields[i].MarshalBinary()
+		if err != nil {
+			return err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return nil
+}
+
+func unmarshalNXMatch(data []byte) ([]MatchField, error) {
+	var fields []MatchField
+	n := 0
+	for n < len(data) {
+		field := new(MatchField)
+		if err := field.UnmarshalBinary(data[n:]); err != nil {
+			return nil, err
+		}
+		fields = append(fields, *field)
+		n += int(field.Len())
+	}
+	return fields, nil
+}
+
+// nx_flow_stats_request, the body of NXST_FLOW and NXST_AGGREGATE requests. The flows are matched with the NXM
+// fields in Match, which is not strict.
+type NXFlowStatsRequest struct {
+	*NXStatsHeader
+	OutPort  uint16
+	MatchLen uint16
+	TableID  uint8
+	Match    []MatchField
+}
+
+// NewNXFlowStatsRequest creates the body of the NXST_FLOW request, a TableID of OFPTT_ALL matches all tables.
+func NewNXFlowStatsRequest(tableID uint8, match []MatchField) *NXFlowStatsRequest {
+	return &NXFlowStatsRequest{
+		NXStatsHeader: NewNXStatsHeader(NXST_FLOW),
+		OutPort:       NXOFPP_NONE,
+		TableID:       tableID,
+		Match:         match,
+	}
+}
+
+// NewNXAggregateStatsRequest creates the body of the NXST_AGGREGATE request.
+func NewNXAggregateStatsRequest(tableID uint8, match []MatchField) *NXFlowStatsRequest {
+	r := NewNXFlowStatsRequest(tableID, match)
+	r.Subtype = NXST_AGGREGATE
+	return r
+}
+
+func (r *NXFlowStatsRequest) Len() uint16 {
+	_, paddedLen := nxMatchLen(r.Match)
+	return r.NXStatsHeader.Len() + 8 + paddedLen
+}
+
+func (r *NXFlowStatsRequest) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, r.Len())
+	var b []byte
+	n := 0
+
+	b, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(r.NXStatsHeader.Len())
+
+	r.MatchLen, _ = nxMatchLen(r.Match)
+	binary.BigEndian.PutUint16(data[n:], r.OutPort)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], r.MatchLen)
+	n += 2
+	data[n] = r.TableID
+	n += 1
+	n += 3 // for padding
+
+	if err = marshalNXMatch(data[n:], r.Match); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (r *NXFlowStatsRequest) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	if len(data) < int(r.NXStatsHeader.Len())+8 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowStatsRequest message")
+	}
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	r.OutPort = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	r.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	r.TableID = data[n]
+	n += 1
+	n += 3 // for padding
+
+	if len(data) < n+int(r.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowStatsRequest's Match")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(r.MatchLen)])
+	if err != nil {
+		klog.ErrorS(err, "Failed to unmarshal NXFlowStatsRequest's Match", "data", data[n:])
+		return err
+	}
+	r.Match = match
+	return nil
+}
+
+// nx_flow_stats, an entry in the NXST_FLOW reply. IdleAge and HardAge are the seconds since the last packet and
+// the last modification of the flow plus one, or 0 if they are unknown. The flow importance is not included in
+// NXST_FLOW, it is only reported by the OpenFlow 1.4+ flow stats.
+type NXFlowStats struct {
+	Length       uint16
+	TableID      uint8
+	DurationSec  uint32
+	DurationNSec uint32
+	Priority     uint16
+	IdleTimeout  uint16
+	HardTimeout  uint16
+	MatchLen     uint16
+	IdleAge      uint16
+	HardAge      uint16
+	Cookie       uint64
+	PacketCount  uint64
+	ByteCount    uint64
+	Match        []MatchField
+	Actions      []Action
+}
+
+func (s *NXFlowStats) Len() (n uint16) {
+	_, n = nxMatchLen(s.Match)
+	n += 48
+	for _, a := range s.Actions {
+		n += a.Len()
+	}
+	return
+}
+
+func (s *NXFlowStats) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, s.Len())
+	n := 0
+
+	s.Length = s.Len()
+	s.MatchLen, _ = nxMatchLen(s.Match)
+	binary.BigEndian.PutUint16(data[n:], s.Length)
+	n += 2
+	data[n] = s.TableID
+	n += 1
+	n += 1 // for padding
+	binary.BigEndian.PutUint32(data[n:], s.DurationSec)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], s.DurationNSec)
+	n += 4
+	binary.BigEndian.PutUint16(data[n:], s.Priority)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.IdleTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.HardTimeout)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.MatchLen)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.IdleAge)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.HardAge)
+	n += 2
+	binary.BigEndian.PutUint64(data[n:], s.Cookie)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], s.ByteCount)
+	n += 8
+
+	if err = marshalNXMatch(data[n:], s.Match); err != nil {
+		return nil, err
+	}
+	n += int((s.MatchLen + 7) / 8 * 8)
+
+	for _, a := range s.Actions {
+		b, err := a.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (s *NXFlowStats) UnmarshalBinary(data []byte) error {
+	if len(data) < 48 {
+		return errors.New("the []byte is too short to unmarshal a full NXFlowStats message")
+	}
+	n := 0
+	s.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.TableID = data[n]
+	n += 1
+	n += 1 // for padding
+	s.DurationSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.DurationNSec = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	s.Priority = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.IdleTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.HardTimeout = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.MatchLen = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.IdleAge = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.HardAge = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	s.Cookie = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	s.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+
+	if len(data) < int(s.Length) || int(s.Length) < n+int(s.MatchLen) {
+		return errors.New("the []byte is too short to unmarshal NXFlowStats's Match and Actions")
+	}
+	match, err := unmarshalNXMatch(data[n : n+int(s.MatchLen)])
+	if err != nil {
+		klog.ErrorS(err, "Failed to unmarshal NXFlowStats's Match", "data", data[n:])
+		return err
+	}
+	s.Match = match
+	n += int((s.MatchLen + 7) / 8 * 8)
+
+	for n < int(s.Length) {
+		a, err := DecodeAction(data[n:s.Length])
+		if err != nil {
+			klog.ErrorS(err, "Failed to unmarshal NXFlowStats's Actions", "data", data[n:])
+			return err
+		}
+		s.Actions = append(s.Actions, a)
+		n += int(a.Len())
+	}
+	return nil
+}
+
+// NXFlowStatsReply is the body of the NXST_FLOW reply, which has the stats of all the flows in the multipart
+// message.
+type NXFlowStatsReply struct {
+	*NXStatsHeader
+	Stats []*NXFlowStats
+}
+
+func NewNXFlowStatsReply() *NXFlowStatsReply {
+	return &NXFlowStatsReply{NXStatsHeader: NewNXStatsHeader(NXST_FLOW)}
+}
+
+func (r *NXFlowStatsReply) Len() (n uint16) {
+	n = r.NXStatsHeader.Len()
+	for _, s := range r.Stats {
+		n += s.Len()
+	}
+	return
+}
+
+func (r *NXFlowStatsReply) MarshalBinary() (data []byte, err error) {
+	data, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range r.Stats {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return
+}
+
+func (r *NXFlowStatsReply) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	for n < len(data) {
+		s := new(NXFlowStats)
+		if err := s.UnmarshalBinary(data[n:]); err != nil {
+			klog.ErrorS(err, "Failed to unmarshal NXFlowStatsReply's Stats", "data", data[n:])
+			return err
+		}
+		r.Stats = append(r.Stats, s)
+		n += int(s.Length)
+	}
+	return nil
+}
+
+// nx_aggregate_stats_reply, the body of the NXST_AGGREGATE reply.
+type NXAggregateStatsReply struct {
+	*NXStatsHeader
+	PacketCount uint64
+	ByteCount   uint64
+	FlowCount   uint32
+}
+
+func NewNXAggregateStatsReply() *NXAggregateStatsReply {
+	return &NXAggregateStatsReply{NXStatsHeader: NewNXStatsHeader(NXST_AGGREGATE)}
+}
+
+func (r *NXAggregateStatsReply) Len() uint16 {
+	return r.NXStatsHeader.Len() + 24
+}
+
+func (r *NXAggregateStatsReply) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, r.Len())
+	var b []byte
+	n := 0
+
+	b, err = r.NXStatsHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(r.NXStatsHeader.Len())
+
+	binary.BigEndian.PutUint64(data[n:], r.PacketCount)
+	n += 8
+	binary.BigEndian.PutUint64(data[n:], r.ByteCount)
+	n += 8
+	binary.BigEndian.PutUint32(data[n:], r.FlowCount)
+	return
+}
+
+func (r *NXAggregateStatsReply) UnmarshalBinary(data []byte) error {
+	r.NXStatsHeader = new(NXStatsHeader)
+	if len(data) < int(r.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full NXAggregateStatsReply message")
+	}
+	n := 0
+	if err := r.NXStatsHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	n += int(r.NXStatsHeader.Len())
+
+	r.PacketCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	r.ByteCount = binary.BigEndian.Uint64(data[n:])
+	n += 8
+	r.FlowCount = binary.BigEndian.Uint32(data[n:])
+	return nil
+}
+
+// decodeNXStatsRequest decodes the body of the Nicira extended multipart requests.
+func decodeNXStatsRequest(subtype uint32, data []byte) (util.Message, error) {
+	var req util.Message
+	switch subtype {
+	case NXST_FLOW, NXST_AGGREGATE:
+		req = new(NXFlowStatsRequest)
+	default:
+		return nil, fmt.Errorf("unsupported Nicira multipart request type: %d", subtype)
+	}
+	if err := req.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// decodeNXStatsReply decodes the body of the Nicira extended multipart replies.
+func decodeNXStatsReply(subtype uint32, data []byte) (util.Message, error) {
+	var reply util.Message
+	switch subtype {
+	case NXST_FLOW:
+		reply = new(NXFlowStatsReply)
+	case NXST_AGGREGATE:
+		reply = new(NXAggregateStatsReply)
+	default:
+		return nil, fmt.Errorf("unsupported Nicira multipart reply type: %d", subtype)
+	}
+	if err := reply.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
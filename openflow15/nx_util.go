@@ -62,6 +62,73 @@ const (
 	LEARN_SPEC_HEADER_MATCH = 13
 )
 
+// NX_HASH_FIELDS are the packet fields hashed by multipath and bundle actions.
+const (
+	NX_HASH_FIELDS_ETH_SRC            = 0
+	NX_HASH_FIELDS_SYMMETRIC_L4       = 1
+	NX_HASH_FIELDS_SYMMETRIC_L3L4     = 2
+	NX_HASH_FIELDS_SYMMETRIC_L3L4_UDP = 3
+	NX_HASH_FIELDS_NW_SRC             = 4
+	NX_HASH_FIELDS_NW_DST             = 5
+	NX_HASH_FIELDS_SYMMETRIC_L3       = 6
+)
+
+// NX_MP_ALG are the algorithms of multipath action.
+const (
+	NX_MP_ALG_MODULO_N       = 0
+	NX_MP_ALG_HASH_THRESHOLD = 1
+	NX_MP_ALG_HRW            = 2
+	NX_MP_ALG_ITER_HASH      = 3
+)
+
+// NX_BD_ALG are the algorithms of bundle and bundle_load actions.
+const (
+	NX_BD_ALG_ACTIVE_BACKUP = 0
+	NX_BD_ALG_HRW           = 1
+)
+
+// NX_ACTION_SAMPLE directions of sample2 action.
+const (
+	NX_ACTION_SAMPLE_DEFAULT = 0
+	NX_ACTION_SAMPLE_INGRESS = 1
+	NX_ACTION_SAMPLE_EGRESS  = 2
+)
+
+// Packet type namespaces (ofp_header_type_namespaces).
+const (
+	OFPHTN_ONF          = 0
+	OFPHTN_ETHERTYPE    = 1
+	OFPHTN_IP_PROTO     = 2
+	OFPHTN_UDP_TCP_PORT = 3
+	OFPHTN_IPV4_OPTION  = 4
+)
+
+// Packet types, encoded as namespace << 16 | ns_type.
+const (
+	PT_ETH            = OFPHTN_ONF<<16 | 0x0000       // Default PT: Ethernet
+	PT_USE_NEXT_PROTO = OFPHTN_ONF<<16 | 0xfffe       // Pseudo PT for decap
+	PT_IPV4           = OFPHTN_ETHERTYPE<<16 | 0x0800 // IPv4
+	PT_IPV6           = OFPHTN_ETHERTYPE<<16 | 0x86dd // IPv6
+	PT_MPLS           = OFPHTN_ETHERTYPE<<16 | 0x8847 // MPLS
+	PT_NSH            = OFPHTN_ETHERTYPE<<16 | 0x894f // NSH
+)
+
+// NXOXM_NSH experimenter fields. The class of these fields is OXM_CLASS_EXPERIMENTER.
+const (
+	NXOXM_NSH_EXPERIMENTER_ID = 0x005ad650
+
+	NXOXM_NSH_FLAGS  = 1
+	NXOXM_NSH_MDTYPE = 2
+	NXOXM_NSH_NP     = 3
+	NXOXM_NSH_SPI    = 4
+	NXOXM_NSH_SI     = 5
+	NXOXM_NSH_C1     = 6
+	NXOXM_NSH_C2     = 7
+	NXOXM_NSH_C3     = 8
+	NXOXM_NSH_C4     = 9
+	NXOXM_NSH_TTL    = 10
+)
+
 // NXM_OF fields. The class number of these fields are 0x0000.
 const (
 	NXM_OF_IN_PORT uint8 = iota
@@ -96,6 +163,12 @@ func newMatchFieldHeader(class uint16, field uint8, length uint8) *MatchField {
 	return &MatchField{Class: class, Field: field, Length: fieldLength, HasMask: false}
 }
 
+// newExperimenterMatchFieldHeader creates an experimenter field header, the length includes the
+// 4-byte experimenter ID.
+func newExperimenterMatchFieldHeader(experimenterID uint32, field uint8, length uint8) *MatchField {
+	return &MatchField{Class: OXM_CLASS_EXPERIMENTER, Field: field, Length: length + 4, HasMask: false, ExperimenterID: experimenterID}
+}
+
 // oxxFieldHeaderMap is map to find target field header without mask using an OVS known OXX field name
 var oxxFieldHeaderMap = map[string]*MatchField{
 	"NXM_OF_IN_PORT":   newMatchFieldHeader(OXM_CLASS_NXM_0, NXM_OF_IN_PORT, 2),
@@ -152,6 +225,8 @@ var oxxFieldHeaderMap = map[string]*MatchField{
 	"NXM_NX_TUN_IPV4_DST":  newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_TUN_IPV4_DST, 4),
 	"NXM_NX_PKT_MARK":      newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_PKT_MARK, 4),
 	"NXM_NX_TCP_FLAGS":     newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_TCP_FLAGS, 2),
+	"NXM_NX_DP_HASH":       newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_DP_HASH, 4),
+	"NXM_NX_RECIRC_ID":     newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_RECIRC_ID, 4),
 	"NXM_NX_CONJ_ID":       newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_CONJ_ID, 4),
 	"NXM_NX_TUN_GBP_ID":    newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_TUN_GBP_ID, 2),
 	"NXM_NX_TUN_GBP_FLAGS": newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_TUN_GBP_FLAGS, 1),
@@ -182,6 +257,15 @@ var oxxFieldHeaderMap = map[string]*MatchField{
 	"NXM_NX_XXREG2":        newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_XXREG2, 16),
 	"NXM_NX_XXREG3":        newMatchFieldHeader(OXM_CLASS_NXM_1, NXM_NX_XXREG3, 16),
 
+	"OXM_OF_PKT_REG0": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG0, 8),
+	"OXM_OF_PKT_REG1": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG1, 8),
+	"OXM_OF_PKT_REG2": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG2, 8),
+	"OXM_OF_PKT_REG3": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG3, 8),
+	"OXM_OF_PKT_REG4": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG4, 8),
+	"OXM_OF_PKT_REG5": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG5, 8),
+	"OXM_OF_PKT_REG6": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG6, 8),
+	"OXM_OF_PKT_REG7": newMatchFieldHeader(OXM_CLASS_PACKET_REGS, OXM_PACKET_REG7, 8),
+
 	"OXM_OF_IN_PORT":        newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_IN_PORT, 4),
 	"OXM_OF_IN_PHY_PORT":    newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_IN_PHY_PORT, 4),
 	"OXM_OF_METADATA":       newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_METADATA, 8),
@@ -222,6 +306,18 @@ var oxxFieldHeaderMap = map[string]*MatchField{
 	"OXM_OF_PBB_ISID":       newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_PBB_ISID, 3),
 	"OXM_OF_TUNNEL_ID":      newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_TUNNEL_ID, 8),
 	"OXM_OF_IPV6_EXTHDR":    newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_IPV6_EXTHDR, 2),
+	"OXM_OF_PACKET_TYPE":    newMatchFieldHeader(OXM_CLASS_OPENFLOW_BASIC, OXM_FIELD_PACKET_TYPE, 4),
+
+	"NXOXM_NSH_FLAGS":  newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_FLAGS, 1),
+	"NXOXM_NSH_MDTYPE": newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_MDTYPE, 1),
+	"NXOXM_NSH_NP":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_NP, 1),
+	"NXOXM_NSH_SPI":    newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_SPI, 4),
+	"NXOXM_NSH_SI":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_SI, 1),
+	"NXOXM_NSH_C1":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_C1, 4),
+	"NXOXM_NSH_C2":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_C2, 4),
+	"NXOXM_NSH_C3":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_C3, 4),
+	"NXOXM_NSH_C4":     newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_C4, 4),
+	"NXOXM_NSH_TTL":    newExperimenterMatchFieldHeader(NXOXM_NSH_EXPERIMENTER_ID, NXOXM_NSH_TTL, 1),
 }
 
 // FindFieldHeaderByName finds OXM/NXM field by name and mask.
@@ -234,13 +330,18 @@ func FindFieldHeaderByName(fieldName string, hasMask bool) (*MatchField, error)
 	length := field.Length
 	if hasMask {
 		length = field.Length * 2
+		if field.ExperimenterID != 0 {
+			// The experimenter ID is not duplicated with the mask.
+			length = field.Length*2 - 4
+		}
 	}
 	// Create a new MatchField and return it to the caller, then it could avoid race condition.
 	return &MatchField{
-		Class:   field.Class,
-		Field:   field.Field,
-		HasMask: hasMask,
-		Length:  length,
+		Class:          field.Class,
+		Field:          field.Field,
+		HasMask:        hasMask,
+		Length:         length,
+		ExperimenterID: field.ExperimenterID,
 	}, nil
 }
 
@@ -250,10 +351,11 @@ func FindOxmIdByName(fieldName string, hasMask bool) (*OxmId, error) {
 		return nil, err
 	}
 	return &OxmId{
-		Class:   matchField.Class,
-		Field:   matchField.Field,
-		HasMask: matchField.HasMask,
-		Length:  matchField.Length,
+		Class:          matchField.Class,
+		Field:          matchField.Field,
+		HasMask:        matchField.HasMask,
+		Length:         matchField.Length,
+		ExperimenterID: matchField.ExperimenterID,
 	}, nil
 }
 
@@ -302,6 +404,32 @@ func (n *NXRange) ToUint32Mask() uint32 {
 	return mask1
 }
 
+// ToUint64Mask generates a uint64 number mask from NXRange.
+func (n *NXRange) ToUint64Mask() uint64 {
+	maxLength := 64
+	end := n.end
+	if end == 0 {
+		end = maxLength - 1
+	}
+	mask := ^uint64(0)
+	mask = mask >> uint64(maxLength-(end-n.start+1))
+	mask = mask << uint64(n.start)
+	return mask
+}
+
+// ToUint128Mask generates a 128-bit mask in network byte order from NXRange.
+func (n *NXRange) ToUint128Mask() [16]byte {
+	end := n.end
+	if end == 0 {
+		end = 127
+	}
+	var mask [16]byte
+	for i := n.start; i <= end; i++ {
+		mask[15-i/8] |= 1 << uint(i%8)
+	}
+	return mask
+}
+
 // ToOfsBits encodes the NXRange to a uint16 number to identify offshift and bits count.
 func (n *NXRange) ToOfsBits() uint16 {
 	return encodeOfsNbitsStartEnd(uint16(n.start), uint16(n.end))
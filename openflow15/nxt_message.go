@@ -3,6 +3,8 @@ package openflow15
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"net"
 
 	"k8s.io/klog/v2"
 
@@ -19,9 +21,11 @@ const (
 	Type_TlvTableMod       = 24
 	Type_TlvTableRequest   = 25
 	Type_TlvTableReply     = 26
+	Type_SetAsyncConfig2   = 27
 	Type_Resume            = 28
 	Type_CtFlushZone       = 29
 	Type_PacketIn2         = 30
+	Type_CtFlush           = 32
 )
 
 // ofpet_tlv_table_mod_failed_code 1.3
@@ -114,6 +118,137 @@ func NewSetControllerID(id uint16) *VendorHeader {
 	return msg
 }
 
+// nx_flow_format
+const (
+	NXFF_OPENFLOW10 = 0 /* Standard OpenFlow 1.0 compatible. */
+	NXFF_NXM        = 2 /* Nicira extended match. */
+)
+
+// FlowFormat is the body of NXT_SET_FLOW_FORMAT, which sets the format of the match in the flow mods and the
+// replies of the Nicira messages sent and received on the connection.
+type FlowFormat struct {
+	Format uint32
+}
+
+func (f *FlowFormat) Len() uint16 {
+	return 4
+}
+
+func (f *FlowFormat) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	binary.BigEndian.PutUint32(data, f.Format)
+	return data, nil
+}
+
+func (f *FlowFormat) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowFormat message")
+	}
+	f.Format = binary.BigEndian.Uint32(data)
+	return nil
+}
+
+// NewSetFlowFormat creates the NXT_SET_FLOW_FORMAT message with a NXFF_* format.
+func NewSetFlowFormat(format uint32) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetFlowFormat)
+	msg.VendorData = &FlowFormat{
+		Format: format,
+	}
+	return msg
+}
+
+// FlowModTableID is the body of NXT_FLOW_MOD_TABLE_ID. When it is enabled, the switch takes the table ID from the
+// high 8 bits of the command of the OpenFlow 1.0 flow mods sent on the connection.
+type FlowModTableID struct {
+	Enable bool
+}
+
+func (f *FlowModTableID) Len() uint16 {
+	return 8
+}
+
+func (f *FlowModTableID) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	if f.Enable {
+		data[0] = 1
+	}
+	// 7 bytes for padding
+	return data, nil
+}
+
+func (f *FlowModTableID) UnmarshalBinary(data []byte) error {
+	if len(data) < int(f.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full FlowModTableID message")
+	}
+	f.Enable = data[0] != 0
+	return nil
+}
+
+func NewFlowModTableID(enable bool) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_FlowModTableId)
+	msg.VendorData = &FlowModTableID{
+		Enable: enable,
+	}
+	return msg
+}
+
+// AsyncConfig2 is the body of NXT_SET_ASYNC_CONFIG2, which carries the asynchronous configuration properties of
+// OFPT_SET_ASYNC in OpenFlow 1.4+. The properties not included keep their current value.
+type AsyncConfig2 struct {
+	Properties []util.Message
+}
+
+func (a *AsyncConfig2) Len() uint16 {
+	n := uint16(0)
+	for _, p := range a.Properties {
+		n += p.Len()
+	}
+	return n
+}
+
+func (a *AsyncConfig2) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, 0, a.Len())
+	for _, p := range a.Properties {
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+func (a *AsyncConfig2) UnmarshalBinary(data []byte) error {
+	a.Properties = nil
+	n := 0
+	for n < len(data) {
+		if len(data[n:]) < 4 {
+			return errors.New("the []byte is too short to unmarshal a full AsyncConfig2 message")
+		}
+		p, err := newAsyncConfigProp(data[n:])
+		if err != nil {
+			return err
+		}
+		if err := p.UnmarshalBinary(data[n:]); err != nil {
+			klog.ErrorS(err, "Failed to unmarshal AsyncConfig2's Properties", "data", data[n:])
+			return err
+		}
+		n += int(p.Len())
+		a.Properties = append(a.Properties, p)
+	}
+	return nil
+}
+
+// NewSetAsyncConfig2 creates the NXT_SET_ASYNC_CONFIG2 message with the properties, e.g., the properties created by
+// NewAsyncConfigPropReasons.
+func NewSetAsyncConfig2(props ...util.Message) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_SetAsyncConfig2)
+	msg.VendorData = &AsyncConfig2{
+		Properties: props,
+	}
+	return msg
+}
+
 type TLVTableMap struct {
 	OptClass  uint16
 	OptType   uint8
@@ -380,13 +515,20 @@ func (p *ContinuationPropStack) UnmarshalBinary(data []byte) error {
 		return errors.New("the []byte is too short to unmarshal a full ContinuationPropStack message")
 	}
 	n += int(p.PropHeader.Len())
-	for _, eachStack := range p.Stack {
-		data[n] = eachStack
-		n++
-	}
+	p.Stack = make([]uint8, int(p.Length)-n)
+	copy(p.Stack, data[n:p.Length])
 	return nil
 }
 
+// NewContinuationPropStack creates a property with one entry of the register stack, which is the
+// value pushed by a NXActionStackPush action.
+func NewContinuationPropStack(stack []uint8) *ContinuationPropStack {
+	return &ContinuationPropStack{
+		PropHeader: &PropHeader{Type: NXCPT_STACK},
+		Stack:      stack,
+	}
+}
+
 type ContinuationPropMirrors struct {
 	*PropHeader /* Type: NXCPT_MIRRORS */
 	Mirrors     uint32
@@ -742,6 +884,8 @@ func DecodeContinuationProp(data []byte) (Property, error) {
 		p = new(ContinuationPropActionSet)
 	case NXCPT_ODP_PORT:
 		p = new(ContinuationPropOdpPort)
+	default:
+		return nil, fmt.Errorf("unknown ContinuationProp type: %v", t)
 	}
 	err := p.UnmarshalBinary(data)
 	if err != nil {
@@ -1189,6 +1333,23 @@ func (p *PacketIn2PropContinuation) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// Props decodes the Continuation properties, e.g., the ContinuationPropStack properties which save
+// the register stack of the paused pipeline, one property per entry from the bottom of the stack.
+func (p *PacketIn2PropContinuation) Props() ([]Property, error) {
+	var props []Property
+	// The nested properties are aligned to 8 bytes, following 4 bytes of padding after the header.
+	n := 4
+	for n+4 <= len(p.Continuation) {
+		prop, err := DecodeContinuationProp(p.Continuation[n:])
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, prop)
+		n += int(prop.Len())
+	}
+	return props, nil
+}
+
 // Decode PacketIn2 Property types.
 func DecodePacketIn2Prop(data []byte) (Property, error) {
 	t := binary.BigEndian.Uint16(data[:2])
@@ -1322,12 +1483,432 @@ func NewResume(props []Property) *VendorHeader {
 	return msg
 }
 
+// CTFlushZone is the body of NXT_CT_FLUSH_ZONE, which flushes all the conntrack entries in the zone.
+type CTFlushZone struct {
+	ZoneID uint16
+}
+
+func (c *CTFlushZone) Len() uint16 {
+	return 8
+}
+
+func (c *CTFlushZone) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(c.Len()))
+	// 6 bytes for padding
+	n := 6
+	binary.BigEndian.PutUint16(data[n:], c.ZoneID)
+	return data, nil
+}
+
+func (c *CTFlushZone) UnmarshalBinary(data []byte) error {
+	if len(data) < int(c.Len()) {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushZone message")
+	}
+	n := 6
+	c.ZoneID = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+func NewCTFlushZone(zoneID uint16) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_CtFlushZone)
+	msg.VendorData = &CTFlushZone{
+		ZoneID: zoneID,
+	}
+	return msg
+}
+
+// The address families used in CTFlush, they are the values on Linux.
+const (
+	CT_FLUSH_AF_UNSPEC = 0
+	CT_FLUSH_AF_INET   = 2
+	CT_FLUSH_AF_INET6  = 10
+)
+
+// nx_ct_flush_tlv_type
+const (
+	NXT_CT_ORIG_TUPLE  = 0 /* Nested NXT_CT_TUPLE_* */
+	NXT_CT_REPLY_TUPLE = 1 /* Nested NXT_CT_TUPLE_* */
+	NXT_CT_ZONE_ID     = 2 /* be16 */
+)
+
+// nx_ct_flush_tuple_tlv_type
+const (
+	NXT_CT_TUPLE_SRC       = 0 /* be128 */
+	NXT_CT_TUPLE_DST       = 1 /* be128 */
+	NXT_CT_TUPLE_SRC_PORT  = 2 /* be16 */
+	NXT_CT_TUPLE_DST_PORT  = 3 /* be16 */
+	NXT_CT_TUPLE_ICMP_ID   = 4 /* be16 */
+	NXT_CT_TUPLE_ICMP_TYPE = 5 /* u8 */
+	NXT_CT_TUPLE_ICMP_CODE = 6 /* u8 */
+)
+
+// CTFlush is the body of NXT_CT_FLUSH, which flushes the conntrack entries matching the IP protocol, the address
+// family and the properties, e.g., the zone and the tuples of the original and reply directions. The entries in
+// all zones are flushed if the zone property is not set.
+type CTFlush struct {
+	IPProto uint8
+	Family  uint8
+	Props   []Property
+}
+
+func (c *CTFlush) Len() (n uint16) {
+	n = 8
+	for _, prop := range c.Props {
+		n += prop.Len()
+	}
+	return
+}
+
+func (c *CTFlush) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, c.Len())
+	n := 0
+	data[n] = c.IPProto
+	n += 1
+	data[n] = c.Family
+	n += 1
+	// 6 bytes for padding
+	n += 6
+
+	for _, prop := range c.Props {
+		b, err := prop.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += int(prop.Len())
+	}
+	return
+}
+
+func (c *CTFlush) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlush message")
+	}
+	n := 0
+	c.IPProto = data[n]
+	n += 1
+	c.Family = data[n]
+	n += 7
+
+	for n < len(data) {
+		prop, err := DecodeCTFlushProp(data[n:])
+		if err != nil {
+			klog.ErrorS(err, "Failed to decode CTFlush's Props", "data", data[n:])
+			return err
+		}
+		c.Props = append(c.Props, prop)
+		n += int(prop.Len())
+	}
+	return nil
+}
+
+func NewCTFlush(ipProto uint8, family uint8, props []Property) *VendorHeader {
+	msg := NewNXTVendorHeader(Type_CtFlush)
+	msg.VendorData = &CTFlush{
+		IPProto: ipProto,
+		Family:  family,
+		Props:   props,
+	}
+	return msg
+}
+
+// CTFlushPropUint16 is a property with a 16-bit value, it is used by NXT_CT_ZONE_ID in CTFlush, and
+// NXT_CT_TUPLE_SRC_PORT, NXT_CT_TUPLE_DST_PORT and NXT_CT_TUPLE_ICMP_ID in CTFlushPropTuple.
+type CTFlushPropUint16 struct {
+	*PropHeader
+	Value uint16
+}
+
+func (p *CTFlushPropUint16) Len() (n uint16) {
+	return p.PropHeader.Len() + 4
+}
+
+func (p *CTFlushPropUint16) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 2
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	binary.BigEndian.PutUint16(data[n:], p.Value)
+	return
+}
+
+func (p *CTFlushPropUint16) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+2 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropUint16 message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Value = binary.BigEndian.Uint16(data[n:])
+	return nil
+}
+
+// CTFlushPropUint8 is a property with an 8-bit value, it is used by NXT_CT_TUPLE_ICMP_TYPE and
+// NXT_CT_TUPLE_ICMP_CODE in CTFlushPropTuple.
+type CTFlushPropUint8 struct {
+	*PropHeader
+	Value uint8
+}
+
+func (p *CTFlushPropUint8) Len() (n uint16) {
+	return p.PropHeader.Len() + 4
+}
+
+func (p *CTFlushPropUint8) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 1
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	data[n] = p.Value
+	return
+}
+
+func (p *CTFlushPropUint8) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+1 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropUint8 message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Value = data[n]
+	return nil
+}
+
+// CTFlushPropAddr is the address property NXT_CT_TUPLE_SRC or NXT_CT_TUPLE_DST in CTFlushPropTuple. An IPv4
+// address is encoded as an IPv4-mapped IPv6 address.
+type CTFlushPropAddr struct {
+	*PropHeader
+	Addr net.IP
+}
+
+func (p *CTFlushPropAddr) Len() (n uint16) {
+	return p.PropHeader.Len() + 20
+}
+
+func (p *CTFlushPropAddr) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.PropHeader.Len() + 16
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+
+	copy(data[n:], p.Addr.To16())
+	return
+}
+
+func (p *CTFlushPropAddr) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Len()) || p.Length < p.PropHeader.Len()+16 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropAddr message")
+	}
+	n += int(p.PropHeader.Len())
+
+	p.Addr = make(net.IP, 16)
+	copy(p.Addr, data[n:n+16])
+	return nil
+}
+
+// CTFlushPropTuple is the property NXT_CT_ORIG_TUPLE or NXT_CT_REPLY_TUPLE in CTFlush, which nests the
+// NXT_CT_TUPLE_* properties of the tuple.
+type CTFlushPropTuple struct {
+	*PropHeader
+	Props []Property
+}
+
+func (p *CTFlushPropTuple) Len() (n uint16) {
+	// The nested properties are aligned to 8 bytes, following 4 bytes of padding after the header.
+	n = p.PropHeader.Len() + 4
+	for _, prop := range p.Props {
+		n += prop.Len()
+	}
+	return
+}
+
+func (p *CTFlushPropTuple) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, p.Len())
+	var b []byte
+	n := 0
+
+	p.Length = p.Len()
+	b, err = p.PropHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[n:], b)
+	n += int(p.PropHeader.Len())
+	// 4 bytes for padding
+	n += 4
+
+	for _, prop := range p.Props {
+		b, err = prop.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += int(prop.Len())
+	}
+	return
+}
+
+func (p *CTFlushPropTuple) UnmarshalBinary(data []byte) error {
+	p.PropHeader = new(PropHeader)
+	n := 0
+
+	if err := p.PropHeader.UnmarshalBinary(data[n:]); err != nil {
+		return err
+	}
+	if len(data) < int(p.Length) || p.Length < 8 {
+		return errors.New("the []byte is too short to unmarshal a full CTFlushPropTuple message")
+	}
+	n += int(p.PropHeader.Len()) + 4
+
+	for n < int(p.Length) {
+		prop, err := DecodeCTFlushTupleProp(data[n:p.Length])
+		if err != nil {
+			klog.ErrorS(err, "Failed to decode CTFlushPropTuple's Props", "data", data[n:p.Length])
+			return err
+		}
+		p.Props = append(p.Props, prop)
+		n += int(prop.Len())
+	}
+	return nil
+}
+
+func NewCTFlushPropZoneID(zoneID uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_ZONE_ID}, Value: zoneID}
+}
+
+// NewCTFlushPropTuple creates the property of the original tuple if reply is false, otherwise the property of the
+// reply tuple.
+func NewCTFlushPropTuple(reply bool, props []Property) *CTFlushPropTuple {
+	propType := uint16(NXT_CT_ORIG_TUPLE)
+	if reply {
+		propType = NXT_CT_REPLY_TUPLE
+	}
+	return &CTFlushPropTuple{PropHeader: &PropHeader{Type: propType}, Props: props}
+}
+
+func NewCTFlushPropSrc(addr net.IP) *CTFlushPropAddr {
+	return &CTFlushPropAddr{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_SRC}, Addr: addr}
+}
+
+func NewCTFlushPropDst(addr net.IP) *CTFlushPropAddr {
+	return &CTFlushPropAddr{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_DST}, Addr: addr}
+}
+
+func NewCTFlushPropSrcPort(port uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_SRC_PORT}, Value: port}
+}
+
+func NewCTFlushPropDstPort(port uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_DST_PORT}, Value: port}
+}
+
+func NewCTFlushPropICMPID(id uint16) *CTFlushPropUint16 {
+	return &CTFlushPropUint16{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_ID}, Value: id}
+}
+
+func NewCTFlushPropICMPType(icmpType uint8) *CTFlushPropUint8 {
+	return &CTFlushPropUint8{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_TYPE}, Value: icmpType}
+}
+
+func NewCTFlushPropICMPCode(icmpCode uint8) *CTFlushPropUint8 {
+	return &CTFlushPropUint8{PropHeader: &PropHeader{Type: NXT_CT_TUPLE_ICMP_CODE}, Value: icmpCode}
+}
+
+// Decode CTFlush Property types.
+func DecodeCTFlushProp(data []byte) (Property, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to unmarshal a CTFlush property")
+	}
+	t := binary.BigEndian.Uint16(data[:2])
+	var p Property
+	switch t {
+	case NXT_CT_ZONE_ID:
+		p = new(CTFlushPropUint16)
+	case NXT_CT_ORIG_TUPLE, NXT_CT_REPLY_TUPLE:
+		p = new(CTFlushPropTuple)
+	default:
+		return nil, fmt.Errorf("unknown CTFlush property type: %d", t)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Decode CTFlushPropTuple Property types.
+func DecodeCTFlushTupleProp(data []byte) (Property, error) {
+	if len(data) < 4 {
+		return nil, errors.New("the []byte is too short to unmarshal a CTFlushPropTuple property")
+	}
+	t := binary.BigEndian.Uint16(data[:2])
+	var p Property
+	switch t {
+	case NXT_CT_TUPLE_SRC, NXT_CT_TUPLE_DST:
+		p = new(CTFlushPropAddr)
+	case NXT_CT_TUPLE_SRC_PORT, NXT_CT_TUPLE_DST_PORT, NXT_CT_TUPLE_ICMP_ID:
+		p = new(CTFlushPropUint16)
+	case NXT_CT_TUPLE_ICMP_TYPE, NXT_CT_TUPLE_ICMP_CODE:
+		p = new(CTFlushPropUint8)
+	default:
+		return nil, fmt.Errorf("unknown CTFlushPropTuple property type: %d", t)
+	}
+	if err := p.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 func decodeVendorData(experimenterType uint32, data []byte) (msg util.Message, err error) {
 	switch experimenterType {
 	case Type_SetPacketInFormat:
 		msg = new(PacketInFormat)
 	case Type_SetControllerId:
 		msg = new(ControllerID)
+	case Type_SetFlowFormat:
+		msg = new(FlowFormat)
+	case Type_FlowModTableId:
+		msg = new(FlowModTableID)
+	case Type_SetAsyncConfig2:
+		msg = new(AsyncConfig2)
 	case Type_TlvTableMod:
 		msg = new(TLVTableMod)
 	case Type_TlvTableReply:
@@ -1338,6 +1919,12 @@ func decodeVendorData(experimenterType uint32, data []byte) (msg util.Message, e
 		msg = new(BundleAdd)
 	case Type_PacketIn2:
 		msg = new(PacketIn2)
+	case Type_CtFlushZone:
+		msg = new(CTFlushZone)
+	case Type_CtFlush:
+		msg = new(CTFlush)
+	default:
+		return nil, fmt.Errorf("unsupported experimenter type: %v", experimenterType)
 	}
 	err = msg.UnmarshalBinary(data)
 	if err != nil {
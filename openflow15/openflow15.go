@@ -11,7 +11,9 @@ package openflow15
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"net"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -545,6 +547,12 @@ func NewErrorMsg() *ErrorMsg {
 	return e
 }
 
+// Error returns the type and the code of the error, so that an ErrorMsg replied by the switch can be returned as an
+// error.
+func (e *ErrorMsg) Error() string {
+	return fmt.Sprintf("error type %d code %d", e.Type, e.Code)
+}
+
 func (e *ErrorMsg) Len() (n uint16) {
 	n = e.Header.Len()
 	n += 2
@@ -1023,7 +1031,7 @@ func (v *VendorHeader) UnmarshalBinary(data []byte) error {
 	n += 4
 	if n < int(v.Header.Length) {
 		var err error
-		v.VendorData, err = decodeVendorData(v.ExperimenterType, data[n:v.Header.Length])
+		v.VendorData, err = decodeExperimenterMessage(v.Vendor, v.ExperimenterType, data[n:v.Header.Length])
 		if err != nil {
 			return err
 		}
@@ -1189,46 +1197,8 @@ func (a *Async_Config) UnmarshalBinary(data []byte) (err error) {
 
 	for n < a.Header.Length {
 		var p util.Message
-		switch binary.BigEndian.Uint16(data[n:]) {
-		case ACPT_PACKET_IN_SLAVE:
-			fallthrough
-		case ACPT_PACKET_IN_MASTER:
-			fallthrough
-		case ACPT_PORT_STATUS_SLAVE:
-			fallthrough
-		case ACPT_PORT_STATUS_MASTER:
-			fallthrough
-		case ACPT_FLOW_REMOVED_SLAVE:
-			fallthrough
-		case ACPT_FLOW_REMOVED_MASTER:
-			fallthrough
-		case ACPT_ROLE_STATUS_SLAVE:
-			fallthrough
-		case ACPT_ROLE_STATUS_MASTER:
-			fallthrough
-		case ACPT_TABLE_STATUS_SLAVE:
-			fallthrough
-		case ACPT_TABLE_STATUS_MASTER:
-			fallthrough
-		case ACPT_REQUESTFORWARD_SLAVE:
-			fallthrough
-		case ACPT_REQUESTFORWARD_MASTER:
-			fallthrough
-		case ACPT_FLOW_STATS_SLAVE:
-			fallthrough
-		case ACPT_FLOW_STATS_MASTER:
-			fallthrough
-		case ACPT_CONT_STATUS_SLAVE:
-			fallthrough
-		case ACPT_CONT_STATUS_MASTER:
-			p = new(AsyncConfigPropReasons)
-
-		case ACPT_EXPERIMENTER_SLAVE:
-			fallthrough
-		case ACPT_EXPERIMENTER_MASTER:
-			p = new(AsyncConfigPropExperimenter)
-		default:
-			err = errors.New("An unknown property type was received")
+		p, err = newAsyncConfigProp(data[n:])
+		if err != nil {
 			return
 		}
 		err = p.UnmarshalBinary(data[n:])
@@ -1242,6 +1212,50 @@ func (a *Async_Config) UnmarshalBinary(data []byte) (err error) {
 	return
 }
 
+// newAsyncConfigProp returns a new property to unmarshal the asynchronous configuration property encoded in data.
+func newAsyncConfigProp(data []byte) (util.Message, error) {
+	switch binary.BigEndian.Uint16(data) {
+	case ACPT_PACKET_IN_SLAVE:
+		fallthrough
+	case ACPT_PACKET_IN_MASTER:
+		fallthrough
+	case ACPT_PORT_STATUS_SLAVE:
+		fallthrough
+	case ACPT_PORT_STATUS_MASTER:
+		fallthrough
+	case ACPT_FLOW_REMOVED_SLAVE:
+		fallthrough
+	case ACPT_FLOW_REMOVED_MASTER:
+		fallthrough
+	case ACPT_ROLE_STATUS_SLAVE:
+		fallthrough
+	case ACPT_ROLE_STATUS_MASTER:
+		fallthrough
+	case ACPT_TABLE_STATUS_SLAVE:
+		fallthrough
+	case ACPT_TABLE_STATUS_MASTER:
+		fallthrough
+	case ACPT_REQUESTFORWARD_SLAVE:
+		fallthrough
+	case ACPT_REQUESTFORWARD_MASTER:
+		fallthrough
+	case ACPT_FLOW_STATS_SLAVE:
+		fallthrough
+	case ACPT_FLOW_STATS_MASTER:
+		fallthrough
+	case ACPT_CONT_STATUS_SLAVE:
+		fallthrough
+	case ACPT_CONT_STATUS_MASTER:
+		return new(AsyncConfigPropReasons), nil
+	case ACPT_EXPERIMENTER_SLAVE:
+		fallthrough
+	case ACPT_EXPERIMENTER_MASTER:
+		return new(AsyncConfigPropExperimenter), nil
+	default:
+		return nil, errors.New("An unknown property type was received")
+	}
+}
+
 type AsyncConfigPropHeader struct {
 	Type   uint16
 	Length uint16
@@ -1274,6 +1288,16 @@ type AsyncConfigPropReasons struct {
 	Mask   uint32
 }
 
+// NewAsyncConfigPropReasons creates the ACPT_* property enabling the reasons in mask, each bit of mask is a reason
+// of the message, e.g., 1 << R_APPLY_ACTION for ACPT_PACKET_IN_MASTER.
+func NewAsyncConfigPropReasons(propType uint16, mask uint32) *AsyncConfigPropReasons {
+	p := new(AsyncConfigPropReasons)
+	p.Header.Type = propType
+	p.Header.Length = p.Len()
+	p.Mask = mask
+	return p
+}
+
 func (p *AsyncConfigPropReasons) Len() uint16 {
 	n := p.Header.Len()
 	n += 4
@@ -1282,7 +1306,7 @@ func (p *AsyncConfigPropReasons) Len() uint16 {
 
 func (p *AsyncConfigPropReasons) MarshalBinary() (data []byte, err error) {
 	data = make([]byte, int(p.Len()))
-	p.Header.Length = 4
+	p.Header.Length = p.Len()
 	b, err := p.Header.MarshalBinary()
 	if err != nil {
 		return
@@ -1447,7 +1471,7 @@ func (r *RoleStatus) UnmarshalBinary(data []byte) (err error) {
 		var p util.Message
 		switch binary.BigEndian.Uint16(data[n:]) {
 		case RPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -1629,7 +1653,7 @@ func (t *TableDesc) UnmarshalBinary(data []byte) (err error) {
 		case OFPTMPT_VACANCY:
 			p = new(TableModPropVacancy)
 		case OFPTMPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -1918,7 +1942,7 @@ func (t *TableMod) UnmarshalBinary(data []byte) (err error) {
 		case OFPTMPT_VACANCY:
 			p = new(TableModPropVacancy)
 		case OFPTMPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -2101,7 +2125,7 @@ func (c *BundleCtrl) UnmarshalBinary(data []byte) (err error) {
 		case BPT_TIME:
 			p = new(BundlePropTime)
 		case BPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -2175,6 +2199,16 @@ func (t *BundlePropTime) UnmarshalBinary(data []byte) (err error) {
 	return
 }
 
+// NewBundlePropTime creates the BPT_TIME property to commit the bundle at schedTime, the commit request must set the
+// BF_TIME flag.
+func NewBundlePropTime(schedTime time.Time) *BundlePropTime {
+	t := new(BundlePropTime)
+	t.Header.Type = BPT_TIME
+	t.SchedTime = NewOfpTime(schedTime)
+	t.Header.Length = t.Len()
+	return t
+}
+
 // ofp_time
 type OfpTime struct {
 	Seconds     uint64
@@ -2182,6 +2216,24 @@ type OfpTime struct {
 	Pad         uint32
 }
 
+// NewOfpTime converts t to the time since the epoch.
+func NewOfpTime(t time.Time) OfpTime {
+	return OfpTime{
+		Seconds:     uint64(t.Unix()),
+		NanoSeconds: uint32(t.Nanosecond()),
+	}
+}
+
+// Time returns the time since the epoch.
+func (t *OfpTime) Time() time.Time {
+	return time.Unix(int64(t.Seconds), int64(t.NanoSeconds))
+}
+
+// Duration returns the time as a duration, which is used by the periods of BundleFeaturesPropTime.
+func (t *OfpTime) Duration() time.Duration {
+	return time.Duration(t.Seconds)*time.Second + time.Duration(t.NanoSeconds)
+}
+
 func (t *OfpTime) Len() uint16 {
 	return 16
 }
@@ -2306,7 +2358,7 @@ func (c *BndleAdd) UnmarshalBinary(data []byte) (err error) {
 		case BPT_TIME:
 			p = new(BundlePropTime)
 		case BPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -2464,7 +2516,7 @@ func (c *ControllerStatus) UnmarshalBinary(data []byte) (err error) {
 		case CSPT_URI:
 			p = new(ControllerStatusPropUri)
 		case CSPT_EXPERIMENTER:
-			p = new(PropExperimenter)
+			p = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
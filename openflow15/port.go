@@ -112,7 +112,7 @@ func (p *Port) UnmarshalBinary(data []byte) (err error) {
 		case PDPT_RECIRCULATE:
 			prop = new(PortDescPropRecirculate)
 		case PDPT_EXPERIMENTER:
-			prop = new(PropExperimenter)
+			prop = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -500,7 +500,7 @@ func (p *PortMod) UnmarshalBinary(data []byte) (err error) {
 		case PMPT_OPTICAL:
 			prop = new(PortModPropOptical)
 		case PMPT_EXPERIMENTER:
-			prop = new(PropExperimenter)
+			prop = newExperimenterProp(data[n:], new(PropExperimenter))
 		default:
 			err = errors.New("An unknown property type was received")
 			return
@@ -399,6 +399,24 @@ func Test_SetAsync(t *testing.T) {
 	}
 }
 
+// The length of the property header is the length of the whole property, including the header.
+func Test_SetAsyncPropertyLength(t *testing.T) {
+	setAsync := openflow15.NewSetAsync()
+	p := new(openflow15.AsyncConfigPropReasons)
+	p.Header.Type = openflow15.ACPT_FLOW_REMOVED_SLAVE
+	p.Mask = 4
+	setAsync.Properties = append(setAsync.Properties, p)
+
+	data, err := setAsync.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed with error code: %v", err)
+	}
+	expected := "0004000800000004"
+	if hex.EncodeToString(data[8:]) != expected {
+		t.Errorf("Marshaled property %x is not equal to %s", data[8:], expected)
+	}
+}
+
 func Test_RoleStatus(t *testing.T) {
 	roleStatus := openflow15.NewRoleStatus()
 	roleStatus.Role = openflow15.CR_ROLE_MASTER
@@ -0,0 +1,812 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// DHCPv6 message types (RFC 8415).
+const (
+	DHCPv6_MSG_SOLICIT             uint8 = 1
+	DHCPv6_MSG_ADVERTISE           uint8 = 2
+	DHCPv6_MSG_REQUEST             uint8 = 3
+	DHCPv6_MSG_CONFIRM             uint8 = 4
+	DHCPv6_MSG_RENEW               uint8 = 5
+	DHCPv6_MSG_REBIND              uint8 = 6
+	DHCPv6_MSG_REPLY               uint8 = 7
+	DHCPv6_MSG_RELEASE             uint8 = 8
+	DHCPv6_MSG_DECLINE             uint8 = 9
+	DHCPv6_MSG_RECONFIGURE         uint8 = 10
+	DHCPv6_MSG_INFORMATION_REQUEST uint8 = 11
+	DHCPv6_MSG_RELAY_FORW          uint8 = 12
+	DHCPv6_MSG_RELAY_REPL          uint8 = 13
+)
+
+// DHCPv6 option codes.
+const (
+	DHCPv6_OPT_CLIENTID     uint16 = 1
+	DHCPv6_OPT_SERVERID     uint16 = 2
+	DHCPv6_OPT_IA_NA        uint16 = 3
+	DHCPv6_OPT_IA_TA        uint16 = 4
+	DHCPv6_OPT_IAADDR       uint16 = 5
+	DHCPv6_OPT_ORO          uint16 = 6
+	DHCPv6_OPT_PREFERENCE   uint16 = 7
+	DHCPv6_OPT_ELAPSED_TIME uint16 = 8
+	DHCPv6_OPT_STATUS_CODE  uint16 = 13
+	DHCPv6_OPT_RAPID_COMMIT uint16 = 14
+	DHCPv6_OPT_DNS_SERVERS  uint16 = 23
+	DHCPv6_OPT_DOMAIN_LIST  uint16 = 24
+	DHCPv6_OPT_IA_PD        uint16 = 25
+	DHCPv6_OPT_IAPREFIX     uint16 = 26
+)
+
+// DHCPv6 status codes.
+const (
+	DHCPv6_STATUS_SUCCESS        uint16 = 0
+	DHCPv6_STATUS_UNSPEC_FAIL    uint16 = 1
+	DHCPv6_STATUS_NO_ADDRS_AVAIL uint16 = 2
+	DHCPv6_STATUS_NO_BINDING     uint16 = 3
+	DHCPv6_STATUS_NOT_ON_LINK    uint16 = 4
+	DHCPv6_STATUS_USE_MULTICAST  uint16 = 5
+	DHCPv6_STATUS_NO_PREFIX      uint16 = 6
+)
+
+// DUID types.
+const (
+	DHCPv6_DUID_LLT  uint16 = 1
+	DHCPv6_DUID_EN   uint16 = 2
+	DHCPv6_DUID_LL   uint16 = 3
+	DHCPv6_DUID_UUID uint16 = 4
+)
+
+const dhcpv6OptHeaderSize = 4
+
+// DHCPv6 is a client/server DHCPv6 message:
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|    msg-type   |               transaction-id                  |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                                                               |
+//	.                            options                            .
+//	.                 (variable number and length)                  .
+//	|                                                               |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// Relay-forward and Relay-reply messages are not supported.
+type DHCPv6 struct {
+	MessageType   uint8
+	TransactionID uint32 // 24-bits
+	Options       []DHCPv6Option
+}
+
+func NewDHCPv6(msgType uint8, xid uint32) (*DHCPv6, error) {
+	if xid == 0 {
+		var err error
+		if xid, err = getRandomXID(); err != nil {
+			return nil, fmt.Errorf("Failed to generate random XID: %v", err)
+		}
+	}
+	return &DHCPv6{
+		MessageType:   msgType,
+		TransactionID: xid & 0xffffff,
+	}, nil
+}
+
+func (d *DHCPv6) Len() (n uint16) {
+	n = 4
+	for _, o := range d.Options {
+		n += o.Len()
+	}
+	return
+}
+
+func (d *DHCPv6) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(d.Len()))
+	binary.BigEndian.PutUint32(data, uint32(d.MessageType)<<24|d.TransactionID&0xffffff)
+	n := 4
+	var b []byte
+	for _, o := range d.Options {
+		if b, err = o.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (d *DHCPv6) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("The []byte is too short to unmarshal a full DHCPv6 message.")
+	}
+	d.MessageType = data[0]
+	if d.MessageType == DHCPv6_MSG_RELAY_FORW || d.MessageType == DHCPv6_MSG_RELAY_REPL {
+		return errors.New("DHCPv6 relay messages are not supported")
+	}
+	d.TransactionID = binary.BigEndian.Uint32(data) & 0xffffff
+	var err error
+	d.Options, err = parseDHCPv6Options(data[4:])
+	return err
+}
+
+// GetOption returns the first option with the given code, or nil if the message
+// does not carry it.
+func (d *DHCPv6) GetOption(code uint16) DHCPv6Option {
+	return getDHCPv6Option(d.Options, code)
+}
+
+// ClientID returns the DUID of the Client Identifier option.
+func (d *DHCPv6) ClientID() *DHCPv6DUID {
+	if o, ok := d.GetOption(DHCPv6_OPT_CLIENTID).(*DHCPv6DUIDOption); ok {
+		return &o.DUID
+	}
+	return nil
+}
+
+// ServerID returns the DUID of the Server Identifier option.
+func (d *DHCPv6) ServerID() *DHCPv6DUID {
+	if o, ok := d.GetOption(DHCPv6_OPT_SERVERID).(*DHCPv6DUIDOption); ok {
+		return &o.DUID
+	}
+	return nil
+}
+
+func newDHCPv6Message(msgType uint8, xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (d *DHCPv6, err error) {
+	if d, err = NewDHCPv6(msgType, xid); err != nil {
+		return
+	}
+	if clientID != nil {
+		d.Options = append(d.Options, NewDHCPv6ClientID(clientID))
+	}
+	if serverID != nil {
+		d.Options = append(d.Options, NewDHCPv6ServerID(serverID))
+	}
+	return
+}
+
+func NewDHCPv6Solicit(xid uint32, clientID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_SOLICIT, xid, clientID, nil)
+}
+
+func NewDHCPv6Advertise(xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_ADVERTISE, xid, clientID, serverID)
+}
+
+func NewDHCPv6Request(xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_REQUEST, xid, clientID, serverID)
+}
+
+func NewDHCPv6Reply(xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_REPLY, xid, clientID, serverID)
+}
+
+func NewDHCPv6Renew(xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_RENEW, xid, clientID, serverID)
+}
+
+func NewDHCPv6Release(xid uint32, clientID *DHCPv6DUID, serverID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_RELEASE, xid, clientID, serverID)
+}
+
+func NewDHCPv6InformationRequest(xid uint32, clientID *DHCPv6DUID) (*DHCPv6, error) {
+	return newDHCPv6Message(DHCPv6_MSG_INFORMATION_REQUEST, xid, clientID, nil)
+}
+
+type DHCPv6Option interface {
+	util.Message
+	OptionCode() uint16
+}
+
+func getDHCPv6Option(options []DHCPv6Option, code uint16) DHCPv6Option {
+	for _, o := range options {
+		if o.OptionCode() == code {
+			return o
+		}
+	}
+	return nil
+}
+
+func newDHCPv6OptionByCode(code uint16) DHCPv6Option {
+	switch code {
+	case DHCPv6_OPT_CLIENTID, DHCPv6_OPT_SERVERID:
+		return new(DHCPv6DUIDOption)
+	case DHCPv6_OPT_IA_NA:
+		return new(DHCPv6IANA)
+	case DHCPv6_OPT_IAADDR:
+		return new(DHCPv6IAAddress)
+	case DHCPv6_OPT_IA_PD:
+		return new(DHCPv6IAPD)
+	case DHCPv6_OPT_IAPREFIX:
+		return new(DHCPv6IAPrefix)
+	case DHCPv6_OPT_DNS_SERVERS:
+		return new(DHCPv6DNSServers)
+	case DHCPv6_OPT_STATUS_CODE:
+		return new(DHCPv6StatusCode)
+	}
+	return new(DHCPv6OptionGeneric)
+}
+
+func parseDHCPv6Options(data []byte) ([]DHCPv6Option, error) {
+	var options []DHCPv6Option
+	n := 0
+	for n < len(data) {
+		if len(data)-n < dhcpv6OptHeaderSize {
+			return nil, errors.New("The []byte is too short to unmarshal a full DHCPv6 option.")
+		}
+		code := binary.BigEndian.Uint16(data[n:])
+		length := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[n+2:]))
+		if len(data)-n < length {
+			return nil, errors.New("The []byte is too short to unmarshal a full DHCPv6 option.")
+		}
+		o := newDHCPv6OptionByCode(code)
+		if err := o.UnmarshalBinary(data[n : n+length]); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+		n += length
+	}
+	return options, nil
+}
+
+func marshalDHCPv6Options(data []byte, options []DHCPv6Option) error {
+	n := 0
+	for _, o := range options {
+		b, err := o.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return nil
+}
+
+func dhcpv6OptionsLen(options []DHCPv6Option) (n uint16) {
+	for _, o := range options {
+		n += o.Len()
+	}
+	return
+}
+
+// putDHCPv6OptionHeader writes the code and the length of the option data.
+func putDHCPv6OptionHeader(data []byte, code uint16) {
+	binary.BigEndian.PutUint16(data, code)
+	binary.BigEndian.PutUint16(data[2:], uint16(len(data)-dhcpv6OptHeaderSize))
+}
+
+func checkDHCPv6Option(data []byte, minLen int) error {
+	if len(data) < dhcpv6OptHeaderSize || len(data) < dhcpv6OptHeaderSize+int(binary.BigEndian.Uint16(data[2:])) ||
+		int(binary.BigEndian.Uint16(data[2:])) < minLen {
+		return errors.New("The []byte is too short to unmarshal a full DHCPv6 option.")
+	}
+	return nil
+}
+
+// DHCPv6OptionGeneric is an option which is not decoded further.
+type DHCPv6OptionGeneric struct {
+	Code uint16
+	Data []byte
+}
+
+func NewDHCPv6Option(code uint16, data []byte) *DHCPv6OptionGeneric {
+	return &DHCPv6OptionGeneric{Code: code, Data: data}
+}
+
+// NewDHCPv6OptionRequest returns an Option Request option with the given
+// option codes.
+func NewDHCPv6OptionRequest(codes ...uint16) *DHCPv6OptionGeneric {
+	data := make([]byte, 2*len(codes))
+	for i, c := range codes {
+		binary.BigEndian.PutUint16(data[2*i:], c)
+	}
+	return NewDHCPv6Option(DHCPv6_OPT_ORO, data)
+}
+
+// NewDHCPv6ElapsedTime returns an Elapsed Time option, in hundredths of a
+// second.
+func NewDHCPv6ElapsedTime(elapsed uint16) *DHCPv6OptionGeneric {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, elapsed)
+	return NewDHCPv6Option(DHCPv6_OPT_ELAPSED_TIME, data)
+}
+
+func NewDHCPv6RapidCommit() *DHCPv6OptionGeneric {
+	return NewDHCPv6Option(DHCPv6_OPT_RAPID_COMMIT, []byte{})
+}
+
+func (o *DHCPv6OptionGeneric) OptionCode() uint16 {
+	return o.Code
+}
+
+func (o *DHCPv6OptionGeneric) Len() uint16 {
+	return uint16(dhcpv6OptHeaderSize + len(o.Data))
+}
+
+func (o *DHCPv6OptionGeneric) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, o.Code)
+	copy(data[dhcpv6OptHeaderSize:], o.Data)
+	return
+}
+
+func (o *DHCPv6OptionGeneric) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 0); err != nil {
+		return err
+	}
+	o.Code = binary.BigEndian.Uint16(data)
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	o.Data = make([]byte, length)
+	copy(o.Data, data[dhcpv6OptHeaderSize:dhcpv6OptHeaderSize+length])
+	return nil
+}
+
+// DHCPv6DUID is a DHCP Unique Identifier. HardwareType and LinkLayerAddr are
+// used by DUID-LLT and DUID-LL, Time by DUID-LLT, EnterpriseNumber by DUID-EN.
+// Identifier holds the identifier of DUID-EN, the UUID of DUID-UUID, or the
+// content of a DUID of unknown type.
+type DHCPv6DUID struct {
+	Type             uint16
+	HardwareType     uint16
+	Time             uint32
+	EnterpriseNumber uint32
+	LinkLayerAddr    net.HardwareAddr
+	Identifier       []byte
+}
+
+func NewDHCPv6DUIDLL(hwAddr net.HardwareAddr) *DHCPv6DUID {
+	return &DHCPv6DUID{Type: DHCPv6_DUID_LL, HardwareType: uint16(DHCP_HW_ETHERNET), LinkLayerAddr: hwAddr}
+}
+
+func NewDHCPv6DUIDLLT(hwAddr net.HardwareAddr, time uint32) *DHCPv6DUID {
+	return &DHCPv6DUID{Type: DHCPv6_DUID_LLT, HardwareType: uint16(DHCP_HW_ETHERNET), Time: time, LinkLayerAddr: hwAddr}
+}
+
+func (d *DHCPv6DUID) Len() uint16 {
+	switch d.Type {
+	case DHCPv6_DUID_LLT:
+		return uint16(8 + len(d.LinkLayerAddr))
+	case DHCPv6_DUID_LL:
+		return uint16(4 + len(d.LinkLayerAddr))
+	case DHCPv6_DUID_EN:
+		return uint16(6 + len(d.Identifier))
+	}
+	return uint16(2 + len(d.Identifier))
+}
+
+func (d *DHCPv6DUID) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(d.Len()))
+	binary.BigEndian.PutUint16(data, d.Type)
+	n := 2
+	switch d.Type {
+	case DHCPv6_DUID_LLT:
+		binary.BigEndian.PutUint16(data[n:], d.HardwareType)
+		n += 2
+		binary.BigEndian.PutUint32(data[n:], d.Time)
+		n += 4
+		copy(data[n:], d.LinkLayerAddr)
+	case DHCPv6_DUID_LL:
+		binary.BigEndian.PutUint16(data[n:], d.HardwareType)
+		n += 2
+		copy(data[n:], d.LinkLayerAddr)
+	case DHCPv6_DUID_EN:
+		binary.BigEndian.PutUint32(data[n:], d.EnterpriseNumber)
+		n += 4
+		copy(data[n:], d.Identifier)
+	default:
+		copy(data[n:], d.Identifier)
+	}
+	return
+}
+
+func (d *DHCPv6DUID) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("The []byte is too short to unmarshal a full DHCPv6DUID.")
+	}
+	d.Type = binary.BigEndian.Uint16(data)
+	n := 2
+	minLen := map[uint16]int{DHCPv6_DUID_LLT: 8, DHCPv6_DUID_LL: 4, DHCPv6_DUID_EN: 6}[d.Type]
+	if len(data) < minLen {
+		return errors.New("The []byte is too short to unmarshal a full DHCPv6DUID.")
+	}
+	switch d.Type {
+	case DHCPv6_DUID_LLT:
+		d.HardwareType = binary.BigEndian.Uint16(data[n:])
+		n += 2
+		d.Time = binary.BigEndian.Uint32(data[n:])
+		n += 4
+		d.LinkLayerAddr = make(net.HardwareAddr, len(data)-n)
+		copy(d.LinkLayerAddr, data[n:])
+	case DHCPv6_DUID_LL:
+		d.HardwareType = binary.BigEndian.Uint16(data[n:])
+		n += 2
+		d.LinkLayerAddr = make(net.HardwareAddr, len(data)-n)
+		copy(d.LinkLayerAddr, data[n:])
+	case DHCPv6_DUID_EN:
+		d.EnterpriseNumber = binary.BigEndian.Uint32(data[n:])
+		n += 4
+		d.Identifier = make([]byte, len(data)-n)
+		copy(d.Identifier, data[n:])
+	default:
+		d.Identifier = make([]byte, len(data)-n)
+		copy(d.Identifier, data[n:])
+	}
+	return nil
+}
+
+// DHCPv6DUIDOption is the Client Identifier or the Server Identifier option.
+type DHCPv6DUIDOption struct {
+	Code uint16
+	DUID DHCPv6DUID
+}
+
+func NewDHCPv6ClientID(duid *DHCPv6DUID) *DHCPv6DUIDOption {
+	return &DHCPv6DUIDOption{Code: DHCPv6_OPT_CLIENTID, DUID: *duid}
+}
+
+func NewDHCPv6ServerID(duid *DHCPv6DUID) *DHCPv6DUIDOption {
+	return &DHCPv6DUIDOption{Code: DHCPv6_OPT_SERVERID, DUID: *duid}
+}
+
+func (o *DHCPv6DUIDOption) OptionCode() uint16 {
+	return o.Code
+}
+
+func (o *DHCPv6DUIDOption) Len() uint16 {
+	return dhcpv6OptHeaderSize + o.DUID.Len()
+}
+
+func (o *DHCPv6DUIDOption) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, o.Code)
+	var b []byte
+	if b, err = o.DUID.MarshalBinary(); err != nil {
+		return
+	}
+	copy(data[dhcpv6OptHeaderSize:], b)
+	return
+}
+
+func (o *DHCPv6DUIDOption) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 2); err != nil {
+		return err
+	}
+	o.Code = binary.BigEndian.Uint16(data)
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	return o.DUID.UnmarshalBinary(data[dhcpv6OptHeaderSize : dhcpv6OptHeaderSize+length])
+}
+
+// DHCPv6IANA is the Identity Association for Non-temporary Addresses option.
+// Options usually contains DHCPv6IAAddress and DHCPv6StatusCode options.
+type DHCPv6IANA struct {
+	IAID    uint32
+	T1      uint32
+	T2      uint32
+	Options []DHCPv6Option
+}
+
+func NewDHCPv6IANA(iaid uint32, t1, t2 uint32, addresses ...*DHCPv6IAAddress) *DHCPv6IANA {
+	o := &DHCPv6IANA{IAID: iaid, T1: t1, T2: t2}
+	for _, a := range addresses {
+		o.Options = append(o.Options, a)
+	}
+	return o
+}
+
+func (o *DHCPv6IANA) OptionCode() uint16 {
+	return DHCPv6_OPT_IA_NA
+}
+
+func (o *DHCPv6IANA) Len() uint16 {
+	return dhcpv6OptHeaderSize + 12 + dhcpv6OptionsLen(o.Options)
+}
+
+func (o *DHCPv6IANA) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_IA_NA)
+	n := dhcpv6OptHeaderSize
+	binary.BigEndian.PutUint32(data[n:], o.IAID)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.T1)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.T2)
+	n += 4
+	err = marshalDHCPv6Options(data[n:], o.Options)
+	return
+}
+
+func (o *DHCPv6IANA) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 12); err != nil {
+		return err
+	}
+	end := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[2:]))
+	n := dhcpv6OptHeaderSize
+	o.IAID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.T1 = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.T2 = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	var err error
+	o.Options, err = parseDHCPv6Options(data[n:end])
+	return err
+}
+
+// Addresses returns the addresses assigned in the IA_NA option.
+func (o *DHCPv6IANA) Addresses() []*DHCPv6IAAddress {
+	var addresses []*DHCPv6IAAddress
+	for _, opt := range o.Options {
+		if a, ok := opt.(*DHCPv6IAAddress); ok {
+			addresses = append(addresses, a)
+		}
+	}
+	return addresses
+}
+
+// DHCPv6IAAddress is the IA Address option, carried in DHCPv6IANA.
+type DHCPv6IAAddress struct {
+	Address           net.IP
+	PreferredLifetime uint32
+	ValidLifetime     uint32
+	Options           []DHCPv6Option
+}
+
+func NewDHCPv6IAAddress(addr net.IP, preferred, valid uint32) *DHCPv6IAAddress {
+	return &DHCPv6IAAddress{Address: addr, PreferredLifetime: preferred, ValidLifetime: valid}
+}
+
+func (o *DHCPv6IAAddress) OptionCode() uint16 {
+	return DHCPv6_OPT_IAADDR
+}
+
+func (o *DHCPv6IAAddress) Len() uint16 {
+	return dhcpv6OptHeaderSize + 24 + dhcpv6OptionsLen(o.Options)
+}
+
+func (o *DHCPv6IAAddress) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_IAADDR)
+	n := dhcpv6OptHeaderSize
+	copy(data[n:], o.Address.To16())
+	n += 16
+	binary.BigEndian.PutUint32(data[n:], o.PreferredLifetime)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.ValidLifetime)
+	n += 4
+	err = marshalDHCPv6Options(data[n:], o.Options)
+	return
+}
+
+func (o *DHCPv6IAAddress) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 24); err != nil {
+		return err
+	}
+	end := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[2:]))
+	n := dhcpv6OptHeaderSize
+	o.Address = make(net.IP, 16)
+	copy(o.Address, data[n:n+16])
+	n += 16
+	o.PreferredLifetime = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.ValidLifetime = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	var err error
+	o.Options, err = parseDHCPv6Options(data[n:end])
+	return err
+}
+
+// DHCPv6IAPD is the Identity Association for Prefix Delegation option.
+// Options usually contains DHCPv6IAPrefix and DHCPv6StatusCode options.
+type DHCPv6IAPD struct {
+	IAID    uint32
+	T1      uint32
+	T2      uint32
+	Options []DHCPv6Option
+}
+
+func NewDHCPv6IAPD(iaid uint32, t1, t2 uint32, prefixes ...*DHCPv6IAPrefix) *DHCPv6IAPD {
+	o := &DHCPv6IAPD{IAID: iaid, T1: t1, T2: t2}
+	for _, p := range prefixes {
+		o.Options = append(o.Options, p)
+	}
+	return o
+}
+
+func (o *DHCPv6IAPD) OptionCode() uint16 {
+	return DHCPv6_OPT_IA_PD
+}
+
+func (o *DHCPv6IAPD) Len() uint16 {
+	return dhcpv6OptHeaderSize + 12 + dhcpv6OptionsLen(o.Options)
+}
+
+func (o *DHCPv6IAPD) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_IA_PD)
+	n := dhcpv6OptHeaderSize
+	binary.BigEndian.PutUint32(data[n:], o.IAID)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.T1)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.T2)
+	n += 4
+	err = marshalDHCPv6Options(data[n:], o.Options)
+	return
+}
+
+func (o *DHCPv6IAPD) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 12); err != nil {
+		return err
+	}
+	end := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[2:]))
+	n := dhcpv6OptHeaderSize
+	o.IAID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.T1 = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.T2 = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	var err error
+	o.Options, err = parseDHCPv6Options(data[n:end])
+	return err
+}
+
+// Prefixes returns the prefixes delegated in the IA_PD option.
+func (o *DHCPv6IAPD) Prefixes() []*DHCPv6IAPrefix {
+	var prefixes []*DHCPv6IAPrefix
+	for _, opt := range o.Options {
+		if p, ok := opt.(*DHCPv6IAPrefix); ok {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+// DHCPv6IAPrefix is the IA Prefix option, carried in DHCPv6IAPD.
+type DHCPv6IAPrefix struct {
+	PreferredLifetime uint32
+	ValidLifetime     uint32
+	PrefixLength      uint8
+	Prefix            net.IP
+	Options           []DHCPv6Option
+}
+
+func NewDHCPv6IAPrefix(prefix *net.IPNet, preferred, valid uint32) *DHCPv6IAPrefix {
+	ones, _ := prefix.Mask.Size()
+	return &DHCPv6IAPrefix{
+		PreferredLifetime: preferred,
+		ValidLifetime:     valid,
+		PrefixLength:      uint8(ones),
+		Prefix:            prefix.IP,
+	}
+}
+
+func (o *DHCPv6IAPrefix) OptionCode() uint16 {
+	return DHCPv6_OPT_IAPREFIX
+}
+
+func (o *DHCPv6IAPrefix) Len() uint16 {
+	return dhcpv6OptHeaderSize + 25 + dhcpv6OptionsLen(o.Options)
+}
+
+func (o *DHCPv6IAPrefix) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_IAPREFIX)
+	n := dhcpv6OptHeaderSize
+	binary.BigEndian.PutUint32(data[n:], o.PreferredLifetime)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], o.ValidLifetime)
+	n += 4
+	data[n] = o.PrefixLength
+	n += 1
+	copy(data[n:], o.Prefix.To16())
+	n += 16
+	err = marshalDHCPv6Options(data[n:], o.Options)
+	return
+}
+
+func (o *DHCPv6IAPrefix) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 25); err != nil {
+		return err
+	}
+	end := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[2:]))
+	n := dhcpv6OptHeaderSize
+	o.PreferredLifetime = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.ValidLifetime = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	o.PrefixLength = data[n]
+	n += 1
+	o.Prefix = make(net.IP, 16)
+	copy(o.Prefix, data[n:n+16])
+	n += 16
+	var err error
+	o.Options, err = parseDHCPv6Options(data[n:end])
+	return err
+}
+
+// DHCPv6DNSServers is the DNS Recursive Name Server option (RFC 3646).
+type DHCPv6DNSServers struct {
+	Servers []net.IP
+}
+
+func NewDHCPv6DNSServers(servers ...net.IP) *DHCPv6DNSServers {
+	return &DHCPv6DNSServers{Servers: servers}
+}
+
+func (o *DHCPv6DNSServers) OptionCode() uint16 {
+	return DHCPv6_OPT_DNS_SERVERS
+}
+
+func (o *DHCPv6DNSServers) Len() uint16 {
+	return uint16(dhcpv6OptHeaderSize + 16*len(o.Servers))
+}
+
+func (o *DHCPv6DNSServers) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_DNS_SERVERS)
+	n := dhcpv6OptHeaderSize
+	for _, s := range o.Servers {
+		copy(data[n:], s.To16())
+		n += 16
+	}
+	return
+}
+
+func (o *DHCPv6DNSServers) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 0); err != nil {
+		return err
+	}
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	if length%16 != 0 {
+		return errors.New("Invalid length of DHCPv6 DNS servers option.")
+	}
+	o.Servers = nil
+	for n := dhcpv6OptHeaderSize; n < dhcpv6OptHeaderSize+length; n += 16 {
+		ip := make(net.IP, 16)
+		copy(ip, data[n:n+16])
+		o.Servers = append(o.Servers, ip)
+	}
+	return nil
+}
+
+// DHCPv6StatusCode is the Status Code option.
+type DHCPv6StatusCode struct {
+	StatusCode uint16
+	Message    string
+}
+
+func NewDHCPv6StatusCode(code uint16, message string) *DHCPv6StatusCode {
+	return &DHCPv6StatusCode{StatusCode: code, Message: message}
+}
+
+func (o *DHCPv6StatusCode) OptionCode() uint16 {
+	return DHCPv6_OPT_STATUS_CODE
+}
+
+func (o *DHCPv6StatusCode) Len() uint16 {
+	return uint16(dhcpv6OptHeaderSize + 2 + len(o.Message))
+}
+
+func (o *DHCPv6StatusCode) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	putDHCPv6OptionHeader(data, DHCPv6_OPT_STATUS_CODE)
+	binary.BigEndian.PutUint16(data[dhcpv6OptHeaderSize:], o.StatusCode)
+	copy(data[dhcpv6OptHeaderSize+2:], o.Message)
+	return
+}
+
+func (o *DHCPv6StatusCode) UnmarshalBinary(data []byte) error {
+	if err := checkDHCPv6Option(data, 2); err != nil {
+		return err
+	}
+	end := dhcpv6OptHeaderSize + int(binary.BigEndian.Uint16(data[2:]))
+	o.StatusCode = binary.BigEndian.Uint16(data[dhcpv6OptHeaderSize:])
+	o.Message = string(data[dhcpv6OptHeaderSize+2 : end])
+	return nil
+}
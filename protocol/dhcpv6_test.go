@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDHCPv6(t *testing.T) {
+	hwAddr, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	clientID := NewDHCPv6DUIDLLT(hwAddr, 0x12345678)
+	serverID := &DHCPv6DUID{Type: DHCPv6_DUID_EN, EnterpriseNumber: 6876, Identifier: []byte{0x01, 0x02}}
+	_, prefix, _ := net.ParseCIDR("2001:db8:1::/48")
+
+	reply, err := NewDHCPv6Reply(0xabcdef, clientID, serverID)
+	require.NoError(t, err)
+	reply.Options = append(reply.Options,
+		NewDHCPv6IANA(1, 3600, 5400, NewDHCPv6IAAddress(net.ParseIP("2001:db8::10"), 7200, 10800)),
+		NewDHCPv6IAPD(2, 3600, 5400, NewDHCPv6IAPrefix(prefix, 7200, 10800)),
+		NewDHCPv6DNSServers(net.ParseIP("2001:db8::53"), net.ParseIP("2001:db8::54")),
+		NewDHCPv6StatusCode(DHCPv6_STATUS_SUCCESS, "ok"),
+		NewDHCPv6RapidCommit(),
+	)
+
+	udp := NewUDP()
+	udp.PortSrc = DHCPv6_SERVER_PORT
+	udp.PortDst = DHCPv6_CLIENT_PORT
+	udp.Payload = reply
+	data, err := udp.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+
+	newUDP := NewUDP()
+	require.NoError(t, newUDP.UnmarshalBinary(data), "Failed to Unmarshal message")
+	msg, ok := newUDP.Payload.(*DHCPv6)
+	require.True(t, ok, "UDP payload is not DHCPv6")
+	assert.Equal(t, DHCPv6_MSG_REPLY, msg.MessageType)
+	assert.Equal(t, uint32(0xabcdef), msg.TransactionID)
+	assert.Equal(t, clientID, msg.ClientID())
+	assert.Equal(t, serverID, msg.ServerID())
+
+	iana, ok := msg.GetOption(DHCPv6_OPT_IA_NA).(*DHCPv6IANA)
+	require.True(t, ok)
+	assert.Equal(t, uint32(5400), iana.T2)
+	require.Len(t, iana.Addresses(), 1)
+	assert.Equal(t, "2001:db8::10", iana.Addresses()[0].Address.String())
+	assert.Equal(t, uint32(10800), iana.Addresses()[0].ValidLifetime)
+
+	iapd, ok := msg.GetOption(DHCPv6_OPT_IA_PD).(*DHCPv6IAPD)
+	require.True(t, ok)
+	require.Len(t, iapd.Prefixes(), 1)
+	assert.Equal(t, uint8(48), iapd.Prefixes()[0].PrefixLength)
+	assert.Equal(t, "2001:db8:1::", iapd.Prefixes()[0].Prefix.String())
+
+	dns, ok := msg.GetOption(DHCPv6_OPT_DNS_SERVERS).(*DHCPv6DNSServers)
+	require.True(t, ok)
+	require.Len(t, dns.Servers, 2)
+	assert.Equal(t, "2001:db8::54", dns.Servers[1].String())
+
+	status, ok := msg.GetOption(DHCPv6_OPT_STATUS_CODE).(*DHCPv6StatusCode)
+	require.True(t, ok)
+	assert.Equal(t, "ok", status.Message)
+	assert.NotNil(t, msg.GetOption(DHCPv6_OPT_RAPID_COMMIT))
+
+	newData, err := newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+}
+
+func TestDHCPv6Solicit(t *testing.T) {
+	hwAddr, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	solicit, err := NewDHCPv6Solicit(0, NewDHCPv6DUIDLL(hwAddr))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, solicit.TransactionID, uint32(0xffffff))
+	solicit.Options = append(solicit.Options,
+		NewDHCPv6ElapsedTime(0),
+		NewDHCPv6OptionRequest(DHCPv6_OPT_DNS_SERVERS, DHCPv6_OPT_DOMAIN_LIST),
+		NewDHCPv6IANA(1, 0, 0),
+	)
+	data, err := solicit.MarshalBinary()
+	require.NoError(t, err)
+	msg := new(DHCPv6)
+	require.NoError(t, msg.UnmarshalBinary(data))
+	assert.Equal(t, DHCPv6_MSG_SOLICIT, msg.MessageType)
+	assert.Equal(t, hwAddr, msg.ClientID().LinkLayerAddr)
+	assert.Nil(t, msg.ServerID())
+	oro, ok := msg.GetOption(DHCPv6_OPT_ORO).(*DHCPv6OptionGeneric)
+	require.True(t, ok)
+	assert.Equal(t, []byte{0x00, 0x17, 0x00, 0x18}, oro.Data)
+
+	// Relay messages are kept as raw bytes in the UDP payload.
+	assert.Error(t, new(DHCPv6).UnmarshalBinary([]byte{DHCPv6_MSG_RELAY_FORW, 0x00}))
+}
@@ -0,0 +1,574 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DNS resource record types.
+const (
+	DNS_TYPE_A     uint16 = 1
+	DNS_TYPE_NS    uint16 = 2
+	DNS_TYPE_MD    uint16 = 3
+	DNS_TYPE_MF    uint16 = 4
+	DNS_TYPE_CNAME uint16 = 5
+	DNS_TYPE_SOA   uint16 = 6
+	DNS_TYPE_MB    uint16 = 7
+	DNS_TYPE_MG    uint16 = 8
+	DNS_TYPE_MR    uint16 = 9
+	DNS_TYPE_PTR   uint16 = 12
+	DNS_TYPE_MINFO uint16 = 14
+	DNS_TYPE_MX    uint16 = 15
+	DNS_TYPE_TXT   uint16 = 16
+	DNS_TYPE_AAAA  uint16 = 28
+	DNS_TYPE_SRV   uint16 = 33
+	DNS_TYPE_OPT   uint16 = 41
+	DNS_TYPE_ANY   uint16 = 255
+)
+
+const DNS_CLASS_IN uint16 = 1
+
+// DNS opcodes.
+const (
+	DNS_OPCODE_QUERY  uint8 = 0
+	DNS_OPCODE_STATUS uint8 = 2
+	DNS_OPCODE_NOTIFY uint8 = 4
+	DNS_OPCODE_UPDATE uint8 = 5
+)
+
+// DNS response codes.
+const (
+	DNS_RCODE_NOERROR  uint8 = 0
+	DNS_RCODE_FORMERR  uint8 = 1
+	DNS_RCODE_SERVFAIL uint8 = 2
+	DNS_RCODE_NXDOMAIN uint8 = 3
+	DNS_RCODE_NOTIMP   uint8 = 4
+	DNS_RCODE_REFUSED  uint8 = 5
+)
+
+const (
+	dnsHeaderLen = 12
+	// dnsMaxPointers limits the number of compression pointers followed when
+	// decoding a name, to protect against pointer loops.
+	dnsMaxPointers = 16
+	dnsMaxNameLen  = 255
+	dnsMaxLabelLen = 63
+)
+
+// DNS is a DNS message (RFC 1035). Names are stored without the trailing dot,
+// the root name is the empty string. Names are compressed when the message is
+// marshaled, except in SRV targets (RFC 2782). A decoded message is marshaled
+// as the original bytes as long as its fields are unchanged.
+//
+//	 0  1  2  3  4  5  6  7  8  9  0  1  2  3  4  5
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                      ID                       |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|QR|   Opcode  |AA|TC|RD|RA|   Z    |   RCODE   |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                    QDCOUNT                    |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                    ANCOUNT                    |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                    NSCOUNT                    |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                    ARCOUNT                    |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type DNS struct {
+	ID                 uint16
+	Response           bool
+	Opcode             uint8 // 4-bits
+	Authoritative      bool
+	Truncated          bool
+	RecursionDesired   bool
+	RecursionAvailable bool
+	Z                  uint8 // 3-bits, including the AD and CD bits of DNSSEC
+	ResponseCode       uint8 // 4-bits
+
+	Questions   []DNSQuestion
+	Answers     []DNSResourceRecord
+	Authorities []DNSResourceRecord
+	Additionals []DNSResourceRecord
+
+	// raw is the decoded message, and encoded is the encoding of the fields
+	// decoded from raw, which tells whether the fields have been changed since.
+	raw     []byte
+	encoded []byte
+}
+
+type DNSQuestion struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+type DNSSRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// DNSResourceRecord is a DNS resource record. The RDATA is decoded into the
+// field matching Type: IP for A and AAAA, CNAME, PTR, TXT and SRV. For other
+// types, the RDATA is kept as raw bytes in Data, where the names of the RFC 1035
+// types, e.g. NS, SOA and MX, are decompressed so that Data doesn't depend on
+// the offsets in the message.
+type DNSResourceRecord struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	TTL   uint32
+
+	IP    net.IP
+	CNAME string
+	PTR   string
+	TXT   []string
+	SRV   DNSSRV
+	Data  []byte
+}
+
+// NewDNSQuery returns a query with the RD bit set for a single question of class
+// IN.
+func NewDNSQuery(id uint16, name string, qtype uint16) *DNS {
+	return &DNS{
+		ID:               id,
+		RecursionDesired: true,
+		Questions:        []DNSQuestion{{Name: name, Type: qtype, Class: DNS_CLASS_IN}},
+	}
+}
+
+// NewDNSResponse returns an empty response to the query, with the same ID,
+// opcode, questions and RD bit.
+func NewDNSResponse(query *DNS, rcode uint8) *DNS {
+	questions := make([]DNSQuestion, len(query.Questions))
+	copy(questions, query.Questions)
+	return &DNS{
+		ID:                 query.ID,
+		Response:           true,
+		Opcode:             query.Opcode,
+		RecursionDesired:   query.RecursionDesired,
+		RecursionAvailable: true,
+		ResponseCode:       rcode,
+		Questions:          questions,
+	}
+}
+
+func NewDNSRecordA(name string, ttl uint32, ip net.IP) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_A, Class: DNS_CLASS_IN, TTL: ttl, IP: ip}
+}
+
+func NewDNSRecordAAAA(name string, ttl uint32, ip net.IP) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_AAAA, Class: DNS_CLASS_IN, TTL: ttl, IP: ip}
+}
+
+func NewDNSRecordCNAME(name string, ttl uint32, cname string) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_CNAME, Class: DNS_CLASS_IN, TTL: ttl, CNAME: cname}
+}
+
+func NewDNSRecordPTR(name string, ttl uint32, ptr string) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_PTR, Class: DNS_CLASS_IN, TTL: ttl, PTR: ptr}
+}
+
+func NewDNSRecordTXT(name string, ttl uint32, txt ...string) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_TXT, Class: DNS_CLASS_IN, TTL: ttl, TXT: txt}
+}
+
+func NewDNSRecordSRV(name string, ttl uint32, srv DNSSRV) DNSResourceRecord {
+	return DNSResourceRecord{Name: name, Type: DNS_TYPE_SRV, Class: DNS_CLASS_IN, TTL: ttl, SRV: srv}
+}
+
+// Len returns the length of the marshaled message, which depends on name
+// compression.
+func (d *DNS) Len() (n uint16) {
+	data, err := d.MarshalBinary()
+	if err != nil {
+		return 0
+	}
+	return uint16(len(data))
+}
+
+func (d *DNS) MarshalBinary() (data []byte, err error) {
+	if data, err = d.encode(); err != nil {
+		return nil, err
+	}
+	if d.raw != nil && bytes.Equal(data, d.encoded) {
+		return append([]byte(nil), d.raw...), nil
+	}
+	return data, nil
+}
+
+func (d *DNS) encode() (data []byte, err error) {
+	e := &dnsEncoder{
+		data:  make([]byte, dnsHeaderLen, 512),
+		names: make(map[string]int),
+	}
+	binary.BigEndian.PutUint16(e.data[0:], d.ID)
+	var flags uint16
+	if d.Response {
+		flags |= 1 << 15
+	}
+	flags |= uint16(d.Opcode&0x0f) << 11
+	if d.Authoritative {
+		flags |= 1 << 10
+	}
+	if d.Truncated {
+		flags |= 1 << 9
+	}
+	if d.RecursionDesired {
+		flags |= 1 << 8
+	}
+	if d.RecursionAvailable {
+		flags |= 1 << 7
+	}
+	flags |= uint16(d.Z&0x07) << 4
+	flags |= uint16(d.ResponseCode & 0x0f)
+	binary.BigEndian.PutUint16(e.data[2:], flags)
+	binary.BigEndian.PutUint16(e.data[4:], uint16(len(d.Questions)))
+	binary.BigEndian.PutUint16(e.data[6:], uint16(len(d.Answers)))
+	binary.BigEndian.PutUint16(e.data[8:], uint16(len(d.Authorities)))
+	binary.BigEndian.PutUint16(e.data[10:], uint16(len(d.Additionals)))
+
+	for _, q := range d.Questions {
+		if err = e.writeName(q.Name, true); err != nil {
+			return nil, err
+		}
+		e.writeUint16(q.Type)
+		e.writeUint16(q.Class)
+	}
+	for _, section := range [][]DNSResourceRecord{d.Answers, d.Authorities, d.Additionals} {
+		for i := range section {
+			if err = e.writeRecord(&section[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e.data, nil
+}
+
+func (d *DNS) UnmarshalBinary(data []byte) error {
+	if len(data) < dnsHeaderLen {
+		return errors.New("The []byte is too short to unmarshal a full DNS message.")
+	}
+	d.raw = nil
+	d.encoded = nil
+	d.ID = binary.BigEndian.Uint16(data[0:])
+	flags := binary.BigEndian.Uint16(data[2:])
+	d.Response = flags&(1<<15) != 0
+	d.Opcode = uint8(flags>>11) & 0x0f
+	d.Authoritative = flags&(1<<10) != 0
+	d.Truncated = flags&(1<<9) != 0
+	d.RecursionDesired = flags&(1<<8) != 0
+	d.RecursionAvailable = flags&(1<<7) != 0
+	d.Z = uint8(flags>>4) & 0x07
+	d.ResponseCode = uint8(flags) & 0x0f
+	qdCount := int(binary.BigEndian.Uint16(data[4:]))
+	anCount := int(binary.BigEndian.Uint16(data[6:]))
+	nsCount := int(binary.BigEndian.Uint16(data[8:]))
+	arCount := int(binary.BigEndian.Uint16(data[10:]))
+
+	n := dnsHeaderLen
+	d.Questions = nil
+	for i := 0; i < qdCount; i++ {
+		name, next, err := readDNSName(data, n)
+		if err != nil {
+			return err
+		}
+		n = next
+		if len(data) < n+4 {
+			return errors.New("The []byte is too short to unmarshal a full DNS question.")
+		}
+		d.Questions = append(d.Questions, DNSQuestion{
+			Name:  name,
+			Type:  binary.BigEndian.Uint16(data[n:]),
+			Class: binary.BigEndian.Uint16(data[n+2:]),
+		})
+		n += 4
+	}
+	var err error
+	if d.Answers, n, err = readDNSRecords(data, n, anCount); err != nil {
+		return err
+	}
+	if d.Authorities, n, err = readDNSRecords(data, n, nsCount); err != nil {
+		return err
+	}
+	if d.Additionals, _, err = readDNSRecords(data, n, arCount); err != nil {
+		return err
+	}
+	// A message which cannot be encoded again is only marshaled if its fields
+	// are changed.
+	if d.encoded, err = d.encode(); err == nil {
+		d.raw = append([]byte(nil), data...)
+	}
+	return nil
+}
+
+type dnsEncoder struct {
+	data []byte
+	// names maps the names already written to their offsets in data, to be
+	// used as compression pointers.
+	names map[string]int
+}
+
+func (e *dnsEncoder) writeUint16(v uint16) {
+	e.data = binary.BigEndian.AppendUint16(e.data, v)
+}
+
+func (e *dnsEncoder) writeUint32(v uint32) {
+	e.data = binary.BigEndian.AppendUint32(e.data, v)
+}
+
+func (e *dnsEncoder) writeName(name string, compress bool) error {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > dnsMaxNameLen-2 {
+		return fmt.Errorf("DNS name %s is too long", name)
+	}
+	for name != "" {
+		key := strings.ToLower(name)
+		if offset, ok := e.names[key]; ok && compress {
+			e.writeUint16(0xc000 | uint16(offset))
+			return nil
+		}
+		if compress && len(e.data) < 0x3fff {
+			e.names[key] = len(e.data)
+		}
+		label := name
+		rest := ""
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			label, rest = name[:i], name[i+1:]
+		}
+		if len(label) == 0 || len(label) > dnsMaxLabelLen {
+			return fmt.Errorf("invalid label in DNS name %s", name)
+		}
+		e.data = append(e.data, byte(len(label)))
+		e.data = append(e.data, label...)
+		name = rest
+	}
+	e.data = append(e.data, 0)
+	return nil
+}
+
+func (e *dnsEncoder) writeRecord(rr *DNSResourceRecord) error {
+	if err := e.writeName(rr.Name, true); err != nil {
+		return err
+	}
+	e.writeUint16(rr.Type)
+	e.writeUint16(rr.Class)
+	e.writeUint32(rr.TTL)
+	lenOffset := len(e.data)
+	e.writeUint16(0)
+	switch rr.Type {
+	case DNS_TYPE_A:
+		ip := rr.IP.To4()
+		if ip == nil {
+			return fmt.Errorf("invalid IPv4 address %s in A record", rr.IP)
+		}
+		e.data = append(e.data, ip...)
+	case DNS_TYPE_AAAA:
+		ip := rr.IP.To16()
+		if ip == nil {
+			return fmt.Errorf("invalid IPv6 address %s in AAAA record", rr.IP)
+		}
+		e.data = append(e.data, ip...)
+	case DNS_TYPE_CNAME:
+		if err := e.writeName(rr.CNAME, true); err != nil {
+			return err
+		}
+	case DNS_TYPE_PTR:
+		if err := e.writeName(rr.PTR, true); err != nil {
+			return err
+		}
+	case DNS_TYPE_TXT:
+		for _, txt := range rr.TXT {
+			if len(txt) > 255 {
+				return errors.New("TXT string is longer than 255 bytes")
+			}
+			e.data = append(e.data, byte(len(txt)))
+			e.data = append(e.data, txt...)
+		}
+	case DNS_TYPE_SRV:
+		e.writeUint16(rr.SRV.Priority)
+		e.writeUint16(rr.SRV.Weight)
+		e.writeUint16(rr.SRV.Port)
+		if err := e.writeName(rr.SRV.Target, false); err != nil {
+			return err
+		}
+	default:
+		e.data = append(e.data, rr.Data...)
+	}
+	binary.BigEndian.PutUint16(e.data[lenOffset:], uint16(len(e.data)-lenOffset-2))
+	return nil
+}
+
+// readDNSName decodes the possibly compressed name at offset and returns it
+// with the offset following the name.
+func readDNSName(data []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	length := 0
+	for pointers := 0; ; {
+		if offset >= len(data) {
+			return "", 0, errors.New("The []byte is too short to unmarshal a full DNS name.")
+		}
+		c := int(data[offset])
+		switch c & 0xc0 {
+		case 0x00:
+			if c == 0 {
+				if next < 0 {
+					next = offset + 1
+				}
+				return strings.Join(labels, "."), next, nil
+			}
+			if offset+1+c > len(data) {
+				return "", 0, errors.New("The []byte is too short to unmarshal a full DNS label.")
+			}
+			length += c + 1
+			if length > dnsMaxNameLen {
+				return "", 0, errors.New("DNS name is too long")
+			}
+			labels = append(labels, string(data[offset+1:offset+1+c]))
+			offset += 1 + c
+		case 0xc0:
+			if offset+2 > len(data) {
+				return "", 0, errors.New("The []byte is too short to unmarshal a DNS compression pointer.")
+			}
+			pointers++
+			if pointers > dnsMaxPointers {
+				return "", 0, errors.New("too many DNS compression pointers")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(data[offset:]) & 0x3fff)
+		default:
+			return "", 0, fmt.Errorf("unsupported DNS label type 0x%x", c&0xc0)
+		}
+	}
+}
+
+func readDNSRecords(data []byte, offset int, count int) ([]DNSResourceRecord, int, error) {
+	var records []DNSResourceRecord
+	for i := 0; i < count; i++ {
+		var rr DNSResourceRecord
+		var err error
+		if rr.Name, offset, err = readDNSName(data, offset); err != nil {
+			return nil, 0, err
+		}
+		if len(data) < offset+10 {
+			return nil, 0, errors.New("The []byte is too short to unmarshal a full DNS resource record.")
+		}
+		rr.Type = binary.BigEndian.Uint16(data[offset:])
+		rr.Class = binary.BigEndian.Uint16(data[offset+2:])
+		rr.TTL = binary.BigEndian.Uint32(data[offset+4:])
+		rdLength := int(binary.BigEndian.Uint16(data[offset+8:]))
+		offset += 10
+		end := offset + rdLength
+		if len(data) < end {
+			return nil, 0, errors.New("The []byte is too short to unmarshal a full DNS resource record.")
+		}
+		if err := rr.unmarshalRData(data, offset, end); err != nil {
+			return nil, 0, err
+		}
+		records = append(records, rr)
+		offset = end
+	}
+	return records, offset, nil
+}
+
+// unmarshalRData decodes data[offset:end]. The whole message is needed to
+// decompress names.
+func (rr *DNSResourceRecord) unmarshalRData(data []byte, offset, end int) error {
+	rdata := data[offset:end]
+	var err error
+	switch rr.Type {
+	case DNS_TYPE_A:
+		if len(rdata) != net.IPv4len {
+			return errors.New("invalid length of A record")
+		}
+		rr.IP = make(net.IP, net.IPv4len)
+		copy(rr.IP, rdata)
+	case DNS_TYPE_AAAA:
+		if len(rdata) != net.IPv6len {
+			return errors.New("invalid length of AAAA record")
+		}
+		rr.IP = make(net.IP, net.IPv6len)
+		copy(rr.IP, rdata)
+	case DNS_TYPE_CNAME:
+		rr.CNAME, _, err = readDNSName(data[:end], offset)
+	case DNS_TYPE_PTR:
+		rr.PTR, _, err = readDNSName(data[:end], offset)
+	case DNS_TYPE_TXT:
+		for n := 0; n < len(rdata); {
+			l := int(rdata[n])
+			if n+1+l > len(rdata) {
+				return errors.New("invalid length of TXT record")
+			}
+			rr.TXT = append(rr.TXT, string(rdata[n+1:n+1+l]))
+			n += 1 + l
+		}
+	case DNS_TYPE_SRV:
+		if len(rdata) < 7 {
+			return errors.New("invalid length of SRV record")
+		}
+		rr.SRV.Priority = binary.BigEndian.Uint16(rdata[0:])
+		rr.SRV.Weight = binary.BigEndian.Uint16(rdata[2:])
+		rr.SRV.Port = binary.BigEndian.Uint16(rdata[4:])
+		rr.SRV.Target, _, err = readDNSName(data[:end], offset+6)
+	default:
+		if layout, ok := dnsRDataLayouts[rr.Type]; ok {
+			rr.Data, err = expandDNSRData(data[:end], offset, layout)
+			break
+		}
+		rr.Data = make([]byte, len(rdata))
+		copy(rr.Data, rdata)
+	}
+	return err
+}
+
+// dnsRDataLayouts describes the RDATA of the RFC 1035 types which contain
+// possibly compressed names, other than CNAME and PTR. Each field is either a
+// name, as 0, or the length of a fixed size field.
+var dnsRDataLayouts = map[uint16][]int{
+	DNS_TYPE_NS:    {0},
+	DNS_TYPE_MD:    {0},
+	DNS_TYPE_MF:    {0},
+	DNS_TYPE_MB:    {0},
+	DNS_TYPE_MG:    {0},
+	DNS_TYPE_MR:    {0},
+	DNS_TYPE_MINFO: {0, 0},
+	DNS_TYPE_MX:    {2, 0},
+	DNS_TYPE_SOA:   {0, 0, 20},
+}
+
+// expandDNSRData returns the RDATA at offset with the names in the layout
+// decompressed. data must end at the end of the RDATA.
+func expandDNSRData(data []byte, offset int, layout []int) ([]byte, error) {
+	e := &dnsEncoder{data: make([]byte, 0, len(data)-offset)}
+	n := offset
+	for _, field := range layout {
+		if field == 0 {
+			name, next, err := readDNSName(data, n)
+			if err != nil {
+				return nil, err
+			}
+			if err = e.writeName(name, false); err != nil {
+				return nil, err
+			}
+			n = next
+			continue
+		}
+		if n+field > len(data) {
+			return nil, errors.New("The []byte is too short to unmarshal a full DNS resource record.")
+		}
+		e.data = append(e.data, data[n:n+field]...)
+		n += field
+	}
+	if n != len(data) {
+		return nil, errors.New("invalid length of DNS resource record")
+	}
+	return e.data, nil
+}
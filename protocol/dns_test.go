@@ -0,0 +1,130 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNS(t *testing.T) {
+	query := NewDNSQuery(0x1234, "www.example.com", DNS_TYPE_A)
+	response := NewDNSResponse(query, DNS_RCODE_NOERROR)
+	response.Answers = []DNSResourceRecord{
+		NewDNSRecordCNAME("www.example.com", 300, "web.example.com"),
+		NewDNSRecordA("web.example.com", 60, net.ParseIP("192.0.2.1")),
+		NewDNSRecordAAAA("web.example.com", 60, net.ParseIP("2001:db8::1")),
+	}
+	response.Authorities = []DNSResourceRecord{
+		NewDNSRecordPTR("1.2.0.192.in-addr.arpa", 3600, "web.example.com."),
+	}
+	response.Additionals = []DNSResourceRecord{
+		NewDNSRecordTXT("example.com", 120, "v=spf1 -all", "hello"),
+		NewDNSRecordSRV("_http._tcp.example.com", 30, DNSSRV{Priority: 10, Weight: 5, Port: 80, Target: "web.example.com"}),
+		{Name: "example.com", Type: DNS_TYPE_MX, Class: DNS_CLASS_IN, TTL: 10, Data: []byte{0x00, 0x0a, 0x00}},
+	}
+
+	udp := NewUDP()
+	udp.PortSrc = DNS_PORT
+	udp.PortDst = 33333
+	udp.Payload = response
+	data, err := udp.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	// The answer name must be compressed as a pointer to the question name.
+	assert.Equal(t, []byte{0xc0, 0x0c}, data[8+33:8+35])
+
+	newUDP := NewUDP()
+	require.NoError(t, newUDP.UnmarshalBinary(data), "Failed to Unmarshal message")
+	msg, ok := newUDP.Payload.(*DNS)
+	require.True(t, ok, "UDP payload is not DNS")
+	assert.Equal(t, uint16(0x1234), msg.ID)
+	assert.True(t, msg.Response)
+	assert.True(t, msg.RecursionDesired)
+	assert.Equal(t, DNS_RCODE_NOERROR, msg.ResponseCode)
+	assert.Equal(t, query.Questions, msg.Questions)
+	require.Len(t, msg.Answers, 3)
+	assert.Equal(t, "web.example.com", msg.Answers[0].CNAME)
+	assert.Equal(t, "192.0.2.1", msg.Answers[1].IP.String())
+	assert.Equal(t, "2001:db8::1", msg.Answers[2].IP.String())
+	require.Len(t, msg.Authorities, 1)
+	assert.Equal(t, "web.example.com", msg.Authorities[0].PTR)
+	require.Len(t, msg.Additionals, 3)
+	assert.Equal(t, []string{"v=spf1 -all", "hello"}, msg.Additionals[0].TXT)
+	assert.Equal(t, response.Additionals[1].SRV, msg.Additionals[1].SRV)
+	assert.Equal(t, response.Additionals[2], msg.Additionals[2])
+
+	newData, err := newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+	assert.Equal(t, int(udp.Len()), len(data))
+}
+
+func TestDNSNameDecoding(t *testing.T) {
+	// A pointer loop must not hang the decoder.
+	loop := []byte{
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xc0, 0x0c, 0x00, 0x01, 0x00, 0x01,
+	}
+	assert.Error(t, new(DNS).UnmarshalBinary(loop))
+
+	query := NewDNSQuery(1, "a.b", DNS_TYPE_ANY)
+	data, err := query.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 'a', 0x01, 'b', 0x00}, data[12:17])
+	assert.Error(t, new(DNS).UnmarshalBinary(data[:15]))
+
+	_, err = NewDNSQuery(1, "a..b", DNS_TYPE_A).MarshalBinary()
+	assert.Error(t, err)
+}
+
+func TestDNSRemarshal(t *testing.T) {
+	name := []byte{0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'c', 'o', 'm', 0x00}
+	raw := []byte{0x00, 0x01, 0x81, 0x80, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+	// Question at offset 12.
+	raw = append(raw, name...)
+	raw = append(raw, 0x00, 0x0f, 0x00, 0x01)
+	// The MX answer doesn't compress its name, the exchange is compressed.
+	raw = append(raw, name...)
+	raw = append(raw, 0x00, 0x0f, 0x00, 0x01, 0x00, 0x00, 0x01, 0x2c, 0x00, 0x09)
+	raw = append(raw, 0x00, 0x0a, 0x04, 'm', 'a', 'i', 'l', 0xc0, 0x0c)
+	// The NS authority with a compressed name server.
+	raw = append(raw, 0xc0, 0x0c, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x01, 0x2c, 0x00, 0x06)
+	raw = append(raw, 0x03, 'n', 's', '1', 0xc0, 0x0c)
+
+	udp := NewUDP()
+	udp.PortSrc = DNS_PORT
+	udp.PortDst = 33333
+	udp.Data = raw
+	data, err := udp.MarshalBinary()
+	require.NoError(t, err)
+	newUDP := NewUDP()
+	require.NoError(t, newUDP.UnmarshalBinary(data))
+	msg, ok := newUDP.Payload.(*DNS)
+	require.True(t, ok, "UDP payload is not DNS")
+	require.Len(t, msg.Answers, 1)
+	require.Len(t, msg.Authorities, 1)
+	mx := append([]byte{0x00, 0x0a, 0x04, 'm', 'a', 'i', 'l'}, name...)
+	ns := append([]byte{0x03, 'n', 's', '1'}, name...)
+	assert.Equal(t, mx, msg.Answers[0].Data)
+	assert.Equal(t, ns, msg.Authorities[0].Data)
+
+	// The unchanged message is marshaled as the original bytes.
+	newData, err := newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+
+	// The changed message is encoded again, with the length of the new bytes.
+	msg.Answers[0].TTL = 60
+	newData, err = newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.NotEqual(t, len(data), len(newData))
+	assert.Equal(t, uint16(len(newData)), binary.BigEndian.Uint16(newData[4:6]))
+	changedUDP := NewUDP()
+	require.NoError(t, changedUDP.UnmarshalBinary(newData))
+	changed := changedUDP.Payload.(*DNS)
+	assert.Equal(t, uint32(60), changed.Answers[0].TTL)
+	assert.Equal(t, mx, changed.Answers[0].Data)
+	assert.Equal(t, ns, changed.Authorities[0].Data)
+}
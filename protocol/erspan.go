@@ -0,0 +1,186 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	ERSPAN_VERSION_II  = 1
+	ERSPAN_VERSION_III = 2
+)
+
+// ERSPAN Type II:
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|  Ver  |          VLAN         | COS | En|T|    Session ID     |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|      Reserved         |                  Index                |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// ERSPAN Type III:
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|  Ver  |          VLAN         | COS |BSO|T|     Session ID    |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                          Timestamp                            |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|             SGT               |P|    FT   |   Hw ID   |D|Gra|O|
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|        Platform Specific SubHeader (8 octets, optional)       |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// The Version field selects the header layout. Index is only used by Type II,
+// while Timestamp, SGT, P, FT, HwID, D, Gra and the platform specific
+// subheader are only used by Type III. The O bit is set if PlatformSpecific
+// is not nil.
+type ERSPAN struct {
+	Version          uint8  // 4-bits
+	VLAN             uint16 // 12-bits
+	COS              uint8  // 3-bits
+	Encap            uint8  // 2-bits, En in Type II and BSO in Type III
+	Truncated        bool
+	SessionID        uint16 // 10-bits
+	Index            uint32 // 20-bits
+	Timestamp        uint32
+	SGT              uint16
+	PDU              bool
+	FrameType        uint8 // 5-bits
+	HardwareID       uint8 // 6-bits
+	Direction        uint8 // 1-bit
+	Granularity      uint8 // 2-bits
+	PlatformSpecific []byte
+	Data             util.Message
+}
+
+func NewERSPANII(sessionID uint16, index uint32) *ERSPAN {
+	return &ERSPAN{
+		Version:   ERSPAN_VERSION_II,
+		SessionID: sessionID,
+		Index:     index,
+	}
+}
+
+func NewERSPANIII(sessionID uint16, hardwareID uint8, direction uint8) *ERSPAN {
+	return &ERSPAN{
+		Version:    ERSPAN_VERSION_III,
+		SessionID:  sessionID,
+		HardwareID: hardwareID,
+		Direction:  direction,
+	}
+}
+
+func (e *ERSPAN) headerLen() uint16 {
+	if e.Version != ERSPAN_VERSION_III {
+		return 8
+	}
+	if e.PlatformSpecific != nil {
+		return 20
+	}
+	return 12
+}
+
+func (e *ERSPAN) Len() (n uint16) {
+	n = e.headerLen()
+	if e.Data != nil {
+		n += e.Data.Len()
+	}
+	return
+}
+
+func (e *ERSPAN) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(e.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], uint16(e.Version&0x0f)<<12|e.VLAN&0x0fff)
+	n += 2
+	word := uint16(e.COS&0x07)<<13 | uint16(e.Encap&0x03)<<11 | e.SessionID&0x03ff
+	if e.Truncated {
+		word |= 1 << 10
+	}
+	binary.BigEndian.PutUint16(data[n:], word)
+	n += 2
+	if e.Version != ERSPAN_VERSION_III {
+		binary.BigEndian.PutUint32(data[n:], e.Index&0x000fffff)
+		n += 4
+	} else {
+		binary.BigEndian.PutUint32(data[n:], e.Timestamp)
+		n += 4
+		binary.BigEndian.PutUint16(data[n:], e.SGT)
+		n += 2
+		word = uint16(e.FrameType&0x1f)<<10 | uint16(e.HardwareID&0x3f)<<4 |
+			uint16(e.Direction&0x01)<<3 | uint16(e.Granularity&0x03)<<1
+		if e.PDU {
+			word |= 1 << 15
+		}
+		if e.PlatformSpecific != nil {
+			word |= 1
+		}
+		binary.BigEndian.PutUint16(data[n:], word)
+		n += 2
+		if e.PlatformSpecific != nil {
+			copy(data[n:n+8], e.PlatformSpecific)
+			n += 8
+		}
+	}
+	if e.Data != nil {
+		var b []byte
+		if b, err = e.Data.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (e *ERSPAN) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("The []byte is too short to unmarshal a full ERSPAN message.")
+	}
+	n := 0
+	word := binary.BigEndian.Uint16(data[n:])
+	e.Version = uint8(word >> 12)
+	e.VLAN = word & 0x0fff
+	n += 2
+	word = binary.BigEndian.Uint16(data[n:])
+	e.COS = uint8(word >> 13)
+	e.Encap = uint8(word>>11) & 0x03
+	e.Truncated = word&(1<<10) != 0
+	e.SessionID = word & 0x03ff
+	n += 2
+	if e.Version != ERSPAN_VERSION_III {
+		e.Index = binary.BigEndian.Uint32(data[n:]) & 0x000fffff
+		n += 4
+	} else {
+		if len(data) < 12 {
+			return errors.New("The []byte is too short to unmarshal a full ERSPAN message.")
+		}
+		e.Timestamp = binary.BigEndian.Uint32(data[n:])
+		n += 4
+		e.SGT = binary.BigEndian.Uint16(data[n:])
+		n += 2
+		word = binary.BigEndian.Uint16(data[n:])
+		e.PDU = word&(1<<15) != 0
+		e.FrameType = uint8(word>>10) & 0x1f
+		e.HardwareID = uint8(word>>4) & 0x3f
+		e.Direction = uint8(word>>3) & 0x01
+		e.Granularity = uint8(word>>1) & 0x03
+		n += 2
+		e.PlatformSpecific = nil
+		if word&1 != 0 {
+			if len(data) < n+8 {
+				return errors.New("The []byte is too short to unmarshal a full ERSPAN message.")
+			}
+			e.PlatformSpecific = make([]byte, 8)
+			copy(e.PlatformSpecific, data[n:n+8])
+			n += 8
+		}
+	}
+	e.Data = new(Ethernet)
+	return e.Data.UnmarshalBinary(data[n:])
+}
@@ -20,6 +20,13 @@ const (
 	IPv6_MSG     = 0x86DD
 	STP_MSG      = 0x4242
 	STP_BPDU_MSG = 0xAAAA
+
+	// Transparent Ethernet Bridging, used by GRE/NVGRE and Geneve to carry
+	// an inner Ethernet frame.
+	TEB_MSG = 0x6558
+	// ERSPAN Type II and Type III payloads carried in GRE.
+	ERSPAN_II_MSG  = 0x88BE
+	ERSPAN_III_MSG = 0x22EB
 )
 
 type Ethernet struct {
@@ -111,17 +118,25 @@ func (e *Ethernet) UnmarshalBinary(data []byte) error {
 	}
 	n += 2
 
-	switch e.Ethertype {
+	e.Data = newMessageByEthertype(e.Ethertype)
+	return e.Data.UnmarshalBinary(data[n:])
+}
+
+// newMessageByEthertype returns an empty message which is able to decode the
+// payload identified by the given ethertype. It is shared by Ethernet and by
+// the tunnel headers (GRE, Geneve) which carry an ethertype for their payload.
+func newMessageByEthertype(ethertype uint16) util.Message {
+	switch ethertype {
 	case IPv4_MSG:
-		e.Data = new(IPv4)
+		return new(IPv4)
 	case IPv6_MSG:
-		e.Data = new(IPv6)
+		return new(IPv6)
 	case ARP_MSG:
-		e.Data = new(ARP)
-	default:
-		e.Data = new(util.Buffer)
+		return new(ARP)
+	case TEB_MSG:
+		return new(Ethernet)
 	}
-	return e.Data.UnmarshalBinary(data[n:])
+	return new(util.Buffer)
 }
 
 const (
@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	GENEVE_FLAG_OAM      = 0x80
+	GENEVE_FLAG_CRITICAL = 0x40
+)
+
+// Geneve (RFC 8926):
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|Ver|  Opt Len  |O|C|    Rsvd.  |          Protocol Type        |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|        Virtual Network Identifier (VNI)       |    Reserved   |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                                                               |
+//	~                    Variable-Length Options                    ~
+//	|                                                               |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// The Opt Len field is computed from Options when the message is marshaled.
+type Geneve struct {
+	Version      uint8 // 2-bits
+	Flags        uint8 // O and C bits
+	ProtocolType uint16
+	VNI          uint32 // 24-bits
+	Options      []*GeneveOption
+	Data         util.Message
+}
+
+func NewGeneve(vni uint32) *Geneve {
+	return &Geneve{
+		ProtocolType: TEB_MSG,
+		VNI:          vni,
+	}
+}
+
+func (g *Geneve) Len() (n uint16) {
+	n = 8
+	for _, o := range g.Options {
+		n += o.Len()
+	}
+	if g.Data != nil {
+		n += g.Data.Len()
+	}
+	return
+}
+
+func (g *Geneve) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(g.Len()))
+	var optLen uint16
+	for _, o := range g.Options {
+		optLen += o.Len()
+	}
+	n := 0
+	data[n] = g.Version<<6 | uint8(optLen/4)&0x3f
+	n += 1
+	data[n] = g.Flags & 0xc0
+	n += 1
+	binary.BigEndian.PutUint16(data[n:], g.ProtocolType)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], g.VNI<<8)
+	n += 4
+	var b []byte
+	for _, o := range g.Options {
+		if b, err = o.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	if g.Data != nil {
+		if b, err = g.Data.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (g *Geneve) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("The []byte is too short to unmarshal a full Geneve message.")
+	}
+	n := 0
+	g.Version = data[n] >> 6
+	optLen := int(data[n]&0x3f) * 4
+	n += 1
+	g.Flags = data[n] & 0xc0
+	n += 1
+	g.ProtocolType = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	g.VNI = binary.BigEndian.Uint32(data[n:]) >> 8
+	n += 4
+	if len(data) < n+optLen {
+		return errors.New("The []byte is too short to unmarshal full Geneve options.")
+	}
+	g.Options = nil
+	for n < 8+optLen {
+		o := new(GeneveOption)
+		if err := o.UnmarshalBinary(data[n : 8+optLen]); err != nil {
+			return err
+		}
+		g.Options = append(g.Options, o)
+		n += int(o.Len())
+	}
+	g.Data = newMessageByEthertype(g.ProtocolType)
+	return g.Data.UnmarshalBinary(data[n:])
+}
+
+// GetOption returns the first option with the given class and type, or nil if
+// no such option exists. The class and type are the values which are mapped to
+// a tun_metadata field with a TLVTableMod message.
+func (g *Geneve) GetOption(class uint16, optType uint8) *GeneveOption {
+	for _, o := range g.Options {
+		if o.Class == class && o.Type == optType {
+			return o
+		}
+	}
+	return nil
+}
+
+// GeneveOption is a Geneve TLV option:
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|          Option Class         |      Type     |R|R|R| Length  |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                      Variable-Length Option Data              |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// Class, Type and len(Data) correspond to OptClass, OptType and OptLength of a
+// TLVTableMap. Data is padded to a multiple of 4 bytes when marshaled.
+type GeneveOption struct {
+	Class uint16
+	Type  uint8
+	Flags uint8 // 3-bits
+	Data  []byte
+}
+
+func (o *GeneveOption) Len() uint16 {
+	return uint16(4 + (len(o.Data)+3)/4*4)
+}
+
+func (o *GeneveOption) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], o.Class)
+	n += 2
+	data[n] = o.Type
+	n += 1
+	data[n] = o.Flags<<5 | uint8((o.Len()-4)/4)&0x1f
+	n += 1
+	copy(data[n:], o.Data)
+	return
+}
+
+func (o *GeneveOption) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("The []byte is too short to unmarshal a full GeneveOption.")
+	}
+	n := 0
+	o.Class = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	o.Type = data[n]
+	n += 1
+	o.Flags = data[n] >> 5
+	length := int(data[n]&0x1f) * 4
+	n += 1
+	if len(data) < n+length {
+		return errors.New("The []byte is too short to unmarshal a full GeneveOption.")
+	}
+	o.Data = make([]byte, length)
+	copy(o.Data, data[n:n+length])
+	return nil
+}
@@ -0,0 +1,150 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	GRE_FLAG_CHECKSUM = 0x8000
+	GRE_FLAG_KEY      = 0x2000
+	GRE_FLAG_SEQ      = 0x1000
+)
+
+// GRE (RFC 2784, RFC 2890):
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|C| |K|S| Reserved0       | Ver |         Protocol Type         |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|      Checksum (optional)      |       Reserved1 (Optional)    |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                         Key (optional)                        |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                 Sequence Number (Optional)                    |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// The optional fields are present on the wire according to the C, K and S
+// bits in Flags.
+type GRE struct {
+	Flags        uint16 // C, K, S bits
+	Version      uint8  // 3-bits
+	ProtocolType uint16
+	Checksum     uint16
+	Key          uint32
+	SeqNum       uint32
+	Data         util.Message
+}
+
+func NewGRE(protocolType uint16) *GRE {
+	return &GRE{ProtocolType: protocolType}
+}
+
+// NewNVGRE returns a GRE header for NVGRE (RFC 7637), which carries an inner
+// Ethernet frame and stores the 24-bit VSID and the 8-bit FlowID in the key.
+func NewNVGRE(vsid uint32, flowID uint8) *GRE {
+	g := NewGRE(TEB_MSG)
+	g.Flags = GRE_FLAG_KEY
+	g.Key = vsid<<8 | uint32(flowID)
+	return g
+}
+
+// VSID returns the NVGRE Virtual Subnet ID stored in the key.
+func (g *GRE) VSID() uint32 {
+	return g.Key >> 8
+}
+
+// FlowID returns the NVGRE FlowID stored in the key.
+func (g *GRE) FlowID() uint8 {
+	return uint8(g.Key)
+}
+
+func (g *GRE) headerLen() uint16 {
+	n := uint16(4)
+	if g.Flags&GRE_FLAG_CHECKSUM != 0 {
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_KEY != 0 {
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_SEQ != 0 {
+		n += 4
+	}
+	return n
+}
+
+func (g *GRE) Len() (n uint16) {
+	n = g.headerLen()
+	if g.Data != nil {
+		n += g.Data.Len()
+	}
+	return
+}
+
+func (g *GRE) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(g.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], g.Flags&0xb000|uint16(g.Version&0x07))
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], g.ProtocolType)
+	n += 2
+	if g.Flags&GRE_FLAG_CHECKSUM != 0 {
+		binary.BigEndian.PutUint16(data[n:], g.Checksum)
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_KEY != 0 {
+		binary.BigEndian.PutUint32(data[n:], g.Key)
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_SEQ != 0 {
+		binary.BigEndian.PutUint32(data[n:], g.SeqNum)
+		n += 4
+	}
+	if g.Data != nil {
+		var b []byte
+		if b, err = g.Data.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (g *GRE) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("The []byte is too short to unmarshal a full GRE message.")
+	}
+	n := 0
+	flg := binary.BigEndian.Uint16(data[n:])
+	g.Flags = flg & 0xb000
+	g.Version = uint8(flg & 0x07)
+	n += 2
+	g.ProtocolType = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	if len(data) < int(g.headerLen()) {
+		return errors.New("The []byte is too short to unmarshal a full GRE message.")
+	}
+	if g.Flags&GRE_FLAG_CHECKSUM != 0 {
+		g.Checksum = binary.BigEndian.Uint16(data[n:])
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_KEY != 0 {
+		g.Key = binary.BigEndian.Uint32(data[n:])
+		n += 4
+	}
+	if g.Flags&GRE_FLAG_SEQ != 0 {
+		g.SeqNum = binary.BigEndian.Uint32(data[n:])
+		n += 4
+	}
+
+	switch g.ProtocolType {
+	case ERSPAN_II_MSG, ERSPAN_III_MSG:
+		g.Data = new(ERSPAN)
+	default:
+		g.Data = newMessageByEthertype(g.ProtocolType)
+	}
+	return g.Data.UnmarshalBinary(data[n:])
+}
@@ -14,9 +14,16 @@ const (
 	Type_TCP      = 0x06
 	Type_UDP      = 0x11
 	Type_IPv6     = 0x29
+	Type_GRE      = 0x2f
 	Type_IPv6ICMP = 0x3a
 )
 
+// IPv4 flags, as stored in the 3-bit Flags field.
+const (
+	IPv4_FLAG_MF = 0x1
+	IPv4_FLAG_DF = 0x2
+)
+
 type IPv4 struct {
 	Version        uint8 //4-bits
 	IHL            uint8 //4-bits
@@ -142,19 +149,352 @@ func (i *IPv4) UnmarshalBinary(data []byte) error {
 	copy(i.NWDst, data[n:n+4])
 	n += 4
 
+	if i.IHL < 5 || int(i.IHL)*4 > len(data) {
+		return errors.New("The IHL of the IPv4 message is invalid for the []byte.")
+	}
 	err := i.Options.UnmarshalBinary(data[n:int(i.IHL*4)])
 	if err != nil {
 		return err
 	}
 	n += int(i.IHL*4) - n
 
+	// The payload of a fragment cannot be decoded on its own, it is kept as raw
+	// bytes until the datagram is reassembled.
+	if i.IsFragment() {
+		i.Data = new(util.Buffer)
+		return i.Data.UnmarshalBinary(data[n:])
+	}
+
 	switch i.Protocol {
 	case Type_ICMP:
 		i.Data = NewICMP()
+	case Type_TCP:
+		i.Data = decodeTCP(data[n:])
+		return nil
 	case Type_UDP:
 		i.Data = NewUDP()
+	case Type_GRE:
+		i.Data = new(GRE)
 	default:
 		i.Data = new(util.Buffer)
 	}
 	return i.Data.UnmarshalBinary(data[n:])
 }
+
+// IsFragment returns whether the packet is a fragment of a larger datagram.
+func (i *IPv4) IsFragment() bool {
+	return i.Flags&IPv4_FLAG_MF != 0 || i.FragmentOffset != 0
+}
+
+// GetOptions decodes the raw Options buffer into typed options.
+func (i *IPv4) GetOptions() ([]IPv4Option, error) {
+	return ParseIPv4Options(i.Options.Bytes())
+}
+
+// SetOptions encodes the options into the Options buffer, padded with
+// End-of-Options to a multiple of 4 bytes, and updates IHL accordingly.
+func (i *IPv4) SetOptions(options []IPv4Option) error {
+	length := 0
+	for _, o := range options {
+		length += int(o.Len())
+	}
+	length = (length + 3) / 4 * 4
+	if length > 40 {
+		return errors.New("IPv4 options exceed 40 bytes")
+	}
+	data := make([]byte, length)
+	n := 0
+	for _, o := range options {
+		b, err := o.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	if err := i.Options.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	i.IHL = uint8(5 + length/4)
+	return nil
+}
+
+// IPv4 option types, including the copied flag and class bits.
+const (
+	IPv4_OPT_EOL          = 0x00
+	IPv4_OPT_NOP          = 0x01
+	IPv4_OPT_RECORD_ROUTE = 0x07
+	IPv4_OPT_TIMESTAMP    = 0x44
+	IPv4_OPT_LSRR         = 0x83
+	IPv4_OPT_SSRR         = 0x89
+	IPv4_OPT_ROUTER_ALERT = 0x94
+)
+
+// IPv4 timestamp option flags.
+const (
+	IPv4_TS_ONLY     = 0
+	IPv4_TS_AND_ADDR = 1
+	IPv4_TS_PRESPEC  = 3
+)
+
+// ipv4OptHeaderSize is the size of the type and length bytes of an option.
+const ipv4OptHeaderSize = 2
+
+type IPv4Option interface {
+	util.Message
+	OptionType() uint8
+}
+
+// IPv4OptionGeneric is an option which is not decoded further. The
+// End-of-Options and No-Operation options are a single byte without Data.
+type IPv4OptionGeneric struct {
+	Type uint8
+	Data []byte
+}
+
+func (o *IPv4OptionGeneric) OptionType() uint8 {
+	return o.Type
+}
+
+func (o *IPv4OptionGeneric) Len() uint16 {
+	if o.Type == IPv4_OPT_EOL || o.Type == IPv4_OPT_NOP {
+		return 1
+	}
+	return uint16(ipv4OptHeaderSize + len(o.Data))
+}
+
+func (o *IPv4OptionGeneric) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	data[0] = o.Type
+	if len(data) > 1 {
+		data[1] = uint8(len(data))
+		copy(data[2:], o.Data)
+	}
+	return
+}
+
+func (o *IPv4OptionGeneric) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("The []byte is too short to unmarshal a full IPv4 option.")
+	}
+	o.Type = data[0]
+	if o.Type == IPv4_OPT_EOL || o.Type == IPv4_OPT_NOP {
+		o.Data = nil
+		return nil
+	}
+	length, err := ipv4OptionLength(data)
+	if err != nil {
+		return err
+	}
+	o.Data = make([]byte, length-ipv4OptHeaderSize)
+	copy(o.Data, data[ipv4OptHeaderSize:length])
+	return nil
+}
+
+// IPv4RouterAlert is the Router Alert option (RFC 2113), which is required in
+// IGMP messages.
+type IPv4RouterAlert struct {
+	Value uint16
+}
+
+func NewIPv4RouterAlert() *IPv4RouterAlert {
+	return &IPv4RouterAlert{Value: 0}
+}
+
+func (o *IPv4RouterAlert) OptionType() uint8 {
+	return IPv4_OPT_ROUTER_ALERT
+}
+
+func (o *IPv4RouterAlert) Len() uint16 {
+	return 4
+}
+
+func (o *IPv4RouterAlert) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	data[0] = IPv4_OPT_ROUTER_ALERT
+	data[1] = uint8(o.Len())
+	binary.BigEndian.PutUint16(data[2:], o.Value)
+	return
+}
+
+func (o *IPv4RouterAlert) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 || data[1] != 4 {
+		return errors.New("The []byte is too short to unmarshal a full IPv4RouterAlert option.")
+	}
+	o.Value = binary.BigEndian.Uint16(data[2:])
+	return nil
+}
+
+// IPv4RecordRoute is the Record Route option. The same format is used by the
+// Loose and Strict Source and Record Route options, which are selected with
+// Type. Pointer is the 1-based offset of the next free slot within the option.
+type IPv4RecordRoute struct {
+	Type    uint8
+	Pointer uint8
+	Routes  []net.IP
+}
+
+// NewIPv4RecordRoute returns an empty Record Route option with room for the
+// given number of addresses.
+func NewIPv4RecordRoute(slots int) *IPv4RecordRoute {
+	o := &IPv4RecordRoute{Type: IPv4_OPT_RECORD_ROUTE, Pointer: 4}
+	for j := 0; j < slots; j++ {
+		o.Routes = append(o.Routes, net.IPv4zero.To4())
+	}
+	return o
+}
+
+func (o *IPv4RecordRoute) OptionType() uint8 {
+	return o.Type
+}
+
+func (o *IPv4RecordRoute) Len() uint16 {
+	return uint16(3 + 4*len(o.Routes))
+}
+
+func (o *IPv4RecordRoute) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	data[0] = o.Type
+	data[1] = uint8(o.Len())
+	data[2] = o.Pointer
+	n := 3
+	for _, ip := range o.Routes {
+		copy(data[n:], ip.To4())
+		n += 4
+	}
+	return
+}
+
+func (o *IPv4RecordRoute) UnmarshalBinary(data []byte) error {
+	length, err := ipv4OptionLength(data)
+	if err != nil {
+		return err
+	}
+	if length < 3 {
+		return errors.New("The []byte is too short to unmarshal a full IPv4RecordRoute option.")
+	}
+	o.Type = data[0]
+	o.Pointer = data[2]
+	o.Routes = nil
+	for n := 3; n+4 <= length; n += 4 {
+		ip := make(net.IP, 4)
+		copy(ip, data[n:n+4])
+		o.Routes = append(o.Routes, ip)
+	}
+	return nil
+}
+
+type IPv4TimestampEntry struct {
+	// Addr is nil if Flag is IPv4_TS_ONLY.
+	Addr      net.IP
+	Timestamp uint32
+}
+
+// IPv4Timestamp is the Internet Timestamp option (RFC 791).
+type IPv4Timestamp struct {
+	Pointer  uint8
+	Overflow uint8 // 4-bits
+	Flag     uint8 // 4-bits
+	Entries  []IPv4TimestampEntry
+}
+
+func (o *IPv4Timestamp) OptionType() uint8 {
+	return IPv4_OPT_TIMESTAMP
+}
+
+func (o *IPv4Timestamp) entryLen() int {
+	if o.Flag == IPv4_TS_ONLY {
+		return 4
+	}
+	return 8
+}
+
+func (o *IPv4Timestamp) Len() uint16 {
+	return uint16(4 + o.entryLen()*len(o.Entries))
+}
+
+func (o *IPv4Timestamp) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	data[0] = IPv4_OPT_TIMESTAMP
+	data[1] = uint8(o.Len())
+	data[2] = o.Pointer
+	data[3] = o.Overflow<<4 | o.Flag&0x0f
+	n := 4
+	for _, e := range o.Entries {
+		if o.Flag != IPv4_TS_ONLY {
+			copy(data[n:], e.Addr.To4())
+			n += 4
+		}
+		binary.BigEndian.PutUint32(data[n:], e.Timestamp)
+		n += 4
+	}
+	return
+}
+
+func (o *IPv4Timestamp) UnmarshalBinary(data []byte) error {
+	length, err := ipv4OptionLength(data)
+	if err != nil {
+		return err
+	}
+	if length < 4 {
+		return errors.New("The []byte is too short to unmarshal a full IPv4Timestamp option.")
+	}
+	o.Pointer = data[2]
+	o.Overflow = data[3] >> 4
+	o.Flag = data[3] & 0x0f
+	o.Entries = nil
+	for n := 4; n+o.entryLen() <= length; {
+		var e IPv4TimestampEntry
+		if o.Flag != IPv4_TS_ONLY {
+			e.Addr = make(net.IP, 4)
+			copy(e.Addr, data[n:n+4])
+			n += 4
+		}
+		e.Timestamp = binary.BigEndian.Uint32(data[n:])
+		n += 4
+		o.Entries = append(o.Entries, e)
+	}
+	return nil
+}
+
+func ipv4OptionLength(data []byte) (int, error) {
+	if len(data) < ipv4OptHeaderSize || int(data[1]) < ipv4OptHeaderSize || len(data) < int(data[1]) {
+		return 0, errors.New("The []byte is too short to unmarshal a full IPv4 option.")
+	}
+	return int(data[1]), nil
+}
+
+func newIPv4OptionByType(optType uint8) IPv4Option {
+	switch optType {
+	case IPv4_OPT_ROUTER_ALERT:
+		return new(IPv4RouterAlert)
+	case IPv4_OPT_RECORD_ROUTE, IPv4_OPT_LSRR, IPv4_OPT_SSRR:
+		return new(IPv4RecordRoute)
+	case IPv4_OPT_TIMESTAMP:
+		return new(IPv4Timestamp)
+	}
+	return new(IPv4OptionGeneric)
+}
+
+// ParseIPv4Options decodes the options part of an IPv4 header. Decoding stops
+// at the End-of-Options option, which is not included in the result.
+func ParseIPv4Options(data []byte) ([]IPv4Option, error) {
+	var options []IPv4Option
+	n := 0
+	for n < len(data) {
+		if data[n] == IPv4_OPT_EOL {
+			break
+		}
+		o := newIPv4OptionByType(data[n])
+		if err := o.UnmarshalBinary(data[n:]); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+		if data[n] == IPv4_OPT_NOP {
+			n += 1
+		} else {
+			n += int(data[n+1])
+		}
+	}
+	return options, nil
+}
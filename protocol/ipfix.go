@@ -0,0 +1,586 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IPFIX (RFC 7011) message constants.
+const (
+	IPFIX_VERSION    = 10
+	IPFIX_PORT       = 4739
+	IPFIX_HEADER_LEN = 16
+
+	IPFIX_SET_TEMPLATE         = 2
+	IPFIX_SET_OPTIONS_TEMPLATE = 3
+	// IPFIX_SET_MIN_DATA is the minimum Set ID of a Data Set, which is the ID of
+	// the Template used to encode its records.
+	IPFIX_SET_MIN_DATA = 256
+
+	// IPFIX_VARIABLE_LENGTH is the field length of a variable-length Information
+	// Element.
+	IPFIX_VARIABLE_LENGTH = 0xffff
+	// IPFIX_ENTERPRISE_VMWARE is the enterprise number used by Open vSwitch for
+	// its own Information Elements.
+	IPFIX_ENTERPRISE_VMWARE = 6876
+)
+
+// IANA Information Elements commonly exported by Open vSwitch.
+const (
+	IPFIX_IE_OCTET_DELTA_COUNT          = 1
+	IPFIX_IE_PACKET_DELTA_COUNT         = 2
+	IPFIX_IE_PROTOCOL_IDENTIFIER        = 4
+	IPFIX_IE_IP_CLASS_OF_SERVICE        = 5
+	IPFIX_IE_SOURCE_TRANSPORT_PORT      = 7
+	IPFIX_IE_SOURCE_IPV4_ADDRESS        = 8
+	IPFIX_IE_INGRESS_INTERFACE          = 10
+	IPFIX_IE_DESTINATION_TRANSPORT_PORT = 11
+	IPFIX_IE_DESTINATION_IPV4_ADDRESS   = 12
+	IPFIX_IE_EGRESS_INTERFACE           = 14
+	IPFIX_IE_SOURCE_IPV6_ADDRESS        = 27
+	IPFIX_IE_DESTINATION_IPV6_ADDRESS   = 28
+	IPFIX_IE_SOURCE_MAC_ADDRESS         = 56
+	IPFIX_IE_VLAN_ID                    = 58
+	IPFIX_IE_FLOW_DIRECTION             = 61
+	IPFIX_IE_DESTINATION_MAC_ADDRESS    = 80
+	IPFIX_IE_OBSERVATION_POINT_ID       = 138
+	IPFIX_IE_OBSERVATION_DOMAIN_ID      = 149
+	IPFIX_IE_ETHERNET_TYPE              = 256
+	IPFIX_IE_DATA_LINK_FRAME_SIZE       = 312
+	IPFIX_IE_DATA_LINK_FRAME_SECTION    = 315
+)
+
+// IPFIXMessage is an IPFIX message. UnmarshalBinary decodes the Template Sets,
+// while the Data Sets are kept as raw bytes because they can only be decoded
+// with the Templates received previously, use IPFIXDecoder to decode them.
+type IPFIXMessage struct {
+	Version             uint16
+	Length              uint16
+	ExportTime          uint32
+	SequenceNumber      uint32
+	ObservationDomainID uint32
+	Sets                []*IPFIXSet
+}
+
+// IPFIXSet is a Template Set, an Options Template Set or a Data Set. When it is
+// marshaled, Templates are encoded for a Template Set and Records are encoded
+// for a Data Set if they are set, Data is used otherwise.
+type IPFIXSet struct {
+	ID        uint16
+	Templates []*IPFIXTemplate
+	Records   []*IPFIXDataRecord
+	Data      []byte
+}
+
+// IPFIXTemplate is a Template Record or an Options Template Record. The first
+// ScopeFieldCount fields of an Options Template Record are scope fields.
+type IPFIXTemplate struct {
+	ID              uint16
+	ScopeFieldCount uint16
+	Fields          []IPFIXFieldSpecifier
+}
+
+// IPFIXFieldSpecifier identifies an Information Element in a Template. The
+// EnterpriseNumber is 0 for IANA Information Elements.
+type IPFIXFieldSpecifier struct {
+	ID               uint16
+	Length           uint16
+	EnterpriseNumber uint32
+}
+
+// IPFIXDataRecord is a Data Record, Values are ordered as the fields of the
+// Template.
+type IPFIXDataRecord struct {
+	Template *IPFIXTemplate
+	Values   [][]byte
+}
+
+func NewIPFIXMessage(observationDomainID uint32, sequenceNumber uint32) *IPFIXMessage {
+	return &IPFIXMessage{
+		Version:             IPFIX_VERSION,
+		ExportTime:          uint32(time.Now().Unix()),
+		SequenceNumber:      sequenceNumber,
+		ObservationDomainID: observationDomainID,
+	}
+}
+
+// NewIPFIXTemplateSet returns a Template Set, or an Options Template Set if any
+// template has scope fields.
+func NewIPFIXTemplateSet(templates ...*IPFIXTemplate) *IPFIXSet {
+	s := &IPFIXSet{ID: IPFIX_SET_TEMPLATE, Templates: templates}
+	for _, t := range templates {
+		if t.ScopeFieldCount > 0 {
+			s.ID = IPFIX_SET_OPTIONS_TEMPLATE
+		}
+	}
+	return s
+}
+
+// NewIPFIXDataSet returns a Data Set encoded with template.
+func NewIPFIXDataSet(template *IPFIXTemplate, records ...*IPFIXDataRecord) *IPFIXSet {
+	return &IPFIXSet{ID: template.ID, Records: records}
+}
+
+func NewIPFIXTemplate(id uint16, fields ...IPFIXFieldSpecifier) *IPFIXTemplate {
+	return &IPFIXTemplate{ID: id, Fields: fields}
+}
+
+// NewIPFIXDataRecord returns a Data Record, values must be ordered as the fields
+// of the template.
+func NewIPFIXDataRecord(template *IPFIXTemplate, values ...[]byte) *IPFIXDataRecord {
+	return &IPFIXDataRecord{Template: template, Values: values}
+}
+
+func (m *IPFIXMessage) Len() (n uint16) {
+	n = IPFIX_HEADER_LEN
+	for _, s := range m.Sets {
+		n += s.Len()
+	}
+	return
+}
+
+// MarshalBinary encodes the message, the Length field is computed from Sets.
+func (m *IPFIXMessage) MarshalBinary() (data []byte, err error) {
+	m.Length = m.Len()
+	data = make([]byte, int(m.Length))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], m.Version)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], m.Length)
+	n += 2
+	binary.BigEndian.PutUint32(data[n:], m.ExportTime)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], m.SequenceNumber)
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], m.ObservationDomainID)
+	n += 4
+	for _, s := range m.Sets {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return
+}
+
+func (m *IPFIXMessage) UnmarshalBinary(data []byte) error {
+	if len(data) < IPFIX_HEADER_LEN {
+		return errors.New("The []byte is too short to unmarshal a full IPFIX message.")
+	}
+	n := 0
+	m.Version = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.Length = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	m.ExportTime = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.SequenceNumber = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	m.ObservationDomainID = binary.BigEndian.Uint32(data[n:])
+	n += 4
+	if m.Version != IPFIX_VERSION {
+		return fmt.Errorf("unsupported IPFIX version %d", m.Version)
+	}
+	if int(m.Length) < IPFIX_HEADER_LEN || int(m.Length) > len(data) {
+		return fmt.Errorf("invalid IPFIX message length %d", m.Length)
+	}
+	m.Sets = nil
+	for n < int(m.Length) {
+		s := new(IPFIXSet)
+		if err := s.UnmarshalBinary(data[n:m.Length]); err != nil {
+			return err
+		}
+		m.Sets = append(m.Sets, s)
+		// Advance with the length on the wire which includes the padding.
+		n += int(binary.BigEndian.Uint16(data[n+2:]))
+	}
+	return nil
+}
+
+func (s *IPFIXSet) Len() (n uint16) {
+	n = 4
+	switch {
+	case len(s.Templates) > 0:
+		for _, t := range s.Templates {
+			n += t.Len()
+		}
+	case len(s.Records) > 0:
+		for _, r := range s.Records {
+			n += r.Len()
+		}
+	default:
+		n += uint16(len(s.Data))
+	}
+	return
+}
+
+func (s *IPFIXSet) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(s.Len()))
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], s.ID)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], s.Len())
+	n += 2
+	var b []byte
+	switch {
+	case len(s.Templates) > 0:
+		for _, t := range s.Templates {
+			if b, err = t.marshal(s.ID == IPFIX_SET_OPTIONS_TEMPLATE); err != nil {
+				return nil, err
+			}
+			copy(data[n:], b)
+			n += len(b)
+		}
+	case len(s.Records) > 0:
+		for _, r := range s.Records {
+			if b, err = r.MarshalBinary(); err != nil {
+				return nil, err
+			}
+			copy(data[n:], b)
+			n += len(b)
+		}
+	default:
+		copy(data[n:], s.Data)
+	}
+	return
+}
+
+// UnmarshalBinary decodes the Set. The records of a Template Set or an Options
+// Template Set are decoded into Templates, the records of a Data Set are kept
+// in Data.
+func (s *IPFIXSet) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("The []byte is too short to unmarshal a full IPFIX set.")
+	}
+	s.ID = binary.BigEndian.Uint16(data[0:])
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	if length < 4 || length > len(data) {
+		return fmt.Errorf("invalid IPFIX set length %d", length)
+	}
+	s.Data = append([]byte(nil), data[4:length]...)
+	s.Templates = nil
+	s.Records = nil
+	if s.ID != IPFIX_SET_TEMPLATE && s.ID != IPFIX_SET_OPTIONS_TEMPLATE {
+		if s.ID < IPFIX_SET_MIN_DATA {
+			return fmt.Errorf("invalid IPFIX set ID %d", s.ID)
+		}
+		return nil
+	}
+	options := s.ID == IPFIX_SET_OPTIONS_TEMPLATE
+	n := 0
+	// A Template Record has at least a 4-byte header, shorter data is padding.
+	for len(s.Data)-n >= 4 {
+		t := new(IPFIXTemplate)
+		read, err := t.unmarshal(s.Data[n:], options)
+		if err != nil {
+			return err
+		}
+		s.Templates = append(s.Templates, t)
+		n += read
+	}
+	// Templates are re-encoded without padding.
+	s.Data = nil
+	return nil
+}
+
+func (t *IPFIXTemplate) Len() (n uint16) {
+	n = 4
+	if t.ScopeFieldCount > 0 && !t.IsWithdrawal() {
+		n += 2
+	}
+	for _, f := range t.Fields {
+		n += f.Len()
+	}
+	return
+}
+
+// IsWithdrawal returns true if the Template Record withdraws the Template, in
+// which case it has no field.
+func (t *IPFIXTemplate) IsWithdrawal() bool {
+	return len(t.Fields) == 0
+}
+
+func (t *IPFIXTemplate) marshal(options bool) ([]byte, error) {
+	if options && t.ScopeFieldCount == 0 && !t.IsWithdrawal() {
+		return nil, errors.New("an Options Template Record must have at least one scope field")
+	}
+	if !options && t.ScopeFieldCount > 0 {
+		return nil, errors.New("a Template Record with scope fields must be in an Options Template Set")
+	}
+	data := make([]byte, t.Len())
+	n := 0
+	binary.BigEndian.PutUint16(data[n:], t.ID)
+	n += 2
+	binary.BigEndian.PutUint16(data[n:], uint16(len(t.Fields)))
+	n += 2
+	if options && !t.IsWithdrawal() {
+		binary.BigEndian.PutUint16(data[n:], t.ScopeFieldCount)
+		n += 2
+	}
+	for _, f := range t.Fields {
+		b, _ := f.MarshalBinary()
+		copy(data[n:], b)
+		n += len(b)
+	}
+	return data, nil
+}
+
+func (t *IPFIXTemplate) unmarshal(data []byte, options bool) (int, error) {
+	if len(data) < 4 {
+		return 0, errors.New("The []byte is too short to unmarshal a full IPFIX template record.")
+	}
+	n := 0
+	t.ID = binary.BigEndian.Uint16(data[n:])
+	n += 2
+	fieldCount := int(binary.BigEndian.Uint16(data[n:]))
+	n += 2
+	t.ScopeFieldCount = 0
+	t.Fields = nil
+	if fieldCount == 0 {
+		return n, nil
+	}
+	if options {
+		if len(data) < n+2 {
+			return 0, errors.New("The []byte is too short to unmarshal a full IPFIX options template record.")
+		}
+		t.ScopeFieldCount = binary.BigEndian.Uint16(data[n:])
+		n += 2
+		if t.ScopeFieldCount == 0 || int(t.ScopeFieldCount) > fieldCount {
+			return 0, fmt.Errorf("invalid IPFIX scope field count %d", t.ScopeFieldCount)
+		}
+	}
+	for i := 0; i < fieldCount; i++ {
+		var f IPFIXFieldSpecifier
+		if err := f.UnmarshalBinary(data[n:]); err != nil {
+			return 0, err
+		}
+		t.Fields = append(t.Fields, f)
+		n += int(f.Len())
+	}
+	return n, nil
+}
+
+func (f *IPFIXFieldSpecifier) Len() (n uint16) {
+	if f.EnterpriseNumber != 0 {
+		return 8
+	}
+	return 4
+}
+
+func (f *IPFIXFieldSpecifier) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(f.Len()))
+	id := f.ID &^ 0x8000
+	if f.EnterpriseNumber != 0 {
+		id |= 0x8000
+		binary.BigEndian.PutUint32(data[4:], f.EnterpriseNumber)
+	}
+	binary.BigEndian.PutUint16(data[0:], id)
+	binary.BigEndian.PutUint16(data[2:], f.Length)
+	return
+}
+
+func (f *IPFIXFieldSpecifier) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("The []byte is too short to unmarshal a full IPFIX field specifier.")
+	}
+	id := binary.BigEndian.Uint16(data[0:])
+	f.ID = id &^ 0x8000
+	f.Length = binary.BigEndian.Uint16(data[2:])
+	f.EnterpriseNumber = 0
+	if id&0x8000 != 0 {
+		if len(data) < 8 {
+			return errors.New("The []byte is too short to unmarshal a full IPFIX field specifier.")
+		}
+		f.EnterpriseNumber = binary.BigEndian.Uint32(data[4:])
+	}
+	return nil
+}
+
+func (r *IPFIXDataRecord) Len() (n uint16) {
+	for i, v := range r.Values {
+		if i < len(r.Template.Fields) && r.Template.Fields[i].Length == IPFIX_VARIABLE_LENGTH {
+			if len(v) < 255 {
+				n += 1
+			} else {
+				n += 3
+			}
+		}
+		n += uint16(len(v))
+	}
+	return
+}
+
+func (r *IPFIXDataRecord) MarshalBinary() (data []byte, err error) {
+	if len(r.Values) != len(r.Template.Fields) {
+		return nil, fmt.Errorf("IPFIX data record has %d values, template %d has %d fields", len(r.Values), r.Template.ID, len(r.Template.Fields))
+	}
+	data = make([]byte, int(r.Len()))
+	n := 0
+	for i, v := range r.Values {
+		f := r.Template.Fields[i]
+		if f.Length == IPFIX_VARIABLE_LENGTH {
+			if len(v) < 255 {
+				data[n] = uint8(len(v))
+				n += 1
+			} else {
+				data[n] = 255
+				binary.BigEndian.PutUint16(data[n+1:], uint16(len(v)))
+				n += 3
+			}
+		} else if len(v) != int(f.Length) {
+			return nil, fmt.Errorf("IPFIX value of field %d has length %d, expected %d", f.ID, len(v), f.Length)
+		}
+		copy(data[n:], v)
+		n += len(v)
+	}
+	return
+}
+
+// unmarshal decodes a Data Record with the Template and returns the number of
+// bytes read.
+func (r *IPFIXDataRecord) unmarshal(data []byte, template *IPFIXTemplate) (int, error) {
+	r.Template = template
+	r.Values = make([][]byte, 0, len(template.Fields))
+	n := 0
+	for _, f := range template.Fields {
+		length := int(f.Length)
+		if f.Length == IPFIX_VARIABLE_LENGTH {
+			if len(data) < n+1 {
+				return 0, errors.New("The []byte is too short to unmarshal a full IPFIX data record.")
+			}
+			length = int(data[n])
+			n += 1
+			if length == 255 {
+				if len(data) < n+2 {
+					return 0, errors.New("The []byte is too short to unmarshal a full IPFIX data record.")
+				}
+				length = int(binary.BigEndian.Uint16(data[n:]))
+				n += 2
+			}
+		}
+		if len(data) < n+length {
+			return 0, errors.New("The []byte is too short to unmarshal a full IPFIX data record.")
+		}
+		r.Values = append(r.Values, append([]byte(nil), data[n:n+length]...))
+		n += length
+	}
+	return n, nil
+}
+
+// GetValue returns the value of the Information Element identified by the
+// enterprise number and the ID, or nil if the Template does not have it.
+func (r *IPFIXDataRecord) GetValue(enterpriseNumber uint32, id uint16) []byte {
+	for i, f := range r.Template.Fields {
+		if f.EnterpriseNumber == enterpriseNumber && f.ID == id && i < len(r.Values) {
+			return r.Values[i]
+		}
+	}
+	return nil
+}
+
+type ipfixTemplateKey struct {
+	observationDomainID uint32
+	templateID          uint16
+}
+
+// IPFIXDecoder decodes IPFIX messages received from one exporter. It keeps the
+// Templates received in previous messages to decode the Data Sets. It is safe
+// for concurrent use.
+type IPFIXDecoder struct {
+	mutex     sync.Mutex
+	templates map[ipfixTemplateKey]*IPFIXTemplate
+}
+
+func NewIPFIXDecoder() *IPFIXDecoder {
+	return &IPFIXDecoder{templates: make(map[ipfixTemplateKey]*IPFIXTemplate)}
+}
+
+// Decode decodes an IPFIX message. The Templates in the message are stored for
+// the following messages, and the Data Sets are decoded into Records. A Data
+// Set with an unknown Template is kept as raw bytes in Data.
+func (d *IPFIXDecoder) Decode(data []byte) (*IPFIXMessage, error) {
+	m := new(IPFIXMessage)
+	if err := m.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, s := range m.Sets {
+		if s.ID == IPFIX_SET_TEMPLATE || s.ID == IPFIX_SET_OPTIONS_TEMPLATE {
+			for _, t := range s.Templates {
+				d.addTemplate(m.ObservationDomainID, s.ID, t)
+			}
+			continue
+		}
+		t, ok := d.templates[ipfixTemplateKey{m.ObservationDomainID, s.ID}]
+		if !ok {
+			continue
+		}
+		records, err := decodeIPFIXRecords(s.Data, t)
+		if err != nil {
+			return nil, err
+		}
+		s.Records = records
+		s.Data = nil
+	}
+	return m, nil
+}
+
+// Template returns the Template received for the Observation Domain, or nil if
+// it is unknown.
+func (d *IPFIXDecoder) Template(observationDomainID uint32, templateID uint16) *IPFIXTemplate {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.templates[ipfixTemplateKey{observationDomainID, templateID}]
+}
+
+func (d *IPFIXDecoder) addTemplate(observationDomainID uint32, setID uint16, t *IPFIXTemplate) {
+	if !t.IsWithdrawal() {
+		d.templates[ipfixTemplateKey{observationDomainID, t.ID}] = t
+		return
+	}
+	// A withdrawal with the Set ID as Template ID withdraws all the Templates
+	// of this type.
+	if t.ID != setID {
+		delete(d.templates, ipfixTemplateKey{observationDomainID, t.ID})
+		return
+	}
+	options := setID == IPFIX_SET_OPTIONS_TEMPLATE
+	for key, template := range d.templates {
+		if key.observationDomainID == observationDomainID && (template.ScopeFieldCount > 0) == options {
+			delete(d.templates, key)
+		}
+	}
+}
+
+func decodeIPFIXRecords(data []byte, t *IPFIXTemplate) ([]*IPFIXDataRecord, error) {
+	var minLen int
+	for _, f := range t.Fields {
+		if f.Length == IPFIX_VARIABLE_LENGTH {
+			minLen += 1
+		} else {
+			minLen += int(f.Length)
+		}
+	}
+	if minLen == 0 {
+		return nil, fmt.Errorf("IPFIX template %d has no data", t.ID)
+	}
+	var records []*IPFIXDataRecord
+	n := 0
+	// Shorter data than a record is padding.
+	for len(data)-n >= minLen {
+		r := new(IPFIXDataRecord)
+		read, err := r.unmarshal(data[n:], t)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+		n += read
+	}
+	return records, nil
+}
@@ -0,0 +1,134 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPFIX(t *testing.T) {
+	// Template similar to the one exported by OVS for sampled packets.
+	template := NewIPFIXTemplate(256,
+		IPFIXFieldSpecifier{ID: IPFIX_IE_OBSERVATION_DOMAIN_ID, Length: 4},
+		IPFIXFieldSpecifier{ID: IPFIX_IE_OBSERVATION_POINT_ID, Length: 4},
+		IPFIXFieldSpecifier{ID: IPFIX_IE_SOURCE_IPV4_ADDRESS, Length: 4},
+		IPFIXFieldSpecifier{ID: IPFIX_IE_DESTINATION_IPV4_ADDRESS, Length: 4},
+		IPFIXFieldSpecifier{ID: IPFIX_IE_DATA_LINK_FRAME_SECTION, Length: IPFIX_VARIABLE_LENGTH},
+		IPFIXFieldSpecifier{ID: 891, Length: 2, EnterpriseNumber: IPFIX_ENTERPRISE_VMWARE},
+	)
+	optionsTemplate := &IPFIXTemplate{ID: 257, ScopeFieldCount: 1, Fields: []IPFIXFieldSpecifier{
+		{ID: IPFIX_IE_OBSERVATION_DOMAIN_ID, Length: 4},
+		{ID: IPFIX_IE_PACKET_DELTA_COUNT, Length: 8},
+	}}
+	uint32Value := func(v uint32) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, v)
+		return b
+	}
+	frame := make([]byte, 300)
+	frame[0] = 0xaa
+
+	templateMsg := NewIPFIXMessage(1, 0)
+	templateMsg.Sets = []*IPFIXSet{NewIPFIXTemplateSet(template), NewIPFIXTemplateSet(optionsTemplate)}
+	dataMsg := NewIPFIXMessage(1, 1)
+	dataMsg.Sets = []*IPFIXSet{
+		NewIPFIXDataSet(template,
+			NewIPFIXDataRecord(template, uint32Value(1), uint32Value(2), net.ParseIP("10.0.0.1").To4(), net.ParseIP("10.0.0.2").To4(), []byte{1, 2, 3}, []byte{0, 1}),
+			NewIPFIXDataRecord(template, uint32Value(1), uint32Value(3), net.ParseIP("10.0.0.3").To4(), net.ParseIP("10.0.0.4").To4(), frame, []byte{0, 2}),
+		),
+		NewIPFIXDataSet(optionsTemplate, NewIPFIXDataRecord(optionsTemplate, uint32Value(1), make([]byte, 8))),
+	}
+
+	// Send the messages to a collector listening on the loopback interface.
+	collector, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer collector.Close()
+	exporter, err := net.DialUDP("udp", nil, collector.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	decoder := NewIPFIXDecoder()
+	buf := make([]byte, 65535)
+	var messages []*IPFIXMessage
+	for _, msg := range []*IPFIXMessage{dataMsg, templateMsg, dataMsg} {
+		data, err := msg.MarshalBinary()
+		require.NoError(t, err, "Failed to Marshal message")
+		_, err = exporter.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, collector.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := collector.ReadFromUDP(buf)
+		require.NoError(t, err)
+		newMsg, err := decoder.Decode(buf[:n])
+		require.NoError(t, err, "Failed to decode message")
+		messages = append(messages, newMsg)
+	}
+
+	// The Data Sets received before the Templates cannot be decoded.
+	require.Len(t, messages[0].Sets, 2)
+	assert.Nil(t, messages[0].Sets[0].Records)
+	assert.NotEmpty(t, messages[0].Sets[0].Data)
+
+	require.Len(t, messages[1].Sets, 2)
+	assert.Equal(t, uint16(IPFIX_SET_TEMPLATE), messages[1].Sets[0].ID)
+	assert.Equal(t, template, messages[1].Sets[0].Templates[0])
+	assert.Equal(t, uint16(IPFIX_SET_OPTIONS_TEMPLATE), messages[1].Sets[1].ID)
+	assert.Equal(t, optionsTemplate, messages[1].Sets[1].Templates[0])
+	assert.Equal(t, template, decoder.Template(1, 256))
+	assert.Nil(t, decoder.Template(2, 256))
+
+	msg := messages[2]
+	assert.Equal(t, uint32(1), msg.ObservationDomainID)
+	assert.Equal(t, uint32(1), msg.SequenceNumber)
+	require.Len(t, msg.Sets, 2)
+	records := msg.Sets[0].Records
+	require.Len(t, records, 2)
+	assert.Equal(t, uint32Value(2), records[0].GetValue(0, IPFIX_IE_OBSERVATION_POINT_ID))
+	assert.Equal(t, net.ParseIP("10.0.0.2").To4(), net.IP(records[0].GetValue(0, IPFIX_IE_DESTINATION_IPV4_ADDRESS)))
+	assert.Equal(t, []byte{1, 2, 3}, records[0].GetValue(0, IPFIX_IE_DATA_LINK_FRAME_SECTION))
+	assert.Equal(t, []byte{0, 1}, records[0].GetValue(IPFIX_ENTERPRISE_VMWARE, 891))
+	assert.Equal(t, frame, records[1].GetValue(0, IPFIX_IE_DATA_LINK_FRAME_SECTION))
+	assert.Nil(t, records[1].GetValue(0, IPFIX_IE_VLAN_ID))
+	require.Len(t, msg.Sets[1].Records, 1)
+	assert.Equal(t, make([]byte, 8), msg.Sets[1].Records[0].GetValue(0, IPFIX_IE_PACKET_DELTA_COUNT))
+
+	// Re-encoding the decoded message must produce the same bytes.
+	expected, _ := dataMsg.MarshalBinary()
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	// Withdraw the Template.
+	withdrawal := NewIPFIXMessage(1, 2)
+	withdrawal.Sets = []*IPFIXSet{{ID: IPFIX_SET_TEMPLATE, Templates: []*IPFIXTemplate{{ID: 256}}}}
+	data, err = withdrawal.MarshalBinary()
+	require.NoError(t, err)
+	_, err = decoder.Decode(data)
+	require.NoError(t, err)
+	assert.Nil(t, decoder.Template(1, 256))
+	assert.NotNil(t, decoder.Template(1, 257))
+}
+
+func TestIPFIXPadding(t *testing.T) {
+	template := NewIPFIXTemplate(300, IPFIXFieldSpecifier{ID: IPFIX_IE_VLAN_ID, Length: 2})
+	msg := NewIPFIXMessage(5, 0)
+	msg.Sets = []*IPFIXSet{NewIPFIXTemplateSet(template)}
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	// Data Set with a 2-byte record followed by 1 byte of padding.
+	data = append(data, 0x01, 0x2c, 0x00, 0x07, 0x00, 0x0a, 0x00)
+	binary.BigEndian.PutUint16(data[2:], uint16(len(data)))
+	newMsg, err := NewIPFIXDecoder().Decode(data)
+	require.NoError(t, err)
+	require.Len(t, newMsg.Sets, 2)
+	require.Len(t, newMsg.Sets[1].Records, 1)
+	assert.Equal(t, []byte{0x00, 0x0a}, newMsg.Sets[1].Records[0].GetValue(0, IPFIX_IE_VLAN_ID))
+
+	assert.Error(t, new(IPFIXMessage).UnmarshalBinary(data[:10]))
+	data[1] = 9
+	assert.Error(t, new(IPFIXMessage).UnmarshalBinary(data))
+}
@@ -167,13 +167,25 @@ checkXHeader:
 			}
 			nxtHeader = i.FragmentHeader.NextHeader
 			n += int(i.FragmentHeader.Len())
+			// The payload of a fragment cannot be decoded on its own, it is
+			// kept as raw bytes until the datagram is reassembled.
+			if i.IsFragment() {
+				i.Data = new(util.Buffer)
+				break checkXHeader
+			}
 		case Type_IPv6ICMP:
 			packetType := data[n]
 			i.Data = NewICMPv6ByHeaderType(packetType)
 			break checkXHeader
+		case Type_TCP:
+			i.Data = decodeTCP(data[n:])
+			return nil
 		case Type_UDP:
 			i.Data = NewUDP()
 			break checkXHeader
+		case Type_GRE:
+			i.Data = new(GRE)
+			break checkXHeader
 		default:
 			i.Data = new(util.Buffer)
 			break checkXHeader
@@ -182,6 +194,13 @@ checkXHeader:
 	return i.Data.UnmarshalBinary(data[n:])
 }
 
+// IsFragment returns whether the packet is a fragment of a larger datagram. An
+// atomic fragment, i.e., a Fragment header with zero offset and no further
+// fragments, is not considered as a fragment.
+func (i *IPv6) IsFragment() bool {
+	return i.FragmentHeader != nil && (i.FragmentHeader.MoreFragments || i.FragmentHeader.FragmentOffset != 0)
+}
+
 type Option struct {
 	Type   uint8
 	Length uint8
@@ -0,0 +1,361 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"antrea.io/libOpenflow/util"
+)
+
+const (
+	// DefaultReassemblyTimeout is the time after which an incomplete datagram is
+	// dropped, as in Linux (net.ipv4.ipfrag_time).
+	DefaultReassemblyTimeout = 30 * time.Second
+	// DefaultReassemblyMaxBytes is the maximum number of fragment payload bytes
+	// buffered for all incomplete datagrams, as in Linux
+	// (net.ipv4.ipfrag_high_thresh).
+	DefaultReassemblyMaxBytes = 4 * 1024 * 1024
+
+	// maxDatagramSize is the maximum size of a reassembled payload.
+	maxDatagramSize = 65535
+)
+
+// Reassembler reassembles IPv4 fragments and IPv6 packets carrying a
+// FragmentHeader. Incomplete datagrams are dropped after Timeout, and the
+// oldest incomplete datagrams are dropped when the buffered payload exceeds
+// MaxBytes. DefaultReassemblyTimeout and DefaultReassemblyMaxBytes are used if
+// Timeout or MaxBytes is not positive, so the zero value is ready to use. It is
+// safe for concurrent use.
+type Reassembler struct {
+	Timeout  time.Duration
+	MaxBytes int
+
+	mutex     sync.Mutex
+	datagrams map[fragmentKey]*fragmentQueue
+	bytes     int
+	// now is replaceable in tests.
+	now func() time.Time
+}
+
+type fragmentKey struct {
+	src      [16]byte
+	dst      [16]byte
+	id       uint32
+	protocol uint8
+	ipv6     bool
+}
+
+type fragment struct {
+	offset int
+	data   []byte
+}
+
+type fragmentQueue struct {
+	created   time.Time
+	fragments []fragment
+	// total is the payload length of the datagram, known once the last
+	// fragment is received, -1 otherwise.
+	total int
+	bytes int
+	// first is the packet carrying the fragment with offset 0, which provides
+	// the headers of the reassembled datagram.
+	first util.Message
+}
+
+// NewReassembler returns a Reassembler. DefaultReassemblyTimeout and
+// DefaultReassemblyMaxBytes are used if timeout or maxBytes is not positive.
+func NewReassembler(timeout time.Duration, maxBytes int) *Reassembler {
+	if timeout <= 0 {
+		timeout = DefaultReassemblyTimeout
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultReassemblyMaxBytes
+	}
+	return &Reassembler{
+		Timeout:   timeout,
+		MaxBytes:  maxBytes,
+		datagrams: make(map[fragmentKey]*fragmentQueue),
+		now:       time.Now,
+	}
+}
+
+// AddIPv4 adds an IPv4 packet to the Reassembler. If the packet is not a
+// fragment, it is returned as is. If the packet completes a datagram, the
+// reassembled packet is returned with its payload decoded according to the
+// protocol. Otherwise nil is returned. The header checksum of the reassembled
+// packet is not recomputed.
+func (r *Reassembler) AddIPv4(pkt *IPv4) (*IPv4, error) {
+	if !pkt.IsFragment() {
+		return pkt, nil
+	}
+	payload, err := pkt.Data.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	// Remove the Ethernet padding if any.
+	if payloadLen := int(pkt.Length) - int(pkt.IHL)*4; payloadLen >= 0 && payloadLen < len(payload) {
+		payload = payload[:payloadLen]
+	}
+	key := fragmentKey{id: uint32(pkt.Id), protocol: pkt.Protocol}
+	copy(key.src[:], pkt.NWSrc.To16())
+	copy(key.dst[:], pkt.NWDst.To16())
+
+	offset := int(pkt.FragmentOffset) * 8
+	more := pkt.Flags&IPv4_FLAG_MF != 0
+	q, data, err := r.add(key, offset, more, payload, pkt)
+	if q == nil || err != nil {
+		return nil, err
+	}
+
+	first := q.first.(*IPv4)
+	ip := *first
+	ip.Flags &^= IPv4_FLAG_MF
+	ip.FragmentOffset = 0
+	ip.Length = uint16(int(ip.IHL)*4 + len(data))
+	ip.Data = util.NewBuffer(data)
+	b, err := ip.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	result := new(IPv4)
+	if err := result.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AddIPv6 adds an IPv6 packet to the Reassembler. If the packet is not a
+// fragment, it is returned as is. If the packet completes a datagram, the
+// reassembled packet is returned without FragmentHeader and with its payload
+// decoded according to the next header. Otherwise nil is returned.
+func (r *Reassembler) AddIPv6(pkt *IPv6) (*IPv6, error) {
+	if !pkt.IsFragment() {
+		return pkt, nil
+	}
+	payload, err := pkt.Data.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	extLen := pkt.Len() - 40 - pkt.Data.Len()
+	if payloadLen := int(pkt.Length) - int(extLen); payloadLen >= 0 && payloadLen < len(payload) {
+		payload = payload[:payloadLen]
+	}
+	key := fragmentKey{id: pkt.FragmentHeader.Identification, ipv6: true}
+	copy(key.src[:], pkt.NWSrc.To16())
+	copy(key.dst[:], pkt.NWDst.To16())
+
+	offset := int(pkt.FragmentHeader.FragmentOffset) * 8
+	q, data, err := r.add(key, offset, pkt.FragmentHeader.MoreFragments, payload, pkt)
+	if q == nil || err != nil {
+		return nil, err
+	}
+
+	first := q.first.(*IPv6)
+	ip := *first
+	nextHeader := first.FragmentHeader.NextHeader
+	// Unlink the Fragment header from the extension header chain.
+	if ip.NextHeader == Type_Fragment {
+		ip.NextHeader = nextHeader
+	}
+	if ip.HbhHeader != nil && ip.HbhHeader.NextHeader == Type_Fragment {
+		hbh := *ip.HbhHeader
+		hbh.NextHeader = nextHeader
+		ip.HbhHeader = &hbh
+	}
+	if ip.RoutingHeader != nil && ip.RoutingHeader.NextHeader == Type_Fragment {
+		routing := *ip.RoutingHeader
+		routing.NextHeader = nextHeader
+		ip.RoutingHeader = &routing
+	}
+	ip.FragmentHeader = nil
+	ip.Data = util.NewBuffer(data)
+	ip.Length = ip.Len() - 40
+	b, err := ip.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	result := new(IPv6)
+	if err := result.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// add stores the fragment and returns the queue and the reassembled payload if
+// the datagram is complete.
+func (r *Reassembler) add(key fragmentKey, offset int, more bool, payload []byte, pkt util.Message) (*fragmentQueue, []byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.init()
+	now := r.now()
+	r.expire(now)
+
+	end := offset + len(payload)
+	if end > maxDatagramSize {
+		return nil, nil, fmt.Errorf("fragment exceeds the maximum datagram size: offset %d, length %d", offset, len(payload))
+	}
+	// The offsets are in units of 8 bytes, so only the last fragment can have
+	// another length.
+	if more && len(payload)%8 != 0 {
+		return nil, nil, fmt.Errorf("non-final fragment length %d is not a multiple of 8", len(payload))
+	}
+	maxBytes := r.maxBytes()
+	if len(payload) > maxBytes {
+		return nil, nil, fmt.Errorf("fragment length %d exceeds the reassembly memory limit %d", len(payload), maxBytes)
+	}
+
+	for r.bytes+len(payload) > maxBytes {
+		if !r.dropOldest(key) {
+			r.drop(key)
+			return nil, nil, errors.New("reassembly memory limit exceeded, datagram dropped")
+		}
+	}
+
+	q, ok := r.datagrams[key]
+	if !ok {
+		q = &fragmentQueue{created: now, total: -1}
+		r.datagrams[key] = q
+	}
+
+	if !more {
+		if q.total >= 0 && q.total != end {
+			r.drop(key)
+			return nil, nil, errors.New("inconsistent last fragment, datagram dropped")
+		}
+		q.total = end
+	}
+	for _, f := range q.fragments {
+		if f.offset == offset && len(f.data) == len(payload) {
+			// Duplicate fragment.
+			return nil, nil, nil
+		}
+		// Overlapping fragments are not allowed (RFC 5722).
+		if offset < f.offset+len(f.data) && f.offset < end {
+			r.drop(key)
+			return nil, nil, errors.New("overlapping fragments, datagram dropped")
+		}
+	}
+	if q.total >= 0 {
+		for _, f := range append(q.fragments, fragment{offset: offset, data: payload}) {
+			if f.offset+len(f.data) > q.total {
+				r.drop(key)
+				return nil, nil, errors.New("fragment beyond the end of the datagram, datagram dropped")
+			}
+		}
+	}
+
+	q.fragments = append(q.fragments, fragment{offset: offset, data: payload})
+	q.bytes += len(payload)
+	r.bytes += len(payload)
+	if offset == 0 {
+		q.first = pkt
+	}
+
+	if q.total < 0 || q.first == nil {
+		return nil, nil, nil
+	}
+	sort.Slice(q.fragments, func(i, j int) bool {
+		return q.fragments[i].offset < q.fragments[j].offset
+	})
+	next := 0
+	for _, f := range q.fragments {
+		if f.offset != next {
+			return nil, nil, nil
+		}
+		next += len(f.data)
+	}
+	if next != q.total {
+		return nil, nil, nil
+	}
+	data := make([]byte, 0, q.total)
+	for _, f := range q.fragments {
+		data = append(data, f.data...)
+	}
+	r.drop(key)
+	return q, data, nil
+}
+
+// Expire drops the incomplete datagrams which have exceeded the timeout and
+// returns the number of dropped datagrams. It is also done implicitly each
+// time a fragment is added.
+func (r *Reassembler) Expire() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.init()
+	return r.expire(r.now())
+}
+
+// Pending returns the number of incomplete datagrams and the number of
+// buffered payload bytes.
+func (r *Reassembler) Pending() (datagrams int, bytes int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.datagrams), r.bytes
+}
+
+// init initializes the fields of a Reassembler not created by NewReassembler.
+func (r *Reassembler) init() {
+	if r.datagrams == nil {
+		r.datagrams = make(map[fragmentKey]*fragmentQueue)
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+}
+
+func (r *Reassembler) timeout() time.Duration {
+	if r.Timeout <= 0 {
+		return DefaultReassemblyTimeout
+	}
+	return r.Timeout
+}
+
+func (r *Reassembler) maxBytes() int {
+	if r.MaxBytes <= 0 {
+		return DefaultReassemblyMaxBytes
+	}
+	return r.MaxBytes
+}
+
+func (r *Reassembler) expire(now time.Time) int {
+	count := 0
+	timeout := r.timeout()
+	for key, q := range r.datagrams {
+		if now.Sub(q.created) >= timeout {
+			r.drop(key)
+			count++
+		}
+	}
+	return count
+}
+
+// dropOldest drops the oldest datagram other than current. It returns false if
+// there is no such datagram.
+func (r *Reassembler) dropOldest(current fragmentKey) bool {
+	var oldestKey fragmentKey
+	var oldest *fragmentQueue
+	for key, q := range r.datagrams {
+		if key == current {
+			continue
+		}
+		if oldest == nil || q.created.Before(oldest.created) {
+			oldestKey, oldest = key, q
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	r.drop(oldestKey)
+	return true
+}
+
+func (r *Reassembler) drop(key fragmentKey) {
+	if q, ok := r.datagrams[key]; ok {
+		r.bytes -= q.bytes
+		delete(r.datagrams, key)
+	}
+}
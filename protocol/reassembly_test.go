@@ -0,0 +1,245 @@
+package protocol
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
+)
+
+func newTestUDPPayload(size int) []byte {
+	udp := NewUDP()
+	udp.PortSrc = 1000
+	udp.PortDst = 2000
+	udp.Data = make([]byte, size)
+	for i := range udp.Data {
+		udp.Data[i] = byte(i)
+	}
+	udp.Length = udp.Len()
+	b, _ := udp.MarshalBinary()
+	return b
+}
+
+// fragmentIPv4 splits the payload in fragments of fragSize bytes and returns
+// the decoded fragments, as they would be received in PacketIn messages.
+func fragmentIPv4(t *testing.T, payload []byte, fragSize int) []*IPv4 {
+	var fragments []*IPv4
+	for offset := 0; offset < len(payload); offset += fragSize {
+		end := offset + fragSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		ip := NewIPv4()
+		ip.Version = 4
+		ip.IHL = 5
+		ip.Id = 0x1234
+		ip.TTL = 64
+		ip.Protocol = Type_UDP
+		ip.NWSrc = net.ParseIP("10.0.0.1")
+		ip.NWDst = net.ParseIP("10.0.0.2")
+		ip.FragmentOffset = uint16(offset / 8)
+		if end < len(payload) {
+			ip.Flags = IPv4_FLAG_MF
+		}
+		ip.Data = util.NewBuffer(payload[offset:end])
+		ip.Length = ip.Len()
+		b, err := ip.MarshalBinary()
+		require.NoError(t, err)
+		fragment := new(IPv4)
+		require.NoError(t, fragment.UnmarshalBinary(b))
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+func TestIPv4Reassembly(t *testing.T) {
+	payload := newTestUDPPayload(3000)
+	fragments := fragmentIPv4(t, payload, 1480)
+	require.Len(t, fragments, 3)
+	for _, f := range fragments {
+		assert.True(t, f.IsFragment())
+		_, ok := f.Data.(*util.Buffer)
+		assert.True(t, ok, "Fragment payload must not be decoded")
+	}
+
+	r := NewReassembler(0, 0)
+	for _, i := range []int{2, 0} {
+		pkt, err := r.AddIPv4(fragments[i])
+		require.NoError(t, err)
+		assert.Nil(t, pkt)
+	}
+	datagrams, bytes := r.Pending()
+	assert.Equal(t, 1, datagrams)
+	assert.Equal(t, len(payload)-1480, bytes)
+
+	pkt, err := r.AddIPv4(fragments[1])
+	require.NoError(t, err)
+	require.NotNil(t, pkt)
+	assert.False(t, pkt.IsFragment())
+	assert.Equal(t, uint16(20+len(payload)), pkt.Length)
+	udp, ok := pkt.Data.(*UDP)
+	require.True(t, ok, "Reassembled payload is not UDP")
+	assert.Equal(t, uint16(2000), udp.PortDst)
+	assert.Equal(t, payload[8:], udp.Data)
+
+	datagrams, bytes = r.Pending()
+	assert.Equal(t, 0, datagrams)
+	assert.Equal(t, 0, bytes)
+}
+
+func TestIPv6Reassembly(t *testing.T) {
+	payload := newTestUDPPayload(2000)
+	var fragments []*IPv6
+	for offset := 0; offset < len(payload); offset += 1232 {
+		end := offset + 1232
+		if end > len(payload) {
+			end = len(payload)
+		}
+		ip := &IPv6{
+			Version:    6,
+			NextHeader: Type_Fragment,
+			HopLimit:   64,
+			NWSrc:      net.ParseIP("2001:db8::1"),
+			NWDst:      net.ParseIP("2001:db8::2"),
+			FragmentHeader: &FragmentHeader{
+				NextHeader:     Type_UDP,
+				FragmentOffset: uint16(offset / 8),
+				MoreFragments:  end < len(payload),
+				Identification: 0xabcd,
+			},
+			Data: util.NewBuffer(payload[offset:end]),
+		}
+		ip.Length = ip.Len() - 40
+		b, err := ip.MarshalBinary()
+		require.NoError(t, err)
+		fragment := new(IPv6)
+		require.NoError(t, fragment.UnmarshalBinary(b))
+		fragments = append(fragments, fragment)
+	}
+	require.Len(t, fragments, 2)
+
+	r := NewReassembler(0, 0)
+	pkt, err := r.AddIPv6(fragments[1])
+	require.NoError(t, err)
+	assert.Nil(t, pkt)
+	pkt, err = r.AddIPv6(fragments[0])
+	require.NoError(t, err)
+	require.NotNil(t, pkt)
+	assert.Nil(t, pkt.FragmentHeader)
+	assert.Equal(t, uint8(Type_UDP), pkt.NextHeader)
+	assert.Equal(t, uint16(len(payload)), pkt.Length)
+	udp, ok := pkt.Data.(*UDP)
+	require.True(t, ok, "Reassembled payload is not UDP")
+	assert.Equal(t, payload[8:], udp.Data)
+}
+
+func TestReassemblyLimits(t *testing.T) {
+	now := time.Now()
+	r := NewReassembler(time.Second, 2000)
+	r.now = func() time.Time { return now }
+
+	fragments := fragmentIPv4(t, newTestUDPPayload(3000), 1480)
+	_, err := r.AddIPv4(fragments[0])
+	require.NoError(t, err)
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 1, r.Expire())
+	datagrams, _ := r.Pending()
+	assert.Equal(t, 0, datagrams)
+
+	// Overlapping fragments drop the datagram.
+	_, err = r.AddIPv4(fragments[0])
+	require.NoError(t, err)
+	overlap := *fragments[1]
+	overlap.FragmentOffset = 100
+	_, err = r.AddIPv4(&overlap)
+	assert.Error(t, err)
+	datagrams, _ = r.Pending()
+	assert.Equal(t, 0, datagrams)
+
+	// Exceeding the memory limit drops the oldest datagram.
+	_, err = r.AddIPv4(fragments[0])
+	require.NoError(t, err)
+	now = now.Add(time.Millisecond)
+	other := *fragments[1]
+	other.Id = 0x5678
+	_, err = r.AddIPv4(&other)
+	require.NoError(t, err)
+	datagrams, bytes := r.Pending()
+	assert.Equal(t, 1, datagrams)
+	assert.Equal(t, 1480, bytes)
+}
+
+func TestIPv4Options(t *testing.T) {
+	ip := NewIPv4()
+	ip.Version = 4
+	ip.Protocol = Type_IGMP
+	ip.Data = NewIGMPv2Report(net.ParseIP("224.0.0.251"))
+	ts := &IPv4Timestamp{
+		Pointer: 13,
+		Flag:    IPv4_TS_AND_ADDR,
+		Entries: []IPv4TimestampEntry{{Addr: net.ParseIP("10.0.0.1").To4(), Timestamp: 1000}},
+	}
+	require.NoError(t, ip.SetOptions([]IPv4Option{NewIPv4RouterAlert(), NewIPv4RecordRoute(2), ts}))
+	// 4 + 11 + 12 bytes padded to 28.
+	assert.Equal(t, uint8(12), ip.IHL)
+
+	b, err := ip.MarshalBinary()
+	require.NoError(t, err)
+	newIP := new(IPv4)
+	require.NoError(t, newIP.UnmarshalBinary(b))
+	options, err := newIP.GetOptions()
+	require.NoError(t, err)
+	require.Len(t, options, 3)
+	assert.Equal(t, NewIPv4RouterAlert(), options[0])
+	rr, ok := options[1].(*IPv4RecordRoute)
+	require.True(t, ok)
+	assert.Equal(t, uint8(IPv4_OPT_RECORD_ROUTE), rr.OptionType())
+	assert.Equal(t, uint8(4), rr.Pointer)
+	assert.Len(t, rr.Routes, 2)
+	assert.Equal(t, ts, options[2])
+}
+
+func TestReassemblerZeroValue(t *testing.T) {
+	r := new(Reassembler)
+	fragments := fragmentIPv4(t, newTestUDPPayload(3000), 1480)
+	var result *IPv4
+	var err error
+	for _, fragment := range fragments {
+		result, err = r.AddIPv4(fragment)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, result)
+	assert.Equal(t, 0, r.Expire())
+
+	// A non-final fragment must carry a multiple of 8 bytes.
+	fragments = fragmentIPv4(t, newTestUDPPayload(3000), 1481)
+	_, err = r.AddIPv4(fragments[0])
+	assert.Error(t, err)
+	datagrams, _ := r.Pending()
+	assert.Equal(t, 0, datagrams)
+}
+
+func TestIPv4BadHeaderLength(t *testing.T) {
+	ip := NewIPv4()
+	ip.Version = 4
+	ip.IHL = 5
+	ip.Protocol = Type_UDP
+	ip.NWSrc = net.ParseIP("10.0.0.1")
+	ip.NWDst = net.ParseIP("10.0.0.2")
+	ip.Data = util.NewBuffer(make([]byte, 18))
+	ip.Length = ip.Len()
+	data, err := ip.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 38)
+
+	// The options would end beyond the data.
+	data[0] = 4<<4 | 12
+	assert.Error(t, new(IPv4).UnmarshalBinary(data))
+	// The header is shorter than the fixed header.
+	data[0] = 4<<4 | 4
+	assert.Error(t, new(IPv4).UnmarshalBinary(data))
+}
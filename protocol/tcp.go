@@ -3,6 +3,33 @@ package protocol
 import (
 	"encoding/binary"
 	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// TCP flags. The flags up to TCP_FLAG_CWR are carried in the Code field, the NS
+// flag (RFC 3540) is the low bit of the data offset byte.
+const (
+	TCP_FLAG_FIN = 0x01
+	TCP_FLAG_SYN = 0x02
+	TCP_FLAG_RST = 0x04
+	TCP_FLAG_PSH = 0x08
+	TCP_FLAG_ACK = 0x10
+	TCP_FLAG_URG = 0x20
+	TCP_FLAG_ECE = 0x40
+	TCP_FLAG_CWR = 0x80
+	TCP_FLAG_NS  = 0x100
+)
+
+// TCP option kinds.
+const (
+	TCP_OPT_EOL            = 0
+	TCP_OPT_NOP            = 1
+	TCP_OPT_MSS            = 2
+	TCP_OPT_WINDOW_SCALE   = 3
+	TCP_OPT_SACK_PERMITTED = 4
+	TCP_OPT_SACK           = 5
+	TCP_OPT_TIMESTAMPS     = 8
 )
 
 type TCP struct {
@@ -11,14 +38,23 @@ type TCP struct {
 	SeqNum  uint32
 	AckNum  uint32
 
+	// HdrLen is the data offset in 32-bit words. If Options is nil, HdrLen is
+	// written as is and Data carries the raw option bytes, if any. Otherwise the
+	// options are padded with TCP_OPT_EOL to HdrLen words, or to the length of
+	// the options if they don't fit in HdrLen words.
 	HdrLen uint8
-	Code   uint8
+	// Code carries all the TCP_FLAG_* bits but TCP_FLAG_NS. It is not masked
+	// with 0x3f, as ECE and CWR (RFC 3168) are the two upper bits.
+	Code uint8
+	// ns is the NS flag, which is accessed with NS and SetFlag.
+	ns bool
 
 	WinSize  uint16
 	Checksum uint16
 	UrgFlag  uint16
 
-	Data []byte
+	Options []*TCPOption
+	Data    []byte
 }
 
 func NewTCP() *TCP {
@@ -27,11 +63,37 @@ func NewTCP() *TCP {
 	return u
 }
 
+// headerLen returns the length of the TCP header written before Data. It is
+// the fixed header if Options is nil, or the header with the options padded as
+// described in HdrLen.
+func (t *TCP) headerLen() uint16 {
+	n := uint16(20)
+	if t.Options == nil {
+		return n
+	}
+	for _, o := range t.Options {
+		n += o.Len()
+	}
+	n = (n + 3) / 4 * 4
+	if hdrLen := uint16(t.HdrLen) * 4; hdrLen > n {
+		n = hdrLen
+	}
+	return n
+}
+
+// dataOffset returns the data offset written in the TCP header.
+func (t *TCP) dataOffset() uint8 {
+	if t.Options == nil && t.HdrLen >= 5 {
+		return t.HdrLen
+	}
+	return uint8(t.headerLen() / 4)
+}
+
 func (t *TCP) Len() (n uint16) {
 	if t.Data != nil {
-		return uint16(20 + len(t.Data))
+		return t.headerLen() + uint16(len(t.Data))
 	}
-	return uint16(20)
+	return t.headerLen()
 }
 
 func (t *TCP) MarshalBinary() (data []byte, err error) {
@@ -41,21 +103,36 @@ func (t *TCP) MarshalBinary() (data []byte, err error) {
 	binary.BigEndian.PutUint32(data[4:8], t.SeqNum)
 	binary.BigEndian.PutUint32(data[8:12], t.AckNum)
 
-	data[12] = (t.HdrLen << 4) & 0xf0
-	data[13] = t.Code & 0x3f
+	hdrLen := t.headerLen()
+	data[12] = t.dataOffset() << 4
+	if t.ns {
+		data[12] |= 0x01
+	}
+	data[13] = t.Code
 
 	binary.BigEndian.PutUint16(data[14:16], t.WinSize)
 	binary.BigEndian.PutUint16(data[16:18], t.Checksum)
 	binary.BigEndian.PutUint16(data[18:20], t.UrgFlag)
 
-	copy(data[20:], t.Data)
+	// Padding after the options is filled with zeros, i.e., TCP_OPT_EOL.
+	n := 20
+	for _, o := range t.Options {
+		var b []byte
+		if b, err = o.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+		n += len(b)
+	}
+
+	copy(data[hdrLen:], t.Data)
 
 	return
 }
 
 func (t *TCP) UnmarshalBinary(data []byte) error {
 	if len(data) < 20 {
-		return errors.New("The []byte is too short to unmarshal a full ARP message.")
+		return errors.New("The []byte is too short to unmarshal a full TCP message.")
 	}
 	t.PortSrc = binary.BigEndian.Uint16(data[:2])
 	t.PortDst = binary.BigEndian.Uint16(data[2:4])
@@ -63,17 +140,237 @@ func (t *TCP) UnmarshalBinary(data []byte) error {
 	t.AckNum = binary.BigEndian.Uint32(data[8:12])
 
 	t.HdrLen = (data[12] >> 4) & 0xf
-	t.Code = data[13] & 0x3f
+	t.ns = data[12]&0x01 != 0
+	t.Code = data[13]
 
 	t.WinSize = binary.BigEndian.Uint16(data[14:16])
 	t.Checksum = binary.BigEndian.Uint16(data[16:18])
 	t.UrgFlag = binary.BigEndian.Uint16(data[18:20])
 
-	if len(data) > 20 {
-		t.Data = make([]byte, (len(data) - 20))
-		copy(t.Data, data[20:])
+	hdrLen := int(t.HdrLen) * 4
+	if hdrLen < 20 || len(data) < hdrLen {
+		return errors.New("The []byte is too short to unmarshal the TCP header with options.")
+	}
+
+	// Options is not nil if the header has options, even if they are only
+	// TCP_OPT_EOL and padding, so that the header is marshaled with them.
+	t.Options = nil
+	if hdrLen > 20 {
+		t.Options = []*TCPOption{}
+	}
+	n := 20
+	for n < hdrLen {
+		o := new(TCPOption)
+		if err := o.UnmarshalBinary(data[n:hdrLen]); err != nil {
+			return err
+		}
+		if o.Kind == TCP_OPT_EOL {
+			break
+		}
+		t.Options = append(t.Options, o)
+		n += int(o.Len())
+	}
+
+	t.Data = make([]byte, len(data)-hdrLen)
+	copy(t.Data, data[hdrLen:])
+
+	return nil
+}
+
+func (t *TCP) FIN() bool { return t.Code&TCP_FLAG_FIN != 0 }
+func (t *TCP) SYN() bool { return t.Code&TCP_FLAG_SYN != 0 }
+func (t *TCP) RST() bool { return t.Code&TCP_FLAG_RST != 0 }
+func (t *TCP) PSH() bool { return t.Code&TCP_FLAG_PSH != 0 }
+func (t *TCP) ACK() bool { return t.Code&TCP_FLAG_ACK != 0 }
+func (t *TCP) URG() bool { return t.Code&TCP_FLAG_URG != 0 }
+func (t *TCP) ECE() bool { return t.Code&TCP_FLAG_ECE != 0 }
+func (t *TCP) CWR() bool { return t.Code&TCP_FLAG_CWR != 0 }
+func (t *TCP) NS() bool  { return t.ns }
+
+// Flags returns all the TCP_FLAG_* bits, including the NS flag.
+func (t *TCP) Flags() uint16 {
+	flags := uint16(t.Code)
+	if t.ns {
+		flags |= TCP_FLAG_NS
+	}
+	return flags
+}
+
+// SetFlag sets or clears the given TCP_FLAG_* bits, including the NS flag.
+func (t *TCP) SetFlag(flag uint16, value bool) {
+	if value {
+		t.Code |= uint8(flag)
+	} else {
+		t.Code &^= uint8(flag)
+	}
+	if flag&TCP_FLAG_NS != 0 {
+		t.ns = value
+	}
+}
+
+// decodeTCP decodes the TCP segment of an IP packet. A truncated or malformed
+// TCP header is not an error for the IP packet, it is kept as a util.Buffer.
+func decodeTCP(data []byte) util.Message {
+	t := NewTCP()
+	if err := t.UnmarshalBinary(data); err != nil {
+		return util.NewBuffer(append([]byte(nil), data...))
+	}
+	return t
+}
+
+// GetOption returns the first option of the given kind, or nil if the TCP
+// header does not carry it.
+func (t *TCP) GetOption(kind uint8) *TCPOption {
+	for _, o := range t.Options {
+		if o.Kind == kind {
+			return o
+		}
+	}
+	return nil
+}
+
+// SetOption replaces the first option of the same kind, or appends the option
+// if the TCP header does not carry it yet.
+func (t *TCP) SetOption(option *TCPOption) {
+	for i, o := range t.Options {
+		if o.Kind == option.Kind {
+			t.Options[i] = option
+			return
+		}
+	}
+	t.Options = append(t.Options, option)
+}
+
+// MSS returns the maximum segment size option value.
+func (t *TCP) MSS() (uint16, bool) {
+	o := t.GetOption(TCP_OPT_MSS)
+	if o == nil || len(o.Data) < 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(o.Data), true
+}
+
+// WindowScale returns the window scale option shift count.
+func (t *TCP) WindowScale() (uint8, bool) {
+	o := t.GetOption(TCP_OPT_WINDOW_SCALE)
+	if o == nil || len(o.Data) < 1 {
+		return 0, false
 	}
+	return o.Data[0], true
+}
 
+// SACKPermitted returns whether the SACK permitted option is present.
+func (t *TCP) SACKPermitted() bool {
+	return t.GetOption(TCP_OPT_SACK_PERMITTED) != nil
+}
+
+// SACKBlocks returns the blocks carried in the SACK option.
+func (t *TCP) SACKBlocks() []TCPSACKBlock {
+	o := t.GetOption(TCP_OPT_SACK)
+	if o == nil {
+		return nil
+	}
+	var blocks []TCPSACKBlock
+	for n := 0; n+8 <= len(o.Data); n += 8 {
+		blocks = append(blocks, TCPSACKBlock{
+			Left:  binary.BigEndian.Uint32(o.Data[n:]),
+			Right: binary.BigEndian.Uint32(o.Data[n+4:]),
+		})
+	}
+	return blocks
+}
+
+// Timestamps returns the TSval and TSecr values of the timestamps option.
+func (t *TCP) Timestamps() (tsVal uint32, tsEcr uint32, ok bool) {
+	o := t.GetOption(TCP_OPT_TIMESTAMPS)
+	if o == nil || len(o.Data) < 8 {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint32(o.Data), binary.BigEndian.Uint32(o.Data[4:]), true
+}
+
+// TCPOption is a TCP option in Kind-Length-Data format. The EOL and NOP
+// options are a single byte without Length and Data. Length covers the Kind
+// and Length bytes, as on the wire, and is computed from Data when the option
+// is marshaled.
+type TCPOption struct {
+	Kind   uint8
+	Length uint8
+	Data   []byte
+}
+
+func (o *TCPOption) Len() uint16 {
+	if o.Kind == TCP_OPT_EOL || o.Kind == TCP_OPT_NOP {
+		return 1
+	}
+	return uint16(2 + len(o.Data))
+}
+
+func (o *TCPOption) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(o.Len()))
+	data[0] = o.Kind
+	if o.Len() == 1 {
+		return
+	}
+	data[1] = uint8(o.Len())
+	copy(data[2:], o.Data)
+	return
+}
+
+func (o *TCPOption) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errors.New("The []byte is too short to unmarshal a full TCPOption.")
+	}
+	o.Kind = data[0]
+	if o.Kind == TCP_OPT_EOL || o.Kind == TCP_OPT_NOP {
+		o.Length = 0
+		o.Data = nil
+		return nil
+	}
+	if len(data) < 2 || data[1] < 2 || len(data) < int(data[1]) {
+		return errors.New("The []byte is too short to unmarshal a full TCPOption.")
+	}
+	o.Length = data[1]
+	o.Data = make([]byte, int(o.Length)-2)
+	copy(o.Data, data[2:o.Length])
 	return nil
+}
+
+type TCPSACKBlock struct {
+	Left  uint32
+	Right uint32
+}
+
+func NewTCPOptionNOP() *TCPOption {
+	return &TCPOption{Kind: TCP_OPT_NOP}
+}
+
+func NewTCPOptionMSS(mss uint16) *TCPOption {
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, mss)
+	return &TCPOption{Kind: TCP_OPT_MSS, Length: 4, Data: data}
+}
+
+func NewTCPOptionWindowScale(shift uint8) *TCPOption {
+	return &TCPOption{Kind: TCP_OPT_WINDOW_SCALE, Length: 3, Data: []byte{shift}}
+}
+
+func NewTCPOptionSACKPermitted() *TCPOption {
+	return &TCPOption{Kind: TCP_OPT_SACK_PERMITTED, Length: 2, Data: []byte{}}
+}
+
+func NewTCPOptionSACK(blocks []TCPSACKBlock) *TCPOption {
+	data := make([]byte, 8*len(blocks))
+	for i, b := range blocks {
+		binary.BigEndian.PutUint32(data[8*i:], b.Left)
+		binary.BigEndian.PutUint32(data[8*i+4:], b.Right)
+	}
+	return &TCPOption{Kind: TCP_OPT_SACK, Length: uint8(2 + len(data)), Data: data}
+}
 
+func NewTCPOptionTimestamps(tsVal, tsEcr uint32) *TCPOption {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint32(data, tsVal)
+	binary.BigEndian.PutUint32(data[4:], tsEcr)
+	return &TCPOption{Kind: TCP_OPT_TIMESTAMPS, Length: 10, Data: data}
 }
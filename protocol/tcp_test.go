@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
+)
+
+func TestTCPOptions(t *testing.T) {
+	tcp := NewTCP()
+	tcp.PortSrc = 40000
+	tcp.PortDst = 443
+	tcp.SeqNum = 0x01020304
+	tcp.SetFlag(TCP_FLAG_SYN, true)
+	tcp.SetFlag(TCP_FLAG_ECE, true)
+	tcp.SetFlag(TCP_FLAG_CWR|TCP_FLAG_NS, true)
+	tcp.WinSize = 64240
+	tcp.Options = []*TCPOption{
+		NewTCPOptionMSS(1460),
+		NewTCPOptionSACKPermitted(),
+		NewTCPOptionTimestamps(0x11111111, 0),
+		NewTCPOptionNOP(),
+		NewTCPOptionWindowScale(7),
+	}
+	tcp.Data = []byte{0xaa, 0xbb}
+
+	data, err := tcp.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	// 20 bytes fixed header + 20 bytes options, and the NS flag.
+	assert.Equal(t, byte(10<<4|0x01), data[12])
+	assert.Equal(t, uint8(0), tcp.HdrLen)
+	assert.Equal(t, 42, len(data))
+	assert.Equal(t, tcp.Len(), uint16(len(data)))
+
+	newTCP := new(TCP)
+	require.NoError(t, newTCP.UnmarshalBinary(data), "Failed to Unmarshal message")
+	assert.Equal(t, uint8(10), newTCP.HdrLen)
+	assert.True(t, newTCP.SYN())
+	assert.True(t, newTCP.ECE())
+	assert.True(t, newTCP.CWR())
+	assert.True(t, newTCP.NS())
+	assert.Equal(t, uint16(TCP_FLAG_SYN|TCP_FLAG_ECE|TCP_FLAG_CWR|TCP_FLAG_NS), newTCP.Flags())
+	assert.False(t, newTCP.ACK())
+	assert.False(t, newTCP.FIN())
+	mss, ok := newTCP.MSS()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(1460), mss)
+	wscale, ok := newTCP.WindowScale()
+	assert.True(t, ok)
+	assert.Equal(t, uint8(7), wscale)
+	assert.True(t, newTCP.SACKPermitted())
+	tsVal, tsEcr, ok := newTCP.Timestamps()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x11111111), tsVal)
+	assert.Equal(t, uint32(0), tsEcr)
+	assert.Len(t, newTCP.Options, 5)
+	assert.Equal(t, []byte{0xaa, 0xbb}, newTCP.Data)
+
+	// Clamp the MSS and add SACK blocks, the header length must follow.
+	newTCP.SetOption(NewTCPOptionMSS(1400))
+	blocks := []TCPSACKBlock{{Left: 100, Right: 200}, {Left: 300, Right: 400}}
+	newTCP.SetOption(NewTCPOptionSACK(blocks))
+	data, err = newTCP.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	assert.Equal(t, byte(15<<4|0x01), data[12])
+	assert.Equal(t, newTCP.Len(), uint16(len(data)))
+	clampedTCP := new(TCP)
+	require.NoError(t, clampedTCP.UnmarshalBinary(data), "Failed to Unmarshal message")
+	mss, _ = clampedTCP.MSS()
+	assert.Equal(t, uint16(1400), mss)
+	assert.Equal(t, blocks, clampedTCP.SACKBlocks())
+	assert.Equal(t, []byte{0xaa, 0xbb}, clampedTCP.Data)
+
+	clampedTCP.SetFlag(TCP_FLAG_NS, false)
+	assert.False(t, clampedTCP.NS())
+	assert.True(t, clampedTCP.CWR())
+}
+
+func TestTCPBadHeaderLength(t *testing.T) {
+	tcp := NewTCP()
+	data, err := tcp.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	assert.Equal(t, byte(5<<4), data[12])
+	data[12] = 0x60
+	assert.Error(t, new(TCP).UnmarshalBinary(data))
+
+	// The IP packet is still decoded, the malformed TCP header is kept as raw bytes.
+	ip := NewIPv4()
+	ip.Version = 4
+	ip.IHL = 5
+	ip.Protocol = Type_TCP
+	ip.NWSrc = net.ParseIP("10.10.0.1")
+	ip.NWDst = net.ParseIP("10.10.0.2")
+	ip.Data = util.NewBuffer(data)
+	ip.Length = ip.Len()
+	ipData, err := ip.MarshalBinary()
+	require.NoError(t, err)
+	newIP := new(IPv4)
+	require.NoError(t, newIP.UnmarshalBinary(ipData))
+	require.IsType(t, &util.Buffer{}, newIP.Data)
+	assert.Equal(t, data, newIP.Data.(*util.Buffer).Bytes())
+
+	ip6 := &IPv6{
+		Version:    6,
+		NextHeader: Type_TCP,
+		HopLimit:   64,
+		NWSrc:      net.ParseIP("2001:db8::1"),
+		NWDst:      net.ParseIP("2001:db8::2"),
+		Data:       util.NewBuffer(data[:10]),
+	}
+	ip6.Length = ip6.Data.Len()
+	ip6Data, err := ip6.MarshalBinary()
+	require.NoError(t, err)
+	newIP6 := new(IPv6)
+	require.NoError(t, newIP6.UnmarshalBinary(ip6Data))
+	require.IsType(t, &util.Buffer{}, newIP6.Data)
+}
+
+func TestTCPHeaderLength(t *testing.T) {
+	// The options are carried as raw bytes in Data, the data offset is HdrLen.
+	tcp := NewTCP()
+	tcp.HdrLen = 6
+	tcp.Code = TCP_FLAG_SYN | TCP_FLAG_CWR
+	tcp.Data = []byte{TCP_OPT_MSS, 4, 0x05, 0xb4}
+	data, err := tcp.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	assert.Equal(t, tcp.Len(), uint16(len(data)))
+	assert.Equal(t, "0000000000000000000000006082000000000000020405b4", hex.EncodeToString(data))
+	newTCP := new(TCP)
+	require.NoError(t, newTCP.UnmarshalBinary(data), "Failed to Unmarshal message")
+	mss, ok := newTCP.MSS()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(1460), mss)
+	assert.Equal(t, uint8(TCP_FLAG_SYN|TCP_FLAG_CWR), newTCP.Code)
+
+	// The options end with TCP_OPT_EOL and padding, the decoded header is
+	// marshaled to the same bytes.
+	for _, s := range []string{
+		"9c40005000000001000000007012ffff000000000101000000000000aabb",
+		"9c4000500000000100000000601200000000000000000000aabb",
+	} {
+		data, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		newTCP := new(TCP)
+		require.NoError(t, newTCP.UnmarshalBinary(data), "Failed to Unmarshal message")
+		assert.Equal(t, []byte{0xaa, 0xbb}, newTCP.Data)
+		newData, err := newTCP.MarshalBinary()
+		require.NoError(t, err, "Failed to Marshal message")
+		assert.Equal(t, data, newData)
+		assert.Equal(t, newTCP.Len(), uint16(len(newData)))
+	}
+}
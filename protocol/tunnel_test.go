@@ -0,0 +1,208 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/util"
+)
+
+func newTestInnerFrame() *Ethernet {
+	udp := NewUDP()
+	udp.PortSrc = 1234
+	udp.PortDst = 5678
+	udp.Data = []byte{0x01, 0x02, 0x03, 0x04}
+	ip := NewIPv4()
+	ip.Version = 4
+	ip.IHL = 5
+	ip.TTL = 64
+	ip.Protocol = Type_UDP
+	ip.NWSrc = net.ParseIP("10.10.0.1")
+	ip.NWDst = net.ParseIP("10.10.0.2")
+	ip.Data = udp
+	ip.Length = ip.Len()
+	eth := NewEthernet()
+	eth.HWSrc, _ = net.ParseMAC("aa:bb:cc:dd:ee:01")
+	eth.HWDst, _ = net.ParseMAC("aa:bb:cc:dd:ee:02")
+	eth.Ethertype = IPv4_MSG
+	eth.Data = ip
+	return eth
+}
+
+func newTestOuterFrame(protocol uint8, payload util.Message) *Ethernet {
+	ip := NewIPv4()
+	ip.Version = 4
+	ip.IHL = 5
+	ip.TTL = 64
+	ip.Protocol = protocol
+	ip.NWSrc = net.ParseIP("192.168.0.1")
+	ip.NWDst = net.ParseIP("192.168.0.2")
+	ip.Data = payload
+	ip.Length = ip.Len()
+	eth := NewEthernet()
+	eth.Ethertype = IPv4_MSG
+	eth.Data = ip
+	return eth
+}
+
+func decodeTunneledFrame(t *testing.T, outer *Ethernet) util.Message {
+	data, err := outer.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal message")
+	newMessage := new(Ethernet)
+	require.NoError(t, newMessage.UnmarshalBinary(data), "Failed to Unmarshal message")
+	newData, err := newMessage.MarshalBinary()
+	require.NoError(t, err, "Failed to Marshal decoded message")
+	assert.Equal(t, data, newData)
+	return newMessage.Data.(*IPv4).Data
+}
+
+func assertInnerFrame(t *testing.T, inner util.Message) {
+	eth, ok := inner.(*Ethernet)
+	require.True(t, ok, "Inner payload is not an Ethernet frame")
+	ip, ok := eth.Data.(*IPv4)
+	require.True(t, ok, "Inner Ethernet payload is not IPv4")
+	assert.Equal(t, "10.10.0.2", ip.NWDst.String())
+	udp, ok := ip.Data.(*UDP)
+	require.True(t, ok, "Inner IPv4 payload is not UDP")
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, udp.Data)
+}
+
+func TestVXLAN(t *testing.T) {
+	vxlan := NewVXLAN(0x123456)
+	vxlan.Data = newTestInnerFrame()
+	udp := NewUDP()
+	udp.PortSrc = 50000
+	udp.PortDst = VXLAN_PORT
+	udp.Payload = vxlan
+	outer := newTestOuterFrame(Type_UDP, udp)
+
+	payload := decodeTunneledFrame(t, outer)
+	newUDP := payload.(*UDP)
+	newVXLAN, ok := newUDP.Payload.(*VXLAN)
+	require.True(t, ok, "UDP payload is not VXLAN")
+	assert.Equal(t, uint8(VXLAN_FLAG_VNI), newVXLAN.Flags)
+	assert.Equal(t, uint32(0x123456), newVXLAN.VNI)
+	assertInnerFrame(t, newVXLAN.Data)
+}
+
+func TestGeneve(t *testing.T) {
+	geneve := NewGeneve(0x654321)
+	geneve.Flags = GENEVE_FLAG_CRITICAL
+	geneve.Options = []*GeneveOption{
+		{Class: 0xffff, Type: 0x80, Data: []byte{0x00, 0x00, 0x00, 0x01}},
+		{Class: 0x0102, Type: 0x01, Flags: 0x01, Data: []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}},
+	}
+	geneve.Data = newTestInnerFrame()
+	udp := NewUDP()
+	udp.PortSrc = 50000
+	udp.PortDst = GENEVE_PORT
+	udp.Payload = geneve
+	outer := newTestOuterFrame(Type_UDP, udp)
+
+	payload := decodeTunneledFrame(t, outer)
+	newGeneve, ok := payload.(*UDP).Payload.(*Geneve)
+	require.True(t, ok, "UDP payload is not Geneve")
+	assert.Equal(t, uint32(0x654321), newGeneve.VNI)
+	assert.Equal(t, uint8(GENEVE_FLAG_CRITICAL), newGeneve.Flags)
+	assert.Equal(t, uint16(TEB_MSG), newGeneve.ProtocolType)
+	require.Len(t, newGeneve.Options, 2)
+	option := newGeneve.GetOption(0x0102, 0x01)
+	require.NotNil(t, option)
+	assert.Equal(t, uint8(0x01), option.Flags)
+	assert.Equal(t, geneve.Options[1].Data, option.Data)
+	assert.Nil(t, newGeneve.GetOption(0x0102, 0x02))
+	assertInnerFrame(t, newGeneve.Data)
+}
+
+func TestUDPDataAfterPayloadDecoding(t *testing.T) {
+	udp := NewUDP()
+	udp.PortSrc = 50000
+	udp.PortDst = VXLAN_PORT
+	vxlan := NewVXLAN(0x123456)
+	vxlan.Data = newTestInnerFrame()
+	udp.Payload = vxlan
+	data, err := udp.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(len(data)), binary.BigEndian.Uint16(data[4:6]))
+
+	// The trailing bytes after the UDP length are not part of the datagram.
+	newUDP := NewUDP()
+	require.NoError(t, newUDP.UnmarshalBinary(append(data, 0x00, 0x00)))
+	require.IsType(t, &VXLAN{}, newUDP.Payload)
+	newData, err := newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+
+	// Data set after decoding takes precedence over the decoded payload.
+	newUDP.Data = []byte{0x01, 0x02, 0x03, 0x04}
+	newData, err = newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(12), newUDP.Len())
+	assert.Equal(t, uint16(12), binary.BigEndian.Uint16(newData[4:6]))
+	assert.Equal(t, newUDP.Data, newData[8:])
+
+	// Data edited in place is detected as well.
+	require.NoError(t, newUDP.UnmarshalBinary(data))
+	newUDP.Data[4] = 0xff
+	newData, err = newUDP.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, byte(0xff), newData[12])
+}
+
+func TestNVGRE(t *testing.T) {
+	gre := NewNVGRE(0xabcdef, 0x12)
+	gre.Data = newTestInnerFrame()
+	outer := newTestOuterFrame(Type_GRE, gre)
+
+	payload := decodeTunneledFrame(t, outer)
+	newGRE, ok := payload.(*GRE)
+	require.True(t, ok, "IPv4 payload is not GRE")
+	assert.Equal(t, uint16(GRE_FLAG_KEY), newGRE.Flags)
+	assert.Equal(t, uint32(0xabcdef), newGRE.VSID())
+	assert.Equal(t, uint8(0x12), newGRE.FlowID())
+	assertInnerFrame(t, newGRE.Data)
+}
+
+func TestERSPAN(t *testing.T) {
+	erspanII := NewERSPANII(0x155, 0x12345)
+	erspanII.VLAN = 100
+	erspanII.COS = 5
+	erspanII.Truncated = true
+
+	erspanIII := NewERSPANIII(0x2aa, 0x15, 1)
+	erspanIII.Timestamp = 0x01020304
+	erspanIII.SGT = 0x1234
+	erspanIII.Granularity = 3
+	erspanIII.PlatformSpecific = []byte{0x08, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04}
+
+	for _, tc := range []struct {
+		erspan       *ERSPAN
+		protocolType uint16
+	}{
+		{erspan: erspanII, protocolType: ERSPAN_II_MSG},
+		{erspan: erspanIII, protocolType: ERSPAN_III_MSG},
+	} {
+		tc.erspan.Data = newTestInnerFrame()
+		gre := NewGRE(tc.protocolType)
+		gre.Flags = GRE_FLAG_SEQ
+		gre.SeqNum = 10
+		gre.Data = tc.erspan
+		outer := newTestOuterFrame(Type_GRE, gre)
+
+		payload := decodeTunneledFrame(t, outer)
+		newGRE := payload.(*GRE)
+		assert.Equal(t, uint32(10), newGRE.SeqNum)
+		newERSPAN, ok := newGRE.Data.(*ERSPAN)
+		require.True(t, ok, "GRE payload is not ERSPAN")
+		oriERSPAN := *tc.erspan
+		oriERSPAN.Data = nil
+		newERSPANCopy := *newERSPAN
+		newERSPANCopy.Data = nil
+		assert.Equal(t, oriERSPAN, newERSPANCopy)
+		assertInnerFrame(t, newERSPAN.Data)
+	}
+}
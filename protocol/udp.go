@@ -1,8 +1,20 @@
 package protocol
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// Well-known UDP ports whose payload is decoded automatically.
+const (
+	VXLAN_PORT         = 4789
+	GENEVE_PORT        = 6081
+	DHCPv6_CLIENT_PORT = 546
+	DHCPv6_SERVER_PORT = 547
+	DNS_PORT           = 53
 )
 
 type UDP struct {
@@ -11,6 +23,12 @@ type UDP struct {
 	Length   uint16
 	Checksum uint16
 	Data     []byte
+	// Payload is the decoded form of Data if the UDP port identifies a known
+	// protocol. Payload takes precedence over Data in Len and MarshalBinary,
+	// unless Data has been changed since Payload was decoded from it.
+	Payload util.Message
+	// payloadData is a copy of the Data that Payload was decoded from.
+	payloadData []byte
 }
 
 func NewUDP() *UDP {
@@ -19,7 +37,16 @@ func NewUDP() *UDP {
 	return u
 }
 
+// usePayload returns true if Payload is marshaled instead of Data, i.e. it was
+// set by the caller, or it was decoded and Data is unchanged since.
+func (u *UDP) usePayload() bool {
+	return u.Payload != nil && (u.payloadData == nil || bytes.Equal(u.Data, u.payloadData))
+}
+
 func (u *UDP) Len() (n uint16) {
+	if u.usePayload() {
+		return 8 + u.Payload.Len()
+	}
 	if u.Data != nil {
 		return uint16(8 + len(u.Data))
 	}
@@ -30,8 +57,17 @@ func (u *UDP) MarshalBinary() (data []byte, err error) {
 	data = make([]byte, int(u.Len()))
 	binary.BigEndian.PutUint16(data[:2], u.PortSrc)
 	binary.BigEndian.PutUint16(data[2:4], u.PortDst)
-	binary.BigEndian.PutUint16(data[4:6], u.Length)
+	// The length always matches the marshaled bytes, regardless of u.Length.
+	binary.BigEndian.PutUint16(data[4:6], uint16(len(data)))
 	binary.BigEndian.PutUint16(data[6:8], u.Checksum)
+	if u.usePayload() {
+		var b []byte
+		if b, err = u.Payload.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		copy(data[8:], b)
+		return
+	}
 	copy(data[8:], u.Data)
 	return
 }
@@ -44,7 +80,41 @@ func (u *UDP) UnmarshalBinary(data []byte) error {
 	u.PortDst = binary.BigEndian.Uint16(data[2:4])
 	u.Length = binary.BigEndian.Uint16(data[4:6])
 	u.Checksum = binary.BigEndian.Uint16(data[6:8])
-	u.Data = append(u.Data, data[8:]...)
+	end := len(data)
+	// Ignore the trailing bytes after the datagram, e.g. the Ethernet padding.
+	if int(u.Length) >= 8 && int(u.Length) < end {
+		end = int(u.Length)
+	}
+	u.Data = append([]byte(nil), data[8:end]...)
 
+	// A payload which cannot be decoded is not an error for the UDP packet
+	// itself, the raw bytes are still available in Data.
+	u.Payload = nil
+	u.payloadData = nil
+	if payload := newUDPPayload(u.PortSrc, u.PortDst); payload != nil {
+		if err := payload.UnmarshalBinary(u.Data); err == nil {
+			u.Payload = payload
+			u.payloadData = make([]byte, len(u.Data))
+			copy(u.payloadData, u.Data)
+		}
+	}
+	return nil
+}
+
+// newUDPPayload returns an empty message to decode the UDP payload according
+// to the source and destination ports, or nil if the ports are unknown.
+func newUDPPayload(portSrc, portDst uint16) util.Message {
+	switch portDst {
+	case VXLAN_PORT:
+		return new(VXLAN)
+	case GENEVE_PORT:
+		return new(Geneve)
+	case DHCPv6_CLIENT_PORT, DHCPv6_SERVER_PORT:
+		return new(DHCPv6)
+	}
+	// DNS responses are sent from port 53.
+	if portSrc == DNS_PORT || portDst == DNS_PORT {
+		return new(DNS)
+	}
 	return nil
 }
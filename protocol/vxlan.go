@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"antrea.io/libOpenflow/util"
+)
+
+// VXLAN flag indicating that the VNI field is valid.
+const VXLAN_FLAG_VNI = 0x08
+
+// VXLAN (RFC 7348):
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|R|R|R|R|I|R|R|R|            Reserved                           |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|                VXLAN Network Identifier (VNI) |   Reserved    |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+type VXLAN struct {
+	Flags uint8
+	VNI   uint32 // 24-bits
+	Data  util.Message
+}
+
+func NewVXLAN(vni uint32) *VXLAN {
+	return &VXLAN{
+		Flags: VXLAN_FLAG_VNI,
+		VNI:   vni,
+	}
+}
+
+func (v *VXLAN) Len() (n uint16) {
+	n = 8
+	if v.Data != nil {
+		n += v.Data.Len()
+	}
+	return
+}
+
+func (v *VXLAN) MarshalBinary() (data []byte, err error) {
+	data = make([]byte, int(v.Len()))
+	n := 0
+	data[n] = v.Flags
+	n += 4
+	binary.BigEndian.PutUint32(data[n:], v.VNI<<8)
+	n += 4
+	if v.Data != nil {
+		var b []byte
+		if b, err = v.Data.MarshalBinary(); err != nil {
+			return
+		}
+		copy(data[n:], b)
+	}
+	return
+}
+
+func (v *VXLAN) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("The []byte is too short to unmarshal a full VXLAN message.")
+	}
+	n := 0
+	v.Flags = data[n]
+	n += 4
+	v.VNI = binary.BigEndian.Uint32(data[n:]) >> 8
+	n += 4
+	v.Data = new(Ethernet)
+	return v.Data.UnmarshalBinary(data[n:])
+}
@@ -0,0 +1,544 @@
+// Package translate converts the messages of the controller between the openflow13 and openflow15 packages, so
+// that the same flows, groups and meters can be programmed on switches speaking either version.
+//
+// The OXM match fields, the actions and the meter bands have the same encoding in both versions, they are
+// converted by decoding their encoding with the target package. The constructs which cannot be expressed in the
+// target version are reported with an *UnsupportedError, a message converted without error is converted back to
+// the same message.
+package translate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"antrea.io/libOpenflow/common"
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/openflow15"
+	"antrea.io/libOpenflow/util"
+)
+
+// UnsupportedError reports the constructs of a message which cannot be expressed in the target version.
+type UnsupportedError struct {
+	Version    uint8
+	Constructs []string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("unsupported in OpenFlow version %d: %s", e.Version, strings.Join(e.Constructs, ", "))
+}
+
+// converter collects the unsupported constructs while converting a message.
+type converter struct {
+	version     uint8
+	unsupported []string
+}
+
+func (c *converter) unsupportedf(format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf(format, args...))
+}
+
+func (c *converter) err() error {
+	if len(c.unsupported) == 0 {
+		return nil
+	}
+	return &UnsupportedError{Version: c.version, Constructs: c.unsupported}
+}
+
+func (c *converter) header(h common.Header) common.Header {
+	h.Version = c.version
+	return h
+}
+
+// reencode marshals msg and unmarshals the result into target, it checks target has the same encoding.
+func (c *converter) reencode(name string, msg util.Message, target util.Message) bool {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		c.unsupportedf("%s: %v", name, err)
+		return false
+	}
+	if err := target.UnmarshalBinary(data); err != nil {
+		c.unsupportedf("%s: %v", name, err)
+		return false
+	}
+	if newData, err := target.MarshalBinary(); err != nil || !bytes.Equal(newData, data) {
+		c.unsupportedf("%s", name)
+		return false
+	}
+	return true
+}
+
+// To15 converts an openflow13 FlowMod, GroupMod, MeterMod, PacketOut or MultipartRequest to openflow15.
+func To15(msg util.Message) (util.Message, error) {
+	switch msg := msg.(type) {
+	case *openflow13.FlowMod:
+		return FlowModTo15(msg)
+	case *openflow13.GroupMod:
+		return GroupModTo15(msg)
+	case *openflow13.MeterMod:
+		return MeterModTo15(msg)
+	case *openflow13.PacketOut:
+		return PacketOutTo15(msg)
+	case *openflow13.MultipartRequest:
+		return MultipartRequestTo15(msg)
+	}
+	return nil, fmt.Errorf("unsupported message to translate: %T", msg)
+}
+
+// To13 converts an openflow15 FlowMod, GroupMod, MeterMod, PacketOut or MultipartRequest to openflow13.
+func To13(msg util.Message) (util.Message, error) {
+	switch msg := msg.(type) {
+	case *openflow15.FlowMod:
+		return FlowModTo13(msg)
+	case *openflow15.GroupMod:
+		return GroupModTo13(msg)
+	case *openflow15.MeterMod:
+		return MeterModTo13(msg)
+	case *openflow15.PacketOut:
+		return PacketOutTo13(msg)
+	case *openflow15.MultipartRequest:
+		return MultipartRequestTo13(msg)
+	}
+	return nil, fmt.Errorf("unsupported message to translate: %T", msg)
+}
+
+func (c *converter) matchFieldsTo15(fields []openflow13.MatchField) []openflow15.MatchField {
+	var result []openflow15.MatchField
+	for i := range fields {
+		var field openflow15.MatchField
+		if c.reencode(fmt.Sprintf("match field class %#x field %d", fields[i].Class, fields[i].Field), &fields[i], &field) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func (c *converter) matchFieldsTo13(fields []openflow15.MatchField) []openflow13.MatchField {
+	var result []openflow13.MatchField
+	for i := range fields {
+		var field openflow13.MatchField
+		if c.reencode(fmt.Sprintf("match field class %#x field %d", fields[i].Class, fields[i].Field), &fields[i], &field) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func (c *converter) matchTo15(m *openflow13.Match) openflow15.Match {
+	match := openflow15.NewMatch()
+	for _, field := range c.matchFieldsTo15(m.Fields) {
+		match.AddField(field)
+	}
+	return *match
+}
+
+func (c *converter) matchTo13(m *openflow15.Match) openflow13.Match {
+	match := openflow13.NewMatch()
+	for _, field := range c.matchFieldsTo13(m.Fields) {
+		match.AddField(field)
+	}
+	return *match
+}
+
+func (c *converter) actionsTo15(actions []openflow13.Action) []openflow15.Action {
+	result := make([]openflow15.Action, 0, len(actions))
+	for _, act := range actions {
+		data, err := act.MarshalBinary()
+		if err == nil {
+			var newAct openflow15.Action
+			if newAct, err = openflow15.DecodeAction(data); err == nil {
+				result = append(result, newAct)
+				continue
+			}
+		}
+		c.unsupportedf("action %T: %v", act, err)
+	}
+	return result
+}
+
+func (c *converter) actionsTo13(actions []openflow15.Action) []openflow13.Action {
+	result := make([]openflow13.Action, 0, len(actions))
+	for _, act := range actions {
+		data, err := act.MarshalBinary()
+		if err == nil {
+			if binary.BigEndian.Uint16(data) == openflow15.ActionType_Meter {
+				c.unsupportedf("meter action not at the beginning of the apply-actions instruction")
+				continue
+			}
+			var newAct openflow13.Action
+			if newAct, err = openflow13.DecodeAction(data); err == nil {
+				result = append(result, newAct)
+				continue
+			}
+		}
+		c.unsupportedf("action %T: %v", act, err)
+	}
+	return result
+}
+
+// instructionsTo15 converts the instructions, the meter instruction is converted to the meter action at the
+// beginning of the apply-actions instruction.
+func (c *converter) instructionsTo15(instructions []openflow13.Instruction) []openflow15.Instruction {
+	result := make([]openflow15.Instruction, 0, len(instructions))
+	var meter *openflow15.ActionMeter
+	var applyActions *openflow15.InstrActions
+	for _, instr := range instructions {
+		switch instr := instr.(type) {
+		case *openflow13.InstrMeter:
+			meter = openflow15.NewActionMeter(instr.MeterId)
+		case *openflow13.InstrGotoTable:
+			result = append(result, openflow15.NewInstrGotoTable(instr.TableId))
+		case *openflow13.InstrWriteMetadata:
+			result = append(result, openflow15.NewInstrWriteMetadata(instr.Metadata, instr.MetadataMask))
+		case *openflow13.InstrActions:
+			newInstr := openflow15.NewInstrApplyActions()
+			newInstr.Type = instr.Type
+			for _, act := range c.actionsTo15(instr.Actions) {
+				newInstr.AddAction(act, false)
+			}
+			if instr.Type == openflow13.InstrType_APPLY_ACTIONS {
+				applyActions = newInstr
+			}
+			result = append(result, newInstr)
+		default:
+			c.unsupportedf("instruction %T", instr)
+		}
+	}
+	if meter != nil {
+		if applyActions == nil {
+			applyActions = openflow15.NewInstrApplyActions()
+			result = append([]openflow15.Instruction{applyActions}, result...)
+		}
+		applyActions.AddAction(meter, true)
+	}
+	return result
+}
+
+// instructionsTo13 converts the instructions, the meter action at the beginning of the apply-actions instruction is
+// converted to the meter instruction.
+func (c *converter) instructionsTo13(instructions []openflow15.Instruction) []openflow13.Instruction {
+	result := make([]openflow13.Instruction, 0, len(instructions)+1)
+	var meter *openflow13.InstrMeter
+	for _, instr := range instructions {
+		switch instr := instr.(type) {
+		case *openflow15.InstrGotoTable:
+			result = append(result, openflow13.NewInstrGotoTable(instr.TableId))
+		case *openflow15.InstrWriteMetadata:
+			result = append(result, openflow13.NewInstrWriteMetadata(instr.Metadata, instr.MetadataMask))
+		case *openflow15.InstrActions:
+			actions := instr.Actions
+			if instr.Type == openflow15.InstrType_APPLY_ACTIONS && len(actions) > 0 {
+				if act, ok := actions[0].(*openflow15.ActionMeter); ok {
+					meter = openflow13.NewInstrMeter(act.MeterId)
+					actions = actions[1:]
+					if len(actions) == 0 {
+						// The apply-actions instruction is added for the meter action only.
+						continue
+					}
+				}
+			}
+			newInstr := openflow13.NewInstrApplyActions()
+			newInstr.Type = instr.Type
+			for _, act := range c.actionsTo13(actions) {
+				newInstr.AddAction(act, false)
+			}
+			result = append(result, newInstr)
+		default:
+			c.unsupportedf("instruction %T", instr)
+		}
+	}
+	if meter != nil {
+		result = append([]openflow13.Instruction{meter}, result...)
+	}
+	return result
+}
+
+// FlowModTo15 converts the FlowMod to openflow15.
+func FlowModTo15(m *openflow13.FlowMod) (*openflow15.FlowMod, error) {
+	c := &converter{version: openflow15.VERSION}
+	flowMod := openflow15.NewFlowMod()
+	flowMod.Header = c.header(m.Header)
+	flowMod.Cookie = m.Cookie
+	flowMod.CookieMask = m.CookieMask
+	flowMod.TableId = m.TableId
+	flowMod.Command = m.Command
+	flowMod.IdleTimeout = m.IdleTimeout
+	flowMod.HardTimeout = m.HardTimeout
+	flowMod.Priority = m.Priority
+	flowMod.BufferId = m.BufferId
+	flowMod.OutPort = m.OutPort
+	flowMod.OutGroup = m.OutGroup
+	flowMod.Flags = m.Flags
+	flowMod.Match = c.matchTo15(&m.Match)
+	flowMod.Instructions = c.instructionsTo15(m.Instructions)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return flowMod, nil
+}
+
+// FlowModTo13 converts the FlowMod to openflow13.
+func FlowModTo13(m *openflow15.FlowMod) (*openflow13.FlowMod, error) {
+	c := &converter{version: openflow13.VERSION}
+	flowMod := openflow13.NewFlowMod()
+	flowMod.Header = c.header(m.Header)
+	flowMod.Cookie = m.Cookie
+	flowMod.CookieMask = m.CookieMask
+	flowMod.TableId = m.TableId
+	flowMod.Command = m.Command
+	flowMod.IdleTimeout = m.IdleTimeout
+	flowMod.HardTimeout = m.HardTimeout
+	flowMod.Priority = m.Priority
+	flowMod.BufferId = m.BufferId
+	flowMod.OutPort = m.OutPort
+	flowMod.OutGroup = m.OutGroup
+	flowMod.Flags = m.Flags
+	if m.Importance != 0 {
+		c.unsupportedf("flow importance %d", m.Importance)
+	}
+	flowMod.Match = c.matchTo13(&m.Match)
+	flowMod.Instructions = c.instructionsTo13(m.Instructions)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return flowMod, nil
+}
+
+// GroupModTo15 converts the GroupMod to openflow15. The buckets are numbered by their positions, the weight is
+// converted to the bucket property of the select groups, and the watch port and group are converted to the bucket
+// properties if they are set. OFPGC_INSERT_BUCKET is not supported, the buckets of the group are unknown so the IDs of
+// the inserted buckets could collide with them.
+func GroupModTo15(m *openflow13.GroupMod) (*openflow15.GroupMod, error) {
+	c := &converter{version: openflow15.VERSION}
+	groupMod := openflow15.NewGroupMod()
+	groupMod.Header = c.header(m.Header)
+	groupMod.Command = m.Command
+	groupMod.Type = m.Type
+	groupMod.GroupId = m.GroupId
+	groupMod.CommandBucketId = openflow15.OFPG_BUCKET_ALL
+	switch m.Command {
+	case openflow13.OFPGC_ADD, openflow13.OFPGC_MODIFY, openflow13.OFPGC_DELETE:
+	case openflow13.OFPGC_INSERT_BUCKET:
+		c.unsupportedf("insertion of buckets without bucket IDs")
+	default:
+		c.unsupportedf("group command %d", m.Command)
+	}
+	for i, bkt := range m.Buckets {
+		bucket := openflow15.NewBucket(uint32(i))
+		for _, act := range c.actionsTo15(bkt.Actions) {
+			bucket.AddAction(act)
+		}
+		if m.Type == openflow13.OFPGT_SELECT {
+			bucket.AddProperty(openflow15.NewGroupBucketPropWeight(bkt.Weight))
+		} else if bkt.Weight != 0 {
+			c.unsupportedf("weight of bucket %d in group type %d", i, m.Type)
+		}
+		if bkt.WatchPort != openflow13.P_ANY {
+			bucket.AddProperty(openflow15.NewGroupBucketPropWatchPort(bkt.WatchPort))
+		}
+		if bkt.WatchGroup != openflow13.OFPG_ANY {
+			bucket.AddProperty(openflow15.NewGroupBucketPropWatchGroup(bkt.WatchGroup))
+		}
+		groupMod.AddBucket(*bucket)
+	}
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return groupMod, nil
+}
+
+// GroupModTo13 converts the GroupMod to openflow13. The bucket IDs must be the positions of the buckets, and the
+// group properties are not supported.
+func GroupModTo13(m *openflow15.GroupMod) (*openflow13.GroupMod, error) {
+	c := &converter{version: openflow13.VERSION}
+	groupMod := openflow13.NewGroupMod()
+	groupMod.Header = c.header(m.Header)
+	groupMod.Command = m.Command
+	groupMod.Type = m.Type
+	groupMod.GroupId = m.GroupId
+	switch m.Command {
+	case openflow15.OFPGC_ADD, openflow15.OFPGC_MODIFY, openflow15.OFPGC_DELETE:
+	case openflow15.OFPGC_INSERT_BUCKET:
+		if m.CommandBucketId != openflow15.OFPG_BUCKET_LAST {
+			c.unsupportedf("insertion of buckets before bucket %d", m.CommandBucketId)
+		}
+	default:
+		c.unsupportedf("group command %d", m.Command)
+	}
+	for _, prop := range m.Properties {
+		c.unsupportedf("group property %T", prop)
+	}
+	for i, bkt := range m.Buckets {
+		bucket := openflow13.NewBucket()
+		if bkt.BucketId != uint32(i) {
+			c.unsupportedf("bucket ID %d at position %d", bkt.BucketId, i)
+		}
+		for _, act := range c.actionsTo13(bkt.Actions) {
+			bucket.AddAction(act)
+		}
+		for _, prop := range bkt.Properties {
+			switch prop := prop.(type) {
+			case *openflow15.GroupBucketPropWeight:
+				bucket.Weight = prop.Weight
+			case *openflow15.GroupBucketPropWatch:
+				if prop.Header.Type == openflow15.GBPT_WATCH_PORT {
+					bucket.WatchPort = prop.Watch
+				} else {
+					bucket.WatchGroup = prop.Watch
+				}
+			default:
+				c.unsupportedf("bucket property %T", prop)
+			}
+		}
+		groupMod.AddBucket(*bucket)
+	}
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return groupMod, nil
+}
+
+// MeterModTo15 converts the MeterMod to openflow15, which has the same encoding.
+func MeterModTo15(m *openflow13.MeterMod) (*openflow15.MeterMod, error) {
+	c := &converter{version: openflow15.VERSION}
+	meterMod := openflow15.NewMeterMod()
+	src := *m
+	src.Header = c.header(m.Header)
+	c.reencode("meter mod", &src, meterMod)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return meterMod, nil
+}
+
+// MeterModTo13 converts the MeterMod to openflow13, which has the same encoding.
+func MeterModTo13(m *openflow15.MeterMod) (*openflow13.MeterMod, error) {
+	c := &converter{version: openflow13.VERSION}
+	meterMod := openflow13.NewMeterMod()
+	src := *m
+	src.Header = c.header(m.Header)
+	c.reencode("meter mod", &src, meterMod)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return meterMod, nil
+}
+
+// PacketOutTo15 converts the PacketOut to openflow15, the input port is converted to the in_port match field.
+func PacketOutTo15(p *openflow13.PacketOut) (*openflow15.PacketOut, error) {
+	c := &converter{version: openflow15.VERSION}
+	packetOut := openflow15.NewPacketOut()
+	packetOut.Header = c.header(p.Header)
+	packetOut.BufferId = p.BufferId
+	packetOut.Match.AddField(*openflow15.NewInPortField(p.InPort))
+	for _, act := range c.actionsTo15(p.Actions) {
+		packetOut.AddAction(act)
+	}
+	packetOut.Data = p.Data
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return packetOut, nil
+}
+
+// PacketOutTo13 converts the PacketOut to openflow13, the match must have the in_port field only.
+func PacketOutTo13(p *openflow15.PacketOut) (*openflow13.PacketOut, error) {
+	c := &converter{version: openflow13.VERSION}
+	packetOut := openflow13.NewPacketOut()
+	packetOut.Header = c.header(p.Header)
+	packetOut.BufferId = p.BufferId
+	for _, field := range p.Match.Fields {
+		inPort, ok := field.Value.(*openflow15.InPortField)
+		if field.Class != openflow15.OXM_CLASS_OPENFLOW_BASIC || field.Field != openflow15.OXM_FIELD_IN_PORT || !ok {
+			c.unsupportedf("packet out match field class %#x field %d", field.Class, field.Field)
+			continue
+		}
+		packetOut.InPort = inPort.InPort
+	}
+	for _, act := range c.actionsTo13(p.Actions) {
+		packetOut.AddAction(act)
+	}
+	packetOut.Data = p.Data
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return packetOut, nil
+}
+
+// MultipartRequestTo15 converts the MultipartRequest to openflow15. The multipart types of OpenFlow 1.3 have the
+// same numbers and encodings in OpenFlow 1.5, except the port description and the group description requests which
+// have a body in OpenFlow 1.5. They are converted to the requests of all the ports and all the groups.
+func MultipartRequestTo15(m *openflow13.MultipartRequest) (*openflow15.MultipartRequest, error) {
+	c := &converter{version: openflow15.VERSION}
+	switch m.Type {
+	case openflow13.MultipartType_PortDesc:
+		req := openflow15.NewMpRequest(openflow15.MultipartType_PortDesc)
+		req.Header = c.header(m.Header)
+		req.Flags = m.Flags
+		req.Body = append(req.Body, &openflow15.PortMultipartRequest{PortNo: openflow15.P_ANY, Pad: make([]byte, 4)})
+		return req, nil
+	case openflow13.MultipartType_GroupDesc:
+		req := openflow15.NewMpRequest(openflow15.MultipartType_GroupDesc)
+		req.Header = c.header(m.Header)
+		req.Flags = m.Flags
+		req.Body = append(req.Body, openflow15.NewGroupMultipartRequest(openflow15.OFPG_ALL))
+		return req, nil
+	}
+	req := openflow15.NewMpRequest(m.Type)
+	src := *m
+	src.Header = c.header(m.Header)
+	c.reencode(fmt.Sprintf("multipart request type %d", m.Type), &src, req)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// MultipartRequestTo13 converts the MultipartRequest to openflow13. The multipart types added by OpenFlow 1.4 and
+// 1.5 are not supported, and the port description and the group description requests must query all the ports and
+// all the groups.
+func MultipartRequestTo13(m *openflow15.MultipartRequest) (*openflow13.MultipartRequest, error) {
+	c := &converter{version: openflow13.VERSION}
+	switch {
+	case m.Type == openflow15.MultipartType_GroupDesc:
+		for _, body := range m.Body {
+			if groupReq, ok := body.(*openflow15.GroupMultipartRequest); !ok || groupReq.GroupId != openflow15.OFPG_ALL {
+				c.unsupportedf("group description request of a single group")
+			}
+		}
+		if err := c.err(); err != nil {
+			return nil, err
+		}
+		return &openflow13.MultipartRequest{
+			Header: c.header(m.Header),
+			Type:   openflow13.MultipartType_GroupDesc,
+			Flags:  m.Flags,
+		}, nil
+	case m.Type == openflow15.MultipartType_PortDesc:
+		for _, body := range m.Body {
+			if portReq, ok := body.(*openflow15.PortMultipartRequest); !ok || portReq.PortNo != openflow15.P_ANY {
+				c.unsupportedf("port description request of a single port")
+			}
+		}
+		if err := c.err(); err != nil {
+			return nil, err
+		}
+		return &openflow13.MultipartRequest{
+			Header: c.header(m.Header),
+			Type:   openflow13.MultipartType_PortDesc,
+			Flags:  m.Flags,
+		}, nil
+	case m.Type > openflow13.MultipartType_PortDesc && m.Type != openflow15.MultipartType_Experimenter:
+		c.unsupportedf("multipart request type %d", m.Type)
+		return nil, c.err()
+	}
+	req := new(openflow13.MultipartRequest)
+	src := *m
+	src.Header = c.header(m.Header)
+	c.reencode(fmt.Sprintf("multipart request type %d", m.Type), &src, req)
+	if err := c.err(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
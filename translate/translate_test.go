@@ -0,0 +1,161 @@
+package translate
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/libOpenflow/openflow13"
+	"antrea.io/libOpenflow/openflow15"
+	"antrea.io/libOpenflow/util"
+)
+
+// assertRoundTrip converts msg to the other version and back, and checks the result is encoded the same as msg.
+func assertRoundTrip(t *testing.T, msg util.Message, to, back func(util.Message) (util.Message, error)) util.Message {
+	converted, err := to(msg)
+	require.NoError(t, err)
+	result, err := back(converted)
+	require.NoError(t, err)
+	expected, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	data, err := result.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+	return converted
+}
+
+func TestFlowMod(t *testing.T) {
+	flowMod := openflow13.NewFlowMod()
+	flowMod.TableId = 1
+	flowMod.Priority = 100
+	flowMod.Cookie = 0x1234
+	flowMod.Match.AddField(*openflow13.NewInPortField(3))
+	flowMod.Match.AddField(*openflow13.NewEthTypeField(0x0800))
+	flowMod.Match.AddField(*openflow13.NewRegMatchField(0, 5, nil))
+	flowMod.AddInstruction(openflow13.NewInstrMeter(10))
+	applyActions := openflow13.NewInstrApplyActions()
+	applyActions.AddAction(openflow13.NewNXActionResubmitTableAction(openflow13.OFPP_IN_PORT, 2), false)
+	applyActions.AddAction(openflow13.NewActionOutput(4), false)
+	flowMod.AddInstruction(applyActions)
+	flowMod.AddInstruction(openflow13.NewInstrGotoTable(3))
+
+	converted := assertRoundTrip(t, flowMod, To15, To13).(*openflow15.FlowMod)
+	assert.Equal(t, uint8(openflow15.VERSION), converted.Version)
+	assert.Equal(t, flowMod.Xid, converted.Xid)
+	require.Len(t, converted.Match.Fields, 3)
+	require.Len(t, converted.Instructions, 2)
+	actions := converted.Instructions[0].(*openflow15.InstrActions).Actions
+	require.Len(t, actions, 3)
+	assert.Equal(t, uint32(10), actions[0].(*openflow15.ActionMeter).MeterId)
+	assert.IsType(t, new(openflow15.NXActionResubmitTable), actions[1])
+
+	// The meter action is converted to the meter instruction only at the beginning of the apply-actions.
+	flowMod15 := openflow15.NewFlowMod()
+	flowMod15.Importance = 1
+	applyActions15 := openflow15.NewInstrApplyActions()
+	applyActions15.AddAction(openflow15.NewActionOutput(4), false)
+	applyActions15.AddAction(openflow15.NewActionMeter(10), false)
+	flowMod15.AddInstruction(applyActions15)
+	flowMod15.AddInstruction(openflow15.NewInstrStatTrigger(0))
+	_, err := FlowModTo13(flowMod15)
+	var unsupportedErr *UnsupportedError
+	require.ErrorAs(t, err, &unsupportedErr)
+	assert.Equal(t, uint8(openflow13.VERSION), unsupportedErr.Version)
+	assert.Len(t, unsupportedErr.Constructs, 3)
+}
+
+func TestGroupMod(t *testing.T) {
+	groupMod := openflow13.NewGroupMod()
+	groupMod.GroupId = 5
+	groupMod.Type = openflow13.OFPGT_SELECT
+	for i := 0; i < 2; i++ {
+		bucket := openflow13.NewBucket()
+		bucket.Weight = 100
+		bucket.AddAction(openflow13.NewActionOutput(uint32(i + 1)))
+		groupMod.AddBucket(*bucket)
+	}
+	converted := assertRoundTrip(t, groupMod, To15, To13).(*openflow15.GroupMod)
+	require.Len(t, converted.Buckets, 2)
+	assert.Equal(t, uint32(1), converted.Buckets[1].BucketId)
+	assert.Equal(t, uint16(100), converted.Buckets[1].Properties[0].(*openflow15.GroupBucketPropWeight).Weight)
+
+	// The inserted buckets can't be numbered without colliding with the buckets of the group.
+	groupMod.Command = openflow13.OFPGC_INSERT_BUCKET
+	_, err := To15(groupMod)
+	var unsupportedErr *UnsupportedError
+	require.ErrorAs(t, err, &unsupportedErr)
+	assert.Equal(t, uint8(openflow15.VERSION), unsupportedErr.Version)
+
+	groupMod15 := openflow15.NewGroupMod()
+	groupMod15.Command = openflow15.OFPGC_REMOVE_BUCKET
+	groupMod15.AddBucket(*openflow15.NewBucket(7))
+	_, err = To13(groupMod15)
+	assert.Error(t, err)
+}
+
+func TestMeterModAndPacketOut(t *testing.T) {
+	meterMod := openflow13.NewMeterMod()
+	meterMod.MeterId = 3
+	meterMod.Flags = openflow13.OFPMF13_KBPS
+	band := &openflow13.MeterBandDrop{MeterBandHeader: *openflow13.NewMeterBandHeader()}
+	band.Type = openflow13.OFPMBT13_DROP
+	band.Rate = 1000
+	meterMod.AddMeterBand(band)
+	assertRoundTrip(t, meterMod, To15, To13)
+
+	packetOut := openflow13.NewPacketOut()
+	packetOut.InPort = 3
+	packetOut.AddAction(openflow13.NewActionOutput(4))
+	packetOut.Data = util.NewBuffer([]byte{1, 2, 3, 4})
+	converted := assertRoundTrip(t, packetOut, To15, To13).(*openflow15.PacketOut)
+	assert.Equal(t, uint32(3), converted.Match.Fields[0].Value.(*openflow15.InPortField).InPort)
+
+	packetOut15 := openflow15.NewPacketOut()
+	packetOut15.Match.AddField(*openflow15.NewRegMatchField(0, 1, nil))
+	packetOut15.Data = util.NewBuffer([]byte{1, 2, 3, 4})
+	_, err := To13(packetOut15)
+	assert.Error(t, err)
+}
+
+func TestMultipartRequest(t *testing.T) {
+	flowStats := openflow13.NewFlowStatsRequest()
+	flowStats.Match.AddField(*openflow13.NewInPortField(3))
+	request := &openflow13.MultipartRequest{
+		Header: openflow13.NewOfp13Header(),
+		Type:   openflow13.MultipartType_Flow,
+		Body:   []util.Message{flowStats},
+	}
+	request.Header.Type = openflow13.Type_MultiPartRequest
+	assertRoundTrip(t, request, To15, To13)
+
+	request = &openflow13.MultipartRequest{
+		Header: openflow13.NewOfp13Header(),
+		Type:   openflow13.MultipartType_PortDesc,
+	}
+	request.Header.Type = openflow13.Type_MultiPartRequest
+	assertRoundTrip(t, request, To15, To13)
+
+	// The group description request of OpenFlow 1.5 has the group ID and 4 bytes of padding.
+	request = &openflow13.MultipartRequest{
+		Header: openflow13.NewOfp13Header(),
+		Type:   openflow13.MultipartType_GroupDesc,
+	}
+	request.Header.Type = openflow13.Type_MultiPartRequest
+	request.Header.Xid = 0x10
+	converted, err := To15(request)
+	require.NoError(t, err)
+	data, err := converted.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "06120018000000100007000000000000fffffffc00000000", hex.EncodeToString(data))
+	assertRoundTrip(t, request, To15, To13)
+
+	groupReq := openflow15.NewMpRequest(openflow15.MultipartType_GroupDesc)
+	groupReq.Body = append(groupReq.Body, openflow15.NewGroupMultipartRequest(1))
+	_, err = To13(groupReq)
+	assert.Error(t, err)
+
+	_, err = To13(openflow15.NewMpRequest(openflow15.MultipartType_FlowMonitor))
+	assert.Error(t, err)
+}
@@ -0,0 +1,360 @@
+package util
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBundleTimeout is the default time to wait for the reply of a bundle control request.
+const DefaultBundleTimeout = 10 * time.Second
+
+// The bundle control types and the atomic flag have the same values in the ONF bundle extension of OpenFlow 1.3 and
+// in OpenFlow 1.4+.
+const (
+	bundleOpenRequest    uint16 = 0
+	bundleCommitRequest  uint16 = 4
+	bundleDiscardRequest uint16 = 6
+
+	bundleFlagAtomic uint16 = 1 << 0
+)
+
+// BundleMessageError is the error replied by the switch for a message added into a bundle transaction. E is the
+// error message of the OpenFlow version.
+type BundleMessageError[E error] struct {
+	// Index is the position of the message in all the messages added into the transaction.
+	Index   int
+	Message Message
+	Error   E
+}
+
+// BundleTransactionError is returned by BundleTransaction when the switch rejects a bundle or any added message.
+type BundleTransactionError[E error] struct {
+	BundleID uint32
+	// Err is the error of the bundle control request, the bundle errors are decoded by the BundleProtocol. It is nil
+	// if only the added messages failed.
+	Err error
+	// Messages are the errors replied for the added messages.
+	Messages []BundleMessageError[E]
+	// Committed are the IDs of the bundles committed before the failing bundle of a split transaction, whose
+	// messages are applied and must be rolled back by the caller if needed.
+	Committed []uint32
+	// DiscardErr is the error of discarding the bundles after the failing bundle, which may remain opened on the
+	// switch until they time out.
+	DiscardErr error
+}
+
+func (e *BundleTransactionError[E]) Error() string {
+	msg := fmt.Sprintf("bundle %d failed", e.BundleID)
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	for _, m := range e.Messages {
+		msg = fmt.Sprintf("%s; message %d failed with %v", msg, m.Index, m.Error)
+	}
+	if len(e.Committed) > 0 {
+		msg = fmt.Sprintf("%s; bundles %v are committed", msg, e.Committed)
+	}
+	if e.DiscardErr != nil {
+		msg = fmt.Sprintf("%s; %v", msg, e.DiscardErr)
+	}
+	return msg
+}
+
+func (e *BundleTransactionError[E]) Unwrap() error {
+	return e.Err
+}
+
+// BundleProtocol builds the bundle messages of an OpenFlow version and decodes their replies for BundleTransaction.
+type BundleProtocol[E error] interface {
+	// NewControl returns the bundle control request with the properties, and its xid.
+	NewControl(bundleID uint32, ctrlType uint16, flags uint16, props []Message) (Message, uint32)
+	// NewAdd returns the request to add msg into the bundle. The request must have the xid of msg.
+	NewAdd(bundleID uint32, flags uint16, msg Message, xid uint32) Message
+	// CheckControlReply returns nil if reply is the successful reply of the control request with ctrlType, or the
+	// error replied by the switch.
+	CheckControlReply(reply Message, ctrlType uint16) error
+	// ParseReply returns the xid of msg and, if msg is an error, the error message. ok is false if msg can't be a
+	// reply of the transaction.
+	ParseReply(msg Message) (xid uint32, errMsg E, isError bool, ok bool)
+}
+
+// BundleTransaction adds messages into bundles and commits or discards them. The messages are sent with the send
+// function, and the replies and errors received on the connection must be passed to HandleMessage.
+type BundleTransaction[E error] struct {
+	// MaxMessages is the maximum number of messages in a bundle, 0 means no limit. When a bundle is full, a new
+	// bundle is opened with the ID returned by NextBundleID for the following messages, and Commit commits the
+	// bundles in order, so the messages are atomic within a bundle only. An atomic bundle is never split.
+	MaxMessages int
+	// NextBundleID returns the ID of the new bundle when a bundle is full. It must not return an ID used by other
+	// bundles on the connection. A full bundle can't be split if it is nil.
+	NextBundleID func() uint32
+	// Timeout is the time to wait for the reply of a bundle control request.
+	Timeout time.Duration
+
+	protocol BundleProtocol[E]
+	send     func(msg Message) error
+	bundleID uint32
+	flags    uint16
+
+	// opMutex serializes Open, Add, Commit and Discard, so that a full bundle is followed by only one new bundle.
+	opMutex sync.Mutex
+	// mutex protects the following fields, which are also accessed by HandleMessage.
+	mutex sync.Mutex
+	// bundles are the IDs of the opened bundles.
+	bundles []uint32
+	// count is the number of messages in the last bundle.
+	count    int
+	messages []Message
+	// xids stores the index of the added messages by xid.
+	xids     map[uint32]int
+	failures []BundleMessageError[E]
+	waiters  map[uint32]chan Message
+	closed   bool
+}
+
+// NewBundleTransaction creates a BundleTransaction with the bundle ID and the bundle flags, using the bundle
+// messages built by protocol.
+func NewBundleTransaction[E error](protocol BundleProtocol[E], send func(msg Message) error, bundleID uint32, flags uint16) *BundleTransaction[E] {
+	return &BundleTransaction[E]{
+		Timeout:  DefaultBundleTimeout,
+		protocol: protocol,
+		send:     send,
+		bundleID: bundleID,
+		flags:    flags,
+		xids:     make(map[uint32]int),
+		waiters:  make(map[uint32]chan Message),
+	}
+}
+
+// Flags returns the bundle flags of the transaction.
+func (t *BundleTransaction[E]) Flags() uint16 {
+	return t.flags
+}
+
+// Open opens the first bundle of the transaction.
+func (t *BundleTransaction[E]) Open() error {
+	t.opMutex.Lock()
+	defer t.opMutex.Unlock()
+	t.mutex.Lock()
+	if len(t.bundles) > 0 || t.closed {
+		t.mutex.Unlock()
+		return errors.New("the bundle transaction is already opened")
+	}
+	t.mutex.Unlock()
+	return t.openBundle(t.bundleID)
+}
+
+func (t *BundleTransaction[E]) openBundle(bundleID uint32) error {
+	if err := t.control(bundleID, bundleOpenRequest, nil); err != nil {
+		return err
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.bundles = append(t.bundles, bundleID)
+	t.count = 0
+	return nil
+}
+
+// nextBundle returns the ID of the bundle replacing the full last bundle.
+func (t *BundleTransaction[E]) nextBundle() (uint32, error) {
+	if t.flags&bundleFlagAtomic != 0 {
+		return 0, fmt.Errorf("the atomic bundle is full with %d messages and can't be split", t.MaxMessages)
+	}
+	if t.NextBundleID == nil {
+		return 0, fmt.Errorf("the bundle is full with %d messages and NextBundleID is not set", t.MaxMessages)
+	}
+	bundleID := t.NextBundleID()
+	for _, id := range t.bundles {
+		if id == bundleID {
+			return 0, fmt.Errorf("the bundle ID %d is already used by the transaction", bundleID)
+		}
+	}
+	return bundleID, nil
+}
+
+// Add adds the messages into the bundle. The xid of a BundleAdd is the xid of its message, so the errors replied for
+// the messages are reported by Commit. The messages must have different xids.
+func (t *BundleTransaction[E]) Add(msgs ...Message) error {
+	t.opMutex.Lock()
+	defer t.opMutex.Unlock()
+	for _, msg := range msgs {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if len(data) < 8 {
+			return errors.New("the message to add into the bundle has no OpenFlow header")
+		}
+		xid := binary.BigEndian.Uint32(data[4:])
+
+		t.mutex.Lock()
+		if len(t.bundles) == 0 || t.closed {
+			t.mutex.Unlock()
+			return errors.New("the bundle transaction is not opened")
+		}
+		if index, ok := t.xids[xid]; ok {
+			t.mutex.Unlock()
+			return fmt.Errorf("the xid %d is already used by message %d in the bundle transaction", xid, index)
+		}
+		bundleID := t.bundles[len(t.bundles)-1]
+		full := t.MaxMessages > 0 && t.count >= t.MaxMessages
+		t.mutex.Unlock()
+		if full {
+			if bundleID, err = t.nextBundle(); err != nil {
+				return err
+			}
+			if err := t.openBundle(bundleID); err != nil {
+				return err
+			}
+		}
+
+		t.mutex.Lock()
+		t.xids[xid] = len(t.messages)
+		t.messages = append(t.messages, msg)
+		t.count++
+		t.mutex.Unlock()
+		if err := t.send(t.protocol.NewAdd(bundleID, t.flags, msg, xid)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit commits the bundles in order. It returns a *BundleTransactionError if a bundle fails to commit or any added
+// message is rejected by the switch. The bundles committed before the failing one are not rolled back but listed in
+// the Committed field of the error, and the bundles after it are discarded.
+func (t *BundleTransaction[E]) Commit() error {
+	return t.CommitWithProperties(nil)
+}
+
+// CommitWithProperties commits the bundles as Commit, with the properties in the commit requests.
+func (t *BundleTransaction[E]) CommitWithProperties(props []Message) error {
+	t.opMutex.Lock()
+	defer t.opMutex.Unlock()
+	bundles, err := t.close()
+	if err != nil {
+		return err
+	}
+	for i, bundleID := range bundles {
+		if err := t.control(bundleID, bundleCommitRequest, props); err != nil {
+			// The switch discards the bundle failing to commit, the following bundles are still opened.
+			var discardErrs []error
+			for _, id := range bundles[i+1:] {
+				if discardErr := t.control(id, bundleDiscardRequest, nil); discardErr != nil {
+					discardErrs = append(discardErrs, fmt.Errorf("failed to discard bundle %d: %w", id, discardErr))
+				}
+			}
+			txnErr := t.newError(bundleID, err)
+			txnErr.Committed = append([]uint32(nil), bundles[:i]...)
+			txnErr.DiscardErr = errors.Join(discardErrs...)
+			return txnErr
+		}
+	}
+	t.mutex.Lock()
+	failures := len(t.failures)
+	t.mutex.Unlock()
+	if failures > 0 {
+		return t.newError(bundles[len(bundles)-1], nil)
+	}
+	return nil
+}
+
+// Discard discards all the bundles of the transaction.
+func (t *BundleTransaction[E]) Discard() error {
+	t.opMutex.Lock()
+	defer t.opMutex.Unlock()
+	bundles, err := t.close()
+	if err != nil {
+		return err
+	}
+	for _, bundleID := range bundles {
+		if discardErr := t.control(bundleID, bundleDiscardRequest, nil); discardErr != nil && err == nil {
+			err = t.newError(bundleID, discardErr)
+		}
+	}
+	return err
+}
+
+func (t *BundleTransaction[E]) close() ([]uint32, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.bundles) == 0 || t.closed {
+		return nil, errors.New("the bundle transaction is not opened")
+	}
+	t.closed = true
+	return t.bundles, nil
+}
+
+func (t *BundleTransaction[E]) newError(bundleID uint32, err error) *BundleTransactionError[E] {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return &BundleTransactionError[E]{
+		BundleID: bundleID,
+		Err:      err,
+		Messages: append([]BundleMessageError[E](nil), t.failures...),
+	}
+}
+
+// control sends the bundle control request and waits for the reply.
+func (t *BundleTransaction[E]) control(bundleID uint32, ctrlType uint16, props []Message) error {
+	msg, xid := t.protocol.NewControl(bundleID, ctrlType, t.flags, props)
+	reply, err := t.Request(msg, xid)
+	if err != nil {
+		return err
+	}
+	return t.protocol.CheckControlReply(reply, ctrlType)
+}
+
+// Request sends msg and waits for the reply with the xid, which is passed to HandleMessage.
+func (t *BundleTransaction[E]) Request(msg Message, xid uint32) (Message, error) {
+	ch := make(chan Message, 1)
+	t.mutex.Lock()
+	t.waiters[xid] = ch
+	t.mutex.Unlock()
+	defer func() {
+		t.mutex.Lock()
+		delete(t.waiters, xid)
+		t.mutex.Unlock()
+	}()
+	if err := t.send(msg); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(t.Timeout)
+	defer timer.Stop()
+	select {
+	case reply := <-ch:
+		return reply, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("timeout waiting for the reply of request %d", xid)
+	}
+}
+
+// HandleMessage handles the bundle control replies and the errors of the transaction, and returns false if msg is
+// not a message of the transaction.
+func (t *BundleTransaction[E]) HandleMessage(msg Message) bool {
+	xid, errMsg, isError, ok := t.protocol.ParseReply(msg)
+	if !ok {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if ch, ok := t.waiters[xid]; ok {
+		delete(t.waiters, xid)
+		ch <- msg
+		return true
+	}
+	if index, ok := t.xids[xid]; ok && isError {
+		t.failures = append(t.failures, BundleMessageError[E]{
+			Index:   index,
+			Message: t.messages[index],
+			Error:   errMsg,
+		})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ExperimenterActionDecoder returns a new action of type A to unmarshal the experimenter action encoded in data. The
+// data starts with the action header, including the experimenter ID.
+type ExperimenterActionDecoder[A any] func(data []byte) (A, error)
+
+// ExperimenterMatchFieldDecoder decodes the value or the mask of an OXM experimenter match field, the data starts
+// after the experimenter ID.
+type ExperimenterMatchFieldDecoder func(class uint16, field uint8, length uint8, hasMask bool, data []byte) (Message, error)
+
+// ExperimenterMessageDecoder decodes the body of an experimenter message, which follows the experimenter ID and
+// the experimenter type.
+type ExperimenterMessageDecoder func(expType uint32, data []byte) (Message, error)
+
+// ExperimenterMultipartDecoder decodes the body of an experimenter multipart request or reply. The data starts
+// with the experimenter ID and the experimenter type, and the length of the returned message must include them.
+type ExperimenterMultipartDecoder func(expType uint32, data []byte) (Message, error)
+
+// ExperimenterPropertyFactory returns a new property to unmarshal an experimenter property with the given property
+// type and experimenter type. It returns nil to decode the property with the generic experimenter property.
+type ExperimenterPropertyFactory func(propType uint16, expType uint32) Message
+
+// ExperimenterRegistry stores the decoders of the experimenter extensions of an OpenFlow version, indexed by
+// experimenter ID. A is the action type of the OpenFlow version. Registering a nil decoder removes the registration.
+type ExperimenterRegistry[A any] struct {
+	mutex             sync.RWMutex
+	actions           map[uint32]ExperimenterActionDecoder[A]
+	matchFields       map[uint32]ExperimenterMatchFieldDecoder
+	messages          map[uint32]ExperimenterMessageDecoder
+	multipartRequests map[uint32]ExperimenterMultipartDecoder
+	multipartReplies  map[uint32]ExperimenterMultipartDecoder
+	properties        map[uint32]ExperimenterPropertyFactory
+}
+
+func NewExperimenterRegistry[A any]() *ExperimenterRegistry[A] {
+	return &ExperimenterRegistry[A]{
+		actions:           make(map[uint32]ExperimenterActionDecoder[A]),
+		matchFields:       make(map[uint32]ExperimenterMatchFieldDecoder),
+		messages:          make(map[uint32]ExperimenterMessageDecoder),
+		multipartRequests: make(map[uint32]ExperimenterMultipartDecoder),
+		multipartReplies:  make(map[uint32]ExperimenterMultipartDecoder),
+		properties:        make(map[uint32]ExperimenterPropertyFactory),
+	}
+}
+
+// register sets or, if the decoder is nil, deletes the decoder of the experimenter in m.
+func register[D any](r *sync.RWMutex, m map[uint32]D, experimenterID uint32, decoder D, isNil bool) {
+	r.Lock()
+	defer r.Unlock()
+	if isNil {
+		delete(m, experimenterID)
+		return
+	}
+	m[experimenterID] = decoder
+}
+
+func (r *ExperimenterRegistry[A]) RegisterAction(experimenterID uint32, decoder ExperimenterActionDecoder[A]) {
+	register(&r.mutex, r.actions, experimenterID, decoder, decoder == nil)
+}
+
+func (r *ExperimenterRegistry[A]) RegisterMatchField(experimenterID uint32, decoder ExperimenterMatchFieldDecoder) {
+	register(&r.mutex, r.matchFields, experimenterID, decoder, decoder == nil)
+}
+
+func (r *ExperimenterRegistry[A]) RegisterMessage(experimenterID uint32, decoder ExperimenterMessageDecoder) {
+	register(&r.mutex, r.messages, experimenterID, decoder, decoder == nil)
+}
+
+func (r *ExperimenterRegistry[A]) RegisterMultipart(experimenterID uint32, request, reply ExperimenterMultipartDecoder) {
+	register(&r.mutex, r.multipartRequests, experimenterID, request, request == nil)
+	register(&r.mutex, r.multipartReplies, experimenterID, reply, reply == nil)
+}
+
+func (r *ExperimenterRegistry[A]) RegisterProperty(experimenterID uint32, factory ExperimenterPropertyFactory) {
+	register(&r.mutex, r.properties, experimenterID, factory, factory == nil)
+}
+
+// DecodeAction decodes the action of the experimenter encoded in data.
+func (r *ExperimenterRegistry[A]) DecodeAction(experimenterID uint32, data []byte) (A, error) {
+	r.mutex.RLock()
+	decoder, ok := r.actions[experimenterID]
+	r.mutex.RUnlock()
+	if !ok {
+		var a A
+		return a, fmt.Errorf("unsupported experimenter action: %v", experimenterID)
+	}
+	return decoder(data)
+}
+
+// MatchFieldDecoder returns the decoder of the match fields of the experimenter, or nil if it is not registered.
+func (r *ExperimenterRegistry[A]) MatchFieldDecoder(experimenterID uint32) ExperimenterMatchFieldDecoder {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.matchFields[experimenterID]
+}
+
+// DecodeMessage decodes the body of the experimenter message.
+func (r *ExperimenterRegistry[A]) DecodeMessage(experimenterID uint32, expType uint32, data []byte) (Message, error) {
+	r.mutex.RLock()
+	decoder, ok := r.messages[experimenterID]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported experimenter message: %v", experimenterID)
+	}
+	return decoder(expType, data)
+}
+
+// DecodeMultipart decodes the experimenter multipart request or reply body at the start of data. The returned body
+// always has a non-zero length, so that the caller can move to the next body.
+func (r *ExperimenterRegistry[A]) DecodeMultipart(data []byte, isReply bool) (Message, error) {
+	if len(data) < 8 {
+		return nil, errors.New("the []byte is too short to unmarshal an experimenter multipart body")
+	}
+	experimenterID := binary.BigEndian.Uint32(data)
+	expType := binary.BigEndian.Uint32(data[4:])
+	r.mutex.RLock()
+	decoder, ok := r.multipartRequests[experimenterID]
+	if isReply {
+		decoder, ok = r.multipartReplies[experimenterID]
+	}
+	r.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported experimenter multipart: %v", experimenterID)
+	}
+	body, err := decoder(expType, data)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil || body.Len() == 0 {
+		return nil, fmt.Errorf("the experimenter multipart body of experimenter %v has a zero length", experimenterID)
+	}
+	return body, nil
+}
+
+// NewProperty returns the property registered for the experimenter property encoded in data, or defaultProp if the
+// experimenter does not register a property for it. The data starts with the property type and length, followed by
+// the experimenter ID and the experimenter type.
+func (r *ExperimenterRegistry[A]) NewProperty(data []byte, defaultProp Message) Message {
+	if len(data) < 12 {
+		return defaultProp
+	}
+	r.mutex.RLock()
+	factory, ok := r.properties[binary.BigEndian.Uint32(data[4:])]
+	r.mutex.RUnlock()
+	if !ok {
+		return defaultProp
+	}
+	if p := factory(binary.BigEndian.Uint16(data), binary.BigEndian.Uint32(data[8:])); p != nil {
+		return p
+	}
+	return defaultProp
+}
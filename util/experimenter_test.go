@@ -0,0 +1,86 @@
+package util
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testExperimenterID = 0x00abcdef
+
+func newTestMultipartBody(expType uint32, length int) []byte {
+	data := make([]byte, length)
+	binary.BigEndian.PutUint32(data, testExperimenterID)
+	binary.BigEndian.PutUint32(data[4:], expType)
+	return data
+}
+
+func TestExperimenterRegistryMultipart(t *testing.T) {
+	r := NewExperimenterRegistry[Message]()
+	data := newTestMultipartBody(1, 12)
+	_, err := r.DecodeMultipart(data, true)
+	assert.Error(t, err, "Bodies of unregistered experimenters should not be decoded")
+	_, err = r.DecodeMultipart(data[:4], true)
+	assert.Error(t, err)
+
+	decode := func(expType uint32, data []byte) (Message, error) {
+		if expType == 0 {
+			// A decoder which doesn't consume the body.
+			return NewBuffer(nil), nil
+		}
+		return NewBuffer(data[:12]), nil
+	}
+	r.RegisterMultipart(testExperimenterID, nil, decode)
+	_, err = r.DecodeMultipart(data, false)
+	assert.Error(t, err, "Only the reply decoder is registered")
+	body, err := r.DecodeMultipart(data, true)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(12), body.Len())
+
+	// A body with zero length would never move the caller to the next body.
+	_, err = r.DecodeMultipart(newTestMultipartBody(0, 12), true)
+	assert.Error(t, err)
+
+	r.RegisterMultipart(testExperimenterID, nil, nil)
+	_, err = r.DecodeMultipart(data, true)
+	assert.Error(t, err)
+}
+
+func TestExperimenterRegistryProperty(t *testing.T) {
+	r := NewExperimenterRegistry[Message]()
+	data := make([]byte, 12)
+	binary.BigEndian.PutUint16(data, 0xffff)
+	binary.BigEndian.PutUint32(data[4:], testExperimenterID)
+	binary.BigEndian.PutUint32(data[8:], 2)
+	defaultProp := NewBuffer(nil)
+	assert.Same(t, defaultProp, r.NewProperty(data, defaultProp))
+
+	prop := NewBuffer(nil)
+	r.RegisterProperty(testExperimenterID, func(propType uint16, expType uint32) Message {
+		if propType == 0xffff && expType == 2 {
+			return prop
+		}
+		return nil
+	})
+	assert.Same(t, prop, r.NewProperty(data, defaultProp))
+	assert.Same(t, defaultProp, r.NewProperty(data[:8], defaultProp))
+	binary.BigEndian.PutUint32(data[8:], 3)
+	assert.Same(t, defaultProp, r.NewProperty(data, defaultProp))
+}
+
+func TestExperimenterRegistryAction(t *testing.T) {
+	r := NewExperimenterRegistry[Message]()
+	_, err := r.DecodeAction(testExperimenterID, nil)
+	assert.Error(t, err)
+	r.RegisterAction(testExperimenterID, func(data []byte) (Message, error) {
+		return NewBuffer(data), nil
+	})
+	act, err := r.DecodeAction(testExperimenterID, []byte{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), act.Len())
+	r.RegisterAction(testExperimenterID, nil)
+	_, err = r.DecodeAction(testExperimenterID, nil)
+	assert.Error(t, err)
+}